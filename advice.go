@@ -0,0 +1,160 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"sort"
+	"sync"
+)
+
+// AdviceHow specifies how advice is combined with the advised function.  See
+// [Advice Combinators].
+//
+// [Advice Combinators]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Advice-Combinators.html
+type AdviceHow Symbol
+
+// Advice combinators for [Env.AdviceAdd].
+const (
+	AdviceAround       AdviceHow = ":around"
+	AdviceBefore       AdviceHow = ":before"
+	AdviceAfter        AdviceHow = ":after"
+	AdviceOverride     AdviceHow = ":override"
+	AdviceAfterUntil   AdviceHow = ":after-until"
+	AdviceAfterWhile   AdviceHow = ":after-while"
+	AdviceBeforeUntil  AdviceHow = ":before-until"
+	AdviceBeforeWhile  AdviceHow = ":before-while"
+	AdviceFilterArgs   AdviceHow = ":filter-args"
+	AdviceFilterReturn AdviceHow = ":filter-return"
+)
+
+// Advice identifies a Go function added as advice to an Emacs function.  The
+// zero Advice doesn’t identify any advice.
+type Advice struct {
+	symbol Name
+	name   Name
+	index  funcIndex
+}
+
+// Name returns the name of the function symbol that represents the advice.
+func (a Advice) Name() Name {
+	return a.name
+}
+
+// AdviceAdd adds fun as advice to the function named symbol using
+// advice-add.  how determines how fun is combined with the original function;
+// fun receives the same arguments as the corresponding Lisp advice.  In
+// particular, for [AdviceAround] the first argument is the original function.
+// Use [Env.AdviceAddAround] to receive it as a Go function instead.
+// AdviceAdd defines a function symbol whose name starts with symbol, followed
+// by “@go-” and a number, and adds that symbol as advice.  Use
+// [Env.AdviceRemove] to remove the advice and release the Go function.
+func (e Env) AdviceAdd(symbol Name, how AdviceHow, fun Func) (Advice, error) {
+	return e.addAdvice(symbol, how, fun, Arity{0, -1})
+}
+
+func (e Env) addAdvice(symbol Name, how AdviceHow, fun Func, arity Arity) (Advice, error) {
+	name, index, err := e.exportNumbered(string(symbol)+"@go-", Lambda{fun, arity, "Advice defined in Go."})
+	if err != nil {
+		return Advice{}, err
+	}
+	if _, err := e.Call("advice-add", symbol, Symbol(how), name); err != nil {
+		e.releaseNumbered(name, index)
+		return Advice{}, err
+	}
+	r := Advice{symbol, name, index}
+	advices.add(r)
+	return r, nil
+}
+
+// AdviceAddAround adds fun as :around advice to the function named symbol.
+// fun receives the original function as a Go function, which it can call
+// any number of times, and the arguments of the advised function.  The
+// original function is only valid during the call to fun.  Otherwise,
+// AdviceAddAround is like [Env.AdviceAdd].
+func (e Env) AdviceAddAround(symbol Name, fun func(e Env, orig Func, args []Value) (Value, error)) (Advice, error) {
+	return e.addAdvice(symbol, AdviceAround, func(e Env, args []Value) (Value, error) {
+		orig := args[0]
+		return fun(e, func(e Env, args []Value) (Value, error) { return e.Funcall(orig, args) }, args[1:])
+	}, Arity{1, -1})
+}
+
+// AdviceRemove removes advice added by [Env.AdviceAdd] or
+// [Env.AdviceAddAround] and releases the Go function.  Removing advice that
+// has already been removed does nothing.
+func (e Env) AdviceRemove(a Advice) error {
+	if !advices.remove(a) {
+		return nil
+	}
+	_, err := e.Call("advice-remove", a.symbol, a.name)
+	if relErr := e.releaseNumbered(a.name, a.index); err == nil {
+		err = relErr
+	}
+	return err
+}
+
+// RemoveAllAdvice removes all advice that has been added using
+// [Env.AdviceAdd] or [Env.AdviceAddAround], as if calling
+// [Env.AdviceRemove] for each of them.  This is useful when unloading a
+// module.  RemoveAllAdvice returns the first error encountered, but tries to
+// remove all advice.
+func RemoveAllAdvice(e Env) error {
+	var err error
+	for _, a := range advices.all() {
+		if rmErr := e.AdviceRemove(a); err == nil {
+			err = rmErr
+		}
+	}
+	return err
+}
+
+// adviceRegistry keeps track of Go advice.
+type adviceRegistry struct {
+	mu      sync.Mutex
+	advices map[funcIndex]Advice
+}
+
+func (r *adviceRegistry) add(a Advice) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.advices == nil {
+		r.advices = make(map[funcIndex]Advice)
+	}
+	r.advices[a.index] = a
+}
+
+// remove removes a from the registry and returns whether it was present.
+func (r *adviceRegistry) remove(a Advice) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.advices[a.index]; !ok {
+		return false
+	}
+	delete(r.advices, a.index)
+	return true
+}
+
+// all returns all registered advice in the order in which it was added.
+func (r *adviceRegistry) all() []Advice {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := make([]Advice, 0, len(r.advices))
+	for _, a := range r.advices {
+		s = append(s, a)
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].index < s[j].index })
+	return s
+}
+
+var advices adviceRegistry
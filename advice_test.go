@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "fmt"
+
+func init() {
+	ERTTest(adviceAround)
+	ERTTest(adviceBefore)
+}
+
+func adviceAround(e Env) error {
+	const fun Name = "emacs-go-test-advised-around"
+	if _, err := e.Eval(List{
+		Symbol("defun"), fun, List{Symbol("x")},
+		List{Symbol("1+"), Symbol("x")},
+	}); err != nil {
+		return err
+	}
+	a, err := e.AdviceAddAround(fun, func(e Env, orig Func, args []Value) (Value, error) {
+		v, err := orig(e, args)
+		if err != nil {
+			return Value{}, err
+		}
+		return e.Call("*", v, Int(10))
+	})
+	if err != nil {
+		return err
+	}
+	var got Int
+	if err := e.CallOut(fun, &got, Int(2)); err != nil {
+		return err
+	}
+	if got != 30 {
+		return fmt.Errorf("advised function: got %d, want 30", got)
+	}
+	if err := e.AdviceRemove(a); err != nil {
+		return err
+	}
+	if err := e.CallOut(fun, &got, Int(2)); err != nil {
+		return err
+	}
+	if got != 3 {
+		return fmt.Errorf("function after removing advice: got %d, want 3", got)
+	}
+	return nil
+}
+
+func adviceBefore(e Env) error {
+	const fun Name = "emacs-go-test-advised-before"
+	if _, err := e.Eval(List{Symbol("defun"), fun, List{Symbol("_x")}, Nil}); err != nil {
+		return err
+	}
+	var got []int64
+	a, err := e.AdviceAdd(fun, AdviceBefore, func(e Env, args []Value) (Value, error) {
+		i, err := e.Int(args[0])
+		if err != nil {
+			return Value{}, err
+		}
+		got = append(got, i)
+		return e.Nil()
+	})
+	if err != nil {
+		return err
+	}
+	defer e.AdviceRemove(a)
+	if _, err := e.Call(fun, Int(7)); err != nil {
+		return err
+	}
+	if len(got) != 1 || got[0] != 7 {
+		return fmt.Errorf("advice received %v, want [7]", got)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// Alist represents an Emacs association list.  Each element becomes a dotted
+// pair (Car . Cdr) in the order given.  Use [Cons] values with the key as Car
+// and the value as Cdr.
+type Alist []Cons
+
+// Emacs creates a new association list from the pairs in a.
+func (a Alist) Emacs(e Env) (Value, error) {
+	l := make(List, len(a))
+	for i, c := range a {
+		l[i] = c
+	}
+	return l.Emacs(e)
+}
+
+// AlistOut is an [Out] that converts an Emacs association list to the slice
+// Data, preserving the order of the pairs.  The concrete key and value types
+// are determined by the return values of the [AlistOut.New] function.
+type AlistOut struct {
+	// New must return a new key and value each time it’s called.
+	New func() (Out, Out)
+
+	// FromEmacs fills Data with the pairs from the association list.  The
+	// Car field of each element receives the key, and the Cdr field
+	// receives the value.
+	Data []Uncons
+}
+
+// FromEmacs sets a.Data to a new slice containing the key–value pairs of the
+// Emacs association list v, in order.  It returns an error if v is not a list.
+// Each element of v should be a dotted pair (key . value); for an element
+// (key value) the value is the list (value).  Like assq, FromEmacs ignores
+// elements that aren’t cons cells.  Since association lists may contain the
+// same key more than once, Data may contain duplicate keys; the first one
+// shadows the others.  FromEmacs calls a.New for each pair in v.  If
+// FromEmacs returns an error, it doesn’t modify a.Data.
+func (a *AlistOut) FromEmacs(e Env, v Value) error {
+	var r []Uncons
+	f := func(elem Value) error {
+		var cons Bool
+		if err := e.CallOut("consp", &cons, elem); err != nil {
+			return err
+		}
+		if !cons {
+			return nil
+		}
+		key, val := a.New()
+		u := Uncons{key, val}
+		if err := u.FromEmacs(e, elem); err != nil {
+			return err
+		}
+		r = append(r, u)
+		return nil
+	}
+	if err := e.Dolist(v, f); err != nil {
+		return err
+	}
+	a.Data = r
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func init() {
+	ERTTest(alist)
+}
+
+func alist(e Env) error {
+	v, err := Alist{
+		{Symbol("b"), Int(2)},
+		{Symbol("a"), Int(1)},
+		{Symbol("b"), Int(3)},
+	}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	var printed String
+	if err := e.CallOut("prin1-to-string", &printed, v); err != nil {
+		return err
+	}
+	if want := "((b . 2) (a . 1) (b . 3))"; string(printed) != want {
+		return fmt.Errorf("Alist: got %s, want %s", printed, want)
+	}
+	// Non-cons elements are ignored.
+	v, err = e.Call("append", v, List{Symbol("c"), Nil})
+	if err != nil {
+		return err
+	}
+	out := AlistOut{New: func() (Out, Out) { return new(Symbol), new(Int) }}
+	if err := out.FromEmacs(e, v); err != nil {
+		return err
+	}
+	var got []string
+	for _, p := range out.Data {
+		got = append(got, fmt.Sprintf("%s=%d", *p.Car.(*Symbol), *p.Cdr.(*Int)))
+	}
+	if want := []string{"b=2", "a=1", "b=3"}; !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("AlistOut: got %q, want %q", got, want)
+	}
+	return nil
+}
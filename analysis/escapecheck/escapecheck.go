@@ -0,0 +1,238 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package escapecheck contains an analyzer that reports [emacs] environments
+// and values that escape the function call in which they’re valid.  Env and
+// Value values are only valid while the exported function or module
+// initializer that received them runs, and only on the Emacs thread.  The
+// analyzer reports Env and Value values that are stored in struct fields or
+// global variables, used by go statements, or sent on channels.
+//
+// The analyzer only looks at the types of fields, variables, and
+// expressions, so it doesn’t know whether a value is still live when it’s
+// used.  It also doesn’t detect functions that start goroutines themselves,
+// such as errgroup.Group.Go.  It doesn’t report anything in the [emacs]
+// package itself, and doesn’t look into types from other packages that
+// contain Env or Value fields, such as emacs.Process.
+//
+// [emacs]: https://pkg.go.dev/github.com/phst/emacs
+package escapecheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports Env and Value values that escape their function call.
+var Analyzer = &analysis.Analyzer{
+	Name:     "emacsescape",
+	Doc:      "check that Emacs environments and values don’t escape the function call in which they’re valid",
+	URL:      "https://pkg.go.dev/github.com/phst/emacs/analysis/escapecheck",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+const emacsPath = "github.com/phst/emacs"
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if pass.Pkg.Path() == emacsPath {
+		return nil, nil
+	}
+	c := checker{pass: pass}
+	for _, imp := range pass.Pkg.Imports() {
+		if imp.Path() == emacsPath {
+			c.env = lookupType(imp, "Env")
+			c.value = lookupType(imp, "Value")
+		}
+	}
+	if c.env == nil && c.value == nil {
+		return nil, nil
+	}
+	for _, f := range pass.Files {
+		for _, d := range f.Decls {
+			if d, ok := d.(*ast.GenDecl); ok && d.Tok == token.VAR {
+				c.checkGlobals(d)
+			}
+		}
+	}
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{(*ast.StructType)(nil), (*ast.GoStmt)(nil), (*ast.SendStmt)(nil)}
+	insp.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.StructType:
+			c.checkFields(n)
+		case *ast.GoStmt:
+			c.checkGo(n)
+		case *ast.SendStmt:
+			c.checkSend(n)
+		}
+	})
+	return nil, nil
+}
+
+func lookupType(pkg *types.Package, name string) types.Type {
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	return obj.Type()
+}
+
+type checker struct {
+	pass       *analysis.Pass
+	env, value types.Type
+}
+
+// checkGlobals reports package-level variables whose type contains Env or
+// Value.
+func (c checker) checkGlobals(d *ast.GenDecl) {
+	for _, s := range d.Specs {
+		for _, id := range s.(*ast.ValueSpec).Names {
+			v, ok := c.pass.TypesInfo.Defs[id].(*types.Var)
+			if !ok {
+				continue
+			}
+			if name := c.contains(v.Type(), false); name != "" {
+				c.pass.Reportf(id.Pos(), "global variable %s stores emacs.%s, which is only valid during a call from Emacs", id.Name, name)
+			}
+		}
+	}
+}
+
+// checkFields reports struct fields whose type contains Env or Value.
+// Fields of named types declared in the current package aren’t reported
+// because the fields of these types are reported themselves.
+func (c checker) checkFields(s *ast.StructType) {
+	for _, f := range s.Fields.List {
+		name := c.contains(c.pass.TypesInfo.TypeOf(f.Type), false)
+		if name == "" {
+			continue
+		}
+		if len(f.Names) == 0 {
+			c.pass.Reportf(f.Pos(), "embedded field stores emacs.%s, which is only valid during a call from Emacs", name)
+		}
+		for _, id := range f.Names {
+			c.pass.Reportf(id.Pos(), "field %s stores emacs.%s, which is only valid during a call from Emacs", id.Name, name)
+		}
+	}
+}
+
+// checkGo reports go statements that pass Env or Value values to the new
+// goroutine, either as arguments, receivers, or captured variables.
+func (c checker) checkGo(g *ast.GoStmt) {
+	call := g.Call
+	for _, arg := range call.Args {
+		if name := c.contains(c.pass.TypesInfo.TypeOf(arg), true); name != "" {
+			c.pass.Reportf(arg.Pos(), "go statement passes emacs.%s to another goroutine", name)
+		}
+	}
+	switch fun := astutil.Unparen(call.Fun).(type) {
+	case *ast.SelectorExpr:
+		sel, ok := c.pass.TypesInfo.Selections[fun]
+		if !ok || sel.Kind() != types.MethodVal {
+			return
+		}
+		if name := c.contains(sel.Recv(), true); name != "" {
+			c.pass.Reportf(fun.X.Pos(), "go statement passes emacs.%s to another goroutine", name)
+		}
+	case *ast.FuncLit:
+		c.checkCaptures(fun)
+	}
+}
+
+// checkCaptures reports local variables of type Env or Value that the
+// function literal lit captures.  Each variable is reported only once.
+func (c checker) checkCaptures(lit *ast.FuncLit) {
+	seen := make(map[*types.Var]bool)
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		v, ok := c.pass.TypesInfo.Uses[id].(*types.Var)
+		if !ok || v.IsField() || seen[v] {
+			return true
+		}
+		if v.Parent() == v.Pkg().Scope() || (v.Pos() >= lit.Pos() && v.Pos() < lit.End()) {
+			// Global variables are reported elsewhere, and
+			// variables declared within the literal don’t escape.
+			return true
+		}
+		if name := c.contains(v.Type(), true); name != "" {
+			seen[v] = true
+			c.pass.Reportf(id.Pos(), "goroutine captures %s, which stores emacs.%s", id.Name, name)
+		}
+		return true
+	})
+}
+
+// checkSend reports channel sends of Env or Value values.
+func (c checker) checkSend(s *ast.SendStmt) {
+	if name := c.contains(c.pass.TypesInfo.TypeOf(s.Value), true); name != "" {
+		c.pass.Reportf(s.Value.Pos(), "channel send passes emacs.%s to another goroutine", name)
+	}
+}
+
+// contains returns "Env" or "Value" if values of type t contain an Env or
+// Value, and the empty string otherwise.  If deep is true, contains also
+// looks into named struct types declared in the current package.
+func (c checker) contains(t types.Type, deep bool) string {
+	return c.containsVisiting(t, deep, make(map[types.Type]bool))
+}
+
+func (c checker) containsVisiting(t types.Type, deep bool, visiting map[types.Type]bool) string {
+	if t == nil {
+		return ""
+	}
+	switch {
+	case c.env != nil && types.Identical(t, c.env):
+		return "Env"
+	case c.value != nil && types.Identical(t, c.value):
+		return "Value"
+	}
+	switch u := t.(type) {
+	case *types.Pointer:
+		return c.containsVisiting(u.Elem(), deep, visiting)
+	case *types.Slice:
+		return c.containsVisiting(u.Elem(), deep, visiting)
+	case *types.Array:
+		return c.containsVisiting(u.Elem(), deep, visiting)
+	case *types.Chan:
+		return c.containsVisiting(u.Elem(), deep, visiting)
+	case *types.Map:
+		if name := c.containsVisiting(u.Key(), deep, visiting); name != "" {
+			return name
+		}
+		return c.containsVisiting(u.Elem(), deep, visiting)
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if name := c.containsVisiting(u.Field(i).Type(), deep, visiting); name != "" {
+				return name
+			}
+		}
+	case *types.Named:
+		if !deep || u.Obj().Pkg() != c.pass.Pkg || visiting[u] {
+			return ""
+		}
+		visiting[u] = true
+		return c.containsVisiting(u.Underlying(), deep, visiting)
+	}
+	return ""
+}
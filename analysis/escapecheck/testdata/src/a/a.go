@@ -0,0 +1,64 @@
+package a
+
+import (
+	"github.com/phst/emacs"
+)
+
+var globalEnv emacs.Env // want `global variable globalEnv stores emacs.Env`
+
+var globalValues []emacs.Value // want `global variable globalValues stores emacs.Value`
+
+var globalRef emacs.GlobalRef
+
+var globalProcess emacs.Process
+
+type state struct {
+	env    emacs.Env              // want `field env stores emacs.Env`
+	values map[string]emacs.Value // want `field values stores emacs.Value`
+	ref    emacs.GlobalRef
+	name   string
+}
+
+type embedded struct {
+	emacs.Value // want `embedded field stores emacs.Value`
+}
+
+type wrapper struct {
+	s *state
+}
+
+var globalWrapper wrapper
+
+func ok(e emacs.Env, v emacs.Value) error {
+	local := struct{ n int }{1}
+	done := make(chan string)
+	go func() {
+		var inner emacs.Value
+		_ = inner
+		done <- "done"
+	}()
+	go helper(local.n)
+	<-done
+	_, err := e.Call("ignore", v)
+	return err
+}
+
+func helper(int) {}
+
+func use(emacs.Env, emacs.Value) {}
+
+func bad(e emacs.Env, v emacs.Value, w *wrapper) {
+	go func() {
+		use(e, v) // want `goroutine captures e, which stores emacs.Env` `goroutine captures v, which stores emacs.Value`
+		use(e, v)
+	}()
+	go use(e, v)            // want `go statement passes emacs.Env to another goroutine` `go statement passes emacs.Value to another goroutine`
+	go e.Call("ignore")     // want `go statement passes emacs.Env to another goroutine`
+	go func() { _ = w.s }() // want `goroutine captures w, which stores emacs.Env`
+	ch := make(chan emacs.Value, 1)
+	ch <- v // want `channel send passes emacs.Value to another goroutine`
+	select {
+	case ch <- v: // want `channel send passes emacs.Value to another goroutine`
+	default:
+	}
+}
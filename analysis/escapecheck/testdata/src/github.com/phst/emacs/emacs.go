@@ -0,0 +1,14 @@
+// Package emacs is a stub of the real emacs package for testing the analyzer.
+package emacs
+
+type Env struct{ p uintptr }
+
+type Value struct{ r uintptr }
+
+type Process struct{ v Value }
+
+func (e Env) Call(name string, args ...Value) (Value, error) { return Value{}, nil }
+
+func (e Env) Go(v Value, p interface{}) error { return nil }
+
+type GlobalRef struct{ v Value }
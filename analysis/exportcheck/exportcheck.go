@@ -0,0 +1,608 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exportcheck contains an analyzer that checks calls to the
+// registration functions of the [emacs] package at build time.  It reports
+// argument and result types that the autoconversion machinery can’t convert,
+// as well as invalid names and usage strings.  Without this analyzer, these
+// mistakes only show up as panics when Emacs loads the module.
+//
+// The analyzer knows about the conversions described in the package
+// documentation of the [emacs] package.  It can’t know about conversions
+// that depend on dynamic types, so it might report false positives for
+// types that are only convertible at runtime.
+//
+// The analyzer also knows about conversions registered using
+// RegisterInFunc and RegisterOutFunc in the analyzed package or its
+// dependencies, as long as the type argument is statically known: an
+// expression using reflect.TypeOf with a non-interface argument,
+// reflect.TypeFor, reflect.PointerTo, or the Elem method, or a variable
+// initialized with such an expression.  Other registrations aren’t modeled,
+// and the analyzer reports the types they make convertible.  In that case,
+// register the conversion in a way the analyzer understands, or don’t run
+// the analyzer on the affected package.
+//
+// [emacs]: https://pkg.go.dev/github.com/phst/emacs
+package exportcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer checks calls to Export, Import, ERTTest, Var, and similar
+// functions of the emacs package.
+var Analyzer = &analysis.Analyzer{
+	Name:      "emacsexport",
+	Doc:       "check that functions exported to or imported from Emacs have convertible types",
+	URL:       "https://pkg.go.dev/github.com/phst/emacs/analysis/exportcheck",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(convertersFact)},
+}
+
+const emacsPath = "github.com/phst/emacs"
+
+// Kinds of calls that we check.
+type callKind int
+
+const (
+	exportCall callKind = iota // function with autoconversion
+	importCall                 // Import
+	ertCall                    // ERTTest
+	varCall                    // Var
+)
+
+// Functions and methods we check, keyed by name.  Methods are keyed by
+// receiver type name and method name, separated by a dot.
+var checkedFuncs = map[string]callKind{
+	"Export":          exportCall,
+	"AutoFunc":        exportCall,
+	"AutoLambda":      exportCall,
+	"Env.Export":      exportCall,
+	"Env.Lambda":      exportCall,
+	"Import":          importCall,
+	"ERTTest":         ertCall,
+	"Env.ERTTest":     ertCall,
+	"Var":             varCall,
+	"Env.Var":         varCall,
+	"DefineError":     varCall,
+	"Env.DefineError": varCall,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	conv := findConverters(pass, insp)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, key := calledFunc(pass.TypesInfo, call)
+		if fn == nil {
+			return
+		}
+		kind, ok := checkedFuncs[key]
+		if !ok {
+			return
+		}
+		c := checker{pass, newModel(fn.Pkg(), conv)}
+		if c.model == nil {
+			return
+		}
+		c.check(call, key, kind)
+	})
+	return nil, nil
+}
+
+// calledFunc returns the emacs function or method called by call, together
+// with its key in checkedFuncs.
+func calledFunc(info *types.Info, call *ast.CallExpr) (*types.Func, string) {
+	var id *ast.Ident
+	switch f := astutil.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = f
+	case *ast.SelectorExpr:
+		id = f.Sel
+	default:
+		return nil, ""
+	}
+	fn, ok := info.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != emacsPath {
+		return nil, ""
+	}
+	sig := fn.Type().(*types.Signature)
+	if recv := sig.Recv(); recv != nil {
+		named, ok := recv.Type().(*types.Named)
+		if !ok {
+			return nil, ""
+		}
+		return fn, named.Obj().Name() + "." + fn.Name()
+	}
+	return fn, fn.Name()
+}
+
+type checker struct {
+	pass  *analysis.Pass
+	model *model
+}
+
+func (c checker) check(call *ast.CallExpr, name string, kind callKind) {
+	if len(call.Args) == 0 {
+		return
+	}
+	switch kind {
+	case exportCall:
+		t := c.pass.TypesInfo.TypeOf(call.Args[0]).Underlying()
+		if types.IsInterface(t) {
+			// Only known at runtime.
+			return
+		}
+		sig, ok := t.(*types.Signature)
+		if !ok {
+			c.pass.Reportf(call.Args[0].Pos(), "argument to %s is not a function", name)
+			return
+		}
+		arity := c.checkExport(call.Args[0], sig)
+		c.checkOptions(call.Args[1:], arity)
+	case importCall:
+		c.checkName(call.Args[0])
+		if len(call.Args) > 1 {
+			c.checkImport(call.Args[1])
+		}
+	case ertCall:
+		c.checkOptions(call.Args[1:], 0)
+	case varCall:
+		c.checkName(call.Args[0])
+	}
+}
+
+// checkExport checks a function passed to Export or a similar function.  It
+// returns the number of parameters that aren’t of type Env or context.Context.
+func (c checker) checkExport(expr ast.Expr, sig *types.Signature) int {
+	params := sig.Params()
+	offset := 0
+	if params.Len() > 0 && c.model.isEnv(params.At(0).Type()) {
+		offset = 1
+	}
+	if params.Len() > offset && isContext(params.At(offset).Type()) {
+		offset++
+	}
+	for i := offset; i < params.Len(); i++ {
+		t := params.At(i).Type()
+		if sig.Variadic() && i == params.Len()-1 {
+			t = t.(*types.Slice).Elem()
+		}
+		if err := c.model.out(types.NewPointer(t)); err != nil {
+			c.pass.Reportf(expr.Pos(), "can’t convert argument %d of type %s from Emacs: %s", i, t, err)
+		}
+	}
+	results := sig.Results()
+	switch results.Len() {
+	case 0:
+	case 1:
+		if t := results.At(0).Type(); !isError(t) {
+			c.checkResult(expr, t)
+		}
+	case 2:
+		if !isError(results.At(1).Type()) {
+			c.pass.Reportf(expr.Pos(), "second result must be error, but is %s", results.At(1).Type())
+		}
+		c.checkResult(expr, results.At(0).Type())
+	default:
+		c.pass.Reportf(expr.Pos(), "function exported to Emacs has too many results")
+	}
+	return params.Len() - offset
+}
+
+func (c checker) checkResult(expr ast.Expr, t types.Type) {
+	if err := c.model.in(t); err != nil {
+		c.pass.Reportf(expr.Pos(), "can’t convert result of type %s to Emacs: %s", t, err)
+	}
+}
+
+// checkImport checks the function pointer passed to Import.
+func (c checker) checkImport(expr ast.Expr) {
+	t := c.pass.TypesInfo.TypeOf(expr).Underlying()
+	if types.IsInterface(t) {
+		// Only known at runtime.
+		return
+	}
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		c.pass.Reportf(expr.Pos(), "second argument to Import must be a pointer to a function variable")
+		return
+	}
+	sig, ok := ptr.Elem().Underlying().(*types.Signature)
+	if !ok {
+		c.pass.Reportf(expr.Pos(), "second argument to Import must be a pointer to a function variable")
+		return
+	}
+	params := sig.Params()
+	if params.Len() == 0 || !c.model.isEnv(params.At(0).Type()) {
+		c.pass.Reportf(expr.Pos(), "imported function must accept an Env as first argument")
+		return
+	}
+	for i := 1; i < params.Len(); i++ {
+		t := params.At(i).Type()
+		if sig.Variadic() && i == params.Len()-1 {
+			t = t.(*types.Slice).Elem()
+		}
+		if err := c.model.in(t); err != nil {
+			c.pass.Reportf(expr.Pos(), "can’t convert argument %d of type %s to Emacs: %s", i, t, err)
+		}
+	}
+	results := sig.Results()
+	if n := results.Len(); n == 0 || n > 2 || !isError(results.At(n-1).Type()) {
+		c.pass.Reportf(expr.Pos(), "imported function must return either an error or a value and an error")
+		return
+	}
+	if results.Len() == 2 {
+		t := results.At(0).Type()
+		if err := c.model.out(types.NewPointer(t)); err != nil {
+			c.pass.Reportf(expr.Pos(), "can’t convert result of type %s from Emacs: %s", t, err)
+		}
+	}
+}
+
+// checkOptions checks the Name and Usage options in opts.  numArgs is the
+// number of non-Env parameters of the function, or 0 for ERT tests.
+func (c checker) checkOptions(opts []ast.Expr, numArgs int) {
+	anonymous := false
+	var name ast.Expr
+	for _, o := range opts {
+		t := c.pass.TypesInfo.TypeOf(o)
+		switch {
+		case c.model.isNamed(t, "Anonymous"):
+			anonymous = true
+		case c.model.isNamed(t, "Name"):
+			name = o
+			c.checkName(o)
+		case c.model.isNamed(t, "Usage"):
+			s, ok := c.constString(o)
+			if !ok {
+				continue
+			}
+			if strings.ContainsRune(s, '\n') {
+				c.pass.Reportf(o.Pos(), "usage string must not contain newlines")
+				continue
+			}
+			if n := countUsageArgs(s); n != numArgs {
+				c.pass.Reportf(o.Pos(), "usage %q names %d arguments, but function accepts %d", s, n, numArgs)
+			}
+		}
+	}
+	if anonymous && name != nil {
+		c.pass.Reportf(name.Pos(), "function declared as anonymous, but has a name")
+	}
+}
+
+// checkName checks that expr, if constant, is a valid Emacs name.
+func (c checker) checkName(expr ast.Expr) {
+	s, ok := c.constString(expr)
+	if !ok {
+		return
+	}
+	switch {
+	case s == "":
+		c.pass.Reportf(expr.Pos(), "empty name")
+	case !utf8.ValidString(s):
+		c.pass.Reportf(expr.Pos(), "name %q is not valid UTF-8", s)
+	}
+}
+
+func (c checker) constString(expr ast.Expr) (string, bool) {
+	tv, ok := c.pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// countUsageArgs returns the number of argument names in the usage string s,
+// excluding lambda list keywords such as &optional.
+func countUsageArgs(s string) int {
+	n := 0
+	for _, f := range strings.Fields(s) {
+		if !strings.HasPrefix(f, "&") {
+			n++
+		}
+	}
+	return n
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// model describes the types that the emacs package can convert.  It mirrors
+// InFuncFor and OutFuncFor.
+type model struct {
+	pkg               *types.Package
+	inIface, outIface *types.Interface
+	env               types.Type
+	callback          types.Type // *Callback, or nil if the package has no Callback type
+	visiting          map[types.Type]bool
+	registered        converters // conversions registered using RegisterInFunc and RegisterOutFunc
+
+	// encoding.TextMarshaler and encoding.TextUnmarshaler
+	textMarshaler, textUnmarshaler *types.Interface
+}
+
+func newModel(pkg *types.Package, registered converters) *model {
+	iface := func(name string) *types.Interface {
+		obj := pkg.Scope().Lookup(name)
+		if obj == nil {
+			return nil
+		}
+		i, _ := obj.Type().Underlying().(*types.Interface)
+		return i
+	}
+	in, out := iface("In"), iface("Out")
+	env := pkg.Scope().Lookup("Env")
+	if in == nil || out == nil || env == nil {
+		return nil
+	}
+	var callback types.Type
+	if obj := pkg.Scope().Lookup("Callback"); obj != nil {
+		callback = types.NewPointer(obj.Type())
+	}
+	return &model{pkg, in, out, env.Type(), callback, make(map[types.Type]bool), registered, textInterface("MarshalText", false), textInterface("UnmarshalText", true)}
+}
+
+// textInterface returns an interface type with a single method of the given
+// name.  If unmarshal is true, the method has the signature
+// func([]byte) error; otherwise, it has the signature func() ([]byte, error).
+// We construct the interfaces instead of looking them up because the
+// analyzed package doesn’t necessarily import the encoding package.
+func textInterface(name string, unmarshal bool) *types.Interface {
+	bytes := types.NewVar(token.NoPos, nil, "", types.NewSlice(types.Typ[types.Byte]))
+	err := types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type())
+	var sig *types.Signature
+	if unmarshal {
+		sig = types.NewSignatureType(nil, nil, nil, types.NewTuple(bytes), types.NewTuple(err), false)
+	} else {
+		sig = types.NewSignatureType(nil, nil, nil, nil, types.NewTuple(bytes, err), false)
+	}
+	return types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, name, sig)}, nil).Complete()
+}
+
+// isCallbackInterface returns whether t is an interface type with a Call
+// method that *Callback implements, like isCallbackInterface in callback.go.
+func (m *model) isCallbackInterface(t types.Type) bool {
+	i, ok := t.Underlying().(*types.Interface)
+	if !ok || m.callback == nil || types.Identical(i, m.inIface) {
+		return false
+	}
+	for j := 0; j < i.NumMethods(); j++ {
+		if i.Method(j).Name() == "Call" {
+			return types.Implements(m.callback, i)
+		}
+	}
+	return false
+}
+
+func (m *model) isEnv(t types.Type) bool {
+	return types.Identical(t, m.env)
+}
+
+func isContext(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "context" && n.Obj().Name() == "Context"
+}
+
+func (m *model) isNamed(t types.Type, name string) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() == m.pkg && n.Obj().Name() == name
+}
+
+// in returns an error if InFuncFor would fail for t.
+func (m *model) in(t types.Type) error {
+	if types.Implements(t, m.inIface) || m.registered.hasIn(t) || isValueType(t) || isBigPtr(t) || m.isCallbackInterface(t) {
+		return nil
+	}
+	// Mirrors the numericKind exclusion in text.go.
+	if types.Implements(t, m.textMarshaler) && !isNumeric(t) {
+		return nil
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Array:
+		return m.inElem(u.Elem())
+	case *types.Slice:
+		return m.inElem(u.Elem())
+	case *types.Map:
+		if err := m.in(u.Key()); err != nil {
+			return err
+		}
+		return m.in(u.Elem())
+	case *types.Struct:
+		return m.structFields(t, u, m.in)
+	case *types.Basic:
+		if isNumber(u) {
+			return nil
+		}
+	}
+	return unknownType(t)
+}
+
+func (m *model) inElem(t types.Type) error {
+	if isByte(t) {
+		return nil
+	}
+	return m.in(t)
+}
+
+// out returns an error if OutFuncFor would fail for the pointer type t.
+func (m *model) out(t types.Type) error {
+	if types.Implements(t, m.outIface) || isBigPtr(t) {
+		return nil
+	}
+	p, ok := t.Underlying().(*types.Pointer)
+	if !ok {
+		return fmt.Errorf("%s is not a pointer type", t)
+	}
+	t = p.Elem()
+	if m.registered.hasOut(t) || isValueType(t) || m.isCallbackInterface(t) {
+		return nil
+	}
+	if types.Implements(types.NewPointer(t), m.textUnmarshaler) && !isNumeric(t) {
+		return nil
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Array:
+		return m.outElem(u.Elem())
+	case *types.Slice:
+		return m.outElem(u.Elem())
+	case *types.Map:
+		if err := m.out(types.NewPointer(u.Key())); err != nil {
+			return err
+		}
+		return m.out(types.NewPointer(u.Elem()))
+	case *types.Struct:
+		return m.structFields(t, u, func(t types.Type) error { return m.out(types.NewPointer(t)) })
+	case *types.Basic:
+		if isNumber(u) {
+			return nil
+		}
+	}
+	return unknownType(t)
+}
+
+func (m *model) outElem(t types.Type) error {
+	if isByte(t) {
+		return nil
+	}
+	return m.out(types.NewPointer(t))
+}
+
+// structFields calls check for the types of the fields of the struct type t
+// that structFields in eieio.go would convert, i.e. the exported fields
+// including promoted ones, excluding fields tagged with emacs:"-".  Like the
+// runtime conversion, it rejects structs without such fields and treats
+// recursive references to t as convertible.
+func (m *model) structFields(t types.Type, s *types.Struct, check func(types.Type) error) error {
+	if m.visiting[t] {
+		return nil
+	}
+	m.visiting[t] = true
+	defer delete(m.visiting, t)
+	n, err := m.checkFields(s, check)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return unknownType(t)
+	}
+	return nil
+}
+
+func (m *model) checkFields(s *types.Struct, check func(types.Type) error) (int, error) {
+	n := 0
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if f.Embedded() {
+			t := f.Type()
+			if p, ok := t.Underlying().(*types.Pointer); ok {
+				t = p.Elem()
+			}
+			if u, ok := t.Underlying().(*types.Struct); ok {
+				k, err := m.checkFields(u, check)
+				if err != nil {
+					return 0, err
+				}
+				n += k
+			}
+			continue
+		}
+		if !f.Exported() {
+			continue
+		}
+		name, _, _ := strings.Cut(reflect.StructTag(s.Tag(i)).Get("emacs"), ",")
+		if name == "-" {
+			continue
+		}
+		if err := check(f.Type()); err != nil {
+			return 0, fmt.Errorf("field %s: %w", f.Name(), err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func unknownType(t types.Type) error {
+	return fmt.Errorf("no known conversion for type %s", t)
+}
+
+// isValueType returns whether t is a key of valueTypes in reflect.go.
+func isValueType(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Basic:
+		return t.Kind() == types.Bool || t.Kind() == types.String
+	case *types.Slice:
+		b, ok := t.Elem().(*types.Basic)
+		return ok && b.Kind() == types.Uint8
+	case *types.Named:
+		obj := t.Obj()
+		return obj.Pkg() != nil && obj.Pkg().Path() == "time" && (obj.Name() == "Time" || obj.Name() == "Duration")
+	}
+	return false
+}
+
+// isBigPtr returns whether t is *big.Int, *big.Float, or *big.Rat, the keys
+// of pointerTypes in reflect.go.
+func isBigPtr(t types.Type) bool {
+	p, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	n, ok := p.Elem().(*types.Named)
+	if !ok || n.Obj().Pkg() == nil || n.Obj().Pkg().Path() != "math/big" {
+		return false
+	}
+	switch n.Obj().Name() {
+	case "Int", "Float", "Rat":
+		return true
+	}
+	return false
+}
+
+func isNumber(b *types.Basic) bool {
+	switch b.Kind() {
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64,
+		types.Float32, types.Float64:
+		return true
+	}
+	return false
+}
+
+// isNumeric returns whether the underlying type of t is an integral or
+// floating-point type, like numericKind in text.go.
+func isNumeric(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && (isNumber(b) || b.Kind() == types.Uintptr)
+}
+
+func isByte(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Uint8
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exportcheck
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// convertersFact records the types for which a package registers
+// conversions using RegisterInFunc and RegisterOutFunc.  Types are
+// identified by their fully qualified type strings.
+type convertersFact struct {
+	In, Out []string
+}
+
+func (*convertersFact) AFact() {}
+
+func (f *convertersFact) String() string {
+	return "converters(in: " + strings.Join(f.In, ", ") + "; out: " + strings.Join(f.Out, ", ") + ")"
+}
+
+// converters is the set of types with registered conversions, keyed by
+// their fully qualified type strings.
+type converters struct {
+	in, out map[string]bool
+}
+
+// hasIn returns whether there’s a registered conversion of t to Emacs.
+func (c converters) hasIn(t types.Type) bool { return c.in[types.TypeString(t, nil)] }
+
+// hasOut returns whether there’s a registered conversion of Emacs values to
+// t.
+func (c converters) hasOut(t types.Type) bool { return c.out[types.TypeString(t, nil)] }
+
+// findConverters returns the conversions registered in the current package
+// and its dependencies, and exports a fact for the conversions registered in
+// the current package.
+func findConverters(pass *analysis.Pass, insp *inspector.Inspector) converters {
+	c := converters{make(map[string]bool), make(map[string]bool)}
+	for _, f := range pass.AllPackageFacts() {
+		if fact, ok := f.Fact.(*convertersFact); ok {
+			for _, s := range fact.In {
+				c.in[s] = true
+			}
+			for _, s := range fact.Out {
+				c.out[s] = true
+			}
+		}
+	}
+	inits := make(map[*types.Var]ast.Expr)
+	for _, i := range pass.TypesInfo.InitOrder {
+		if len(i.Lhs) == 1 {
+			inits[i.Lhs[0]] = i.Rhs
+		}
+	}
+	var fact convertersFact
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, key := calledFunc(pass.TypesInfo, call)
+		if fn == nil || len(call.Args) == 0 || (key != "RegisterInFunc" && key != "RegisterOutFunc") {
+			return
+		}
+		t := reflectType(pass.TypesInfo, inits, call.Args[0])
+		if t == nil {
+			return
+		}
+		s := types.TypeString(t, nil)
+		if key == "RegisterInFunc" {
+			fact.In = append(fact.In, s)
+			c.in[s] = true
+		} else {
+			fact.Out = append(fact.Out, s)
+			c.out[s] = true
+		}
+	})
+	if len(fact.In) > 0 || len(fact.Out) > 0 {
+		sort.Strings(fact.In)
+		sort.Strings(fact.Out)
+		pass.ExportPackageFact(&fact)
+	}
+	return c
+}
+
+// reflectType returns the type that the reflect.Type expression expr
+// denotes, or nil if that isn’t known statically.  It understands
+// reflect.TypeOf with an argument of non-interface type, reflect.TypeFor,
+// reflect.PointerTo, the Elem method, and variables initialized with such
+// expressions.
+func reflectType(info *types.Info, inits map[*types.Var]ast.Expr, expr ast.Expr) types.Type {
+	expr = astutil.Unparen(expr)
+	if id, ok := expr.(*ast.Ident); ok {
+		v, ok := info.Uses[id].(*types.Var)
+		if !ok || inits[v] == nil {
+			return nil
+		}
+		// Only follow one level of indirection to avoid cycles.
+		expr = astutil.Unparen(inits[v])
+		if _, ok := expr.(*ast.Ident); ok {
+			return nil
+		}
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	fun := astutil.Unparen(call.Fun)
+	if sel, ok := fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Elem" && len(call.Args) == 0 {
+		if _, ok := info.Selections[sel]; ok {
+			p, ok := reflectType(info, inits, sel.X).(interface{ Elem() types.Type })
+			if !ok {
+				return nil
+			}
+			return p.Elem()
+		}
+	}
+	var id *ast.Ident
+	switch f := fun.(type) {
+	case *ast.SelectorExpr:
+		id = f.Sel
+	case *ast.IndexExpr:
+		// reflect.TypeFor[T]
+		if sel, ok := astutil.Unparen(f.X).(*ast.SelectorExpr); ok {
+			id = sel.Sel
+		}
+	}
+	if id == nil {
+		return nil
+	}
+	fn, ok := info.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "reflect" {
+		return nil
+	}
+	switch fn.Name() {
+	case "TypeOf":
+		if len(call.Args) != 1 {
+			return nil
+		}
+		t := info.TypeOf(call.Args[0])
+		if t == nil || types.IsInterface(t) {
+			return nil
+		}
+		return t
+	case "TypeFor":
+		if inst, ok := info.Instances[id]; ok && inst.TypeArgs.Len() == 1 {
+			return inst.TypeArgs.At(0)
+		}
+	case "PointerTo", "PtrTo":
+		if len(call.Args) != 1 {
+			return nil
+		}
+		if t := reflectType(info, inits, call.Args[0]); t != nil {
+			return types.NewPointer(t)
+		}
+	}
+	return nil
+}
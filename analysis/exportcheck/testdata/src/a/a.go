@@ -0,0 +1,166 @@
+package a // want package:"converters\\(in: a.celsius, a.kelvin; out: a.celsius\\)"
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"reflect"
+	"time"
+
+	"b"
+	"github.com/phst/emacs"
+)
+
+type myInt int
+
+type myString string
+
+func ok1(e emacs.Env, a int, b string, c []byte, d map[string][]float64, t time.Time, v emacs.Value, i big.Int) (myInt, error) {
+	return 0, nil
+}
+
+func okBig(i big.Int, f big.Float, r big.Rat) (*big.Rat, error) { return &r, nil }
+
+func okBigFloat() *big.Float { return nil }
+
+func ok2(s ...string) []string { return s }
+
+func ok3(e emacs.Env, ctx context.Context, a int) error { return nil }
+
+type predicate interface {
+	Call(emacs.Env, ...emacs.In) (emacs.Value, error)
+}
+
+func ok4(p predicate) predicate { return p }
+
+type notCallback interface{ Frobnicate() }
+
+func badCallback(c notCallback) {}
+
+// emacser has the same method set as emacs.In, which *emacs.Callback
+// implements, but it isn’t a callback interface because it lacks a Call
+// method.
+type emacser interface {
+	Emacs(emacs.Env) (emacs.Value, error)
+}
+
+func badEmacser(e emacser) {}
+
+func badIn(i emacs.In) {}
+
+type options struct {
+	Name     string
+	Children []options
+	Internal chan int `emacs:"-"`
+	inner
+}
+
+type inner struct{ Depth int }
+
+func ok5(o options) options { return o }
+
+type badOptions struct{ C chan int }
+
+func badStruct(o badOptions) {}
+
+// text implements encoding.TextMarshaler and encoding.TextUnmarshaler, so
+// its unsupported field doesn’t matter.
+type text struct{ C chan int }
+
+func (text) MarshalText() ([]byte, error) { return nil, nil }
+
+func (*text) UnmarshalText([]byte) error { return nil }
+
+func ok6(t text, ip net.IP) net.IP { return ip }
+
+func ok7(t text) text { return t }
+
+// marshalOnly only implements encoding.TextMarshaler, so it can only be
+// converted to Emacs.
+type marshalOnly struct{ C chan int }
+
+func (marshalOnly) MarshalText() ([]byte, error) { return nil, nil }
+
+func okMarshalOnly() marshalOnly { return marshalOnly{} }
+
+func badMarshalOnly(m marshalOnly) {}
+
+// celsius and kelvin are only convertible because of the registered
+// conversions below.
+type celsius struct{ c chan int }
+
+type kelvin struct{ c chan int }
+
+var celsiusType = reflect.TypeOf(celsius{})
+
+func init() {
+	emacs.RegisterInFunc(celsiusType, nil)
+	emacs.RegisterOutFunc(celsiusType, nil)
+	emacs.RegisterInFunc(reflect.TypeOf((*kelvin)(nil)).Elem(), nil)
+}
+
+func okRegistered(c celsius, f b.Fahrenheit) (celsius, error) { return c, nil }
+
+func okRegisteredIn() kelvin { return kelvin{} }
+
+func okRegisteredDep() b.Fahrenheit { return b.Fahrenheit{} }
+
+func badRegisteredOut(k kelvin) {}
+
+func badArg(c chan int) {}
+
+func badResult() myString { return "" }
+
+func badErr() (int, int) { return 0, 0 }
+
+func init() {
+	emacs.Export(ok1, emacs.Usage("A B C D T V I"))
+	emacs.Export(okBig, emacs.Usage("I F R"))
+	emacs.Export(okBigFloat)
+	emacs.Export(ok2, emacs.Name("ok-2"), emacs.Usage("STRINGS"))
+	emacs.Export(ok3, emacs.Usage("A"))
+	emacs.Export(ok4, emacs.Usage("P"))
+	emacs.Export(ok5, emacs.Usage("OPTIONS"))
+	emacs.Export(ok6, emacs.Usage("T IP"))
+	emacs.Export(ok7, emacs.Usage("T"))
+	emacs.Export(okMarshalOnly)
+	emacs.Export(okRegistered, emacs.Usage("C F"))
+	emacs.Export(okRegisteredIn)
+	emacs.Export(okRegisteredDep)
+	emacs.Export(badRegisteredOut)                        // want `can’t convert argument 0 of type a.kelvin from Emacs`
+	emacs.Export(badMarshalOnly)                          // want `can’t convert argument 0 of type a.marshalOnly from Emacs`
+	emacs.Export(badStruct)                               // want `can’t convert argument 0 of type a.badOptions from Emacs`
+	emacs.Export(badEmacser)                              // want `can’t convert argument 0 of type a.emacser from Emacs`
+	emacs.Export(badIn)                                   // want `can’t convert argument 0 of type github.com/phst/emacs.In from Emacs`
+	emacs.Export(badCallback)                             // want `can’t convert argument 0 of type a.notCallback from Emacs`
+	emacs.Export(badArg)                                  // want `can’t convert argument 0 of type chan int from Emacs`
+	emacs.Export(badResult)                               // want `can’t convert result of type a.myString to Emacs`
+	emacs.Export(badErr)                                  // want `second result must be error`
+	emacs.Export(ok2, emacs.Usage("A B"))                 // want `usage "A B" names 2 arguments, but function accepts 1`
+	emacs.Export(ok2, emacs.Name(""))                     // want `empty name`
+	emacs.Export(ok2, emacs.Anonymous{}, emacs.Name("x")) // want `function declared as anonymous, but has a name`
+	emacs.Var("", nil, "")                                // want `empty name`
+	emacs.ERTTest(func(emacs.Env) error { return nil }, emacs.Name("test"))
+}
+
+var (
+	goodImport func(emacs.Env, string, ...int) (map[string]bool, error)
+	badImport1 func(string) error
+	badImport2 func(emacs.Env, chan int) error
+	badImport3 func(emacs.Env) (myString, error)
+	badImport4 func(emacs.Env) int
+)
+
+func init() {
+	emacs.Import("good", &goodImport)
+	emacs.Import("bad-1", &badImport1) // want `imported function must accept an Env as first argument`
+	emacs.Import("bad-2", &badImport2) // want `can’t convert argument 1 of type chan int to Emacs`
+	emacs.Import("bad-3", &badImport3) // want `can’t convert result of type a.myString from Emacs`
+	emacs.Import("bad-4", &badImport4) // want `imported function must return either an error or a value and an error`
+	emacs.Import("", &goodImport)      // want `empty name`
+}
+
+func lateExport(e emacs.Env) error {
+	_, err := e.Export(badArg) // want `can’t convert argument 0 of type chan int from Emacs`
+	return err
+}
@@ -0,0 +1,16 @@
+package b
+
+import (
+	"reflect"
+
+	"github.com/phst/emacs"
+)
+
+// Fahrenheit is only convertible because of the registered conversions
+// below.
+type Fahrenheit struct{ c chan int }
+
+func init() {
+	emacs.RegisterInFunc(reflect.TypeFor[Fahrenheit](), nil)
+	emacs.RegisterOutFunc(reflect.TypeFor[Fahrenheit](), nil)
+}
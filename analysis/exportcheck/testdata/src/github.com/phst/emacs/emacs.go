@@ -0,0 +1,54 @@
+// Package emacs is a stub of the real emacs package for testing the analyzer.
+package emacs
+
+import "reflect"
+
+type Env struct{}
+
+type Value struct{}
+
+func (Value) Emacs(Env) (Value, error) { return Value{}, nil }
+
+func (*Value) FromEmacs(Env, Value) error { return nil }
+
+type In interface{ Emacs(Env) (Value, error) }
+
+type Out interface{ FromEmacs(Env, Value) error }
+
+type InFunc func(reflect.Value) In
+
+type OutFunc func(reflect.Value) Out
+
+func RegisterInFunc(t reflect.Type, f InFunc) {}
+
+func RegisterOutFunc(t reflect.Type, f OutFunc) {}
+
+type Callback struct{}
+
+func (*Callback) Call(Env, ...In) (Value, error) { return Value{}, nil }
+
+func (*Callback) Emacs(Env) (Value, error) { return Value{}, nil }
+
+type Symbol string
+
+type Name Symbol
+
+type Doc string
+
+type Usage string
+
+type Anonymous struct{}
+
+type Option interface{}
+
+func Export(fun interface{}, opts ...Option) {}
+
+func Import(name Name, fp interface{}) {}
+
+type ERTTestFunc func(Env) error
+
+func ERTTest(fun ERTTestFunc, opts ...Option) {}
+
+func Var(name Name, init In, doc Doc) Name { return name }
+
+func (Env) Export(fun interface{}, opts ...Option) (Value, error) { return Value{}, nil }
@@ -15,9 +15,13 @@
 package emacs
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net"
+	"sync"
 	"sync/atomic"
 )
 
@@ -28,10 +32,15 @@ import (
 // background and report its result asynchronously using the channel.
 //
 // Async requires a way to notify Emacs about pending asynchronous results; use
-// [NotifyWriter] or [NotifyListener] to create notification channels.  When
+// [Env.NotifyChannel], [NotifyWriter], or [NotifyListener] to create
+// notification channels, or use [Env.NewAsync], which also flushes results.  When
 // notified about asynchronous operations, Emacs should then use [Async.Flush]
 // to return the pending results.
 //
+// Emacs can cancel pending operations using [Async.Cancel].  Operations
+// started with [Async.StartContext] should watch their context to stop work
+// once they’re canceled.
+//
 // Async doesn’t prescribe any specific programming model on the Emacs side;
 // the example uses the [“aio” package].
 //
@@ -40,8 +49,39 @@ type Async struct {
 	notifyCh    chan<- struct{}
 	promiseCh   chan AsyncData
 	nextPromise uint64
+
+	mu      sync.Mutex
+	pending map[AsyncHandle]*asyncOp
+}
+
+// asyncOp is a pending asynchronous operation.
+type asyncOp struct {
+	cancel   context.CancelCauseFunc
+	canceled chan struct{} // closed by Async.Cancel
+	done     chan struct{} // closed once the final result is queued
+	updates  chan AsyncData
+}
+
+// update sends a partial result or progress update for the operation.  It
+// blocks until d is queued for [Async.Flush].
+func (op *asyncOp) update(d AsyncData) error {
+	select {
+	case op.updates <- d:
+		return nil
+	case <-op.canceled:
+		return asyncCanceled.Error()
+	case <-op.done:
+		select {
+		case <-op.canceled:
+			return asyncCanceled.Error()
+		default:
+			return errAsyncFinished
+		}
+	}
 }
 
+var errAsyncFinished = errors.New("asynchronous operation already finished")
+
 // NewAsync creates a new [Async] object.  It will use the given notification
 // channel to signal completion of an asynchronous operation to Emacs; use
 // [NotifyWriter] or [NotifyListener] to create usable channels.
@@ -73,31 +113,159 @@ func NewAsync(notifyCh chan<- struct{}) *Async {
 //	}
 //
 // Here, performOperation should write the result to the channel once
-// available.
+// available.  Use [Async.StartContext] if the operation should stop once
+// Emacs cancels it.
 func (a *Async) Start() (AsyncHandle, chan<- Result) {
+	_, h, ch := a.StartContext(context.Background())
+	return h, ch
+}
+
+// StartContext is like [Async.Start], but also returns a context derived from
+// parent.  The context is canceled once the operation has written its result
+// or Emacs has canceled the operation using [Async.Cancel].  In the latter
+// case, [context.Cause] returns an error that signals go-async-canceled.  If
+// parent is nil, StartContext uses [context.Background].
+func (a *Async) StartContext(parent context.Context) (context.Context, AsyncHandle, chan<- Result) {
 	ch := make(chan Result)
+	ctx, h, _ := a.start(parent, ch)
+	return ctx, h, ch
+}
+
+// start registers a new operation and starts forwarding its results.  The
+// operation writes its final result to ch.  Partial results and progress
+// updates go through the updates channel of the returned asyncOp.
+func (a *Async) start(parent context.Context, ch <-chan Result) (context.Context, AsyncHandle, *asyncOp) {
+	if parent == nil {
+		parent = context.Background()
+	}
 	h := AsyncHandle(atomic.AddUint64(&a.nextPromise, 1))
 	if h == 0 {
 		panic("too many asynchronous operations")
 	}
 	h--
-	go a.forward(h, ch)
-	return h, ch
+	ctx, cancel := context.WithCancelCause(parent)
+	op := &asyncOp{cancel, make(chan struct{}), make(chan struct{}), make(chan AsyncData)}
+	a.mu.Lock()
+	if a.pending == nil {
+		a.pending = make(map[AsyncHandle]*asyncOp)
+	}
+	a.pending[h] = op
+	a.mu.Unlock()
+	atomic.AddInt64(&asyncStats.running, 1)
+	go a.forward(h, op, ch)
+	return ctx, h, op
+}
+
+// Cancel cancels the pending operation with the given handle.  It cancels
+// the context of the operation and queues a result whose Canceled field is
+// set, without waiting for the operation to finish.  The operation may still
+// write its result to its channel, but [Async.Flush] won’t return it.
+// Cancel returns whether the operation was still pending.  Export a function
+// that calls Cancel so that Emacs can cancel operations, e.g. if the user
+// quits while waiting for a result.
+func (a *Async) Cancel(h AsyncHandle) bool {
+	a.mu.Lock()
+	op, ok := a.pending[h]
+	delete(a.pending, h)
+	a.mu.Unlock()
+	if !ok {
+		return false
+	}
+	op.cancel(asyncCanceled.Error())
+	close(op.canceled)
+	return true
 }
 
-func (a *Async) forward(h AsyncHandle, ch <-chan Result) {
-	a.promiseCh <- AsyncData{h, <-ch}
+func (a *Async) forward(h AsyncHandle, op *asyncOp, ch <-chan Result) {
+	defer close(op.done)
+	var r Result
+loop:
+	for {
+		select {
+		case d := <-op.updates:
+			if a.isPending(h) {
+				d.Handle = h
+				a.queue(d)
+			}
+		case r = <-ch:
+			break loop
+		case <-op.canceled:
+			// Discard the result so that the operation doesn’t
+			// block.
+			go func() { <-ch }()
+			break loop
+		}
+	}
+	a.mu.Lock()
+	_, ok := a.pending[h]
+	delete(a.pending, h)
+	a.mu.Unlock()
+	d := AsyncData{Handle: h, Result: r}
+	if ok {
+		op.cancel(nil)
+	} else {
+		// Canceled, possibly after we’ve received the result.
+		d = AsyncData{Handle: h, Result: Result{Err: asyncCanceled.Error()}, Canceled: true}
+	}
+	atomic.AddInt64(&asyncStats.running, -1)
+	a.queue(d)
+}
+
+func (a *Async) isPending(h AsyncHandle) bool {
+	return a.op(h) != nil
+}
+
+// op returns the pending operation with handle h, or nil if there’s no such
+// operation.
+func (a *Async) op(h AsyncHandle) *asyncOp {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.pending[h]
+}
+
+// Progress publishes progress information for the pending operation with
+// handle h.  It blocks until the information is queued for [Async.Flush],
+// which returns it as an [AsyncData] value whose Progress field is set.
+// Progress returns an error if there’s no such operation, e.g. because it
+// has already finished or Emacs has canceled it.  See
+// [ExportAsyncProgressFunction] for a way to display the progress in Emacs.
+func (a *Async) Progress(h AsyncHandle, p AsyncProgress) error {
+	op := a.op(h)
+	if op == nil {
+		return fmt.Errorf("no pending asynchronous operation with handle %d", h)
+	}
+	return op.update(AsyncData{Result: Result{Value: p}, Progress: true})
+}
+
+// queue queues d for [Async.Flush] and notifies Emacs.
+func (a *Async) queue(d AsyncData) {
+	atomic.AddInt64(&asyncStats.queued, 1)
+	a.promiseCh <- d
 	a.notifyCh <- struct{}{}
 }
 
+// asyncStats contains statistics across all [Async] objects.  The fields are
+// accessed atomically.
+var asyncStats struct {
+	// running is the number of operations that haven’t produced a
+	// result yet.
+	running int64
+
+	// queued is the number of results that haven’t been flushed yet.
+	queued int64
+}
+
 // Flush returns and removes all pending asynchronous operation results.  You
 // should call this method from Emacs Lisp when notified about pending
-// asynchronous results.
+// asynchronous results.  Results for the same handle are returned in the
+// order in which they were produced: first any partial results, then the
+// final result.
 func (a *Async) Flush() []AsyncData {
 	var r []AsyncData
 	for {
 		select {
 		case v := <-a.promiseCh:
+			atomic.AddInt64(&asyncStats.queued, -1)
 			r = append(r, v)
 		default:
 			return r
@@ -114,6 +282,18 @@ func (h AsyncHandle) Emacs(e Env) (Value, error) {
 	return Uint(h).Emacs(e)
 }
 
+// FromEmacs implements [Out.FromEmacs].  It sets *h to the handle
+// represented by the integer v.  This allows exported functions, such as a
+// wrapper around [Async.Cancel], to accept handles.
+func (h *AsyncHandle) FromEmacs(e Env, v Value) error {
+	var u Uint
+	if err := u.FromEmacs(e, v); err != nil {
+		return err
+	}
+	*h = AsyncHandle(u)
+	return nil
+}
+
 // Result contains the result of an asynchronous operation.  If Err is set,
 // Value is ignored.
 type Result struct {
@@ -126,11 +306,35 @@ type Result struct {
 type AsyncData struct {
 	Handle AsyncHandle
 	Result
+
+	// Canceled is true if Emacs has canceled the operation using
+	// [Async.Cancel].  In that case, Err signals go-async-canceled.
+	Canceled bool
+
+	// Partial is true if Value is an intermediate value sent using
+	// [AsyncStream.Send].  More results for the same handle follow.
+	Partial bool
+
+	// Progress is true if Value is an [AsyncProgress] value published
+	// using [Async.Progress] or [AsyncStream.Progress].  More results for
+	// the same handle follow.
+	Progress bool
 }
 
 // Emacs implements [In.Emacs].  It returns a triple (handle value error).
 // If Err is set, the error element will be of the form (symbol . data).
+// For canceled operations, the error symbol is go-async-canceled.  Partial
+// results and progress updates are represented as
+// (handle value nil kind) instead, where kind is either partial or
+// progress.  See [AsyncProgress.Emacs] for the representation of progress
+// values.
 func (d AsyncData) Emacs(e Env) (Value, error) {
+	switch {
+	case d.Partial:
+		return e.List(d.Handle, d.Value, Nil, Symbol("partial"))
+	case d.Progress:
+		return e.List(d.Handle, d.Value, Nil, Symbol("progress"))
+	}
 	return e.List(d.Handle, d.Value, errorData(d.Err))
 }
 
@@ -147,7 +351,10 @@ func errorData(err error) In {
 	}
 }
 
-var asyncError = DefineError("go-async-error", "Generic asynchronous Go error", baseError)
+var (
+	asyncError    = DefineError("go-async-error", "Generic asynchronous Go error", baseError)
+	asyncCanceled = DefineError("go-async-canceled", "Asynchronous operation canceled", asyncError)
+)
 
 // NotifyWriter returns a channel that causes some arbitrary content to be
 // written to the given writer whenever something is written to the channel.
@@ -15,18 +15,22 @@
 package emacs
 
 import (
+	"context"
 	"errors"
 	"log"
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sync"
+	"testing"
 )
 
 func ExampleAsync() {
 	Export(mersennePrimeAsyncP, Doc("Return a promise that resolves to a Boolean value indicating whether 2^N − 1 is probably prime."), Usage("N"))
 	Export(asyncSocket, Doc("Return a filename of a socket to connect to"))
 	Export(asyncFlush, Doc("Return a vector of asynchronous promise results"))
+	Export(asyncCancel, Doc("Cancel the asynchronous operation with handle H.\nReturn whether the operation was still pending."), Usage("H"))
 }
 
 func mersennePrimeAsyncP(n uint16) AsyncHandle {
@@ -59,6 +63,10 @@ func asyncFlush() []AsyncData {
 	return async.Flush()
 }
 
+func asyncCancel(h AsyncHandle) bool {
+	return async.Cancel(h)
+}
+
 func initAsync() {
 	dir, err := os.MkdirTemp("", "emacs-")
 	if err != nil {
@@ -77,3 +85,91 @@ func initAsync() {
 func init() {
 	ExampleAsync()
 }
+
+func TestAsyncCancel(t *testing.T) {
+	notify := make(chan struct{}, 10)
+	a := NewAsync(notify)
+	ctx, h, ch := a.StartContext(nil)
+	_, h2, ch2 := a.StartContext(context.Background())
+	if !a.Cancel(h) {
+		t.Error("Cancel: got false, want true")
+	}
+	if a.Cancel(h) {
+		t.Error("second Cancel: got true, want false")
+	}
+	<-ctx.Done()
+	var cause Error
+	if err := context.Cause(ctx); !errors.As(err, &cause) || cause.Symbol != asyncCanceled {
+		t.Errorf("context.Cause: got %v, want go-async-canceled", err)
+	}
+	// Writing the result of a canceled operation must not block.
+	ch <- Result{Value: Int(1)}
+	ch2 <- Result{Value: Int(2)}
+	<-notify
+	<-notify
+	got := a.Flush()
+	if len(got) != 2 {
+		t.Fatalf("Flush: got %d results, want 2", len(got))
+	}
+	for _, d := range got {
+		switch d.Handle {
+		case h:
+			if !d.Canceled || !errors.As(d.Err, &cause) || cause.Symbol != asyncCanceled {
+				t.Errorf("canceled operation: got %+v", d)
+			}
+		case h2:
+			if d.Canceled || d.Err != nil || d.Value != Int(2) {
+				t.Errorf("finished operation: got %+v", d)
+			}
+		default:
+			t.Errorf("unknown handle %d", d.Handle)
+		}
+	}
+	if a.Cancel(h2) {
+		t.Error("Cancel after completion: got true, want false")
+	}
+}
+
+func TestAsyncStream(t *testing.T) {
+	notify := make(chan struct{}, 10)
+	a := NewAsync(notify)
+	_, h, s := a.StartStream(nil)
+	go func() {
+		for i := 0; i < 3; i++ {
+			if err := s.Send(Int(i)); err != nil {
+				t.Error(err)
+			}
+		}
+		s.Finish(Result{Value: String("done")})
+	}()
+	var got []AsyncData
+	for len(got) < 4 {
+		<-notify
+		got = append(got, a.Flush()...)
+	}
+	want := []AsyncData{
+		{Handle: h, Result: Result{Value: Int(0)}, Partial: true},
+		{Handle: h, Result: Result{Value: Int(1)}, Partial: true},
+		{Handle: h, Result: Result{Value: Int(2)}, Partial: true},
+		{Handle: h, Result: Result{Value: String("done")}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flush: got %+v, want %+v", got, want)
+	}
+	if err := s.Send(Int(3)); err != errAsyncFinished {
+		t.Errorf("Send after Finish: got error %v, want %v", err, errAsyncFinished)
+	}
+	s.Finish(Result{Value: Int(4)})
+
+	_, h, s = a.StartStream(context.Background())
+	a.Cancel(h)
+	var cause Error
+	if err := s.Send(Int(0)); !errors.As(err, &cause) || cause.Symbol != asyncCanceled {
+		t.Errorf("Send after Cancel: got error %v, want go-async-canceled", err)
+	}
+	s.Finish(Result{Value: Int(1)})
+	<-notify
+	if got := a.Flush(); len(got) != 1 || !got[0].Canceled {
+		t.Errorf("Flush after Cancel: got %+v, want one canceled result", got)
+	}
+}
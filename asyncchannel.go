@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "errors"
+
+// NotifyChannel creates an Emacs process that receives notifications and
+// returns a channel suitable for [NewAsync].  Each time something is written
+// to the channel, Emacs calls the process filter function filter with the
+// process and some arbitrary output.  filter is typically a symbol naming a
+// Lisp function or a function value.
+//
+// NotifyChannel creates a pipe process and opens a pipe to it using
+// [Env.OpenPipe].  The notification process is named “go-async”; if you
+// want to use it in other ways, use get-process or process-list to retrieve
+// it.  The process lives until Emacs exits or you delete it.
+func (e Env) NotifyChannel(filter In) (chan<- struct{}, error) {
+	proc, err := e.Call(
+		"make-pipe-process",
+		Symbol(":name"), String("go-async"),
+		Symbol(":noquery"), T,
+		Symbol(":coding"), Symbol("binary"),
+		Symbol(":filter"), filter,
+	)
+	if err != nil {
+		return nil, err
+	}
+	f, err := e.OpenPipe(proc)
+	if err != nil {
+		e.Call("delete-process", proc)
+		return nil, err
+	}
+	return NotifyWriter(f), nil
+}
+
+// NewAsync creates a new [Async] object that delivers results to Emacs
+// without further setup.  It creates a notification channel using
+// [Env.NotifyChannel], with a process filter that calls [Async.Flush] and
+// then calls the Lisp function handler once for each result, passing the
+// result as described in [AsyncData.Emacs].  Because the filter flushes all
+// results, calling Flush yourself isn’t necessary, and typically returns
+// nothing.  If handler signals an error for some result, the filter still
+// passes the remaining results to handler and then signals the first error.
+// handler is typically a symbol naming a Lisp function or a function value.
+// NewAsync keeps a global reference to it.
+//
+// The notification process, the process filter, and the global reference to
+// handler stay alive until Emacs exits; there’s no way to release them.
+// Therefore you should call NewAsync only once per module, for example in a
+// function registered with [OnInit], and share the resulting Async object.
+// [AsyncShim] does this for you.
+func (e Env) NewAsync(handler In) (*Async, error) {
+	if handler == nil {
+		return nil, errors.New("nil handler for asynchronous results")
+	}
+	h, err := handler.Emacs(e)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := e.GlobalRef(h)
+	if err != nil {
+		return nil, err
+	}
+	var a *Async
+	filter := func(e Env, _ []Value) (Value, error) {
+		var first error
+		for _, d := range a.Flush() {
+			v, err := d.Emacs(e)
+			if err == nil {
+				_, err = e.Funcall(ref.Value(), []Value{v})
+			}
+			if first == nil {
+				first = err
+			}
+		}
+		if first != nil {
+			return Value{}, first
+		}
+		return e.Nil()
+	}
+	name, index, err := e.exportNumbered("go--async-filter-", Lambda{filter, Arity{2, 2}, "Process filter for asynchronous Go operations."})
+	if err != nil {
+		e.FreeGlobalRef(ref)
+		return nil, err
+	}
+	ch, err := e.NotifyChannel(name)
+	if err != nil {
+		e.releaseNumbered(name, index)
+		e.FreeGlobalRef(ref)
+		return nil, err
+	}
+	a = NewAsync(ch)
+	return a, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+func init() {
+	ERTTest(asyncNewAsync, Requires{OpenChannel})
+}
+
+func asyncNewAsync(e Env) error {
+	var got []AsyncHandle
+	handler, del, err := e.Lambda(func(e Env, d Value) error {
+		var h AsyncHandle
+		if err := e.CallOut("car", &h, d); err != nil {
+			return err
+		}
+		got = append(got, h)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer del()
+	a, err := e.NewAsync(handler)
+	if err != nil {
+		return err
+	}
+	h, ch := a.Start()
+	go func() { ch <- Result{Value: Int(42)} }()
+	for i := 0; i < 100 && len(got) == 0; i++ {
+		if _, err := e.Call("accept-process-output", Nil, Float(0.1)); err != nil {
+			return err
+		}
+	}
+	if len(got) != 1 || got[0] != h {
+		return fmt.Errorf("handler received %v, want [%d]", got, h)
+	}
+	if _, err := e.NewAsync(nil); err == nil {
+		return errors.New("NewAsync(nil) succeeded")
+	}
+	return nil
+}
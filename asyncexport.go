@@ -0,0 +1,168 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ExportAsync is like [Export], but exports fun as an asynchronous function.
+// The exported function converts its arguments on the Emacs thread, starts
+// an asynchronous operation using [Async.StartContext], and returns the
+// [AsyncHandle] of the operation.  It then calls fun in a new goroutine.
+// Once fun returns, its result or error becomes the result of the
+// operation, which [Async.Flush] returns.  A panic in fun becomes an error
+// that signals go-panic.
+//
+// async returns the [Async] object that manages the operations; the
+// exported function calls it each time it’s called from Emacs, so it can
+// create the Async object lazily.  If async returns an error, the exported
+// function returns it without starting an operation.
+//
+// Because fun runs outside of the Emacs thread, it may not accept an [Env]
+// argument; ExportAsync panics if it does.  Optionally, the first argument
+// of fun may be of type [context.Context].  In that case, fun receives the
+// context of the operation, which is canceled when Emacs cancels the
+// operation using [Async.Cancel].  Otherwise, the same rules as for
+// [Export] apply.  In particular, the name of the function is derived from
+// the Go name of fun unless a [Name] option is given.  ExportAsync converts
+// the result to an Emacs value only when Flush returns it.
+//
+// The typical usage pattern is:
+//
+//	func init() {
+//		emacs.ExportAsync(getAsync, fetchURL, emacs.Doc("Fetch URL asynchronously."))
+//	}
+//
+//	func fetchURL(ctx context.Context, url string) (string, error) {
+//		// …
+//	}
+func ExportAsync(async func(Env) (*Async, error), fun interface{}, opts ...Option) {
+	if async == nil {
+		panic("nil Async function")
+	}
+	v := reflect.ValueOf(fun)
+	w := asyncWrapper(async, v)
+	Export(w.Interface(), append(append([]Option(nil), opts...), derivedName{v})...)
+}
+
+// asyncWrapper returns a function that starts an asynchronous operation
+// calling fun and returns its handle.  The function accepts an [Env] and the
+// same arguments as fun, except for a leading [context.Context].
+func asyncWrapper(async func(Env) (*Async, error), fun reflect.Value) reflect.Value {
+	if fun.Kind() != reflect.Func {
+		panic(fmt.Errorf("ExportAsync: got %s, want function", fun.Type()))
+	}
+	t := fun.Type()
+	if t.NumIn() > 0 && t.In(0) == envType {
+		panic(fmt.Errorf("ExportAsync: function of type %s may not accept an Env because it runs in a different goroutine", t))
+	}
+	hasContext := t.NumIn() > 0 && t.In(0) == contextType
+	in := []reflect.Type{envType}
+	for i := 0; i < t.NumIn(); i++ {
+		if i > 0 || !hasContext {
+			in = append(in, t.In(i))
+		}
+	}
+	result, err := asyncResult(t)
+	if err != nil {
+		panic(fmt.Errorf("ExportAsync: %s", err))
+	}
+	wt := reflect.FuncOf(in, []reflect.Type{reflect.TypeOf(AsyncHandle(0)), errorType}, t.IsVariadic())
+	return reflect.MakeFunc(wt, func(args []reflect.Value) []reflect.Value {
+		e := args[0].Interface().(Env)
+		a, err := async(e)
+		if err == nil && a == nil {
+			err = errNoAsync.Error()
+		}
+		if err != nil {
+			return []reflect.Value{reflect.ValueOf(AsyncHandle(0)), reflect.ValueOf(&err).Elem()}
+		}
+		ctx, h, ch := a.StartContext(context.Background())
+		args = args[1:]
+		if hasContext {
+			args = append([]reflect.Value{reflect.ValueOf(ctx)}, args...)
+		}
+		go func() {
+			var r Result
+			defer func() {
+				if x := recover(); x != nil {
+					r = Result{Err: errPanic.Error(String(fmt.Sprint(x)))}
+				}
+				ch <- r
+			}()
+			var out []reflect.Value
+			if t.IsVariadic() {
+				out = fun.CallSlice(args)
+			} else {
+				out = fun.Call(args)
+			}
+			r = result(out)
+		}()
+		return []reflect.Value{reflect.ValueOf(h), reflect.Zero(errorType)}
+	})
+}
+
+// asyncResult returns a function that converts the results of a function of
+// type t to a [Result].
+func asyncResult(t reflect.Type) (func([]reflect.Value) Result, error) {
+	n := t.NumOut()
+	hasErr := n > 0 && t.Out(n-1) == errorType
+	if hasErr {
+		n--
+	}
+	switch n {
+	case 0:
+		return func(out []reflect.Value) Result {
+			if hasErr {
+				if err, _ := out[0].Interface().(error); err != nil {
+					return Result{Err: err}
+				}
+			}
+			return Result{Value: Nil}
+		}, nil
+	case 1:
+		conv, err := InFuncFor(t.Out(0))
+		if err != nil {
+			return nil, fmt.Errorf("don’t know how to convert result type: %s", err)
+		}
+		return func(out []reflect.Value) Result {
+			if hasErr {
+				if err, _ := out[1].Interface().(error); err != nil {
+					return Result{Err: err}
+				}
+			}
+			return Result{Value: conv(out[0])}
+		}, nil
+	default:
+		return nil, fmt.Errorf("function of type %s has too many results", t)
+	}
+}
+
+// derivedName is an [Option] that derives the function name from the Go
+// function fun unless the options specify a name or an anonymous function.
+// It has to come last.
+type derivedName struct{ fun reflect.Value }
+
+func (n derivedName) apply(o *exportAuto) {
+	if o.name == "" && o.flag&exportAnonymous == 0 {
+		o.name = lispName(n.fun)
+		o.flag |= exportDerivedName
+	}
+}
+
+var errNoAsync = DefineError("go-async-unavailable", "No Async object for asynchronous function", asyncError)
@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	exportAsyncNotify = make(chan struct{}, 10)
+	exportAsync       = NewAsync(exportAsyncNotify)
+)
+
+func init() {
+	getAsync := func(Env) (*Async, error) { return exportAsync, nil }
+	ExportAsync(getAsync, goAsyncDivide)
+	ExportAsync(getAsync, func(ctx context.Context) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	}, Name("go--async-wait"))
+	ERTTest(exportAsyncDivide)
+	ERTTest(exportAsyncCancel)
+}
+
+func goAsyncDivide(a, b int) (int, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+// flushExportAsync waits for the result of the operation with handle h.
+func flushExportAsync(h AsyncHandle) (AsyncData, error) {
+	for {
+		select {
+		case <-exportAsyncNotify:
+		case <-time.After(10 * time.Second):
+			return AsyncData{}, fmt.Errorf("timed out waiting for operation %d", h)
+		}
+		for _, d := range exportAsync.Flush() {
+			if d.Handle == h {
+				return d, nil
+			}
+		}
+	}
+}
+
+func exportAsyncDivide(e Env) error {
+	var h AsyncHandle
+	if err := e.CallOut("go-async-divide", &h, Int(7), Int(2)); err != nil {
+		return err
+	}
+	d, err := flushExportAsync(h)
+	if err != nil {
+		return err
+	}
+	if d.Err != nil {
+		return d.Err
+	}
+	var got Int
+	if err := e.CallOut("identity", &got, d.Value); err != nil {
+		return err
+	}
+	if got != 3 {
+		return fmt.Errorf("go-async-divide 7 2: got %d, want 3", got)
+	}
+	if err := e.CallOut("go-async-divide", &h, Int(1), Int(0)); err != nil {
+		return err
+	}
+	if d, err = flushExportAsync(h); err != nil {
+		return err
+	}
+	if d.Err == nil {
+		return errors.New("go-async-divide 1 0: got no error")
+	}
+	return nil
+}
+
+func exportAsyncCancel(e Env) error {
+	var h AsyncHandle
+	if err := e.CallOut("go--async-wait", &h); err != nil {
+		return err
+	}
+	if !exportAsync.Cancel(h) {
+		return fmt.Errorf("operation %d not pending", h)
+	}
+	d, err := flushExportAsync(h)
+	if err != nil {
+		return err
+	}
+	if !d.Canceled {
+		return fmt.Errorf("got %+v, want canceled result", d)
+	}
+	return nil
+}
+
+func TestAsyncWrapperInvalid(t *testing.T) {
+	getAsync := func(Env) (*Async, error) { return exportAsync, nil }
+	for _, fun := range []interface{}{
+		42,
+		func(Env) {},
+		func() (int, int, error) { return 0, 0, nil },
+		func() chan int { return nil },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("asyncWrapper(%T) didn’t panic", fun)
+				}
+			}()
+			asyncWrapper(getAsync, reflect.ValueOf(fun))
+		}()
+	}
+}
+
+func TestAsyncWrapperType(t *testing.T) {
+	getAsync := func(Env) (*Async, error) { return exportAsync, nil }
+	got := asyncWrapper(getAsync, reflect.ValueOf(func(context.Context, string, ...int) error { return nil })).Type()
+	want := reflect.TypeOf(func(Env, string, ...int) (AsyncHandle, error) { return 0, nil })
+	if got != want {
+		t.Errorf("asyncWrapper: got type %s, want %s", got, want)
+	}
+}
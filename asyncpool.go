@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Pool runs asynchronous operations with bounded concurrency.  Create a Pool
+// using [NewPool]; the zero Pool isn’t valid.  [Pool.Go] starts an
+// operation if fewer than the configured number of operations are running,
+// and queues it otherwise.  Queued operations start in the order in which
+// they were submitted.  Modules that fan out to network or CPU-heavy work
+// can use a Pool instead of building their own throttling.
+//
+// Operations started by a Pool are ordinary operations of the underlying
+// [Async] object, so Emacs can flush and cancel them as usual.  If Emacs
+// cancels a queued operation, it never runs.  Use [Pool.Stats] to inspect
+// the queue depth; [PoolStats] is convertible to an Emacs value, so you can
+// export a function that returns it:
+//
+//	emacs.Export(pool.Stats, emacs.Name("my-module-pool-stats"))
+type Pool struct {
+	async *Async
+	limit int
+
+	mu      sync.Mutex
+	running int
+	queue   []poolJob
+}
+
+// poolJob is an operation submitted to a [Pool].
+type poolJob struct {
+	ctx context.Context
+	fun func(context.Context) (In, error)
+	ch  chan<- Result
+}
+
+// NewPool returns a new [Pool] that runs at most limit operations of a
+// concurrently.  NewPool panics if a is nil or limit isn’t positive.
+func NewPool(a *Async, limit int) *Pool {
+	if a == nil {
+		panic("nil Async object")
+	}
+	if limit <= 0 {
+		panic(fmt.Errorf("invalid concurrency limit %d", limit))
+	}
+	return &Pool{async: a, limit: limit}
+}
+
+// Go starts a new asynchronous operation that calls fun in its own
+// goroutine, and returns the handle of the operation.  If the pool is at its
+// concurrency limit, the operation waits in a queue until another operation
+// has finished.  fun receives the context of the operation, which is
+// canceled if Emacs cancels the operation using [Async.Cancel].  Once fun
+// returns, its value or error becomes the result of the operation.  A nil
+// value is converted to nil, and a panic in fun becomes an error that
+// signals go-panic.  Go never blocks, and you can call it from any
+// goroutine.
+func (p *Pool) Go(fun func(context.Context) (In, error)) AsyncHandle {
+	if fun == nil {
+		panic("nil pool function")
+	}
+	ctx, h, ch := p.async.StartContext(nil)
+	j := poolJob{ctx, fun, ch}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running < p.limit {
+		p.running++
+		go p.work(j)
+	} else {
+		p.queue = append(p.queue, j)
+	}
+	return h
+}
+
+// work runs j and then queued jobs until the queue is empty.
+func (p *Pool) work(j poolJob) {
+	for {
+		j.run()
+		p.mu.Lock()
+		if len(p.queue) == 0 {
+			p.running--
+			p.mu.Unlock()
+			return
+		}
+		j = p.queue[0]
+		p.queue[0] = poolJob{} // allow garbage collection
+		p.queue = p.queue[1:]
+		p.mu.Unlock()
+	}
+}
+
+// run calls the function of j and writes its result.  If the operation was
+// canceled while queued, run doesn’t call the function.
+func (j poolJob) run() {
+	var r Result
+	defer func() {
+		if x := recover(); x != nil {
+			r = Result{Err: errPanic.Error(String(fmt.Sprint(x)))}
+		}
+		j.ch <- r
+	}()
+	if err := context.Cause(j.ctx); err != nil {
+		r = Result{Err: err}
+		return
+	}
+	v, err := j.fun(j.ctx)
+	if v == nil {
+		v = Nil
+	}
+	r = Result{v, err}
+}
+
+// Stats returns the current state of the pool.
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PoolStats{Limit: p.limit, Running: p.running, Queued: len(p.queue)}
+}
+
+// PoolStats describes the state of a [Pool].  Use [Pool.Stats] to retrieve
+// it.
+type PoolStats struct {
+	// Limit is the maximum number of concurrently running operations.
+	Limit int
+
+	// Running is the number of operations that are currently running.
+	Running int
+
+	// Queued is the number of operations that wait for a free slot.  It
+	// includes operations that Emacs has canceled but that haven’t
+	// reached the front of the queue yet.
+	Queued int
+}
+
+// Emacs implements [In.Emacs].  It returns a property list
+// (:limit LIMIT :running RUNNING :queued QUEUED).
+func (s PoolStats) Emacs(e Env) (Value, error) {
+	return e.List(Symbol(":limit"), Int(s.Limit), Symbol(":running"), Int(s.Running), Symbol(":queued"), Int(s.Queued))
+}
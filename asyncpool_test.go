@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	notify := make(chan struct{}, 10)
+	a := NewAsync(notify)
+	p := NewPool(a, 2)
+	release := make(chan struct{})
+	started := make(chan int, 5)
+	var handles []AsyncHandle
+	for i := 0; i < 5; i++ {
+		i := i
+		handles = append(handles, p.Go(func(context.Context) (In, error) {
+			started <- i
+			<-release
+			return Int(i), nil
+		}))
+	}
+	<-started
+	<-started
+	if got, want := p.Stats(), (PoolStats{Limit: 2, Running: 2, Queued: 3}); got != want {
+		t.Errorf("Stats: got %+v, want %+v", got, want)
+	}
+	if !a.Cancel(handles[4]) {
+		t.Error("Cancel: got false, want true")
+	}
+	close(release)
+	got := make(map[AsyncHandle]AsyncData)
+	for len(got) < 5 {
+		<-notify
+		for _, d := range a.Flush() {
+			got[d.Handle] = d
+		}
+	}
+	for i, h := range handles[:4] {
+		if d := got[h]; d.Err != nil || d.Value != Int(i) {
+			t.Errorf("operation %d: got %+v, want value %d", i, d, i)
+		}
+	}
+	var cause Error
+	if d := got[handles[4]]; !d.Canceled || !errors.As(d.Err, &cause) || cause.Symbol != asyncCanceled {
+		t.Errorf("canceled operation: got %+v", d)
+	}
+	close(started)
+	var order []int
+	for i := range started {
+		order = append(order, i)
+	}
+	if len(order) != 2 || order[0] != 2 || order[1] != 3 {
+		t.Errorf("queued operations started in order %v, want [2 3]", order)
+	}
+}
+
+func TestPoolPanic(t *testing.T) {
+	notify := make(chan struct{}, 10)
+	a := NewAsync(notify)
+	p := NewPool(a, 1)
+	h := p.Go(func(context.Context) (In, error) { panic("boom") })
+	<-notify
+	got := a.Flush()
+	var cause Error
+	if len(got) != 1 || got[0].Handle != h || !errors.As(got[0].Err, &cause) || cause.Symbol != errPanic {
+		t.Errorf("Flush: got %+v, want one go-panic error", got)
+	}
+}
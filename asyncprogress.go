@@ -0,0 +1,129 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// AsyncProgress describes the progress of an asynchronous operation.  Use
+// [Async.Progress] or [AsyncStream.Progress] to publish it.
+type AsyncProgress struct {
+	// Fraction is the fraction of the work that’s done, between 0 and 1.
+	// A negative value means that the fraction isn’t known.
+	Fraction float64
+
+	// Message describes what the operation is doing.  It may be empty.
+	Message string
+}
+
+// Emacs implements [In.Emacs].  It returns a property list
+// (:fraction FRACTION :message MESSAGE).  FRACTION is nil if the fraction
+// isn’t known, and MESSAGE is nil if the message is empty.
+func (p AsyncProgress) Emacs(e Env) (Value, error) {
+	var fraction, message In = Nil, Nil
+	if p.Fraction >= 0 {
+		fraction = Float(min(p.Fraction, 1))
+	}
+	if p.Message != "" {
+		message = String(p.Message)
+	}
+	return e.List(Symbol(":fraction"), fraction, Symbol(":message"), message)
+}
+
+// ExportAsyncProgressFunction arranges for a Lisp function with the given
+// name to be defined once the module is loaded.  The function accepts one
+// element of the list returned by [Async.Flush].  For progress updates, it
+// creates or updates a progress reporter for the operation (see
+// make-progress-reporter) and returns t.  For final results, it finishes the
+// progress reporter of the operation, if any, and returns nil; it also
+// returns nil for partial results.  Call the function for each flushed
+// result and skip the results for which it returns non-nil.  The function is
+// written in Lisp and keeps the progress reporters in a hash table keyed by
+// operation handle, so use separate functions for separate [Async] objects.
+// Call ExportAsyncProgressFunction in an init function.
+func ExportAsyncProgressFunction(name Name) {
+	OnInit(func(e Env) error {
+		_, err := e.Eval(asyncProgressForm(name))
+		return err
+	})
+}
+
+// asyncProgressForm returns a form that defines the function described in
+// [ExportAsyncProgressFunction].
+func asyncProgressForm(name Name) In {
+	reporter := Symbol("reporter")
+	reporters := Symbol("reporters")
+	handle := Symbol("handle")
+	fraction := Symbol("fraction")
+	message := Symbol("message")
+	return List{
+		Symbol("let"), List{List{reporters, List{Symbol("make-hash-table"), Symbol(":test"), List{Symbol("function"), Symbol("eql")}}}},
+		List{
+			Symbol("defalias"), List{Symbol("quote"), Symbol(name)},
+			List{
+				Symbol("lambda"), List{Symbol("data")},
+				String("Display progress of the asynchronous operation in DATA.\n" +
+					"DATA is an element of the list of flushed asynchronous results.\n" +
+					"If DATA is a progress update, update the progress reporter of\n" +
+					"its operation and return t.  Otherwise, finish the progress\n" +
+					"reporter if DATA is a final result, and return nil."),
+				List{
+					Symbol("let"), List{
+						List{handle, List{Symbol("nth"), Int(0), Symbol("data")}},
+						List{Symbol("kind"), List{Symbol("nth"), Int(3), Symbol("data")}},
+					},
+					List{
+						Symbol("if"), List{Symbol("eq"), Symbol("kind"), List{Symbol("quote"), Symbol("progress")}},
+						List{
+							Symbol("let*"), List{
+								List{Symbol("progress"), List{Symbol("nth"), Int(1), Symbol("data")}},
+								List{fraction, List{Symbol("plist-get"), Symbol("progress"), Symbol(":fraction")}},
+								List{message, List{Symbol("or"), List{Symbol("plist-get"), Symbol("progress"), Symbol(":message")}, String("Working...")}},
+								List{reporter, List{Symbol("gethash"), handle, reporters}},
+							},
+							List{
+								Symbol("unless"), reporter,
+								List{
+									Symbol("setq"), reporter,
+									List{
+										Symbol("if"), fraction,
+										List{Symbol("make-progress-reporter"), message, Int(0), Int(100)},
+										List{Symbol("make-progress-reporter"), message},
+									},
+								},
+								List{Symbol("puthash"), handle, reporter, reporters},
+							},
+							List{
+								Symbol("progress-reporter-force-update"), reporter,
+								List{Symbol("and"), fraction, List{Symbol("round"), List{Symbol("*"), Int(100), fraction}}},
+								message,
+							},
+							T,
+						},
+						List{
+							Symbol("unless"), List{Symbol("eq"), Symbol("kind"), List{Symbol("quote"), Symbol("partial")}},
+							List{
+								Symbol("let"), List{List{reporter, List{Symbol("gethash"), handle, reporters}}},
+								List{
+									Symbol("when"), reporter,
+									List{Symbol("progress-reporter-done"), reporter},
+									List{Symbol("remhash"), handle, reporters},
+								},
+							},
+							Nil,
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	ERTTest(asyncProgressFunction)
+}
+
+func asyncProgressFunction(e Env) error {
+	const name = "go--test-async-progress"
+	if _, err := e.Eval(asyncProgressForm(name)); err != nil {
+		return err
+	}
+	defer e.Call("fmakunbound", Symbol(name))
+	for _, tc := range []struct {
+		data AsyncData
+		want bool
+	}{
+		{AsyncData{Handle: 1, Result: Result{Value: AsyncProgress{0.5, "Halfway"}}, Progress: true}, true},
+		{AsyncData{Handle: 1, Result: Result{Value: AsyncProgress{-1, ""}}, Progress: true}, true},
+		{AsyncData{Handle: 1, Result: Result{Value: Int(1)}, Partial: true}, false},
+		{AsyncData{Handle: 1, Result: Result{Value: Int(2)}}, false},
+	} {
+		var got Bool
+		if err := e.CallOut(name, &got, tc.data); err != nil {
+			return err
+		}
+		if bool(got) != tc.want {
+			return fmt.Errorf("%s %+v: got %t, want %t", name, tc.data, got, tc.want)
+		}
+	}
+	return nil
+}
+
+func TestAsyncProgress(t *testing.T) {
+	notify := make(chan struct{}, 10)
+	a := NewAsync(notify)
+	h, ch := a.Start()
+	if err := a.Progress(h, AsyncProgress{0.25, "Loading"}); err != nil {
+		t.Fatal(err)
+	}
+	ch <- Result{Value: Int(1)}
+	<-notify
+	<-notify
+	got := a.Flush()
+	want := []AsyncData{
+		{Handle: h, Result: Result{Value: AsyncProgress{0.25, "Loading"}}, Progress: true},
+		{Handle: h, Result: Result{Value: Int(1)}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flush: got %+v, want %+v", got, want)
+	}
+	if err := a.Progress(h, AsyncProgress{Fraction: 1}); err == nil {
+		t.Error("Progress after completion succeeded")
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"strings"
+	"sync"
+)
+
+// AsyncShim defines the Lisp side of asynchronous operations, so that modules
+// don’t have to write their own code to dispatch results.  Create an
+// AsyncShim using [NewAsyncShim] in an init function, and pass its
+// [AsyncShim.Async] method to [ExportAsync]:
+//
+//	var shim = emacs.NewAsyncShim("my-module")
+//
+//	func init() {
+//		emacs.ExportAsync(shim.Async, fetchURL, emacs.Doc("Fetch URL asynchronously."))
+//	}
+//
+// Loading the module then defines the following Lisp functions, where
+// PREFIX is the prefix passed to NewAsyncShim:
+//
+//   - (PREFIX-then HANDLE CALLBACK &optional PARTIAL) arranges for CALLBACK
+//     to be called with two arguments VALUE and ERROR once the operation
+//     with the given handle has finished.  ERROR is either nil or a cons
+//     (SYMBOL . DATA) describing the error.  If the operation has already
+//     finished, PREFIX-then calls CALLBACK immediately.  PARTIAL, if
+//     non-nil, is called with each intermediate value of streaming
+//     operations (see [Async.StartStream]); intermediate values that arrive
+//     before PREFIX-then is called are lost.
+//   - (PREFIX-wait HANDLE &optional TIMEOUT) waits for the operation to
+//     finish and returns its value or signals its error.  If TIMEOUT is
+//     non-nil, it signals an error after TIMEOUT seconds.
+//   - (PREFIX-aio HANDLE) returns a promise of the [“aio” package] that
+//     resolves to the value of the operation or signals its error.
+//   - (PREFIX-cancel HANDLE) cancels the operation using [Async.Cancel] and
+//     returns whether it was still pending.  Its callback then receives an
+//     error with the symbol go-async-canceled.
+//
+// Progress updates (see [Async.Progress]) are displayed using progress
+// reporters as described in [ExportAsyncProgressFunction].  The shim keeps
+// pending operations in a hash table named PREFIX--promises.
+//
+// [“aio” package]: https://github.com/skeeto/emacs-aio
+type AsyncShim struct {
+	prefix Name
+
+	mu    sync.Mutex
+	async *Async
+}
+
+// NewAsyncShim returns a new [AsyncShim] whose Lisp functions start with
+// prefix and a hyphen, and arranges for them to be defined when Emacs loads
+// the module.  prefix must be a valid symbol name that doesn’t need quoting.
+// Call NewAsyncShim in an init function or when initializing a global
+// variable.
+func NewAsyncShim(prefix Name) *AsyncShim {
+	if prefix == "" {
+		panic("empty prefix for asynchronous shim")
+	}
+	s := &AsyncShim{prefix: prefix}
+	OnInit(s.define)
+	return s
+}
+
+// Async returns the [Async] object that delivers results to the Lisp
+// functions of the shim.  It creates the Async object using [Env.NewAsync]
+// the first time it’s called.  Async has the right signature to be passed to
+// [ExportAsync].  You can call Async safely from multiple goroutines, but it
+// needs a live environment.
+func (s *AsyncShim) Async(e Env) (*Async, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.async == nil {
+		a, err := e.NewAsync(s.prefix + "--dispatch")
+		if err != nil {
+			return nil, err
+		}
+		s.async = a
+	}
+	return s.async, nil
+}
+
+func (s *AsyncShim) define(e Env) error {
+	if _, err := e.Eval(asyncProgressForm(s.prefix + "--progress")); err != nil {
+		return err
+	}
+	form, err := e.Call("read", String(strings.ReplaceAll(asyncShimSource, "PREFIX", string(s.prefix))))
+	if err != nil {
+		return err
+	}
+	if _, err := e.Eval(form); err != nil {
+		return err
+	}
+	_, err = e.ExportFunc(s.prefix+"-cancel", func(e Env, args []Value) (Value, error) {
+		var h AsyncHandle
+		if err := h.FromEmacs(e, args[0]); err != nil {
+			return Value{}, err
+		}
+		s.mu.Lock()
+		a := s.async
+		s.mu.Unlock()
+		return Bool(a != nil && a.Cancel(h)).Emacs(e)
+	}, Arity{1, 1}, "Cancel the asynchronous operation with the given HANDLE.\nReturn whether the operation was still pending.\n\n(fn HANDLE)")
+	return err
+}
+
+// asyncShimSource is the Lisp source code of the shim.  PREFIX is replaced
+// by the actual prefix.
+const asyncShimSource = `(progn
+  (defvar PREFIX--promises (make-hash-table :test #'eql)
+    "Asynchronous operations, keyed by handle.
+Values are vectors [CALLBACK PARTIAL RESULT].  RESULT is a cons
+(VALUE . ERROR) if the operation has finished before a callback
+was registered.")
+
+  (defun PREFIX--dispatch (data)
+    "Dispatch the asynchronous result DATA to its callbacks."
+    (PREFIX--progress data)
+    (let* ((handle (nth 0 data))
+           (kind (nth 3 data))
+           (entry (gethash handle PREFIX--promises)))
+      (cond
+       ((eq kind 'progress))
+       ((eq kind 'partial)
+        (when (and entry (aref entry 1))
+          (funcall (aref entry 1) (nth 1 data))))
+       ((and entry (aref entry 0))
+        (remhash handle PREFIX--promises)
+        (funcall (aref entry 0) (nth 1 data) (nth 2 data)))
+       (t
+        (puthash handle (vector nil nil (cons (nth 1 data) (nth 2 data)))
+                 PREFIX--promises)))))
+
+  (defun PREFIX-then (handle callback &optional partial)
+    "Call CALLBACK once the asynchronous operation HANDLE has finished.
+CALLBACK receives two arguments, the value and the error of the
+operation.  The error is either nil or a cons (SYMBOL . DATA).
+If PARTIAL is non-nil, call it with each intermediate value of
+the operation.  Return HANDLE."
+    (let* ((entry (gethash handle PREFIX--promises))
+           (result (and entry (aref entry 2))))
+      (if result
+          (progn
+            (remhash handle PREFIX--promises)
+            (funcall callback (car result) (cdr result)))
+        (puthash handle (vector callback partial nil) PREFIX--promises)))
+    handle)
+
+  (defun PREFIX-wait (handle &optional timeout)
+    "Wait for the asynchronous operation HANDLE and return its value.
+Signal the error of the operation if it has failed.  If TIMEOUT
+is non-nil, signal an error after waiting for TIMEOUT seconds."
+    (let ((deadline (and timeout (+ (float-time) timeout)))
+          (result nil))
+      (PREFIX-then handle (lambda (value err) (setq result (cons value err))))
+      (while (not result)
+        (when (and deadline (> (float-time) deadline))
+          (error "Timed out waiting for asynchronous operation %d" handle))
+        (accept-process-output nil 0.05))
+      (when (cdr result)
+        (signal (car (cdr result)) (cdr (cdr result))))
+      (car result)))
+
+  (defun PREFIX-aio (handle)
+    "Return an aio promise for the asynchronous operation HANDLE."
+    (require 'aio)
+    (let ((promise (aio-promise)))
+      (PREFIX-then
+       handle
+       (lambda (value err)
+         (aio-resolve promise
+                      (if err
+                          (lambda () (signal (car err) (cdr err)))
+                        (lambda () value)))))
+      promise)))`
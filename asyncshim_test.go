@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var testAsyncShim = NewAsyncShim("go--test-async-shim")
+
+func init() {
+	ERTTest(asyncShimWait, Requires{OpenChannel})
+	ERTTest(asyncShimThen, Requires{OpenChannel})
+	ERTTest(asyncShimCancel, Requires{OpenChannel})
+}
+
+func asyncShimWait(e Env) error {
+	a, err := testAsyncShim.Async(e)
+	if err != nil {
+		return err
+	}
+	h, ch := a.Start()
+	go func() { ch <- Result{Value: Int(42)} }()
+	var got Int
+	if err := e.CallOut("go--test-async-shim-wait", &got, h, Float(10)); err != nil {
+		return err
+	}
+	if got != 42 {
+		return fmt.Errorf("go--test-async-shim-wait: got %d, want 42", got)
+	}
+	h, ch = a.Start()
+	go func() { ch <- Result{Err: errors.New("failure")} }()
+	if _, err := e.Call("go--test-async-shim-wait", h, Float(10)); err == nil {
+		return errors.New("go--test-async-shim-wait succeeded for failing operation")
+	}
+	return nil
+}
+
+func asyncShimThen(e Env) error {
+	a, err := testAsyncShim.Async(e)
+	if err != nil {
+		return err
+	}
+	h, ch := a.Start()
+	ch <- Result{Value: String("early")}
+	// Receive the result before registering the callback to check that
+	// the shim stores it.
+	for i := 0; i < 10; i++ {
+		if _, err := e.Call("accept-process-output", Nil, Float(0.1)); err != nil {
+			return err
+		}
+	}
+	var got String
+	callback, del, err := e.Lambda(func(v String, err Value) { got = v })
+	if err != nil {
+		return err
+	}
+	defer del()
+	if _, err := e.Call("go--test-async-shim-then", h, callback); err != nil {
+		return err
+	}
+	if got != "early" {
+		return fmt.Errorf("callback received %q, want %q", got, "early")
+	}
+	return nil
+}
+
+func asyncShimCancel(e Env) error {
+	a, err := testAsyncShim.Async(e)
+	if err != nil {
+		return err
+	}
+	_, h, ch := a.StartContext(nil)
+	var pending Bool
+	if err := e.CallOut("go--test-async-shim-cancel", &pending, h); err != nil {
+		return err
+	}
+	if !pending {
+		return errors.New("go--test-async-shim-cancel: operation wasn’t pending")
+	}
+	close(ch)
+	_, err = e.Call("go--test-async-shim-wait", h, Float(10))
+	var sig Signal
+	if !errors.As(err, &sig) || !e.hasCondition(sig, "go-async-canceled") {
+		return fmt.Errorf("go--test-async-shim-wait: got error %v, want go-async-canceled", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"context"
+	"sync"
+)
+
+// AsyncStream is the sending side of a streaming asynchronous operation
+// created by [Async.StartStream].  A streaming operation can send any number
+// of intermediate values, such as chunks of output, table rows, or log lines,
+// before finishing with a final result.  [Async.Flush] returns the
+// intermediate values as [AsyncData] values whose Partial field is set, in
+// the order in which they were sent.  You can call the methods of an
+// AsyncStream from any goroutine, but not concurrently.
+type AsyncStream struct {
+	ch   chan<- Result
+	op   *asyncOp
+	once sync.Once
+}
+
+// StartStream starts a new streaming asynchronous operation.  It returns a
+// context as described in [Async.StartContext], a handle for the operation,
+// and a stream to send the results to.  The typical usage pattern is:
+//
+//	func operation() AsyncHandle {
+//	    ctx, h, s := async.StartStream(nil)
+//	    go func() {
+//	        for line := range lines(ctx) {
+//	            if err := s.Send(String(line)); err != nil {
+//	                return  // canceled
+//	            }
+//	        }
+//	        s.Finish(Result{Value: Nil})
+//	    }()
+//	    return h
+//	}
+func (a *Async) StartStream(parent context.Context) (context.Context, AsyncHandle, *AsyncStream) {
+	ch := make(chan Result)
+	ctx, h, op := a.start(parent, ch)
+	return ctx, h, &AsyncStream{ch: ch, op: op}
+}
+
+// Send sends an intermediate value.  It blocks until the value is queued for
+// [Async.Flush].  Send returns an error if Emacs has canceled the operation
+// using [Async.Cancel] or if [AsyncStream.Finish] has already been called;
+// in the former case, the error signals go-async-canceled.
+func (s *AsyncStream) Send(v In) error {
+	return s.op.update(AsyncData{Result: Result{Value: v}, Partial: true})
+}
+
+// Progress publishes progress information like [Async.Progress].  It returns
+// the same errors as [AsyncStream.Send].
+func (s *AsyncStream) Progress(p AsyncProgress) error {
+	return s.op.update(AsyncData{Result: Result{Value: p}, Progress: true})
+}
+
+// Finish sends the final result and ends the operation.  Calls after the
+// first one have no effect.  If Emacs has canceled the operation, Finish
+// discards r.
+func (s *AsyncStream) Finish(r Result) {
+	s.once.Do(func() { s.ch <- r })
+}
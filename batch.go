@@ -0,0 +1,235 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// #include "emacs-module.h"
+// #include "wrappers.h"
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// Batch records a sequence of operations that [Env.Batch] later executes with
+// a single call into Emacs.  Each call from Go into Emacs has a noticeable
+// overhead, which dominates the runtime if a module creates large data
+// structures element by element.  Batch avoids this overhead.  Each recording
+// method returns a [BatchRef] that refers to the result of the operation; pass
+// it to later operations.  For example, the following code creates a list of
+// conses:
+//
+//	var b emacs.Batch
+//	list := b.Symbol("nil")
+//	for i := 0; i < 1000; i++ {
+//		elem := b.Call("cons", b.Int(int64(i)), b.String(names[i]))
+//		list = b.Call("cons", elem, list)
+//	}
+//	vals, err := e.Batch(&b)
+//	if err != nil {
+//		return err
+//	}
+//	result := vals[list]
+//
+// The zero Batch is empty and ready to use.  Recording methods never fail;
+// if an argument is invalid, [Env.Batch] returns an error without executing
+// any operation.
+type Batch struct {
+	ops     []C.struct_phst_emacs_batch_op
+	strs    []byte
+	args    []C.int64_t
+	syms    map[Symbol]BatchRef
+	maxArgs int
+	hasCR   bool
+	err     error
+}
+
+// BatchRef refers to the result of an operation recorded in a [Batch].  It’s
+// an index into the slice returned by [Env.Batch].
+type BatchRef int
+
+// Value records an operation that returns v.  Use it to pass values that
+// already exist to later operations.
+func (b *Batch) Value(v Value) BatchRef {
+	return b.add(C.struct_phst_emacs_batch_op{kind: C.phst_emacs_batch_value, value: v.raw()})
+}
+
+// Int records an operation that creates an Emacs integer.
+func (b *Batch) Int(i int64) BatchRef {
+	return b.add(C.struct_phst_emacs_batch_op{kind: C.phst_emacs_batch_integer, integer: C.int64_t(i)})
+}
+
+// Float records an operation that creates an Emacs floating-point number.
+func (b *Batch) Float(f float64) BatchRef {
+	return b.add(C.struct_phst_emacs_batch_op{kind: C.phst_emacs_batch_float, floating: C.double(f)})
+}
+
+// String records an operation that creates an Emacs string.  s must be a valid
+// UTF-8 string.
+func (b *Batch) String(s string) BatchRef {
+	if !utf8.ValidString(s) {
+		b.fail(WrongTypeArgument("valid-string-p", String(fmt.Sprintf("%+q", s))))
+	}
+	if strings.ContainsRune(s, '\r') {
+		b.hasCR = true
+	}
+	offset := b.addString(s)
+	return b.add(C.struct_phst_emacs_batch_op{kind: C.phst_emacs_batch_string, offset: offset, length: C.int64_t(len(s))})
+}
+
+// Symbol records an operation that interns s in the default obarray.  The
+// batch interns each symbol only once, and reuses symbols cached by
+// [Env.Intern].
+func (b *Batch) Symbol(s Symbol) BatchRef {
+	if r, ok := b.syms[s]; ok {
+		return r
+	}
+	var r BatchRef
+	if isNonNullASCII(string(s)) {
+		offset := b.addString(string(s))
+		r = b.add(C.struct_phst_emacs_batch_op{kind: C.phst_emacs_batch_intern, offset: offset, length: C.int64_t(len(s))})
+	} else {
+		r = b.Call("intern", b.String(string(s)))
+	}
+	if b.syms == nil {
+		b.syms = make(map[Symbol]BatchRef)
+	}
+	b.syms[s] = r
+	return r
+}
+
+// Call records an operation that calls the Emacs function named fun with the
+// given arguments.
+func (b *Batch) Call(fun Name, args ...BatchRef) BatchRef {
+	return b.Funcall(b.Symbol(Symbol(fun)), args...)
+}
+
+// Funcall records an operation that calls the function fun with the given
+// arguments.
+func (b *Batch) Funcall(fun BatchRef, args ...BatchRef) BatchRef {
+	b.check(fun)
+	offset := C.int64_t(len(b.args))
+	for _, a := range args {
+		b.check(a)
+		b.args = append(b.args, C.int64_t(a))
+	}
+	if len(args) > b.maxArgs {
+		b.maxArgs = len(args)
+	}
+	return b.add(C.struct_phst_emacs_batch_op{kind: C.phst_emacs_batch_funcall, offset: offset, length: C.int64_t(len(args)), function: C.int64_t(fun)})
+}
+
+// Len returns the number of recorded operations.
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// Reset removes all recorded operations, so that b can be reused.  It keeps
+// the allocated memory.
+func (b *Batch) Reset() {
+	b.ops = b.ops[:0]
+	b.strs = b.strs[:0]
+	b.args = b.args[:0]
+	b.syms = nil
+	b.maxArgs = 0
+	b.hasCR = false
+	b.err = nil
+}
+
+func (b *Batch) add(op C.struct_phst_emacs_batch_op) BatchRef {
+	b.ops = append(b.ops, op)
+	return BatchRef(len(b.ops) - 1)
+}
+
+// addString appends s followed by a null byte to the string buffer and
+// returns its offset.
+func (b *Batch) addString(s string) C.int64_t {
+	offset := C.int64_t(len(b.strs))
+	b.strs = append(b.strs, s...)
+	b.strs = append(b.strs, 0)
+	return offset
+}
+
+// check records an error if r doesn’t refer to an operation recorded so far.
+func (b *Batch) check(r BatchRef) {
+	if r < 0 || int(r) >= len(b.ops) {
+		b.fail(fmt.Errorf("invalid batch reference %d", r))
+	}
+}
+
+func (b *Batch) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Batch executes the operations recorded in b in sequence.  It returns the
+// results of all operations, indexed by [BatchRef].  If an operation exits
+// nonlocally, Batch stops and returns the error.  Batch doesn’t modify b, so
+// you can execute the same batch several times.
+func (e Env) Batch(b *Batch) ([]Value, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	n := len(b.ops)
+	if n == 0 {
+		return nil, nil
+	}
+	ops := make([]C.struct_phst_emacs_batch_op, n)
+	copy(ops, b.ops)
+	for s, r := range b.syms {
+		if op := &ops[r]; op.kind == C.phst_emacs_batch_intern {
+			if v, ok := symbols.get(s); ok {
+				*op = C.struct_phst_emacs_batch_op{kind: C.phst_emacs_batch_value, value: v.raw()}
+			}
+		}
+	}
+	results := make([]C.emacs_value, n)
+	scratch := make([]C.emacs_value, b.maxArgs+1)
+	var strs *C.char
+	if len(b.strs) > 0 {
+		strs = (*C.char)(unsafe.Pointer(&b.strs[0]))
+	}
+	var args *C.int64_t
+	if len(b.args) > 0 {
+		args = &b.args[0]
+	}
+	exec := func() (Value, error) {
+		r := C.phst_emacs_batch(e.raw(), &ops[0], C.int64_t(n), strs, args, &scratch[0], &results[0])
+		return Value{}, e.check(r.base)
+	}
+	var err error
+	if b.hasCR {
+		// See [String.Emacs].
+		_, err = e.Let("inhibit-eol-conversion", T, exec)
+	} else {
+		_, err = exec()
+	}
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]Value, n)
+	for i, v := range results {
+		vals[i] = e.value(v)
+	}
+	for s, r := range b.syms {
+		if ops[r].kind == C.phst_emacs_batch_intern {
+			symbols.add(e, s, vals[r])
+		}
+	}
+	return vals, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	ERTTest(batchList)
+}
+
+func batchList(e Env) error {
+	var b Batch
+	list := b.Symbol("nil")
+	for i := 3; i > 0; i-- {
+		elem := b.Call("cons", b.Int(int64(i)), b.String(fmt.Sprint("x\r", i)))
+		list = b.Call("cons", elem, list)
+	}
+	list = b.Call("vconcat", list, b.Call("list", b.Float(0.5), b.Symbol("émacs")))
+	vals, err := e.Batch(&b)
+	if err != nil {
+		return err
+	}
+	var got String
+	if err := e.CallOut("prin1-to-string", &got, vals[list]); err != nil {
+		return err
+	}
+	if want := `[(1 . "x\r1") (2 . "x\r2") (3 . "x\r3") 0.5 émacs]`; string(got) != want {
+		return fmt.Errorf("got %s, want %s", got, want)
+	}
+	b.Reset()
+	b.Call("car", b.Int(1))
+	if _, err := e.Batch(&b); !e.IsWrongTypeArgument(err) {
+		return fmt.Errorf("got error %v, want wrong-type-argument", err)
+	}
+	return nil
+}
+
+func TestBatchInvalid(t *testing.T) {
+	var b Batch
+	b.Call("list", BatchRef(5))
+	if _, err := (Env{}).Batch(&b); err == nil {
+		t.Error("got no error for invalid reference")
+	}
+	b.Reset()
+	b.String("\xff")
+	if _, err := (Env{}).Batch(&b); err == nil {
+		t.Error("got no error for invalid UTF-8 string")
+	}
+	b.Reset()
+	if a, c := b.Symbol("foo"), b.Symbol("foo"); a != c || b.Len() != 1 {
+		t.Errorf("interning foo twice: got references %d and %d and %d operations, want one operation", a, c, b.Len())
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// ERTBenchmarkFunc is a function that implements a benchmark.  It should run
+// the benchmarked operation n times.  Use [ERTBenchmark] to register
+// ERTBenchmarkFunc functions.
+type ERTBenchmarkFunc func(e Env, n int) error
+
+// ERTBenchmark arranges for a Go function to be exported as a benchmark.
+// Call ERTBenchmark in an init function.  Loading the dynamic module will
+// then define an ERT test that runs the benchmark using [Env.Benchmark] and
+// prints the result using message, in a format similar to “go test -bench”:
+//
+//	my-benchmark  123456  812 ns/op  3.00 crossings/op
+//
+// Crossings are calls from Go into Emacs, which are relatively expensive.
+// Tracking their number over time helps to find unnecessary conversions.
+//
+// The ERT test name is derived from the function name as for [ERTTest], and
+// you can pass the same options as for ERTTest.  The ERT test always has the
+// tag go-benchmark, so that you can select or exclude benchmarks using ERT
+// test selectors such as (tag go-benchmark) or (not (tag go-benchmark)).  By
+// default, each benchmark runs for about a second; pass an [ERTBenchTime]
+// option to change that.
+//
+// You can call ERTBenchmark safely from multiple goroutines.
+func ERTBenchmark(fun ERTBenchmarkFunc, opts ...Option) {
+	d, _ := autoFunc(fun, opts)
+	ertTests.MustEnqueue(d.name, benchmarkTest(d, fun))
+}
+
+// ERTBenchmark exports a Go function as a benchmark.  Unlike the global
+// [ERTBenchmark] function, Env.ERTBenchmark requires a live environment and
+// defines the ERT test immediately.
+func (e Env) ERTBenchmark(fun ERTBenchmarkFunc, opts ...Option) error {
+	d, _ := autoFunc(fun, opts)
+	return ertTests.RegisterAndDefine(e, d.name, benchmarkTest(d, fun))
+}
+
+// ERTBenchTime is an [Option] for [ERTBenchmark] that sets the approximate
+// duration of a benchmark run.  The default is one second.
+type ERTBenchTime time.Duration
+
+func (t ERTBenchTime) apply(o *exportAuto) { o.benchTime = time.Duration(t) }
+
+func benchmarkTest(d exportAuto, fun ERTBenchmarkFunc) ertTest {
+	name := d.name
+	benchTime := d.benchTime
+	if benchTime <= 0 {
+		benchTime = time.Second
+	}
+	call := func(e Env, _ []Value) (Value, error) {
+		r, err := e.Benchmark(fun, benchTime)
+		if err != nil {
+			return Value{}, err
+		}
+		return e.Call("message", String("%s  %s"), String(name), String(r.String()))
+	}
+	tags := append(ERTTags{"go-benchmark"}, d.ertTags...)
+	return ertTest{name, call, d.doc, d.requires, tags, d.ertExpected, d.ertFixtures}
+}
+
+// BenchmarkResult contains the result of a benchmark run.
+type BenchmarkResult struct {
+	// N is the number of iterations.
+	N int
+
+	// T is the total time taken.
+	T time.Duration
+
+	// Crossings is the total number of calls from Go into Emacs.
+	Crossings uint64
+}
+
+// NsPerOp returns the average time per iteration in nanoseconds.
+func (r BenchmarkResult) NsPerOp() int64 {
+	if r.N <= 0 {
+		return 0
+	}
+	return r.T.Nanoseconds() / int64(r.N)
+}
+
+// CrossingsPerOp returns the average number of calls from Go into Emacs per
+// iteration.
+func (r BenchmarkResult) CrossingsPerOp() float64 {
+	if r.N <= 0 {
+		return 0
+	}
+	return float64(r.Crossings) / float64(r.N)
+}
+
+// String formats the result like “123456  812 ns/op  3.00 crossings/op”.
+func (r BenchmarkResult) String() string {
+	return fmt.Sprintf("%d  %d ns/op  %.2f crossings/op", r.N, r.NsPerOp(), r.CrossingsPerOp())
+}
+
+// Emacs returns a property list with the keys :n, :ns-per-op, and
+// :crossings-per-op.
+func (r BenchmarkResult) Emacs(e Env) (Value, error) {
+	return List{
+		Symbol(":n"), Int(r.N),
+		Symbol(":ns-per-op"), Int(r.NsPerOp()),
+		Symbol(":crossings-per-op"), Float(r.CrossingsPerOp()),
+	}.Emacs(e)
+}
+
+// Benchmark runs fun repeatedly with increasing iteration counts, like the
+// Go testing package does, until a run takes at least benchTime.  It returns
+// the result of the last run.  If fun returns an error, Benchmark returns it
+// immediately.
+func (e Env) Benchmark(fun ERTBenchmarkFunc, benchTime time.Duration) (BenchmarkResult, error) {
+	n := 1
+	for {
+		r, err := e.benchmarkRun(fun, n)
+		if err != nil || r.T >= benchTime || n >= maxBenchmarkN {
+			return r, err
+		}
+		n = nextBenchmarkN(n, r.T, benchTime)
+	}
+}
+
+// benchmarkRun runs fun once with n iterations.
+func (e Env) benchmarkRun(fun ERTBenchmarkFunc, n int) (BenchmarkResult, error) {
+	runtime.GC()
+	atomic.AddInt32(&countCrossings, 1)
+	defer atomic.AddInt32(&countCrossings, -1)
+	c := atomic.LoadUint64(&crossings)
+	start := time.Now()
+	err := fun(e, n)
+	d := time.Since(start)
+	return BenchmarkResult{n, d, atomic.LoadUint64(&crossings) - c}, err
+}
+
+// nextBenchmarkN predicts the number of iterations required to reach
+// benchTime, given that n iterations took d.  Like the Go testing package, it
+// overshoots by 20 percent, grows by at least one and at most a factor of
+// 100.
+func nextBenchmarkN(n int, d, benchTime time.Duration) int {
+	prev := int64(n)
+	var next int64
+	if d > 0 {
+		next = int64(float64(benchTime) * float64(prev) / float64(d))
+	} else {
+		next = 100 * prev
+	}
+	next += next / 5
+	if next > 100*prev {
+		next = 100 * prev
+	}
+	if next < prev+1 {
+		next = prev + 1
+	}
+	if next > maxBenchmarkN {
+		next = maxBenchmarkN
+	}
+	return int(next)
+}
+
+const maxBenchmarkN = 1e9
@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func init() {
+	ERTBenchmark(benchmarkIdentity, ERTBenchTime(10*time.Millisecond))
+	ERTTest(benchmarkCrossings)
+}
+
+func benchmarkIdentity(e Env, n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := e.Call("identity", Int(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func benchmarkCrossings(e Env) error {
+	r, err := e.Benchmark(benchmarkIdentity, time.Millisecond)
+	if err != nil {
+		return err
+	}
+	if r.N <= 0 {
+		return fmt.Errorf("benchmark ran %d iterations", r.N)
+	}
+	// Each iteration creates an integer and calls a function.
+	if got := r.CrossingsPerOp(); got < 2 {
+		return fmt.Errorf("got %.2f crossings per operation, want at least 2", got)
+	}
+	return nil
+}
+
+func TestNextBenchmarkN(t *testing.T) {
+	for _, tc := range []struct {
+		n         int
+		d, target time.Duration
+		want      int
+	}{
+		{1, time.Millisecond, time.Second, 100},
+		{100, 100 * time.Millisecond, time.Second, 1200},
+		{1000, 0, time.Second, 100000},
+		{10, time.Second, time.Millisecond, 11},
+		{maxBenchmarkN / 2, time.Millisecond, time.Second, maxBenchmarkN},
+	} {
+		if got := nextBenchmarkN(tc.n, tc.d, tc.target); got != tc.want {
+			t.Errorf("nextBenchmarkN(%d, %s, %s) = %d, want %d", tc.n, tc.d, tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestBenchmarkResult(t *testing.T) {
+	r := BenchmarkResult{N: 4, T: 400 * time.Nanosecond, Crossings: 10}
+	if got, want := r.String(), "4  100 ns/op  2.50 crossings/op"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// BigFloat is a type with underlying type [big.Float] that knows how to
+// convert itself to and from an Emacs value.  Emacs doesn’t have
+// arbitrary-precision floating-point numbers, so converting a BigFloat to
+// Emacs rounds it to the nearest floating-point number.
+type BigFloat big.Float
+
+// String formats the number as a string.  It calls big.Float.String.
+func (f *BigFloat) String() string { return (*big.Float)(f).String() }
+
+// Emacs creates an Emacs floating-point number that’s closest to f.  It
+// returns an error of type overflow-error if f is finite, but its magnitude is
+// too large for a floating-point number.
+func (f *BigFloat) Emacs(e Env) (Value, error) {
+	b := (*big.Float)(f)
+	g, _ := b.Float64()
+	if math.IsInf(g, 0) && !b.IsInf() {
+		return Value{}, OverflowError(b.Text('g', 10))
+	}
+	return Float(g).Emacs(e)
+}
+
+// FromEmacs sets *f to the number stored in v, which can be an integer or a
+// floating-point number.  See [Env.BigFloat].
+func (f *BigFloat) FromEmacs(e Env, v Value) error {
+	return e.BigFloat(v, (*big.Float)(f))
+}
+
+// BigFloat sets z to the number stored in v.  v can be an integer or a
+// floating-point number; the conversion is exact unless z has a nonzero
+// precision that’s too small.  BigFloat returns an error if v is not a
+// number, or if it’s a NaN.
+func (e Env) BigFloat(v Value, z *big.Float) error {
+	var isInt Bool
+	if err := e.CallOut("integerp", &isInt, v); err != nil {
+		return err
+	}
+	if isInt {
+		var i big.Int
+		if err := e.BigInt(v, &i); err != nil {
+			return err
+		}
+		z.SetInt(&i)
+		return nil
+	}
+	g, err := e.Float(v)
+	if err != nil {
+		return err
+	}
+	if math.IsNaN(g) {
+		return DomainError("NaN")
+	}
+	z.SetFloat64(g)
+	return nil
+}
+
+// BigRat is a type with underlying type [big.Rat] that knows how to convert
+// itself to and from an Emacs value.  Emacs doesn’t have a rational number
+// type; BigRat uses the representation of Calc, the Emacs calculator: an
+// integer if the denominator is 1, and otherwise a list (frac NUM DEN).
+type BigRat big.Rat
+
+// String formats the rational number as a string.  It calls big.Rat.String.
+func (r *BigRat) String() string { return (*big.Rat)(r).String() }
+
+// Emacs creates an integer or a list (frac NUM DEN) representing r.  It
+// returns an error if the numerator or denominator is too big for Emacs.
+func (r *BigRat) Emacs(e Env) (Value, error) {
+	b := (*big.Rat)(r)
+	if b.IsInt() {
+		return (*BigInt)(b.Num()).Emacs(e)
+	}
+	return List{Symbol("frac"), (*BigInt)(b.Num()), (*BigInt)(b.Denom())}.Emacs(e)
+}
+
+// FromEmacs sets *r to the number stored in v.  See [Env.BigRat].
+func (r *BigRat) FromEmacs(e Env, v Value) error {
+	return e.BigRat(v, (*big.Rat)(r))
+}
+
+// BigRat sets z to the rational number stored in v.  v can be an integer, a
+// finite floating-point number, or a list (frac NUM DEN) as used by Calc,
+// where NUM and DEN are integers and DEN is nonzero.  The conversion is
+// always exact.  BigRat returns an error if v isn’t one of these.
+func (e Env) BigRat(v Value, z *big.Rat) error {
+	var isInt, isFloat Bool
+	if err := e.CallOut("integerp", &isInt, v); err != nil {
+		return err
+	}
+	if isInt {
+		var i big.Int
+		if err := e.BigInt(v, &i); err != nil {
+			return err
+		}
+		z.SetInt(&i)
+		return nil
+	}
+	if err := e.CallOut("floatp", &isFloat, v); err != nil {
+		return err
+	}
+	if isFloat {
+		g, err := e.Float(v)
+		if err != nil {
+			return err
+		}
+		if z.SetFloat64(g) == nil {
+			return DomainError(strconv.FormatFloat(g, 'g', -1, 64))
+		}
+		return nil
+	}
+	var elems []Value
+	if err := e.Dolist(v, func(u Value) error {
+		elems = append(elems, u)
+		return nil
+	}); err != nil || len(elems) != 3 {
+		return WrongTypeArgument("go-rational-p", v)
+	}
+	if s, err := e.Symbol(elems[0]); err != nil || s != "frac" {
+		return WrongTypeArgument("go-rational-p", v)
+	}
+	var num, den big.Int
+	if err := e.BigInt(elems[1], &num); err != nil {
+		return err
+	}
+	if err := e.BigInt(elems[2], &den); err != nil {
+		return err
+	}
+	if den.Sign() == 0 {
+		return DomainError("zero denominator")
+	}
+	z.SetFrac(&num, &den)
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+func init() {
+	ERTTest(bigFloatConversion)
+	ERTTest(bigRatConversion)
+}
+
+func bigFloatConversion(e Env) error {
+	v, err := (*BigFloat)(big.NewFloat(0.25)).Emacs(e)
+	if err != nil {
+		return err
+	}
+	var f Float
+	if err := f.FromEmacs(e, v); err != nil {
+		return err
+	}
+	if f != 0.25 {
+		return fmt.Errorf("got %v, want 0.25", f)
+	}
+	huge := new(big.Float).SetMantExp(big.NewFloat(1), 2000)
+	if _, err := (*BigFloat)(huge).Emacs(e); !e.IsOverflowError(err) {
+		return fmt.Errorf("converting %s: got error %v, want overflow-error", huge, err)
+	}
+	i, err := (*BigInt)(new(big.Int).Lsh(big.NewInt(1), 100)).Emacs(e)
+	if err != nil {
+		return err
+	}
+	var z big.Float
+	if err := e.BigFloat(i, &z); err != nil {
+		return err
+	}
+	if want := new(big.Float).SetMantExp(big.NewFloat(1), 100); z.Cmp(want) != 0 {
+		return fmt.Errorf("converting 2^100: got %s, want %s", &z, want)
+	}
+	nan, err := Float(math.NaN()).Emacs(e)
+	if err != nil {
+		return err
+	}
+	if err := e.BigFloat(nan, &z); !e.IsDomainError(err) {
+		return fmt.Errorf("converting NaN: got error %v, want domain-error", err)
+	}
+	return nil
+}
+
+func bigRatConversion(e Env) error {
+	for _, tc := range []struct {
+		in   *big.Rat
+		want string
+	}{
+		{big.NewRat(3, 1), "3"},
+		{big.NewRat(-1, 3), "(frac -1 3)"},
+		{new(big.Rat).SetFrac(new(big.Int).Lsh(big.NewInt(1), 80), big.NewInt(7)), "(frac 1208925819614629174706176 7)"},
+	} {
+		v, err := e.Emacs(tc.in)
+		if err != nil {
+			return err
+		}
+		var s String
+		if err := e.CallOut("prin1-to-string", &s, v); err != nil {
+			return err
+		}
+		if string(s) != tc.want {
+			return fmt.Errorf("converting %s: got %s, want %s", tc.in, s, tc.want)
+		}
+		got := new(big.Rat)
+		if err := e.Go(v, got); err != nil {
+			return err
+		}
+		if got.Cmp(tc.in) != 0 {
+			return fmt.Errorf("roundtrip: got %s, want %s", got, tc.in)
+		}
+	}
+	v, err := Float(0.75).Emacs(e)
+	if err != nil {
+		return err
+	}
+	var z big.Rat
+	if err := e.BigRat(v, &z); err != nil {
+		return err
+	}
+	if z.Cmp(big.NewRat(3, 4)) != 0 {
+		return fmt.Errorf("converting 0.75: got %s, want 3/4", &z)
+	}
+	zero, err := e.Eval(List{Symbol("quote"), List{Symbol("frac"), Int(1), Int(0)}})
+	if err != nil {
+		return err
+	}
+	if err := e.BigRat(zero, &z); !e.IsDomainError(err) {
+		return fmt.Errorf("converting zero denominator: got error %v, want domain-error", err)
+	}
+	return nil
+}
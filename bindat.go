@@ -0,0 +1,504 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Bindat is an [In] that converts the Go struct Data to the alist that
+// represents unpacked bindat data, for example to pass it to bindat-pack
+// together with the spec returned by [BindatSpec].  Data must be a struct or
+// a pointer to a struct.  Padding fields are omitted.  See [Packing and
+// Unpacking Byte Arrays].
+//
+// The binary layout of a struct type is given by struct tags of the form
+// bindat:"TYPE", where TYPE is one of the following:
+//
+//   - u8, u16, u24, or u32 for unsigned big-endian integers
+//   - u16r, u24r, or u32r for unsigned little-endian integers
+//   - str LEN for a byte string of fixed length LEN
+//   - strz LEN for a null-terminated string in a field of fixed length LEN;
+//     the string can have at most LEN − 1 bytes
+//   - vec LEN ELEM for an array of LEN integers of type ELEM
+//
+// Fields without bindat tag use a default type if possible: u8, u16, or u32
+// for uint8, uint16, or uint32 fields, str N for [N]byte fields, and vec N
+// u16 or vec N u32 for [N]uint16 or [N]uint32 fields.  Blank fields of type
+// [N]byte are padding of N bytes.  Field names map to Lisp symbols as
+// described in [EIEIOOut].
+//
+// [Packing and Unpacking Byte Arrays]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Byte-Packing.html
+type Bindat struct {
+	Data interface{}
+}
+
+// Emacs returns a new alist that maps field names to values.
+func (b Bindat) Emacs(e Env) (Value, error) {
+	v, err := structValue(reflect.ValueOf(b.Data))
+	if err != nil {
+		return Value{}, err
+	}
+	items, err := bindatLayout(v.Type())
+	if err != nil {
+		return Value{}, err
+	}
+	var r List
+	for _, it := range items {
+		if it.field == nil {
+			continue
+		}
+		r = append(r, Cons{it.field.name, it.field.in(bindatField(v, it.field.index))})
+	}
+	return r.Emacs(e)
+}
+
+// BindatOut is an [Out] that converts an alist representing unpacked bindat
+// data, for example the return value of bindat-unpack, to the Go struct
+// that Data points to.  See [Bindat] for the layout rules.  Fields that don’t
+// appear in the alist keep their values.
+type BindatOut struct {
+	Data interface{}
+}
+
+// FromEmacs sets the fields of b.Data from the alist v.
+func (b BindatOut) FromEmacs(e Env, v Value) error {
+	p := reflect.ValueOf(b.Data)
+	if p.Kind() != reflect.Ptr || p.IsNil() || p.Elem().Kind() != reflect.Struct {
+		return WrongTypeArgument("go-struct-pointer-p", String(fmt.Sprintf("%T", b.Data)))
+	}
+	s := p.Elem()
+	items, err := bindatLayout(s.Type())
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		if it.field == nil {
+			continue
+		}
+		cell, err := e.Call("assq", it.field.name, v)
+		if err != nil {
+			return err
+		}
+		if e.IsNil(cell) {
+			continue
+		}
+		fp, err := fieldPointer(s, it.field.index)
+		if err != nil {
+			return err
+		}
+		if err := e.CdrOut(cell, it.field.out(fp)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BindatSpec returns the bindat specification for the struct type of
+// prototype, which must be a struct or a pointer to a struct.  The result is
+// a list of field specifications in the format accepted by bindat-pack and
+// bindat-unpack, for example ((type u8) (length u16r) (id strz 8)).  See
+// [Bindat] for the layout rules.  The spec describes the same layout that
+// [MarshalBindat] and [UnmarshalBindat] use.
+func BindatSpec(prototype interface{}) (List, error) {
+	v, err := structValue(reflect.ValueOf(prototype))
+	if err != nil {
+		return nil, err
+	}
+	items, err := bindatLayout(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	r := make(List, len(items))
+	for i, it := range items {
+		var spec List
+		if it.field != nil {
+			spec = append(spec, it.field.name)
+		}
+		spec = append(spec, Symbol(it.typ))
+		if it.typ != bindatFill && it.n == 0 {
+			r[i] = spec
+			continue
+		}
+		spec = append(spec, Int(it.n))
+		if it.elem != "" {
+			spec = append(spec, Symbol(it.elem))
+		}
+		r[i] = spec
+	}
+	return r, nil
+}
+
+// BindatPack packs the Go struct v using bindat-pack and the spec returned
+// by [BindatSpec].  It returns the same bytes as [MarshalBindat], but uses
+// Emacs to do the work.
+func (e Env) BindatPack(v interface{}) ([]byte, error) {
+	spec, err := BindatSpec(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireBindat(e); err != nil {
+		return nil, err
+	}
+	var r Bytes
+	err = e.CallOut("bindat-pack", &r, spec, Bindat{v})
+	return r, err
+}
+
+// BindatUnpack unpacks data into the Go struct that v points to using
+// bindat-unpack and the spec returned by [BindatSpec].
+func (e Env) BindatUnpack(data []byte, v interface{}) error {
+	spec, err := BindatSpec(v)
+	if err != nil {
+		return err
+	}
+	if err := requireBindat(e); err != nil {
+		return err
+	}
+	return e.CallOut("bindat-unpack", BindatOut{v}, spec, Bytes(data))
+}
+
+// bindatField returns the field of the struct v with the given index.  The
+// binary layout has no notion of omitted fields, so fields promoted through
+// a nil embedded pointer are represented by their zero value.
+func bindatField(v reflect.Value, index []int) reflect.Value {
+	f, ok := fieldValue(v, index)
+	if !ok {
+		return reflect.Zero(v.Type().FieldByIndex(index).Type)
+	}
+	return f
+}
+
+func requireBindat(e Env) error {
+	_, err := e.Call("require", Symbol("bindat"))
+	return err
+}
+
+// MarshalBindat returns the binary representation of the Go struct v, which
+// must be a struct or a pointer to a struct, without involving Emacs.  The
+// result is the same as packing v with [Env.BindatPack].
+func MarshalBindat(v interface{}) ([]byte, error) {
+	s, err := structValue(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	items, err := bindatLayout(s.Type())
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	for _, it := range items {
+		if it.field == nil {
+			b.Write(make([]byte, it.n))
+			continue
+		}
+		f := bindatField(s, it.field.index)
+		switch it.typ {
+		case bindatStr, bindatStrz:
+			var data []byte
+			if f.Kind() == reflect.String {
+				data = []byte(f.String())
+			} else {
+				data = make([]byte, f.Len())
+				for i := range data {
+					data[i] = byte(f.Index(i).Uint())
+				}
+			}
+			max := it.n
+			if it.typ == bindatStrz {
+				max--
+			}
+			if len(data) > max {
+				return nil, fmt.Errorf("field %s: string of length %d doesn’t fit into %s %d", it.field.name, len(data), it.typ, it.n)
+			}
+			b.Write(data)
+			b.Write(make([]byte, it.n-len(data)))
+		case bindatVec:
+			if f.Len() != it.n {
+				return nil, fmt.Errorf("field %s: got %d elements, want %d", it.field.name, f.Len(), it.n)
+			}
+			for i := 0; i < it.n; i++ {
+				if err := putBindatUint(&b, it.elem, f.Index(i).Uint()); err != nil {
+					return nil, fmt.Errorf("field %s: %w", it.field.name, err)
+				}
+			}
+		default:
+			if err := putBindatUint(&b, it.typ, f.Uint()); err != nil {
+				return nil, fmt.Errorf("field %s: %w", it.field.name, err)
+			}
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBindat parses the binary representation data into the Go struct
+// that v points to, without involving Emacs.  The result is the same as
+// unpacking data with [Env.BindatUnpack].  UnmarshalBindat ignores trailing
+// data.
+func UnmarshalBindat(data []byte, v interface{}) error {
+	p := reflect.ValueOf(v)
+	if p.Kind() != reflect.Ptr || p.IsNil() || p.Elem().Kind() != reflect.Struct {
+		return WrongTypeArgument("go-struct-pointer-p", String(fmt.Sprintf("%T", v)))
+	}
+	s := p.Elem()
+	items, err := bindatLayout(s.Type())
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		size := it.n
+		switch it.typ {
+		case bindatVec:
+			size *= bindatUintSize(it.elem)
+		case bindatStr, bindatStrz, bindatFill:
+		default:
+			size = bindatUintSize(it.typ)
+		}
+		if len(data) < size {
+			return errors.New("bindat data too short")
+		}
+		chunk := data[:size]
+		data = data[size:]
+		if it.field == nil {
+			continue
+		}
+		fp, err := fieldPointer(s, it.field.index)
+		if err != nil {
+			return err
+		}
+		f := fp.Elem()
+		switch it.typ {
+		case bindatStr, bindatStrz:
+			if it.typ == bindatStrz {
+				if i := bytes.IndexByte(chunk, 0); i >= 0 {
+					chunk = chunk[:i]
+				}
+			}
+			switch f.Kind() {
+			case reflect.String:
+				f.SetString(string(chunk))
+			case reflect.Slice:
+				f.SetBytes(append([]byte(nil), chunk...))
+			default:
+				reflect.Copy(f, reflect.ValueOf(chunk))
+			}
+		case bindatVec:
+			if f.Kind() == reflect.Slice {
+				f.Set(reflect.MakeSlice(f.Type(), it.n, it.n))
+			}
+			n := bindatUintSize(it.elem)
+			for i := 0; i < it.n; i++ {
+				f.Index(i).SetUint(getBindatUint(it.elem, chunk[i*n:(i+1)*n]))
+			}
+		default:
+			f.SetUint(getBindatUint(it.typ, chunk))
+		}
+	}
+	return nil
+}
+
+// Bindat type names.
+const (
+	bindatStr  = "str"
+	bindatStrz = "strz"
+	bindatVec  = "vec"
+	bindatFill = "fill"
+)
+
+// bindatItem describes one item in a bindat specification.
+type bindatItem struct {
+	field *structField // nil for padding
+	typ   string
+	n     int    // length for str, strz, vec, and fill
+	elem  string // element type for vec
+}
+
+// bindatLayout returns the bindat items for the struct type t.
+func bindatLayout(t reflect.Type) ([]bindatItem, error) {
+	fields, err := structFields(t)
+	if err != nil {
+		return nil, err
+	}
+	byIndex := make(map[string]*structField, len(fields))
+	for i := range fields {
+		byIndex[fmt.Sprint(fields[i].index)] = &fields[i]
+	}
+	var r []bindatItem
+	for _, f := range reflect.VisibleFields(t) {
+		if f.Name == "_" {
+			if f.Type.Kind() != reflect.Array || f.Type.Elem().Kind() != reflect.Uint8 {
+				return nil, fmt.Errorf("padding field of type %s must be a byte array", t)
+			}
+			r = append(r, bindatItem{typ: bindatFill, n: f.Type.Len()})
+			continue
+		}
+		sf := byIndex[fmt.Sprint(f.Index)]
+		if sf == nil {
+			continue
+		}
+		it, err := bindatType(f.Type, f.Tag.Get("bindat"))
+		if err != nil {
+			return nil, fmt.Errorf("field %s of type %s: %w", f.Name, t, err)
+		}
+		it.field = sf
+		r = append(r, it)
+	}
+	return r, nil
+}
+
+// bindatType parses the bindat struct tag for a field of type t.  If the tag
+// is empty, bindatType returns a default type.
+func bindatType(t reflect.Type, tag string) (bindatItem, error) {
+	words := strings.Fields(tag)
+	if len(words) == 0 {
+		switch {
+		case t.Kind() == reflect.Uint8:
+			return bindatItem{typ: "u8"}, nil
+		case t.Kind() == reflect.Uint16:
+			return bindatItem{typ: "u16"}, nil
+		case t.Kind() == reflect.Uint32:
+			return bindatItem{typ: "u32"}, nil
+		case t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8:
+			return bindatItem{typ: bindatStr, n: t.Len()}, nil
+		case t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint16:
+			return bindatItem{typ: bindatVec, n: t.Len(), elem: "u16"}, nil
+		case t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint32:
+			return bindatItem{typ: bindatVec, n: t.Len(), elem: "u32"}, nil
+		default:
+			return bindatItem{}, fmt.Errorf("no default bindat type for %s", t)
+		}
+	}
+	switch typ := words[0]; typ {
+	case bindatStr, bindatStrz:
+		if len(words) != 2 {
+			return bindatItem{}, fmt.Errorf("invalid bindat tag %q", tag)
+		}
+		n, err := strconv.Atoi(words[1])
+		if err != nil || n <= 0 {
+			return bindatItem{}, fmt.Errorf("invalid length in bindat tag %q", tag)
+		}
+		switch {
+		case t.Kind() == reflect.String, t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		case typ == bindatStr && t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8:
+			if t.Len() != n {
+				return bindatItem{}, fmt.Errorf("array of type %s doesn’t match bindat type str %d", t, n)
+			}
+		default:
+			return bindatItem{}, fmt.Errorf("bindat type %s requires a string or byte slice, not %s", typ, t)
+		}
+		return bindatItem{typ: typ, n: n}, nil
+	case bindatVec:
+		if len(words) != 3 {
+			return bindatItem{}, fmt.Errorf("invalid bindat tag %q", tag)
+		}
+		n, err := strconv.Atoi(words[1])
+		if err != nil || n <= 0 {
+			return bindatItem{}, fmt.Errorf("invalid length in bindat tag %q", tag)
+		}
+		elem := words[2]
+		if bindatUintSize(elem) == 0 {
+			return bindatItem{}, fmt.Errorf("invalid element type in bindat tag %q", tag)
+		}
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return bindatItem{}, fmt.Errorf("bindat type vec requires a slice or array, not %s", t)
+		}
+		if err := checkBindatUint(t.Elem(), elem); err != nil {
+			return bindatItem{}, err
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are represented as unibyte strings in Emacs,
+			// but bindat-unpack returns vectors.
+			return bindatItem{}, fmt.Errorf("bindat type vec requires elements wider than a byte, not %s", t)
+		}
+		if t.Kind() == reflect.Array && t.Len() != n {
+			return bindatItem{}, fmt.Errorf("array of type %s doesn’t match bindat type vec %d", t, n)
+		}
+		return bindatItem{typ: typ, n: n, elem: elem}, nil
+	default:
+		if len(words) != 1 || bindatUintSize(typ) == 0 {
+			return bindatItem{}, fmt.Errorf("invalid bindat tag %q", tag)
+		}
+		if err := checkBindatUint(t, typ); err != nil {
+			return bindatItem{}, err
+		}
+		return bindatItem{typ: typ}, nil
+	}
+}
+
+// checkBindatUint checks that the type t can hold values of the bindat
+// integer type typ.
+func checkBindatUint(t reflect.Type, typ string) error {
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if t.Bits() < 8*bindatUintSize(typ) {
+			return fmt.Errorf("type %s too small for bindat type %s", t, typ)
+		}
+		return nil
+	default:
+		return fmt.Errorf("bindat type %s requires an unsigned integer type, not %s", typ, t)
+	}
+}
+
+// bindatUintSize returns the size in bytes of the bindat integer type typ, or
+// zero if typ isn’t an integer type.
+func bindatUintSize(typ string) int {
+	switch typ {
+	case "u8":
+		return 1
+	case "u16", "u16r":
+		return 2
+	case "u24", "u24r":
+		return 3
+	case "u32", "u32r":
+		return 4
+	default:
+		return 0
+	}
+}
+
+func putBindatUint(b *bytes.Buffer, typ string, v uint64) error {
+	n := bindatUintSize(typ)
+	if v>>(8*n) != 0 {
+		return OverflowError(fmt.Sprint(v))
+	}
+	buf := make([]byte, n)
+	for i := range buf {
+		shift := 8 * (n - 1 - i)
+		if strings.HasSuffix(typ, "r") {
+			shift = 8 * i
+		}
+		buf[i] = byte(v >> shift)
+	}
+	b.Write(buf)
+	return nil
+}
+
+func getBindatUint(typ string, b []byte) uint64 {
+	var r uint64
+	n := len(b)
+	for i, c := range b {
+		shift := 8 * (n - 1 - i)
+		if strings.HasSuffix(typ, "r") {
+			shift = 8 * i
+		}
+		r |= uint64(c) << shift
+	}
+	return r
+}
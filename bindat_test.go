@@ -0,0 +1,138 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	ERTTest(bindat)
+}
+
+type bindatHeader struct {
+	Type   uint8
+	_      [1]byte
+	Length uint32  `bindat:"u16r"`
+	Offset uint32  `bindat:"u24"`
+	ID     string  `bindat:"strz 8"`
+	Magic  [2]byte `emacs:"magic-bytes"`
+	Ports  [2]uint16
+	Ignore int `emacs:"-"`
+}
+
+var (
+	bindatValue = bindatHeader{
+		Type:   7,
+		Length: 0x0102,
+		Offset: 0x030405,
+		ID:     "abc",
+		Magic:  [2]byte{'G', 'O'},
+		Ports:  [2]uint16{80, 443},
+	}
+	bindatData = []byte{
+		7, 0,
+		0x02, 0x01,
+		0x03, 0x04, 0x05,
+		'a', 'b', 'c', 0, 0, 0, 0, 0,
+		'G', 'O',
+		0, 80, 0x01, 0xBB,
+	}
+)
+
+func TestMarshalBindat(t *testing.T) {
+	got, err := MarshalBindat(bindatValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, bindatData) {
+		t.Errorf("MarshalBindat: got %v, want %v", got, bindatData)
+	}
+	var h bindatHeader
+	if err := UnmarshalBindat(bindatData, &h); err != nil {
+		t.Fatal(err)
+	}
+	if h != bindatValue {
+		t.Errorf("UnmarshalBindat: got %+v, want %+v", h, bindatValue)
+	}
+	if err := UnmarshalBindat(bindatData[:10], &h); err == nil {
+		t.Error("UnmarshalBindat: got no error for truncated data")
+	}
+}
+
+type bindatEmbedded struct {
+	Code uint8
+}
+
+type bindatWithEmbedded struct {
+	*bindatEmbedded
+	Flags uint8
+}
+
+func TestMarshalBindatNilEmbedded(t *testing.T) {
+	got, err := MarshalBindat(bindatWithEmbedded{Flags: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0, 3}; !bytes.Equal(got, want) {
+		t.Errorf("MarshalBindat: got %v, want %v", got, want)
+	}
+}
+
+func TestBindatSpec(t *testing.T) {
+	got, err := BindatSpec(bindatHeader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := List{
+		List{Symbol("type"), Symbol("u8")},
+		List{Symbol("fill"), Int(1)},
+		List{Symbol("length"), Symbol("u16r")},
+		List{Symbol("offset"), Symbol("u24")},
+		List{Symbol("id"), Symbol("strz"), Int(8)},
+		List{Symbol("magic-bytes"), Symbol("str"), Int(2)},
+		List{Symbol("ports"), Symbol("vec"), Int(2), Symbol("u16")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BindatSpec: got %v, want %v", got, want)
+	}
+	type invalid struct {
+		S string
+	}
+	if _, err := BindatSpec(invalid{}); err == nil {
+		t.Error("BindatSpec: got no error for string field without tag")
+	}
+}
+
+func bindat(e Env) error {
+	got, err := e.BindatPack(bindatValue)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(got, bindatData) {
+		return fmt.Errorf("BindatPack: got %v, want %v", got, bindatData)
+	}
+	var h bindatHeader
+	if err := e.BindatUnpack(bindatData, &h); err != nil {
+		return err
+	}
+	if h != bindatValue {
+		return fmt.Errorf("BindatUnpack: got %+v, want %+v", h, bindatValue)
+	}
+	return nil
+}
@@ -53,7 +53,7 @@ func (b *Bool) FromEmacs(e Env, v Value) error {
 
 // IsNotNil returns false if and only if the given Emacs value is nil.
 func (e Env) IsNotNil(v Value) bool {
-	return bool(C.phst_emacs_is_not_nil(e.raw(), v.r))
+	return bool(C.phst_emacs_is_not_nil(e.raw(), v.raw()))
 }
 
 // IsNil returns true if and only if the given Emacs value is nil.
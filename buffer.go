@@ -0,0 +1,139 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// BufferWriter returns a writer that appends the bytes written to it to the
+// given buffer.  The writer decodes the bytes as UTF-8; it replaces invalid
+// byte sequences with U+FFFD REPLACEMENT CHARACTER.  A multibyte sequence may
+// be split across several Write calls; the writer holds back incomplete
+// sequences until the rest arrives.  Writing inserts the text at the end of
+// the buffer without moving point, even if the buffer is read-only.  Because
+// writing calls into Emacs, you may only use the writer while e is live, and
+// only on the Emacs thread.
+func (e Env) BufferWriter(buffer Value) io.Writer {
+	return &bufferWriter{e: e, buffer: buffer}
+}
+
+type bufferWriter struct {
+	e       Env
+	buffer  Value
+	partial []byte // incomplete UTF-8 sequence from the last Write call
+}
+
+func (w *bufferWriter) Write(p []byte) (int, error) {
+	b := append(append([]byte(nil), w.partial...), p...)
+	n := len(b) - incompleteSuffix(b)
+	if n > 0 {
+		text := strings.ToValidUTF8(string(b[:n]), string(utf8.RuneError))
+		if err := w.e.insertAtEnd(w.buffer, text); err != nil {
+			return 0, err
+		}
+	}
+	w.partial = b[n:]
+	return len(p), nil
+}
+
+// incompleteSuffix returns the length of the incomplete UTF-8 sequence at the
+// end of b, or zero if b doesn’t end in an incomplete sequence.
+func incompleteSuffix(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax+1; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return 0
+			}
+			return len(b) - i
+		}
+	}
+	return 0
+}
+
+// insertAtEnd inserts text at the end of buffer without moving point.  It
+// binds inhibit-read-only so that it also works in read-only buffers.
+// buffer is evaluated, so it can be a form that returns a buffer.
+func (e Env) insertAtEnd(buffer In, text string) error {
+	_, err := e.Eval(List{
+		Symbol("with-current-buffer"), buffer,
+		List{
+			Symbol("save-excursion"),
+			List{Symbol("goto-char"), List{Symbol("point-max")}},
+			List{
+				Symbol("let"), List{List{Symbol("inhibit-read-only"), T}},
+				List{Symbol("insert"), String(text)},
+			},
+		},
+	})
+	return err
+}
+
+// Buffer is an Emacs buffer object.  See [Buffers].  Like a [Value], a
+// Buffer is only valid while the environment that created it is live.
+// Buffer implements [In] and [Out], so exported functions can accept and
+// return buffers.
+//
+// [Buffers]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Buffers.html
+type Buffer struct{ v Value }
+
+// CurrentBuffer returns the current buffer, like current-buffer.
+func (e Env) CurrentBuffer() (Buffer, error) {
+	v, err := e.Call("current-buffer")
+	return Buffer{v}, err
+}
+
+// Value returns the Emacs buffer object.
+func (b Buffer) Value() Value {
+	return b.v
+}
+
+// Emacs implements [In.Emacs].  It returns the buffer object.
+func (b Buffer) Emacs(Env) (Value, error) {
+	return b.v, nil
+}
+
+// FromEmacs implements [Out.FromEmacs].  It sets *b to the buffer object v.
+// It returns an error if v isn’t a buffer.
+func (b *Buffer) FromEmacs(e Env, v Value) error {
+	var ok Bool
+	if err := e.CallOut("bufferp", &ok, v); err != nil {
+		return err
+	}
+	if !ok {
+		return WrongTypeArgument("bufferp", v)
+	}
+	*b = Buffer{v}
+	return nil
+}
+
+// Name returns the name of the buffer.  It returns an empty string if the
+// buffer has been killed.
+func (b Buffer) Name(e Env) (string, error) {
+	v, err := e.Call("buffer-name", b.v)
+	if err != nil || e.IsNil(v) {
+		return "", err
+	}
+	return e.Str(v)
+}
+
+// Live returns whether the buffer hasn’t been killed.
+func (b Buffer) Live(e Env) (bool, error) {
+	var r Bool
+	err := e.CallOut("buffer-live-p", &r, b.v)
+	return bool(r), err
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	ERTTest(bufferWriterInsert)
+}
+
+func bufferWriterInsert(e Env) error {
+	buffer, err := e.Call("generate-new-buffer", String("*temp*"))
+	if err != nil {
+		return err
+	}
+	defer e.Call("kill-buffer", buffer)
+	w := e.BufferWriter(buffer)
+	// Split the multibyte sequence for “ü” across two writes.
+	for _, b := range [][]byte{[]byte("Hall\xc3"), []byte("\xbcchen \xff\n")} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	v, err := e.Eval(List{Symbol("with-current-buffer"), buffer, List{Symbol("buffer-string")}})
+	if err != nil {
+		return err
+	}
+	got, err := e.Str(v)
+	if err != nil {
+		return err
+	}
+	if want := "Hallüchen �\n"; string(got) != want {
+		return fmt.Errorf("buffer contents: got %q, want %q", got, want)
+	}
+	return nil
+}
+
+func TestIncompleteSuffix(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 0},
+		{"ab\xc3", 1},
+		{"ab\xc3\xbc", 0},
+		{"\xe2\x80", 2},
+		{"\xf0\x9f\x98", 3},
+		{"\xf0\x9f\x98\x80", 0},
+		{"\x80", 0},
+		{"\xff", 0},
+	} {
+		if got := incompleteSuffix([]byte(tc.in)); got != tc.want {
+			t.Errorf("incompleteSuffix(%q) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+}
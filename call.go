@@ -66,3 +66,19 @@ func (e Env) Invoke(fun interface{}, out interface{}, in ...interface{}) error {
 	}
 	return e.Go(rv, out)
 }
+
+// Call calls the Emacs function named fun with the given arguments and
+// converts the result to a value of type T.  It’s a type-safe shorthand for
+// [Env.CallOut] that doesn’t require declaring a result variable.  Call uses
+// [Env.Go] to convert the result, so T can be any type that [NewOut] supports
+// through a pointer to T, e.g., [Value], [String], string, int, or a slice or
+// struct type.
+func Call[T any](e Env, fun Name, args ...In) (T, error) {
+	var r T
+	v, err := e.Call(fun, args...)
+	if err != nil {
+		return r, err
+	}
+	err = e.Go(v, &r)
+	return r, err
+}
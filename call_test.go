@@ -14,7 +14,11 @@
 
 package emacs
 
-import "time"
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
 
 func ExampleEnv_Call() {
 	// Assumes that env is a valid Env value.
@@ -43,3 +47,37 @@ func ExampleEnv_Invoke() {
 }
 
 var env Env // invalid, for exposition only
+
+func ExampleCall() {
+	// Assumes that env is a valid Env value.
+	n, err := Call[int](env, "length", List{Int(1), Int(2)})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(n)
+}
+
+func init() {
+	ERTTest(callGeneric)
+}
+
+func callGeneric(e Env) error {
+	n, err := Call[int](e, "length", List{Int(1), Int(2)})
+	if err != nil {
+		return err
+	}
+	if n != 2 {
+		return fmt.Errorf("length: got %d, want 2", n)
+	}
+	s, err := Call[[]string](e, "split-string", String("a b"))
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(s, []string{"a", "b"}) {
+		return fmt.Errorf("split-string: got %q, want [a b]", s)
+	}
+	if _, err := Call[String](e, "length", String("abc")); !e.IsWrongTypeArgument(err) {
+		return fmt.Errorf("got error %v, want wrong-type-argument", err)
+	}
+	return nil
+}
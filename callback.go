@@ -0,0 +1,152 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+)
+
+// Callback is an Emacs function that Go code can keep and call later, after
+// the call that received it has returned.  The conversion machinery creates
+// Callback values for callback interfaces, which are interface types with a
+// Call method that *Callback implements, for example:
+//
+//	type Predicate interface {
+//		Call(e emacs.Env, args ...emacs.In) (emacs.Value, error)
+//	}
+//
+// When converting an Emacs value to a variable of such an interface type,
+// for example in an argument of a function
+// exported using [Export], the variable receives a *Callback for the Emacs
+// function.  The Emacs function stays alive as long as the Callback is
+// reachable from Go, or until you call [Callback.Release].
+//
+// Like all interaction with Emacs, calling a Callback requires a live
+// environment, so you can only call it from a function called by Emacs.
+type Callback struct {
+	ref Value // global reference, zero after Release
+}
+
+// NewCallback returns a new Callback for the Emacs function fun.  It returns
+// an error if fun isn’t a function.
+func (e Env) NewCallback(fun Value) (*Callback, error) {
+	ok, err := e.FunctionP(fun)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, WrongTypeArgument("functionp", fun)
+	}
+	ref, err := e.makeGlobalRef(fun)
+	if err != nil {
+		return nil, err
+	}
+	c := &Callback{ref}
+	runtime.SetFinalizer(c, (*Callback).finalize)
+	return c, nil
+}
+
+// Call calls the function with the given arguments.  It returns an error if
+// the Callback has been released.
+func (c *Callback) Call(e Env, args ...In) (Value, error) {
+	if c.ref.r == nil {
+		return Value{}, errors.New("callback already released")
+	}
+	vals := make([]Value, len(args))
+	for i, a := range args {
+		v, err := a.Emacs(e)
+		if err != nil {
+			return Value{}, err
+		}
+		vals[i] = v
+	}
+	return e.Funcall(c.ref, vals)
+}
+
+// Emacs returns the function object.  This allows passing a Callback back to
+// Emacs.  Emacs returns an error if the Callback has been released.
+func (c *Callback) Emacs(e Env) (Value, error) {
+	if c.ref.r == nil {
+		return Value{}, errors.New("callback already released")
+	}
+	return c.ref, nil
+}
+
+// Release releases the function object.  Afterwards, calling the Callback
+// fails.  Calling Release is optional, since the function is released once
+// the Callback is garbage-collected, but calling Release frees resources
+// earlier.  Calling Release more than once has no effect.
+func (c *Callback) Release(e Env) error {
+	if c.ref.r == nil {
+		return nil
+	}
+	runtime.SetFinalizer(c, nil)
+	ref := c.ref
+	c.ref = Value{}
+	return e.freeGlobalRef(ref)
+}
+
+func (c *Callback) finalize() {
+	if c.ref.r != nil {
+		releaseLater(c.ref)
+	}
+}
+
+// isCallbackInterface returns whether t is an interface type with a Call
+// method that *Callback implements.  This excludes [In] and other interfaces
+// that *Callback happens to implement without being callbacks.
+func isCallbackInterface(t reflect.Type) bool {
+	if t.Kind() != reflect.Interface || t == inType {
+		return false
+	}
+	_, ok := t.MethodByName("Call")
+	return ok && callbackType.Implements(t)
+}
+
+var callbackType = reflect.TypeOf((*Callback)(nil))
+
+// callbackIn converts a value of a callback interface type to Emacs.  nil
+// becomes the Emacs symbol nil.
+func callbackIn(v reflect.Value) In {
+	if v.IsNil() {
+		return Nil
+	}
+	if i, ok := v.Interface().(In); ok {
+		return i
+	}
+	return unconvertible{v.Elem().Type()}
+}
+
+// unconvertible is an [In] that always fails.
+type unconvertible struct{ t reflect.Type }
+
+func (u unconvertible) Emacs(Env) (Value, error) {
+	return Value{}, WrongTypeArgument("go-known-type-p", String(u.t.String()))
+}
+
+func callbackOut(v reflect.Value) Out { return reflectCallback(v) }
+
+type reflectCallback reflect.Value
+
+func (r reflectCallback) FromEmacs(e Env, v Value) error {
+	c, err := e.NewCallback(v)
+	if err != nil {
+		return err
+	}
+	reflect.Value(r).Elem().Set(reflect.ValueOf(c))
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	ERTTest(callbackInterface)
+}
+
+type testPredicate interface {
+	Call(e Env, args ...In) (Value, error)
+}
+
+func callbackInterface(e Env) error {
+	var stored testPredicate
+	store, del, err := e.Lambda(func(p testPredicate) { stored = p })
+	if err != nil {
+		return err
+	}
+	defer del()
+	// Store the Emacs function in one call, and call it in a later one, so
+	// that the original argument value is no longer valid.
+	if _, err := e.Call("funcall", store, Symbol("natnump")); err != nil {
+		return err
+	}
+	if stored == nil {
+		return errors.New("callback not stored")
+	}
+	call, del2, err := e.Lambda(func(e Env, i int) (bool, error) {
+		v, err := stored.Call(e, Int(i))
+		if err != nil {
+			return false, err
+		}
+		return e.IsNotNil(v), nil
+	})
+	if err != nil {
+		return err
+	}
+	defer del2()
+	for _, tc := range []struct {
+		arg  int
+		want bool
+	}{{5, true}, {-5, false}} {
+		var got Bool
+		if err := e.CallOut("funcall", &got, call, Int(tc.arg)); err != nil {
+			return err
+		}
+		if bool(got) != tc.want {
+			return fmt.Errorf("(natnump %d): got %t, want %t", tc.arg, got, tc.want)
+		}
+	}
+	c := stored.(*Callback)
+	if err := c.Release(e); err != nil {
+		return err
+	}
+	if _, err := c.Call(e, Int(1)); err == nil {
+		return errors.New("calling a released callback succeeded")
+	}
+	if _, err := e.Call("funcall", store, Int(1)); !e.IsWrongTypeArgument(err) {
+		return fmt.Errorf("passing a non-function as callback: got error %v, want wrong-type-argument", err)
+	}
+	return nil
+}
+
+func TestIsCallbackInterface(t *testing.T) {
+	for _, tc := range []struct {
+		t    reflect.Type
+		want bool
+	}{
+		{reflect.TypeOf((*testPredicate)(nil)).Elem(), true},
+		{reflect.TypeOf((*interface{})(nil)).Elem(), false},
+		{reflect.TypeOf((*error)(nil)).Elem(), false},
+		{reflect.TypeOf((*In)(nil)).Elem(), false},
+		{reflect.TypeOf((*interface {
+			Emacs(Env) (Value, error)
+		})(nil)).Elem(), false},
+		{callbackType, false},
+	} {
+		if got := isCallbackInterface(tc.t); got != tc.want {
+			t.Errorf("isCallbackInterface(%v): got %t, want %t", tc.t, got, tc.want)
+		}
+	}
+	if _, err := OutFuncFor(reflect.PointerTo(reflect.TypeOf((*testPredicate)(nil)).Elem())); err != nil {
+		t.Errorf("OutFuncFor(*testPredicate): %v", err)
+	}
+	if _, err := OutFuncFor(reflect.TypeOf((*In)(nil))); err == nil {
+		t.Error("OutFuncFor(*In) succeeded unexpectedly")
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// #include "emacs-module.h"
+// #include <stdbool.h>
+// bool phst_emacs_has_open_channel(emacs_env *env) {
+//   return (size_t)env->size >= sizeof(struct emacs_env_28);
+// }
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Capability describes an optional feature of the Emacs instance that loads
+// the module, such as native JSON support or tree-sitter.  Use
+// [NewCapability], [FeatureCapability], or [FunctionCapability] to create
+// Capability objects, or use one of the predefined capabilities.  Capability
+// objects can’t be copied.
+//
+// Loading the module detects all capabilities that have been created before.
+// Use [RequireCapability] to make loading the module fail if some capability
+// isn’t available, and the [Requires] option to define functions or ERT tests
+// only if some capability is available.
+type Capability struct {
+	name  string
+	probe func(Env) (bool, error)
+
+	mu        sync.Mutex
+	detected  bool
+	available bool
+}
+
+// NewCapability creates a new [Capability] with the given human-readable name.
+// Loading the module calls probe to find out whether the capability is
+// available.  Typically you should call NewCapability in an init function or
+// initialize a global variable with its return value.  You can call
+// NewCapability safely from multiple goroutines.
+func NewCapability(name string, probe func(Env) (bool, error)) *Capability {
+	if name == "" {
+		panic("empty capability name")
+	}
+	if probe == nil {
+		panic(fmt.Errorf("nil probe for capability %s", name))
+	}
+	c := &Capability{name: name, probe: probe}
+	capabilities.add(c)
+	return c
+}
+
+// FeatureCapability returns a new [Capability] that is available if feature
+// has been provided, i.e., if (featurep feature) returns non-nil.
+func FeatureCapability(feature Name) *Capability {
+	return NewCapability(fmt.Sprintf("feature %s", feature), func(e Env) (bool, error) {
+		var r Bool
+		err := e.CallOut("featurep", &r, feature)
+		return bool(r), err
+	})
+}
+
+// FunctionCapability returns a new [Capability] that is available if fun is
+// defined as a function, i.e., if (fboundp fun) returns non-nil.
+func FunctionCapability(fun Name) *Capability {
+	return NewCapability(fmt.Sprintf("function %s", fun), func(e Env) (bool, error) {
+		var r Bool
+		err := e.CallOut("fboundp", &r, fun)
+		return bool(r), err
+	})
+}
+
+// Predefined capabilities.
+var (
+	// OpenChannel is available if the module environment supports
+	// opening pipes to pipe processes using [Env.OpenPipe].
+	OpenChannel = NewCapability("module channels", func(e Env) (bool, error) {
+		return bool(C.phst_emacs_has_open_channel(e.raw())), nil
+	})
+
+	// NativeJSON is available if Emacs has been built with native JSON
+	// support.
+	NativeJSON = NewCapability("native JSON support", predicateCapability("json-available-p"))
+
+	// NativeCompilation is available if Emacs has been built with native
+	// compilation support.
+	NativeCompilation = NewCapability("native compilation", predicateCapability("native-comp-available-p"))
+
+	// TreeSitter is available if Emacs has been built with tree-sitter
+	// support.
+	TreeSitter = NewCapability("tree-sitter support", predicateCapability("treesit-available-p"))
+
+	// Threads is available if Emacs has been built with support for Lisp
+	// threads.
+	Threads = FeatureCapability("threads")
+)
+
+// predicateCapability returns a probe function that returns whether the
+// nullary function pred is defined and returns non-nil.
+func predicateCapability(pred Name) func(Env) (bool, error) {
+	return func(e Env) (bool, error) {
+		var bound Bool
+		if err := e.CallOut("fboundp", &bound, pred); err != nil || !bound {
+			return false, err
+		}
+		var r Bool
+		err := e.CallOut(pred, &r)
+		return bool(r), err
+	}
+}
+
+// String returns the human-readable name of the capability.
+func (c *Capability) String() string {
+	return c.name
+}
+
+// Available returns whether the capability is available in the Emacs instance
+// that has loaded the module.  It can only be called after the capability has
+// been detected, i.e., after the module has been loaded for capabilities
+// created before loading.  Available panics if the capability hasn’t been
+// detected yet; use [Capability.Detect] in that case.  You can call Available
+// safely from multiple goroutines.
+func (c *Capability) Available() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.detected {
+		panic(fmt.Errorf("capability %s not yet detected", c.name))
+	}
+	return c.available
+}
+
+// Detect returns whether the capability is available in the Emacs instance
+// represented by e.  Detect caches the result, so subsequent calls to Detect
+// and [Capability.Available] return the same value without calling into
+// Emacs.
+func (c *Capability) Detect(e Env) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.detected {
+		ok, err := c.probe(e)
+		if err != nil {
+			return false, fmt.Errorf("can’t detect capability %s: %w", c.name, err)
+		}
+		c.available = ok
+		c.detected = true
+	}
+	return c.available, nil
+}
+
+// RequireCapability arranges for loading the module to fail if any of the
+// given capabilities isn’t available.  Together with [RequireEmacs], all
+// unmet requirements are reported in a single error.  Call RequireCapability
+// before Emacs loads the module, typically in an init function.  You can call
+// RequireCapability safely from multiple goroutines.
+func RequireCapability(cs ...*Capability) {
+	for _, c := range cs {
+		if c == nil {
+			panic("nil capability")
+		}
+	}
+	requirements.mu.Lock()
+	defer requirements.mu.Unlock()
+	requirements.caps = append(requirements.caps, cs...)
+}
+
+// Requires is an [Option] that restricts a function or ERT test to Emacs
+// instances that provide all the given capabilities.  If some capability is
+// missing, [Export] silently skips defining the function, [Env.Export]
+// returns an error, and [ERTTest] and [Env.ERTTest] define a test that is
+// always skipped.
+type Requires []*Capability
+
+func (r Requires) apply(o *exportAuto) { o.requires = append(o.requires, r...) }
+
+// missing returns the first capability in r that isn’t available.
+func (r Requires) missing(e Env) (*Capability, error) {
+	for _, c := range r {
+		ok, err := c.Detect(e)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+type capabilityRegistry struct {
+	mu   sync.Mutex
+	caps []*Capability
+}
+
+var capabilities capabilityRegistry
+
+func (r *capabilityRegistry) add(c *Capability) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.caps = append(r.caps, c)
+}
+
+func (r *capabilityRegistry) detect(e Env) error {
+	r.mu.Lock()
+	caps := make([]*Capability, len(r.caps))
+	copy(caps, r.caps)
+	r.mu.Unlock()
+	for _, c := range caps {
+		if _, err := c.Detect(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requirementSet contains the requirements declared using [RequireEmacs] and
+// [RequireCapability].
+type requirementSet struct {
+	mu    sync.Mutex
+	major int
+	caps  []*Capability
+}
+
+var requirements requirementSet
+
+// check detects all capabilities and verifies that all requirements are met.
+// If not, it returns a single error describing all unmet requirements.
+func (r *requirementSet) check(e Env, major int) error {
+	if err := capabilities.detect(e); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var problems []string
+	if major < r.major {
+		problems = append(problems, fmt.Sprintf("Emacs %d or later (this is Emacs %d)", r.major, major))
+	}
+	for _, c := range r.caps {
+		ok, err := c.Detect(e)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			problems = append(problems, c.name)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return plainError.Error(String("This module requires " + strings.Join(problems, ", ")))
+}
+
+// plainError is the standard error symbol.  We use it to report unmet
+// requirements because it’s always defined, even before the module has
+// defined its own error symbols.
+var plainError = ErrorSymbol{"error", "error"}
@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	emacsFeature    = FeatureCapability("emacs")
+	missingFunction = FunctionCapability("go-nonexistent-function")
+)
+
+func init() {
+	ERTTest(capabilityAvailable)
+	ERTTest(requirementCheck)
+	ERTTest(missingCapability, Requires{missingFunction})
+	Export(missingCapability, Requires{missingFunction})
+}
+
+func capabilityAvailable(e Env) error {
+	if !emacsFeature.Available() {
+		return errors.New("feature emacs unexpectedly unavailable")
+	}
+	if missingFunction.Available() {
+		return errors.New("function go-nonexistent-function unexpectedly available")
+	}
+	var bound Bool
+	if err := e.CallOut("fboundp", &bound, Name("missing-capability")); err != nil {
+		return err
+	}
+	if bound {
+		return errors.New("function missing-capability unexpectedly defined")
+	}
+	return nil
+}
+
+// requirementCheck tests a local requirement set, since global requirements
+// would prevent the test module from loading in older Emacs versions.
+func requirementCheck(e Env) error {
+	major := majorVersion.load()
+	met := requirementSet{major: major, caps: []*Capability{emacsFeature}}
+	if err := met.check(e, major); err != nil {
+		return fmt.Errorf("requirements unexpectedly unmet: %s", e.Message(err))
+	}
+	unmet := requirementSet{major: major + 1, caps: []*Capability{emacsFeature, missingFunction}}
+	err := unmet.check(e, major)
+	if err == nil {
+		return errors.New("unmet requirements unexpectedly met")
+	}
+	msg := e.Message(err)
+	for _, want := range []string{fmt.Sprintf("Emacs %d or later", major+1), missingFunction.name} {
+		if !strings.Contains(msg, want) {
+			return fmt.Errorf("error message %q doesn’t contain %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, emacsFeature.name) {
+		return fmt.Errorf("error message %q mentions available capability %s", msg, emacsFeature.name)
+	}
+	return nil
+}
+
+func missingCapability(e Env) error {
+	return fmt.Errorf("test should have been skipped")
+}
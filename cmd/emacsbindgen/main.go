@@ -0,0 +1,421 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary emacsbindgen generates typed Go bindings for existing Emacs
+// functions, typically built-in ones.  Usage:
+//
+//	emacsbindgen [-list FILE] [-types FILE] [-dir DIR] [LISP-NAME...]
+//
+// emacsbindgen runs Emacs in batch mode to query the argument list,
+// documentation string, and defining file of each requested function.  It
+// then writes one Go file per defining feature into DIR.  Each file declares
+// a variable for each function and initializes it using [emacs.Import].  The
+// variable names are the Lisp names converted to Go CamelCase, for example
+// StringTrim for string-trim.
+//
+// Required arguments become parameters of type [emacs.Value], and optional
+// arguments and rest arguments become a single variadic parameter of type
+// [emacs.Value].  The result type is [emacs.Value] as well.  To use more
+// specific types, pass a types file using -types.  Each nonempty line in that
+// file that doesn’t start with # must be of the form LISP-NAME SIGNATURE,
+// where SIGNATURE is a Go function type acceptable to [emacs.Import].  The
+// signature may only refer to predeclared types and types in package emacs.
+//
+// The -list flag names a file containing additional function names, one per
+// line.  Functions defined in C source end up in a file for the pseudo-feature
+// “primitives”.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	list := flag.String("list", "", "file containing function names, one per line")
+	types := flag.String("types", "", "file containing signature overrides")
+	dir := flag.String("dir", ".", "output directory")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name for the generated files")
+	emacs := flag.String("emacs", "emacs", "Emacs binary to query")
+	flag.Parse()
+	names := flag.Args()
+	if *list != "" {
+		l, err := readLines(*list)
+		if err != nil {
+			log.Fatal(err)
+		}
+		names = append(names, l...)
+	}
+	if len(names) == 0 {
+		log.Fatal("no functions given")
+	}
+	if *pkg == "" {
+		abs, err := filepath.Abs(*dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		*pkg = strings.ReplaceAll(filepath.Base(abs), "-", "_")
+	}
+	sigs := make(map[string]string)
+	if *types != "" {
+		l, err := readLines(*types)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, line := range l {
+			name, sig, ok := strings.Cut(line, " ")
+			if !ok {
+				log.Fatalf("%s: invalid line %q", *types, line)
+			}
+			sigs[name] = strings.TrimSpace(sig)
+		}
+	}
+	infos, err := query(*emacs, names)
+	if err != nil {
+		log.Fatal(err)
+	}
+	files, err := generate(*pkg, infos, sigs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, b := range files {
+		if err := os.WriteFile(filepath.Join(*dir, name), b, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// readLines returns the nonempty lines in the given file that don’t start
+// with #.
+func readLines(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			r = append(r, line)
+		}
+	}
+	return r, s.Err()
+}
+
+// funcInfo describes an Emacs function as reported by Emacs.
+type funcInfo struct {
+	Name string `json:"name"`
+	// Argument list with all symbol names in lowercase, or nil if
+	// unknown.
+	Args []string `json:"args"`
+	// Minimum and maximum number of arguments; Max is −1 for functions
+	// with &rest arguments.
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+	Doc  string `json:"doc"`
+	File string `json:"file"`
+}
+
+// query runs Emacs to retrieve information about the functions with the given
+// names.
+func query(emacs string, names []string) ([]funcInfo, error) {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = strconv.Quote(n)
+	}
+	form := fmt.Sprintf(`(progn
+  (require 'json)
+  (princ
+   (json-encode
+    (vconcat
+     (mapcar (lambda (name)
+               (let* ((sym (intern name))
+                      (_ (unless (fboundp sym) (error "Function %%s not defined" name)))
+                      (arity (func-arity sym))
+                      (args (help-function-arglist sym t)))
+                 (list (cons 'name name)
+                       (cons 'args (and (listp args)
+                                        (vconcat (mapcar (lambda (a) (downcase (symbol-name a))) args))))
+                       (cons 'min (car arity))
+                       (cons 'max (if (numberp (cdr arity)) (cdr arity) -1))
+                       (cons 'doc (or (ignore-errors (documentation sym t)) ""))
+                       (cons 'file (or (symbol-file sym 'defun) "")))))
+             '(%s))))))`, strings.Join(quoted, " "))
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(emacs, "--quick", "--batch", "--eval", form)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("can’t query function information from Emacs: %w\n%s", err, stderr.Bytes())
+	}
+	var infos []funcInfo
+	if err := json.Unmarshal(stdout.Bytes(), &infos); err != nil {
+		return nil, fmt.Errorf("can’t parse Emacs output: %w", err)
+	}
+	if len(infos) != len(names) {
+		return nil, errors.New("Emacs returned the wrong number of function descriptions")
+	}
+	return infos, nil
+}
+
+// generate returns the generated Go files, keyed by file name.
+func generate(pkg string, infos []funcInfo, sigs map[string]string) (map[string][]byte, error) {
+	byFeature := make(map[string][]funcInfo)
+	goNames := make(map[string]string)
+	for _, i := range infos {
+		n := goName(i.Name)
+		if other, dup := goNames[n]; dup {
+			return nil, fmt.Errorf("functions %s and %s both map to Go name %s", other, i.Name, n)
+		}
+		goNames[n] = i.Name
+		f := feature(i.File)
+		byFeature[f] = append(byFeature[f], i)
+	}
+	files := make(map[string][]byte)
+	for f, is := range byFeature {
+		sort.Slice(is, func(a, b int) bool { return is[a].Name < is[b].Name })
+		b, err := generateFile(pkg, f, is, sigs)
+		if err != nil {
+			return nil, err
+		}
+		files[strings.ReplaceAll(f, "-", "_")+"_bindings.go"] = b
+	}
+	return files, nil
+}
+
+func generateFile(pkg, feature string, infos []funcInfo, sigs map[string]string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by emacsbindgen; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintln(&b, `import "github.com/phst/emacs"`)
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "// Bindings for functions defined in %s.\n", feature)
+	var names []string
+	for _, i := range infos {
+		sig, ok := sigs[i.Name]
+		if ok {
+			if err := checkSignature(sig); err != nil {
+				return nil, fmt.Errorf("invalid signature for %s: %w", i.Name, err)
+			}
+		} else {
+			sig = signature(i)
+		}
+		n := goName(i.Name)
+		names = append(names, n)
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "// %s calls the Emacs function %s.\n", n, i.Name)
+		if doc := docComment(i); doc != "" {
+			fmt.Fprintln(&b, "//")
+			for _, line := range strings.Split(doc, "\n") {
+				fmt.Fprintln(&b, strings.TrimRight("// "+line, " "))
+			}
+		}
+		fmt.Fprintf(&b, "var %s %s\n", n, sig)
+	}
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "func init() {")
+	for j, i := range infos {
+		fmt.Fprintf(&b, "\temacs.Import(%q, &%s)\n", i.Name, names[j])
+	}
+	fmt.Fprintln(&b, "}")
+	return format.Source(b.Bytes())
+}
+
+// signature returns the default Go signature for the function described by
+// i.
+func signature(i funcInfo) string {
+	params := []string{"e emacs.Env"}
+	required, optional := arguments(i)
+	used := map[string]bool{"e": true}
+	for _, a := range required {
+		params = append(params, paramName(a, used)+" emacs.Value")
+	}
+	if optional != "" {
+		params = append(params, paramName(optional, used)+" ...emacs.Value")
+	}
+	return fmt.Sprintf("func(%s) (emacs.Value, error)", strings.Join(params, ", "))
+}
+
+// arguments returns the names of the required arguments of the function
+// described by i, and a name for its optional arguments or an empty string if
+// it doesn’t accept optional arguments.
+func arguments(i funcInfo) (required []string, optional string) {
+	var opt []string
+	state := ""
+	for _, a := range i.Args {
+		switch a {
+		case "&optional", "&rest":
+			state = a
+		default:
+			if state == "" {
+				required = append(required, a)
+			} else {
+				opt = append(opt, a)
+			}
+		}
+	}
+	if i.Args == nil || len(required) != i.Min {
+		// Argument list unknown or inconsistent with arity.
+		required = make([]string, i.Min)
+		for j := range required {
+			required[j] = fmt.Sprintf("arg%d", j+1)
+		}
+		opt = nil
+	}
+	switch {
+	case i.Max == i.Min:
+		return required, ""
+	case len(opt) == 1:
+		return required, opt[0]
+	default:
+		return required, "rest"
+	}
+}
+
+// paramName converts the Lisp argument name a to a Go parameter name that’s
+// not in used.
+func paramName(a string, used map[string]bool) string {
+	n := camel(a, false)
+	if n == "" || token.Lookup(n).IsKeyword() || predeclared[n] || n == "emacs" {
+		n += "Arg"
+	}
+	for base, k := n, 2; used[n]; k++ {
+		n = fmt.Sprintf("%s%d", base, k)
+	}
+	used[n] = true
+	return n
+}
+
+var predeclared = map[string]bool{
+	"any": true, "append": true, "bool": true, "byte": true, "cap": true,
+	"clear": true, "close": true, "comparable": true, "complex": true,
+	"copy": true, "delete": true, "error": true, "false": true,
+	"float32": true, "float64": true, "imag": true, "int": true,
+	"len": true, "make": true, "max": true, "min": true, "new": true,
+	"nil": true, "panic": true, "print": true, "println": true,
+	"real": true, "recover": true, "rune": true, "string": true,
+	"true": true, "uint": true,
+}
+
+// goName converts the Lisp function name n to an exported Go identifier.
+func goName(n string) string {
+	r := camel(n, true)
+	if r == "" || !unicode.IsLetter(rune(r[0])) {
+		r = "F" + r
+	}
+	return r
+}
+
+var punctuation = strings.NewReplacer(
+	"+", "-plus-", "*", "-star-", "/", "-slash-", "<", "-less-", ">", "-greater-",
+	"=", "-equal-", "%", "-percent-", "?", "-p-", "!", "-bang-", ":", "-", ".", "-",
+)
+
+// camel converts a Lisp symbol name to CamelCase.  If upper is false, the
+// first letter is lowercase.
+func camel(n string, upper bool) string {
+	var b strings.Builder
+	for i, w := range strings.FieldsFunc(punctuation.Replace(n), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		w = strings.ToLower(w)
+		if i > 0 || upper {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+var nativeHash = regexp.MustCompile(`-[0-9a-f]{8}-[0-9a-f]{8}$`)
+
+// feature returns the feature name for the given defining file.
+func feature(file string) string {
+	if file == "" || strings.HasPrefix(file, "C source") {
+		return "primitives"
+	}
+	base := filepath.Base(file)
+	for _, ext := range []string{".gz", ".elc", ".el", ".eln"} {
+		base = strings.TrimSuffix(base, ext)
+	}
+	return nativeHash.ReplaceAllString(base, "")
+}
+
+var usageLine = regexp.MustCompile(`\n*\(fn.*\)\s*$`)
+
+// docComment returns the documentation for the function described by i as it
+// should appear in the Go doc comment.  It starts with the Lisp calling
+// convention.
+func docComment(i funcInfo) string {
+	doc := strings.TrimSpace(usageLine.ReplaceAllString(i.Doc, ""))
+	if i.Args == nil {
+		return doc
+	}
+	usage := make([]string, len(i.Args))
+	for j, a := range i.Args {
+		if strings.HasPrefix(a, "&") {
+			usage[j] = a
+		} else {
+			usage[j] = strings.ToUpper(a)
+		}
+	}
+	r := "\t(" + strings.Join(append([]string{i.Name}, usage...), " ") + ")"
+	if doc != "" {
+		r += "\n\n" + doc
+	}
+	return r
+}
+
+// checkSignature checks that sig is a function type that only refers to
+// predeclared types and package emacs.
+func checkSignature(sig string) error {
+	e, err := parser.ParseExpr(sig)
+	if err != nil {
+		return err
+	}
+	if _, ok := e.(*ast.FuncType); !ok {
+		return errors.New("not a function type")
+	}
+	var bad error
+	ast.Inspect(e, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); !ok || id.Name != "emacs" {
+				bad = fmt.Errorf("signature refers to package other than emacs: %s", sig)
+			}
+			return false
+		}
+		return true
+	})
+	return bad
+}
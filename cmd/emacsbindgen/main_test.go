@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	infos := []funcInfo{
+		{
+			Name: "string-trim",
+			Args: []string{"string", "&optional", "trim-left", "trim-right"},
+			Min:  1, Max: 3,
+			Doc:  "Trim STRING of leading and trailing strings.\n\n(fn STRING &optional TRIM-LEFT TRIM-RIGHT)",
+			File: "/usr/share/emacs/29.4/lisp/emacs-lisp/subr-x.elc",
+		},
+		{
+			Name: "1+",
+			Args: []string{"number"},
+			Min:  1, Max: 1,
+			Doc: "Return NUMBER plus one.",
+		},
+		{
+			Name: "concat",
+			Min:  0, Max: -1,
+		},
+	}
+	files, err := generate("foo", infos, map[string]string{"1+": "func(emacs.Env, int) (int, error)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want 2", len(files))
+	}
+	got := string(files["subr_x_bindings.go"])
+	want := `// Code generated by emacsbindgen; DO NOT EDIT.
+
+package foo
+
+import "github.com/phst/emacs"
+
+// Bindings for functions defined in subr-x.
+
+// StringTrim calls the Emacs function string-trim.
+//
+//	(string-trim STRING &optional TRIM-LEFT TRIM-RIGHT)
+//
+// Trim STRING of leading and trailing strings.
+var StringTrim func(e emacs.Env, stringArg emacs.Value, rest ...emacs.Value) (emacs.Value, error)
+
+func init() {
+	emacs.Import("string-trim", &StringTrim)
+}
+`
+	if got != want {
+		t.Errorf("subr-x bindings: got\n%s\nwant\n%s", got, want)
+	}
+	got = string(files["primitives_bindings.go"])
+	want = `// Code generated by emacsbindgen; DO NOT EDIT.
+
+package foo
+
+import "github.com/phst/emacs"
+
+// Bindings for functions defined in primitives.
+
+// F1Plus calls the Emacs function 1+.
+//
+//	(1+ NUMBER)
+//
+// Return NUMBER plus one.
+var F1Plus func(emacs.Env, int) (int, error)
+
+// Concat calls the Emacs function concat.
+var Concat func(e emacs.Env, rest ...emacs.Value) (emacs.Value, error)
+
+func init() {
+	emacs.Import("1+", &F1Plus)
+	emacs.Import("concat", &Concat)
+}
+`
+	if got != want {
+		t.Errorf("primitive bindings: got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateInvalidSignature(t *testing.T) {
+	infos := []funcInfo{{Name: "ignore", Min: 0, Max: -1}}
+	if _, err := generate("foo", infos, map[string]string{"ignore": "func(emacs.Env, time.Time) error"}); err == nil {
+		t.Error("generate: got no error for signature referring to package time")
+	}
+}
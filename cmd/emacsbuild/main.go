@@ -0,0 +1,71 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary emacsbuild builds a Go package as an Emacs module for one or more
+// platforms.  Usage:
+//
+//	emacsbuild [-o NAME] [-platforms GOOS/GOARCH,...] [-universal] [PACKAGE]
+//
+// emacsbuild chooses the right build mode, file name extension, and C
+// compiler for each platform, and finds emacs-module.h for the Emacs binary
+// given by -emacs unless -include is given.  It prints the names of the
+// resulting module files.  See package github.com/phst/emacs/modulebuild for
+// details.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/phst/emacs/modulebuild"
+)
+
+func main() {
+	var o modulebuild.Options
+	flag.StringVar(&o.Output, "o", "", "output file name without extension; defaults to the package name")
+	platforms := flag.String("platforms", "", "comma-separated list of GOOS/GOARCH platforms; defaults to the host platform")
+	flag.BoolVar(&o.Universal, "universal", false, "combine macOS modules into a single universal file")
+	flag.StringVar(&o.IncludeDir, "include", "", "directory containing emacs-module.h")
+	flag.StringVar(&o.Emacs, "emacs", "emacs", "Emacs binary used to find emacs-module.h")
+	flag.Parse()
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		o.Package = flag.Arg(0)
+	default:
+		log.Fatal("at most one package allowed")
+	}
+	if *platforms != "" {
+		for _, s := range strings.Split(*platforms, ",") {
+			p, err := modulebuild.ParsePlatform(s)
+			if err != nil {
+				log.Fatal(err)
+			}
+			o.Platforms = append(o.Platforms, p)
+		}
+	}
+	o.Stdout = os.Stderr
+	o.Stderr = os.Stderr
+	results, err := modulebuild.Build(context.Background(), o)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, r := range results {
+		fmt.Println(r.File)
+	}
+}
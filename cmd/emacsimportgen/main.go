@@ -0,0 +1,431 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary emacsimportgen generates Go wrappers for Emacs functions.  It’s meant
+// to be used with go generate:
+//
+//	//go:generate go run github.com/phst/emacs/cmd/emacsimportgen
+//
+// emacsimportgen scans the Go files in the current directory for comments of
+// the form
+//
+//	//emacs:import LISP-NAME GO-NAME [SIGNATURE]
+//
+// and generates a file that declares a variable GO-NAME for each such comment
+// and imports the Emacs function LISP-NAME into it.  If SIGNATURE is present,
+// it must be a Go function type acceptable to [emacs.Import], for example
+// func(emacs.Env, string) (string, error).  Packages mentioned in the
+// signature must be imported by the file containing the comment.  If
+// SIGNATURE is absent, the variable has type [emacs.Func] and is initialized
+// using [emacs.ImportFunc].
+//
+// Alternatively, you can pass a specification file using the -spec flag.  Each
+// nonempty line in the file that doesn’t start with # must either be of the
+// form LISP-NAME GO-NAME [SIGNATURE] or an import declaration of the form
+// import [NAME] "PATH".
+//
+// Unless the -nodoc flag is given, emacsimportgen runs Emacs in batch mode to
+// retrieve the documentation strings of the imported functions and adds them
+// to the doc comments of the generated variables.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const emacsPath = "github.com/phst/emacs"
+
+func main() {
+	output := flag.String("output", "emacs_imports.go", "name of the generated file")
+	spec := flag.String("spec", "", "read import specifications from this file instead of scanning Go files")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name for the generated file")
+	emacs := flag.String("emacs", "emacs", "Emacs binary used to retrieve documentation strings")
+	noDoc := flag.Bool("nodoc", false, "don’t retrieve documentation strings")
+	flag.Parse()
+	if flag.NArg() != 0 {
+		log.Fatal("emacsimportgen doesn’t accept positional arguments")
+	}
+	var s *imports
+	var err error
+	if *spec != "" {
+		s, err = readSpec(*spec)
+	} else {
+		s, err = scanDir(".", *output)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *pkg == "" {
+		*pkg = s.pkg
+	}
+	if *pkg == "" {
+		log.Fatal("can’t determine package name; pass -package")
+	}
+	if !*noDoc {
+		if err := s.fetchDocs(*emacs); err != nil {
+			log.Fatal(err)
+		}
+	}
+	b, err := s.generate(*pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// imports is a set of functions to import.
+type imports struct {
+	pkg   string
+	funcs []*importedFunc
+	// Maps package names to import paths.
+	paths map[string]string
+}
+
+type importedFunc struct {
+	lispName string
+	goName   string
+	sig      *ast.FuncType // nil for ImportFunc
+	sigText  string
+	doc      string
+	pos      string
+}
+
+// scanDir scans the non-test Go files in dir for //emacs:import comments.  It
+// ignores the file named output.
+func scanDir(dir, output string) (*imports, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	s := &imports{paths: make(map[string]string)}
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") || filepath.Base(name) == filepath.Base(output) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if s.pkg == "" {
+			s.pkg = f.Name.Name
+		}
+		paths := fileImports(f)
+		for _, g := range f.Comments {
+			for _, c := range g.List {
+				rest, ok := strings.CutPrefix(c.Text, "//emacs:import ")
+				if !ok {
+					continue
+				}
+				fun, err := parseLine(rest, fset.Position(c.Pos()).String())
+				if err != nil {
+					return nil, err
+				}
+				if err := s.add(fun, paths); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// readSpec reads a specification file.
+func readSpec(name string) (*imports, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	s := &imports{paths: make(map[string]string)}
+	paths := map[string]string{"emacs": emacsPath}
+	var funcs []*importedFunc
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		pos := fmt.Sprintf("%s:%d", name, i+1)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "import "); ok {
+			n, p, err := parseImport(rest)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pos, err)
+			}
+			paths[n] = p
+			continue
+		}
+		fun, err := parseLine(line, pos)
+		if err != nil {
+			return nil, err
+		}
+		funcs = append(funcs, fun)
+	}
+	for _, fun := range funcs {
+		if err := s.add(fun, paths); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func parseImport(s string) (name, path string, err error) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		path, err = strconv.Unquote(fields[0])
+		name = defaultImportName(path)
+	case 2:
+		name = fields[0]
+		path, err = strconv.Unquote(fields[1])
+	default:
+		err = fmt.Errorf("invalid import declaration %q", s)
+	}
+	return
+}
+
+func fileImports(f *ast.File) map[string]string {
+	r := make(map[string]string)
+	for _, i := range f.Imports {
+		p, err := strconv.Unquote(i.Path.Value)
+		if err != nil {
+			continue
+		}
+		n := defaultImportName(p)
+		if i.Name != nil {
+			n = i.Name.Name
+		}
+		r[n] = p
+	}
+	return r
+}
+
+func defaultImportName(p string) string {
+	return path.Base(p)
+}
+
+// parseLine parses a specification of the form LISP-NAME GO-NAME [SIGNATURE].
+func parseLine(line, pos string) (*importedFunc, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("%s: import specification %q needs at least a Lisp and a Go name", pos, line)
+	}
+	fun := &importedFunc{lispName: fields[0], goName: fields[1], pos: pos}
+	if !token.IsIdentifier(fun.goName) {
+		return nil, fmt.Errorf("%s: invalid Go identifier %q", pos, fun.goName)
+	}
+	if len(fields) == 2 {
+		return fun, nil
+	}
+	fun.sigText = strings.Join(fields[2:], " ")
+	expr, err := parser.ParseExpr(fun.sigText)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid signature %q: %w", pos, fun.sigText, err)
+	}
+	sig, ok := expr.(*ast.FuncType)
+	if !ok {
+		return nil, fmt.Errorf("%s: signature %q is not a function type", pos, fun.sigText)
+	}
+	fun.sig = sig
+	return fun, nil
+}
+
+// add adds fun to s, resolving the packages in the signature of fun using
+// paths.
+func (s *imports) add(fun *importedFunc, paths map[string]string) error {
+	for _, f := range s.funcs {
+		if f.goName == fun.goName {
+			return fmt.Errorf("%s: duplicate Go name %s, previously declared at %s", fun.pos, fun.goName, f.pos)
+		}
+	}
+	if fun.sig != nil {
+		if err := checkSignature(fun); err != nil {
+			return err
+		}
+		var err error
+		ast.Inspect(fun.sig, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok || err != nil {
+				return err == nil
+			}
+			id, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			p, ok := paths[id.Name]
+			if !ok {
+				err = fmt.Errorf("%s: unknown package %s in signature", fun.pos, id.Name)
+				return false
+			}
+			if q, dup := s.paths[id.Name]; dup && q != p {
+				err = fmt.Errorf("%s: package name %s refers to both %s and %s", fun.pos, id.Name, q, p)
+				return false
+			}
+			s.paths[id.Name] = p
+			return false
+		})
+		if err != nil {
+			return err
+		}
+	}
+	s.funcs = append(s.funcs, fun)
+	return nil
+}
+
+// checkSignature performs a syntactic check of the requirements that
+// emacs.Import imposes on signatures.
+func checkSignature(fun *importedFunc) error {
+	params := fun.sig.Params.List
+	if len(params) == 0 || !isSelector(params[0].Type, "Env") {
+		return fmt.Errorf("%s: first argument of %s must be of type emacs.Env", fun.pos, fun.goName)
+	}
+	var results []ast.Expr
+	if fun.sig.Results != nil {
+		for _, f := range fun.sig.Results.List {
+			n := len(f.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				results = append(results, f.Type)
+			}
+		}
+	}
+	if len(results) == 0 || len(results) > 2 {
+		return fmt.Errorf("%s: %s must return one or two results", fun.pos, fun.goName)
+	}
+	if id, ok := results[len(results)-1].(*ast.Ident); !ok || id.Name != "error" {
+		return fmt.Errorf("%s: last result of %s must be of type error", fun.pos, fun.goName)
+	}
+	return nil
+}
+
+func isSelector(e ast.Expr, name string) bool {
+	sel, ok := e.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == name
+}
+
+// fetchDocs runs Emacs to retrieve the documentation strings of all functions
+// in s.
+func (s *imports) fetchDocs(emacs string) error {
+	if len(s.funcs) == 0 {
+		return nil
+	}
+	names := make([]string, len(s.funcs))
+	for i, f := range s.funcs {
+		names[i] = strconv.Quote(f.lispName)
+	}
+	// We print a JSON array of documentation strings, using an empty
+	// string for functions without documentation.
+	form := fmt.Sprintf(`(progn
+  (require 'json)
+  (princ
+   (json-encode
+    (vconcat
+     (mapcar (lambda (name)
+               (let ((sym (intern name)))
+                 (or (and (fboundp sym) (ignore-errors (documentation sym))) "")))
+             '(%s))))))`, strings.Join(names, " "))
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(emacs, "--quick", "--batch", "--eval", form)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("can’t retrieve documentation strings from Emacs: %w\n%s", err, stderr.Bytes())
+	}
+	var docs []string
+	if err := json.Unmarshal(stdout.Bytes(), &docs); err != nil {
+		return fmt.Errorf("can’t parse Emacs output: %w", err)
+	}
+	if len(docs) != len(s.funcs) {
+		return errors.New("Emacs returned the wrong number of documentation strings")
+	}
+	for i, d := range docs {
+		s.funcs[i].doc = d
+	}
+	return nil
+}
+
+// generate returns the formatted Go source code for s.
+func (s *imports) generate(pkg string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by emacsimportgen; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	paths := map[string]string{"emacs": emacsPath}
+	for n, p := range s.paths {
+		paths[n] = p
+	}
+	var names []string
+	for n := range paths {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(&b, "import (")
+	for _, n := range names {
+		p := paths[n]
+		if n == defaultImportName(p) {
+			fmt.Fprintf(&b, "\t%q\n", p)
+		} else {
+			fmt.Fprintf(&b, "\t%s %q\n", n, p)
+		}
+	}
+	fmt.Fprintln(&b, ")")
+	for _, f := range s.funcs {
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "// %s calls the Emacs function %s.\n", f.goName, f.lispName)
+		if f.doc != "" {
+			fmt.Fprintln(&b, "//")
+			for _, line := range strings.Split(strings.TrimRight(f.doc, "\n"), "\n") {
+				fmt.Fprintln(&b, strings.TrimRight("// "+line, " "))
+			}
+		}
+		if f.sig == nil {
+			fmt.Fprintf(&b, "var %s = emacs.ImportFunc(%q)\n", f.goName, f.lispName)
+		} else {
+			fmt.Fprintf(&b, "var %s %s\n", f.goName, f.sigText)
+		}
+	}
+	var typed []*importedFunc
+	for _, f := range s.funcs {
+		if f.sig != nil {
+			typed = append(typed, f)
+		}
+	}
+	if len(typed) > 0 {
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "func init() {")
+		for _, f := range typed {
+			fmt.Fprintf(&b, "\temacs.Import(%q, &%s)\n", f.lispName, f.goName)
+		}
+		fmt.Fprintln(&b, "}")
+	}
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanDir(t *testing.T) {
+	dir := t.TempDir()
+	src := `package foo
+
+import (
+	"time"
+
+	"github.com/phst/emacs"
+)
+
+//emacs:import format-time-string formatTimeString func(emacs.Env, string, time.Time) (string, error)
+//emacs:import ignore ignore
+`
+	if err := os.WriteFile(filepath.Join(dir, "foo.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := scanDir(dir, "emacs_imports.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.funcs[0].doc = "Use FORMAT-STRING to format the time TIME.\n"
+	got, err := s.generate(s.pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `// Code generated by emacsimportgen; DO NOT EDIT.
+
+package foo
+
+import (
+	"github.com/phst/emacs"
+	"time"
+)
+
+// formatTimeString calls the Emacs function format-time-string.
+//
+// Use FORMAT-STRING to format the time TIME.
+var formatTimeString func(emacs.Env, string, time.Time) (string, error)
+
+// ignore calls the Emacs function ignore.
+var ignore = emacs.ImportFunc("ignore")
+
+func init() {
+	emacs.Import("format-time-string", &formatTimeString)
+}
+`
+	if string(got) != want {
+		t.Errorf("generated code:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestReadSpecErrors(t *testing.T) {
+	for _, spec := range []string{
+		"message",
+		"message message func(string) error",
+		"message message func(emacs.Env, string)",
+		"message message func(emacs.Env, foo.Bar) error",
+		"message 1message",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "spec")
+			if err := os.WriteFile(name, []byte(spec), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if s, err := readSpec(name); err == nil {
+				t.Errorf("readSpec(%q) = %v, want error", spec, s)
+			}
+		})
+	}
+}
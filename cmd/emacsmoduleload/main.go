@@ -0,0 +1,218 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary emacsmoduleload checks that an Emacs module loads successfully.  It’s
+// meant as a smoke test for continuous integration.  Usage:
+//
+//	emacsmoduleload [-emacs BINARY,...] [-module FILE] [-functions NAME,...] [PACKAGE]
+//
+// Unless -module is given, emacsmoduleload first builds the Go package
+// PACKAGE (default: the current directory) for the host platform using
+// package github.com/phst/emacs/modulebuild.  It then starts each of the
+// given Emacs binaries in batch mode and loads the module, which also runs
+// the module’s initialization functions.  If -functions is given, it also
+// checks that loading the module has defined the given functions.
+//
+// emacsmoduleload prints one JSON object per Emacs binary to standard output.
+// The object has the following fields:
+//
+//   - emacs: the Emacs binary
+//   - version: the value of emacs-version, if Emacs could be started
+//   - ok: whether the module loaded successfully
+//   - kind: a classification of the failure; one of “emacs” (Emacs couldn’t be
+//     started or doesn’t support modules), “undefined-symbol” (the dynamic
+//     linker couldn’t resolve a symbol), “version” (the module doesn’t support
+//     this Emacs version or one of its requirements isn’t met), “license”
+//     (the module isn’t GPL-compatible), “missing-function” (a function passed
+//     to -functions isn’t defined), or “load” (any other failure)
+//   - error: the Lisp error symbol, if any
+//   - message: a human-readable error message
+//   - missing: the functions passed to -functions that aren’t defined
+//
+// The exit status is nonzero if the module fails to load in any of the Emacs
+// binaries.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/phst/emacs/modulebuild"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run loads the module and returns the exit status.  It doesn’t call
+// os.Exit or log.Fatal, so that deferred cleanup runs.
+func run() int {
+	emacsen := flag.String("emacs", "emacs", "comma-separated list of Emacs binaries to test")
+	module := flag.String("module", "", "module file to load; if empty, build the module")
+	functions := flag.String("functions", "", "comma-separated list of functions that the module should define")
+	flag.Parse()
+	if flag.NArg() > 1 {
+		log.Print("at most one package allowed")
+		return 1
+	}
+	if *module == "" {
+		dir, err := os.MkdirTemp("", "emacsmoduleload-")
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		defer os.RemoveAll(dir)
+		results, err := modulebuild.Build(context.Background(), modulebuild.Options{
+			Package: flag.Arg(0),
+			Output:  filepath.Join(dir, "module"),
+			Stdout:  os.Stderr,
+			Stderr:  os.Stderr,
+		})
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		*module = results[0].File
+	}
+	abs, err := filepath.Abs(*module)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	var funcs []string
+	if *functions != "" {
+		funcs = strings.Split(*functions, ",")
+	}
+	enc := json.NewEncoder(os.Stdout)
+	failed := false
+	for _, emacs := range strings.Split(*emacsen, ",") {
+		r := load(emacs, abs, funcs)
+		if err := enc.Encode(r); err != nil {
+			log.Print(err)
+			return 1
+		}
+		failed = failed || !r.OK
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// result is the outcome of loading the module into one Emacs binary.
+type result struct {
+	Emacs   string   `json:"emacs"`
+	Version string   `json:"version,omitempty"`
+	OK      bool     `json:"ok"`
+	Kind    string   `json:"kind,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// load starts emacs in batch mode, loads the module file into it, and checks
+// that funcs are defined afterwards.
+func load(emacs, file string, funcs []string) result {
+	quoted := make([]string, len(funcs))
+	for i, f := range funcs {
+		quoted[i] = strconv.Quote(f)
+	}
+	form := fmt.Sprintf(`(progn
+  (require 'json)
+  (require 'seq)
+  (princ
+   (json-encode
+    (cons
+     (cons 'version emacs-version)
+     (if (not (and (boundp 'module-file-suffix) module-file-suffix))
+         '((error . "") (message . "Emacs doesn’t support dynamic modules"))
+       (condition-case err
+           (progn
+             (module-load %s)
+             (list (cons 'missing
+                         (vconcat (seq-remove (lambda (f) (fboundp (intern f)))
+                                              '(%s))))))
+         (error
+          (list (cons 'error (symbol-name (car err)))
+                (cons 'message (error-message-string err))))))))))`,
+		strconv.Quote(file), strings.Join(quoted, " "))
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(emacs, "--quick", "--batch", "--eval", form)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return result{
+			Emacs:   emacs,
+			Kind:    "emacs",
+			Message: strings.TrimSpace(fmt.Sprintf("can’t run Emacs: %s\n%s", err, stderr.Bytes())),
+		}
+	}
+	return parse(emacs, stdout.Bytes())
+}
+
+// parse parses the output of the Lisp form in load.
+func parse(emacs string, output []byte) result {
+	var out struct {
+		Version string   `json:"version"`
+		Error   *string  `json:"error"`
+		Message string   `json:"message"`
+		Missing []string `json:"missing"`
+	}
+	if err := json.Unmarshal(output, &out); err != nil {
+		return result{Emacs: emacs, Kind: "emacs", Message: fmt.Sprintf("can’t parse Emacs output: %s", err)}
+	}
+	r := result{Emacs: emacs, Version: out.Version}
+	switch {
+	case out.Error != nil:
+		r.Error = *out.Error
+		r.Message = out.Message
+		r.Kind = classify(r.Error, r.Message)
+	case len(out.Missing) > 0:
+		r.Kind = "missing-function"
+		r.Missing = out.Missing
+		r.Message = "module doesn’t define " + strings.Join(out.Missing, ", ")
+	default:
+		r.OK = true
+	}
+	return r
+}
+
+// classify returns the kind of a load failure given the Lisp error symbol and
+// the error message.
+func classify(symbol, message string) string {
+	switch {
+	case symbol == "":
+		return "emacs"
+	case symbol == "module-not-gpl-compatible":
+		return "license"
+	case strings.Contains(message, "undefined symbol") || // GNU/Linux
+		strings.Contains(message, "Symbol not found") || // macOS
+		strings.Contains(message, "specified procedure could not be found"): // Windows
+		return "undefined-symbol"
+	case strings.Contains(message, "unsupported Emacs version") ||
+		strings.Contains(message, "This module requires"):
+		return "version"
+	default:
+		return "load"
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		output string
+		want   result
+	}{
+		{
+			`{"version":"29.4","missing":[]}`,
+			result{Emacs: "emacs", Version: "29.4", OK: true},
+		},
+		{
+			`{"version":"29.4","missing":["foo"]}`,
+			result{Emacs: "emacs", Version: "29.4", Kind: "missing-function", Message: "module doesn’t define foo", Missing: []string{"foo"}},
+		},
+		{
+			`{"version":"29.4","error":"module-open-failed","message":"Module could not be opened: \"/tmp/m.so\", \"/tmp/m.so: undefined symbol: foo\""}`,
+			result{Emacs: "emacs", Version: "29.4", Kind: "undefined-symbol", Error: "module-open-failed", Message: `Module could not be opened: "/tmp/m.so", "/tmp/m.so: undefined symbol: foo"`},
+		},
+		{
+			`{"version":"28.2","error":"error","message":"This module requires Emacs 29 or later (this is Emacs 28)"}`,
+			result{Emacs: "emacs", Version: "28.2", Kind: "version", Error: "error", Message: "This module requires Emacs 29 or later (this is Emacs 28)"},
+		},
+		{
+			`{"version":"29.4","error":"module-not-gpl-compatible","message":"Module is not GPL compatible"}`,
+			result{Emacs: "emacs", Version: "29.4", Kind: "license", Error: "module-not-gpl-compatible", Message: "Module is not GPL compatible"},
+		},
+		{
+			`{"version":"29.4","error":"","message":"Emacs doesn’t support dynamic modules"}`,
+			result{Emacs: "emacs", Version: "29.4", Kind: "emacs", Message: "Emacs doesn’t support dynamic modules"},
+		},
+	} {
+		if got := parse("emacs", []byte(tc.output)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parse(%s): got %+v, want %+v", tc.output, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary emacspkg assembles an Emacs module written in Go into a multi-file
+// package for package.el.  The resulting tar file can be installed using
+// package-install-file or distributed on an ELPA.  Usage:
+//
+//	emacspkg -name NAME -version VERSION [flags] [GOOS/GOARCH=MODULE...]
+//
+// Each positional argument specifies a compiled module file for one platform.
+// Alternatively or additionally, pass -build PACKAGE to build the module using
+// package github.com/phst/emacs/modulebuild, by default for the host platform
+// only; use -platforms to build for other platforms.  The package contains
+// the module files, a loader NAME.el that loads the module file matching the
+// running Emacs, and a package descriptor NAME-pkg.el.  The loader has
+// autoload cookies for all functions passed to -autoload, so that package.el
+// generates autoloads for them.
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/phst/emacs/modulebuild"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run assembles the package and returns the exit status.  It doesn’t call
+// os.Exit or log.Fatal, so that deferred cleanup runs.
+func run() int {
+	var p pkg
+	flag.StringVar(&p.name, "name", "", "package name, also used as feature name of the loader")
+	flag.StringVar(&p.version, "version", "", "package version, for example 1.2.3")
+	flag.StringVar(&p.summary, "summary", "", "one-line package summary")
+	flag.StringVar(&p.emacsVersion, "emacs-version", "28.1", "minimum Emacs version")
+	flag.StringVar(&p.url, "url", "", "package homepage")
+	autoloads := flag.String("autoload", "", "comma-separated list of functions to autoload")
+	build := flag.String("build", "", "build the given Go package and include it")
+	platforms := flag.String("platforms", "", "comma-separated list of GOOS/GOARCH platforms for -build; defaults to the host platform")
+	output := flag.String("output", "", "output file; defaults to NAME-VERSION.tar in the current directory")
+	flag.Parse()
+	if *autoloads != "" {
+		p.autoloads = strings.Split(*autoloads, ",")
+	}
+	for _, arg := range flag.Args() {
+		m, err := parseModuleArg(arg)
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		p.modules = append(p.modules, m)
+	}
+	if *build != "" {
+		dir, err := os.MkdirTemp("", "emacspkg-")
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		defer os.RemoveAll(dir)
+		ms, err := buildModules(*build, *platforms, filepath.Join(dir, p.name))
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		p.modules = append(p.modules, ms...)
+	}
+	if *output == "" {
+		*output = p.name + "-" + p.version + ".tar"
+	}
+	if err := p.validate(); err != nil {
+		log.Print(err)
+		return 1
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	if err := p.write(f); err != nil {
+		f.Close()
+		log.Print(err)
+		return 1
+	}
+	if err := f.Close(); err != nil {
+		log.Print(err)
+		return 1
+	}
+	return 0
+}
+
+// pkg describes a package to assemble.
+type pkg struct {
+	name, version, summary, emacsVersion, url string
+	autoloads                                 []string
+	modules                                   []module
+}
+
+// module is a compiled module for a single platform.
+type module struct {
+	goos, goarch string
+	file         string
+}
+
+func parseModuleArg(arg string) (module, error) {
+	platform, file, ok := strings.Cut(arg, "=")
+	if !ok {
+		return module{}, fmt.Errorf("invalid module argument %q, must be of the form GOOS/GOARCH=FILE", arg)
+	}
+	goos, goarch, ok := strings.Cut(platform, "/")
+	if !ok || goos == "" || goarch == "" {
+		return module{}, fmt.Errorf("invalid platform %q, must be of the form GOOS/GOARCH", platform)
+	}
+	return module{goos, goarch, file}, nil
+}
+
+// buildModules builds the module in the given Go package for the given
+// comma-separated platforms.  output is the module file name without
+// extension.
+func buildModules(goPkg, platforms, output string) ([]module, error) {
+	o := modulebuild.Options{Package: goPkg, Output: output, Stdout: os.Stderr, Stderr: os.Stderr}
+	if platforms != "" {
+		for _, s := range strings.Split(platforms, ",") {
+			p, err := modulebuild.ParsePlatform(s)
+			if err != nil {
+				return nil, err
+			}
+			o.Platforms = append(o.Platforms, p)
+		}
+	}
+	results, err := modulebuild.Build(context.Background(), o)
+	if err != nil {
+		return nil, err
+	}
+	var ms []module
+	for _, r := range results {
+		for _, p := range r.Platforms {
+			ms = append(ms, module{p.GOOS, p.GOARCH, r.File})
+		}
+	}
+	return ms, nil
+}
+
+var (
+	namePattern    = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	versionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*$`)
+)
+
+func (p *pkg) validate() error {
+	if !namePattern.MatchString(p.name) {
+		return fmt.Errorf("invalid package name %q", p.name)
+	}
+	if !versionPattern.MatchString(p.version) {
+		return fmt.Errorf("invalid package version %q", p.version)
+	}
+	if !versionPattern.MatchString(p.emacsVersion) {
+		return fmt.Errorf("invalid Emacs version %q", p.emacsVersion)
+	}
+	if strings.ContainsRune(p.summary, '\n') {
+		return errors.New("summary must be a single line")
+	}
+	if len(p.modules) == 0 {
+		return errors.New("no modules given")
+	}
+	seen := make(map[string]bool)
+	for _, m := range p.modules {
+		if _, ok := systemTypes[m.goos]; !ok {
+			return fmt.Errorf("unsupported operating system %s", m.goos)
+		}
+		if _, ok := architectures[m.goarch]; !ok {
+			return fmt.Errorf("unsupported architecture %s", m.goarch)
+		}
+		key := m.goos + "/" + m.goarch
+		if seen[key] {
+			return fmt.Errorf("duplicate module for platform %s", key)
+		}
+		seen[key] = true
+	}
+	sort.Slice(p.modules, func(i, j int) bool {
+		a, b := p.modules[i], p.modules[j]
+		return a.goos < b.goos || a.goos == b.goos && a.goarch < b.goarch
+	})
+	return nil
+}
+
+// Maps GOOS values to values of the Emacs variable system-type.
+var systemTypes = map[string]string{
+	"linux":   "gnu/linux",
+	"darwin":  "darwin",
+	"windows": "windows-nt",
+	"freebsd": "berkeley-unix",
+	"openbsd": "berkeley-unix",
+	"netbsd":  "berkeley-unix",
+}
+
+// Maps GOARCH values to regular expressions matching the architecture part of
+// the Emacs variable system-configuration.
+var architectures = map[string]string{
+	"amd64": `\(?:x86_64\|amd64\)`,
+	"arm64": `\(?:aarch64\|arm64\)`,
+	"386":   `i[3-6]86`,
+}
+
+func (p *pkg) moduleName(m module) string {
+	return fmt.Sprintf("%s-module-%s-%s%s", p.name, m.goos, m.goarch, modulebuild.Extension(m.goos))
+}
+
+// write writes the package tar file to w.
+func (p *pkg) write(w io.Writer) error {
+	dir := p.name + "-" + p.version + "/"
+	now := time.Now()
+	t := tar.NewWriter(w)
+	if err := t.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: dir, Mode: 0755, ModTime: now}); err != nil {
+		return err
+	}
+	add := func(name string, mode int64, b []byte) error {
+		h := &tar.Header{Typeflag: tar.TypeReg, Name: dir + name, Mode: mode, Size: int64(len(b)), ModTime: now}
+		if err := t.WriteHeader(h); err != nil {
+			return err
+		}
+		_, err := t.Write(b)
+		return err
+	}
+	if err := add(p.name+"-pkg.el", 0644, p.descriptor()); err != nil {
+		return err
+	}
+	if err := add(p.name+".el", 0644, p.loader()); err != nil {
+		return err
+	}
+	for _, m := range p.modules {
+		b, err := os.ReadFile(m.file)
+		if err != nil {
+			return err
+		}
+		if err := add(p.moduleName(m), 0755, b); err != nil {
+			return err
+		}
+	}
+	return t.Close()
+}
+
+// descriptor returns the contents of the package descriptor file NAME-pkg.el.
+func (p *pkg) descriptor() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, ";;; %s-pkg.el --- package descriptor  -*- no-byte-compile: t -*-\n\n", p.name)
+	fmt.Fprintf(&b, "(define-package %s %s %s\n  '((emacs %s))", lispString(p.name), lispString(p.version), lispString(p.summary), lispString(p.emacsVersion))
+	if p.url != "" {
+		fmt.Fprintf(&b, "\n  :url %s", lispString(p.url))
+	}
+	fmt.Fprintln(&b, ")")
+	return b.Bytes()
+}
+
+// loader returns the contents of the loader file NAME.el.
+func (p *pkg) loader() []byte {
+	var b bytes.Buffer
+	n := p.name
+	fmt.Fprintf(&b, ";;; %s.el --- %s  -*- lexical-binding: t; -*-\n\n", n, p.summary)
+	fmt.Fprintf(&b, ";; Version: %s\n", p.version)
+	fmt.Fprintf(&b, ";; Package-Requires: ((emacs %s))\n", lispString(p.emacsVersion))
+	if p.url != "" {
+		fmt.Fprintf(&b, ";; URL: %s\n", p.url)
+	}
+	fmt.Fprintln(&b, "\n;;; Commentary:\n\n;; Loads the dynamic module for the running Emacs.  Generated by emacspkg.\n\n;;; Code:")
+	fmt.Fprintf(&b, `
+(defconst %[1]s--directory
+  (file-name-directory (or load-file-name buffer-file-name))
+  "Directory containing the %[1]s package.")
+
+(defun %[1]s--module-file ()
+  "Return the name of the dynamic module file for the running Emacs."
+  (cond
+`, n)
+	for _, m := range p.modules {
+		fmt.Fprintf(&b, "   ((and (eq system-type '%s)\n         (string-match-p %s system-configuration))\n    %s)\n",
+			systemTypes[m.goos], lispString("\\`"+architectures[m.goarch]), lispString(p.moduleName(m)))
+	}
+	fmt.Fprintf(&b, `   (t (error "Package %[1]s doesn’t support %%s on %%s" system-type system-configuration))))
+
+(module-load (expand-file-name (%[1]s--module-file) %[1]s--directory))
+`, n)
+	for _, a := range p.autoloads {
+		fmt.Fprintf(&b, "\n;;;###autoload\n(autoload '%s %s)\n", a, lispString(n))
+	}
+	fmt.Fprintf(&b, "\n(provide '%s)\n\n;;; %s.el ends here\n", n, n)
+	return b.Bytes()
+}
+
+// lispString returns s as an Emacs Lisp string literal.
+func lispString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
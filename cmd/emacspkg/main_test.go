@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	linux := filepath.Join(dir, "linux.so")
+	darwin := filepath.Join(dir, "darwin.dylib")
+	for _, f := range []string{linux, darwin} {
+		if err := os.WriteFile(f, []byte(filepath.Base(f)), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := pkg{
+		name:         "foo",
+		version:      "1.2",
+		summary:      "Frobnicate things",
+		emacsVersion: "28.1",
+		autoloads:    []string{"foo-bar"},
+		modules: []module{
+			{"linux", "amd64", linux},
+			{"darwin", "arm64", darwin},
+		},
+	}
+	if err := p.validate(); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := p.write(&b); err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	r := tar.NewReader(&b)
+	for {
+		h, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[h.Name] = string(c)
+	}
+	for name, want := range map[string]string{
+		"foo-1.2/":                              "",
+		"foo-1.2/foo-module-linux-amd64.so":     "linux.so",
+		"foo-1.2/foo-module-darwin-arm64.dylib": "darwin.dylib",
+	} {
+		if got, ok := files[name]; !ok || got != want {
+			t.Errorf("file %s: got %q, want %q", name, got, want)
+		}
+	}
+	if got, want := files["foo-1.2/foo-pkg.el"], `(define-package "foo" "1.2" "Frobnicate things"`+"\n  '((emacs \"28.1\")))\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("package descriptor: got %q, want suffix %q", got, want)
+	}
+	loader := files["foo-1.2/foo.el"]
+	for _, want := range []string{
+		";; Version: 1.2\n",
+		"((and (eq system-type 'darwin)\n         (string-match-p \"\\\\`\\\\(?:aarch64\\\\|arm64\\\\)\" system-configuration))\n    \"foo-module-darwin-arm64.dylib\")",
+		";;;###autoload\n(autoload 'foo-bar \"foo\")\n",
+		"(provide 'foo)\n",
+	} {
+		if !strings.Contains(loader, want) {
+			t.Errorf("loader %q doesn’t contain %q", loader, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, p := range []pkg{
+		{name: "Foo", version: "1", emacsVersion: "28.1", modules: []module{{"linux", "amd64", "x"}}},
+		{name: "foo", version: "1.x", emacsVersion: "28.1", modules: []module{{"linux", "amd64", "x"}}},
+		{name: "foo", version: "1", emacsVersion: "28.1"},
+		{name: "foo", version: "1", emacsVersion: "28.1", modules: []module{{"plan9", "amd64", "x"}}},
+		{name: "foo", version: "1", emacsVersion: "28.1", modules: []module{{"linux", "amd64", "x"}, {"linux", "amd64", "y"}}},
+	} {
+		if err := p.validate(); err == nil {
+			t.Errorf("validate(%+v): got no error", p)
+		}
+	}
+}
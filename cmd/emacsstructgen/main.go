@@ -0,0 +1,430 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary emacsstructgen generates Go types that mirror Lisp structures defined
+// using cl-defstruct.  Usage:
+//
+//	emacsstructgen [-output FILE] [-package NAME] [-structs NAME,...] FILE.el...
+//
+// emacsstructgen reads the top-level cl-defstruct forms in the given Emacs
+// Lisp files.  For each structure it generates a Go struct type with one field
+// per slot, including slots inherited using :include.  The field types are
+// derived from the :type slot options: string becomes string, integer and
+// fixnum become int64, float and number become float64, boolean becomes bool,
+// and symbol becomes [emacs.Symbol].  All other slots have type
+// [emacs.Value]; such fields must contain valid values before you convert the
+// structure to Emacs.
+//
+// The generated type implements [emacs.In] and [emacs.Out] by calling the
+// structure’s constructor (for In) or its predicate and slot accessors (for
+// Out), so the conversion works for all structure representations that
+// cl-defstruct supports.  The type also has methods EmacsAlist and
+// FromEmacsAlist that convert from and to association lists keyed by slot
+// names.  In addition, emacsstructgen generates a variable for each slot
+// accessor and initializes it using [emacs.Import].
+//
+// Use -structs to restrict the output to some of the structures.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	output := flag.String("output", "emacs_structs.go", "name of the generated file")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name for the generated file")
+	only := flag.String("structs", "", "comma-separated list of structures to generate; defaults to all")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("no Emacs Lisp files given")
+	}
+	if *pkg == "" {
+		log.Fatal("can’t determine package name; pass -package")
+	}
+	var all []*structDef
+	for _, f := range flag.Args() {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defs, err := parseFile(string(b))
+		if err != nil {
+			log.Fatalf("%s: %s", f, err)
+		}
+		all = append(all, defs...)
+	}
+	var names []string
+	if *only != "" {
+		names = strings.Split(*only, ",")
+	}
+	b, err := generate(*pkg, all, names)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// structDef describes a structure defined using cl-defstruct.
+type structDef struct {
+	name        string
+	doc         string
+	concName    string
+	include     string
+	constructor string // empty if there’s no keyword constructor
+	predicate   string // empty if there’s no predicate
+	slots       []slot // own slots, excluding included ones
+}
+
+type slot struct {
+	name, typ string
+}
+
+// parseFile returns the structures defined by the top-level cl-defstruct
+// forms in src.
+func parseFile(src string) ([]*structDef, error) {
+	r := newReader(src)
+	var defs []*structDef
+	for {
+		x, err := r.read()
+		if err == errEOF {
+			return defs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		l, ok := x.(list)
+		if !ok || len(l) < 2 || (l[0] != symbol("cl-defstruct") && l[0] != symbol("defstruct")) {
+			continue
+		}
+		d, err := parseDefstruct(l)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		defs = append(defs, d)
+	}
+}
+
+func parseDefstruct(l list) (*structDef, error) {
+	d := new(structDef)
+	var opts list
+	switch n := l[1].(type) {
+	case symbol:
+		d.name = string(n)
+	case list:
+		if len(n) == 0 {
+			return nil, errors.New("invalid cl-defstruct name")
+		}
+		name, ok := n[0].(symbol)
+		if !ok {
+			return nil, errors.New("invalid cl-defstruct name")
+		}
+		d.name = string(name)
+		opts = n[1:]
+	default:
+		return nil, errors.New("invalid cl-defstruct name")
+	}
+	d.concName = d.name + "-"
+	d.constructor = "make-" + d.name
+	d.predicate = d.name + "-p"
+	typed, named := false, false
+	for _, o := range opts {
+		key, args := o, list(nil)
+		if ol, ok := o.(list); ok && len(ol) > 0 {
+			key, args = ol[0], ol[1:]
+		}
+		switch key {
+		case symbol(":conc-name"):
+			switch {
+			case len(args) == 0 || args[0] == symbol("nil"):
+				d.concName = ""
+			default:
+				s, err := stringOrSymbol(args[0])
+				if err != nil {
+					return nil, fmt.Errorf("structure %s: invalid :conc-name: %w", d.name, err)
+				}
+				d.concName = s
+			}
+		case symbol(":constructor"):
+			// Only the first constructor without a BOA argument list
+			// accepts keyword arguments.
+			if len(args) == 0 {
+				continue
+			}
+			if args[0] == symbol("nil") {
+				d.constructor = ""
+				continue
+			}
+			if len(args) == 1 {
+				if s, ok := args[0].(symbol); ok {
+					d.constructor = string(s)
+				}
+			}
+		case symbol(":predicate"):
+			if len(args) > 0 {
+				if s, ok := args[0].(symbol); ok {
+					d.predicate = string(s)
+					if s == "nil" {
+						d.predicate = ""
+					}
+				}
+			}
+		case symbol(":include"):
+			if len(args) == 0 {
+				return nil, fmt.Errorf("structure %s: missing :include argument", d.name)
+			}
+			s, ok := args[0].(symbol)
+			if !ok {
+				return nil, fmt.Errorf("structure %s: invalid :include argument", d.name)
+			}
+			d.include = string(s)
+		case symbol(":type"):
+			typed = true
+		case symbol(":named"):
+			named = true
+		}
+	}
+	if typed && !named {
+		// Unnamed typed structures have no predicate.
+		d.predicate = ""
+	}
+	rest := l[2:]
+	if len(rest) > 0 {
+		if s, ok := rest[0].(str); ok {
+			d.doc = string(s)
+			rest = rest[1:]
+		}
+	}
+	for _, x := range rest {
+		var s slot
+		switch x := x.(type) {
+		case symbol:
+			s.name = string(x)
+		case list:
+			if len(x) == 0 {
+				return nil, fmt.Errorf("structure %s: invalid slot", d.name)
+			}
+			name, ok := x[0].(symbol)
+			if !ok {
+				return nil, fmt.Errorf("structure %s: invalid slot", d.name)
+			}
+			s.name = string(name)
+			for i := 2; i+1 < len(x); i += 2 {
+				if x[i] == symbol(":type") {
+					s.typ = goType(x[i+1])
+				}
+			}
+		default:
+			return nil, fmt.Errorf("structure %s: invalid slot", d.name)
+		}
+		if s.typ == "" {
+			s.typ = "emacs.Value"
+		}
+		d.slots = append(d.slots, s)
+	}
+	return d, nil
+}
+
+func stringOrSymbol(x sexp) (string, error) {
+	switch x := x.(type) {
+	case symbol:
+		return string(x), nil
+	case str:
+		return string(x), nil
+	default:
+		return "", errors.New("expected string or symbol")
+	}
+}
+
+// goType returns the Go type for the given cl-defstruct :type slot option.
+func goType(t sexp) string {
+	if l, ok := t.(list); ok && len(l) == 2 && l[0] == symbol("quote") {
+		t = l[1]
+	}
+	switch t {
+	case symbol("string"):
+		return "string"
+	case symbol("integer"), symbol("fixnum"), symbol("natnum"):
+		return "int64"
+	case symbol("float"), symbol("number"):
+		return "float64"
+	case symbol("boolean"):
+		return "bool"
+	case symbol("symbol"):
+		return "emacs.Symbol"
+	default:
+		return "emacs.Value"
+	}
+}
+
+// allSlots returns all slots of d, including included ones.
+func allSlots(d *structDef, byName map[string]*structDef) ([]slot, error) {
+	var r []slot
+	seen := map[string]bool{d.name: true}
+	for p := d.include; p != ""; {
+		if seen[p] {
+			return nil, fmt.Errorf("structure %s: circular :include", d.name)
+		}
+		seen[p] = true
+		parent, ok := byName[p]
+		if !ok {
+			return nil, fmt.Errorf("structure %s includes unknown structure %s", d.name, p)
+		}
+		r = append(append([]slot(nil), parent.slots...), r...)
+		p = parent.include
+	}
+	return append(r, d.slots...), nil
+}
+
+// generate returns the formatted Go source code for the given structures.  If
+// names is nonempty, it only generates code for the structures listed in it.
+func generate(pkg string, defs []*structDef, names []string) ([]byte, error) {
+	byName := make(map[string]*structDef)
+	for _, d := range defs {
+		if _, dup := byName[d.name]; dup {
+			return nil, fmt.Errorf("duplicate structure %s", d.name)
+		}
+		byName[d.name] = d
+	}
+	if len(names) > 0 {
+		defs = nil
+		for _, n := range names {
+			d, ok := byName[n]
+			if !ok {
+				return nil, fmt.Errorf("structure %s not found", n)
+			}
+			defs = append(defs, d)
+		}
+	}
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by emacsstructgen; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintln(&b, `import "github.com/phst/emacs"`)
+	var accessors [][2]string
+	for _, d := range defs {
+		slots, err := allSlots(d, byName)
+		if err != nil {
+			return nil, err
+		}
+		if d.constructor == "" {
+			return nil, fmt.Errorf("structure %s has no keyword constructor", d.name)
+		}
+		t := camel(d.name)
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "// %s mirrors the Lisp structure %s.\n", t, d.name)
+		if d.doc != "" {
+			fmt.Fprintln(&b, "//")
+			for _, line := range strings.Split(strings.TrimRight(d.doc, "\n"), "\n") {
+				fmt.Fprintln(&b, strings.TrimRight("// "+line, " "))
+			}
+		}
+		fmt.Fprintf(&b, "type %s struct {\n", t)
+		for _, s := range slots {
+			fmt.Fprintf(&b, "\t%s %s\n", camel(s.name), s.typ)
+		}
+		fmt.Fprintln(&b, "}")
+
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "// Emacs creates a new %s structure by calling %s.\n", d.name, d.constructor)
+		fmt.Fprintf(&b, "func (s *%s) Emacs(e emacs.Env) (emacs.Value, error) {\n", t)
+		fmt.Fprintf(&b, "\treturn e.Call(%q", d.constructor)
+		for _, s := range slots {
+			fmt.Fprintf(&b, ",\n\t\temacs.Symbol(%q), emacs.NewIn(s.%s)", ":"+s.name, camel(s.name))
+		}
+		fmt.Fprintln(&b, ")\n}")
+
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "// FromEmacs sets *s from the %s structure v.\n", d.name)
+		fmt.Fprintf(&b, "func (s *%s) FromEmacs(e emacs.Env, v emacs.Value) error {\n", t)
+		if d.predicate != "" {
+			fmt.Fprintln(&b, "\tvar ok emacs.Bool")
+			fmt.Fprintf(&b, "\tif err := e.CallOut(%q, &ok, v); err != nil {\n\t\treturn err\n\t}\n", d.predicate)
+			fmt.Fprintf(&b, "\tif !ok {\n\t\treturn emacs.WrongTypeArgument(%q, v)\n\t}\n", d.predicate)
+		}
+		fmt.Fprintf(&b, "\tvar r %s\n", t)
+		for _, s := range slots {
+			acc := d.concName + s.name
+			fmt.Fprintf(&b, "\tif err := e.CallOut(%q, emacs.NewOut(&r.%s), v); err != nil {\n\t\treturn err\n\t}\n", acc, camel(s.name))
+		}
+		fmt.Fprintln(&b, "\t*s = r\n\treturn nil\n}")
+
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "// EmacsAlist returns an association list that maps the slot names of %s\n// to the slot values in s.\n", d.name)
+		fmt.Fprintf(&b, "func (s *%s) EmacsAlist(e emacs.Env) (emacs.Value, error) {\n", t)
+		fmt.Fprint(&b, "\treturn e.List(")
+		for i, s := range slots {
+			if i > 0 {
+				fmt.Fprint(&b, ",")
+			}
+			fmt.Fprintf(&b, "\n\t\temacs.Cons{Car: emacs.Symbol(%q), Cdr: emacs.NewIn(s.%s)}", s.name, camel(s.name))
+		}
+		fmt.Fprintln(&b, ")\n}")
+
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "// FromEmacsAlist sets *s from an association list that maps slot names of\n// %s to values.  Slots missing from the list get their zero value.\n", d.name)
+		fmt.Fprintf(&b, "func (s *%s) FromEmacsAlist(e emacs.Env, alist emacs.Value) error {\n", t)
+		fmt.Fprintf(&b, "\tvar r %s\n", t)
+		for _, s := range slots {
+			fmt.Fprintf(&b, "\tif c, err := e.Call(\"assq\", emacs.Symbol(%q), alist); err != nil {\n\t\treturn err\n\t} else if e.IsNotNil(c) {\n", s.name)
+			fmt.Fprintf(&b, "\t\tif err := e.CdrOut(c, emacs.NewOut(&r.%s)); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", camel(s.name))
+		}
+		fmt.Fprintln(&b, "\t*s = r\n\treturn nil\n}")
+
+		for _, s := range slots {
+			acc := d.concName + s.name
+			v := camel(acc)
+			if v == t {
+				v += "Slot"
+			}
+			fmt.Fprintln(&b)
+			fmt.Fprintf(&b, "// %s calls the accessor %s for the slot %s of %s.\n", v, acc, s.name, d.name)
+			fmt.Fprintf(&b, "var %s func(emacs.Env, emacs.Value) (%s, error)\n", v, s.typ)
+			accessors = append(accessors, [2]string{acc, v})
+		}
+	}
+	if len(accessors) > 0 {
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "func init() {")
+		for _, a := range accessors {
+			fmt.Fprintf(&b, "\temacs.Import(%q, &%s)\n", a[0], a[1])
+		}
+		fmt.Fprintln(&b, "}")
+	}
+	return format.Source(b.Bytes())
+}
+
+// camel converts a Lisp symbol name to an exported Go identifier.
+func camel(n string) string {
+	var b strings.Builder
+	for _, w := range strings.FieldsFunc(n, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	r := b.String()
+	if r == "" || !unicode.IsLetter(rune(r[0])) {
+		r = "X" + r
+	}
+	return r
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const src = `;;; shapes.el --- shapes  -*- lexical-binding: t; -*-
+
+(require 'cl-lib)
+
+(defun shapes-area (s) "Return the area of S." (* 2 ?\) 3.5))
+
+(cl-defstruct (shape (:constructor shape-create) (:copier nil))
+  "A shape.
+Has a \"name\"."
+  (name "" :type string :read-only t)
+  tags)
+
+(cl-defstruct (circle (:include shape) (:conc-name circle--))
+  (radius 1.0 :type float))
+
+(cl-defstruct (point (:type list) (:constructor nil))
+  x y)
+`
+
+func TestParseFile(t *testing.T) {
+	defs, err := parseFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) != 3 {
+		t.Fatalf("got %d structures, want 3", len(defs))
+	}
+	s, c, p := defs[0], defs[1], defs[2]
+	if s.name != "shape" || s.constructor != "shape-create" || s.predicate != "shape-p" || s.concName != "shape-" || s.doc != "A shape.\nHas a \"name\"." {
+		t.Errorf("shape: got %+v", s)
+	}
+	if len(s.slots) != 2 || s.slots[0] != (slot{"name", "string"}) || s.slots[1] != (slot{"tags", "emacs.Value"}) {
+		t.Errorf("shape slots: got %+v", s.slots)
+	}
+	if c.include != "shape" || c.concName != "circle--" || c.constructor != "make-circle" {
+		t.Errorf("circle: got %+v", c)
+	}
+	if p.predicate != "" || p.constructor != "" {
+		t.Errorf("point: got %+v", p)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	defs, err := parseFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generate("foo", defs, nil); err == nil {
+		t.Error("generate: got no error for structure without constructor")
+	}
+	b, err := generate("foo", defs, []string{"circle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		"type Circle struct {\n\tName   string\n\tTags   emacs.Value\n\tRadius float64\n}\n",
+		"return e.Call(\"make-circle\",\n\t\temacs.Symbol(\":name\"), emacs.NewIn(s.Name),",
+		"if err := e.CallOut(\"circle-p\", &ok, v); err != nil {",
+		"if err := e.CallOut(\"circle--radius\", emacs.NewOut(&r.Radius), v); err != nil {",
+		"emacs.Cons{Car: emacs.Symbol(\"tags\"), Cdr: emacs.NewIn(s.Tags)}",
+		"var CircleRadius func(emacs.Env, emacs.Value) (float64, error)",
+		"\temacs.Import(\"circle--name\", &CircleName)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code doesn’t contain %q:\n%s", want, got)
+		}
+	}
+}
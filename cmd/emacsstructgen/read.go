@@ -0,0 +1,284 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// This file contains a minimal Emacs Lisp reader.  It understands enough of
+// the syntax to skip over arbitrary forms and to extract symbols, strings,
+// and lists from cl-defstruct forms.
+
+// sexp is a Lisp object read by [reader].  It’s one of symbol, str, list, or
+// atom.
+type sexp interface{}
+
+type (
+	symbol string
+	str    string
+	list   []sexp
+	// atom is any other object, such as a number, character, or vector.
+	atom struct{}
+)
+
+type reader struct {
+	src  string
+	pos  int
+	line int
+}
+
+func newReader(src string) *reader {
+	return &reader{src: src, line: 1}
+}
+
+var errEOF = errors.New("end of input")
+
+func (r *reader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", r.line, fmt.Sprintf(format, args...))
+}
+
+func (r *reader) peek() (rune, bool) {
+	if r.pos >= len(r.src) {
+		return 0, false
+	}
+	c, _ := utf8.DecodeRuneInString(r.src[r.pos:])
+	return c, true
+}
+
+func (r *reader) next() (rune, bool) {
+	c, ok := r.peek()
+	if ok {
+		r.pos += utf8.RuneLen(c)
+		if c == '\n' {
+			r.line++
+		}
+	}
+	return c, ok
+}
+
+// skip skips whitespace and comments.
+func (r *reader) skip() {
+	for {
+		c, ok := r.peek()
+		switch {
+		case !ok:
+			return
+		case c == ';':
+			for c != '\n' && ok {
+				c, ok = r.next()
+			}
+		case strings.ContainsRune(" \t\n\r\f", c):
+			r.next()
+		case strings.HasPrefix(r.src[r.pos:], "#|"):
+			// Not valid Emacs Lisp, but cheap to support.
+			end := strings.Index(r.src[r.pos:], "|#")
+			if end < 0 {
+				r.pos = len(r.src)
+				return
+			}
+			r.line += strings.Count(r.src[r.pos:r.pos+end], "\n")
+			r.pos += end + 2
+		default:
+			return
+		}
+	}
+}
+
+// read reads the next object.  It returns errEOF at the end of the input.
+func (r *reader) read() (sexp, error) {
+	r.skip()
+	c, ok := r.peek()
+	if !ok {
+		return nil, errEOF
+	}
+	switch c {
+	case '(':
+		r.next()
+		return r.readList(')')
+	case '[':
+		r.next()
+		if _, err := r.readList(']'); err != nil {
+			return nil, err
+		}
+		return atom{}, nil
+	case ')', ']':
+		return nil, r.errorf("unexpected %c", c)
+	case '"':
+		r.next()
+		return r.readString()
+	case '?':
+		r.next()
+		if c, _ := r.next(); c == '\\' {
+			r.next()
+			// Skip modifiers such as ?\C-a and ?\^I.
+			for {
+				c, ok := r.peek()
+				if !ok || strings.ContainsRune(" \t\n()[]\";", c) {
+					break
+				}
+				r.next()
+			}
+		}
+		return atom{}, nil
+	case '\'', '`', ',':
+		r.next()
+		if c == ',' {
+			if c, _ := r.peek(); c == '@' {
+				r.next()
+			}
+		}
+		x, err := r.read()
+		if err != nil {
+			return nil, noEOF(err)
+		}
+		head := map[rune]symbol{'\'': "quote", '`': "`", ',': ","}[c]
+		return list{head, x}, nil
+	case '#':
+		r.next()
+		d, _ := r.next()
+		switch d {
+		case '\'':
+			x, err := r.read()
+			if err != nil {
+				return nil, noEOF(err)
+			}
+			return list{symbol("function"), x}, nil
+		case '(', '[', 's':
+			if d == 's' {
+				r.next()
+			}
+			if _, err := r.readList(map[rune]rune{'(': ')', '[': ']', 's': ')'}[d]); err != nil {
+				return nil, err
+			}
+			return atom{}, nil
+		case ':':
+			return r.readSymbol()
+		default:
+			r.readToken()
+			return atom{}, nil
+		}
+	default:
+		return r.readSymbol()
+	}
+}
+
+func noEOF(err error) error {
+	if err == errEOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func (r *reader) readList(end rune) (list, error) {
+	var l list
+	for {
+		r.skip()
+		c, ok := r.peek()
+		if !ok {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if c == end {
+			r.next()
+			return l, nil
+		}
+		x, err := r.read()
+		if err != nil {
+			return nil, noEOF(err)
+		}
+		// Treat dotted pairs as lists; the difference doesn’t matter
+		// for our purposes.
+		if x != symbol(".") {
+			l = append(l, x)
+		}
+	}
+}
+
+func (r *reader) readString() (str, error) {
+	var b strings.Builder
+	for {
+		c, ok := r.next()
+		if !ok {
+			return "", io.ErrUnexpectedEOF
+		}
+		switch c {
+		case '"':
+			return str(b.String()), nil
+		case '\\':
+			d, ok := r.next()
+			if !ok {
+				return "", io.ErrUnexpectedEOF
+			}
+			switch d {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case '\n', ' ':
+				// Escaped newlines and spaces are ignored.
+			default:
+				b.WriteRune(d)
+			}
+		default:
+			b.WriteRune(c)
+		}
+	}
+}
+
+func (r *reader) readToken() string {
+	var b strings.Builder
+	for {
+		c, ok := r.peek()
+		if !ok || strings.ContainsRune(" \t\n\r\f()[]\"';`,", c) {
+			return b.String()
+		}
+		r.next()
+		if c == '\\' {
+			if c, ok = r.next(); !ok {
+				return b.String()
+			}
+		}
+		b.WriteRune(c)
+	}
+}
+
+func (r *reader) readSymbol() (sexp, error) {
+	t := r.readToken()
+	if t == "" {
+		c, _ := r.next()
+		return nil, r.errorf("unexpected character %q", c)
+	}
+	if isNumber(t) {
+		return atom{}, nil
+	}
+	return symbol(t), nil
+}
+
+func isNumber(t string) bool {
+	u := strings.TrimLeft(t, "+-")
+	if u == "" || !(u[0] >= '0' && u[0] <= '9' || u[0] == '.') {
+		return false
+	}
+	if strings.HasSuffix(u, "INF") || strings.HasSuffix(u, "NaN") {
+		return true
+	}
+	_, err := strconv.ParseFloat(strings.TrimSuffix(t, "."), 64)
+	return err == nil
+}
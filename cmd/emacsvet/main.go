@@ -0,0 +1,30 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary emacsvet runs the analyzers for Emacs modules written using the
+// github.com/phst/emacs package.  You can run it directly or as a vet tool:
+//
+//	go install github.com/phst/emacs/cmd/emacsvet
+//	go vet -vettool=$(which emacsvet) ./...
+package main
+
+import (
+	"github.com/phst/emacs/analysis/escapecheck"
+	"github.com/phst/emacs/analysis/exportcheck"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(escapecheck.Analyzer, exportcheck.Analyzer)
+}
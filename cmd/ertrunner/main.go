@@ -0,0 +1,387 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary ertrunner runs the ERT tests of an Emacs module in batch mode and
+// reports the results like go test.  Usage:
+//
+//	ertrunner [-emacs BINARY] [-module FILE] [-load FILE,...] [-run REGEXP] [-selector SELECTOR] [-timeout DURATION] [-v] [-json] [PACKAGE]
+//
+// Unless -module is given, ertrunner first builds the Go package PACKAGE
+// (default: the current directory) for the host platform using package
+// github.com/phst/emacs/modulebuild.  It then starts Emacs in batch mode,
+// loads the module, which defines the tests registered with
+// github.com/phst/emacs.ERTTest and similar functions, and loads the Lisp
+// files given by -load, which can define further tests.  Afterwards it runs
+// the ERT tests that match both the regular expression given by -run and the
+// ERT test selector given by -selector.  The selector is read as a Lisp form;
+// see the ERT manual for the available selectors.  For example, -selector
+// '(not (tag :slow))' skips tests tagged with :slow.
+//
+// ertrunner prints the results in the same format as go test, or in the
+// format of go test -json if -json is given, so that tools that process Go
+// test output also work for ERT tests.  Failed tests print their failure
+// condition and the messages they’ve emitted.  A test that fails but is
+// expected to fail counts as passed.  The exit status is nonzero if any test
+// had an unexpected result, or if Emacs or the module couldn’t be loaded.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phst/emacs/modulebuild"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run runs the tests and returns the exit status.  It doesn’t call os.Exit
+// or log.Fatal, so that deferred cleanup runs.
+func run() int {
+	emacs := flag.String("emacs", "emacs", "Emacs binary to run the tests in")
+	module := flag.String("module", "", "module file to load; if empty, build the module")
+	loads := flag.String("load", "", "comma-separated list of Lisp files to load after the module")
+	run := flag.String("run", "", "run only tests whose names match this regular expression")
+	selector := flag.String("selector", "t", "run only tests that match this ERT selector")
+	timeout := flag.Duration("timeout", 10*time.Minute, "abort if the tests take longer than this")
+	verbose := flag.Bool("v", false, "print results for all tests, not only for failed ones")
+	jsonOutput := flag.Bool("json", false, "print results in the format of go test -json")
+	flag.Parse()
+	if flag.NArg() > 1 {
+		log.Print("at most one package allowed")
+		return 1
+	}
+	pkg := flag.Arg(0)
+	if pkg == "" {
+		pkg = "."
+	}
+	if *module == "" {
+		dir, err := os.MkdirTemp("", "ertrunner-")
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		defer os.RemoveAll(dir)
+		results, err := modulebuild.Build(context.Background(), modulebuild.Options{
+			Package: flag.Arg(0),
+			Output:  filepath.Join(dir, "module"),
+			Stdout:  os.Stderr,
+			Stderr:  os.Stderr,
+		})
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		*module = results[0].File
+	} else if flag.NArg() == 0 {
+		pkg = *module
+	}
+	abs, err := filepath.Abs(*module)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	var files []string
+	if *loads != "" {
+		for _, f := range strings.Split(*loads, ",") {
+			a, err := filepath.Abs(f)
+			if err != nil {
+				log.Print(err)
+				return 1
+			}
+			files = append(files, a)
+		}
+	}
+	sel := *selector
+	if *run != "" {
+		sel = fmt.Sprintf("(and %s %s)", strconv.Quote(*run), sel)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	start := time.Now()
+	results, err := runTests(ctx, *emacs, abs, files, sel)
+	cancel()
+	elapsed := time.Since(start)
+	var r reporter
+	if *jsonOutput {
+		r = &jsonReporter{enc: json.NewEncoder(os.Stdout), pkg: pkg, now: time.Now}
+	} else {
+		r = &textReporter{w: os.Stdout, pkg: pkg, verbose: *verbose}
+	}
+	if !report(r, results, err, elapsed) {
+		return 1
+	}
+	return 0
+}
+
+// testResult is the outcome of a single ERT test, as written by the Lisp form
+// in runTests.
+type testResult struct {
+	// Name is the name of the test.
+	Name string `json:"name"`
+
+	// Result is one of “passed”, “failed”, “skipped”, “quit”, or
+	// “aborted”.
+	Result string `json:"result"`
+
+	// Expected is true if the result matches the expected result of the
+	// test.
+	Expected bool `json:"expected"`
+
+	// Duration is the duration of the test in seconds.
+	Duration float64 `json:"duration"`
+
+	// Condition is the printed representation of the failure condition,
+	// if any.
+	Condition string `json:"condition"`
+
+	// Messages contains the messages emitted during the test.
+	Messages string `json:"messages"`
+}
+
+// action returns the go test action corresponding to the result: “pass”,
+// “fail”, or “skip”.
+func (r testResult) action() string {
+	switch {
+	case !r.Expected:
+		return "fail"
+	case r.Result == "skipped":
+		return "skip"
+	default:
+		return "pass"
+	}
+}
+
+// output returns the lines to print for the test result, not including the
+// status line.
+func (r testResult) output() []string {
+	var lines []string
+	if r.Condition != "" {
+		lines = append(lines, fmt.Sprintf("%s: %s", r.Result, r.Condition))
+	}
+	for _, l := range strings.Split(strings.TrimRight(r.Messages, "\n"), "\n") {
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// runTests starts emacs in batch mode, loads the module file and the Lisp
+// files into it, and runs the ERT tests that match selector.
+func runTests(ctx context.Context, emacs, module string, files []string, selector string) ([]testResult, error) {
+	out, err := os.CreateTemp("", "ertrunner-*.json")
+	if err != nil {
+		return nil, err
+	}
+	out.Close()
+	defer os.Remove(out.Name())
+	quoted := make([]string, len(files))
+	for i, f := range files {
+		quoted[i] = strconv.Quote(f)
+	}
+	// We don’t use the default batch reporter of ERT, but write one JSON
+	// object per test to a file.  Tests might also print to standard
+	// output, so we can’t use it to transport results.
+	form := fmt.Sprintf(`(progn
+  (require 'ert)
+  (require 'json)
+  (module-load %s)
+  (dolist (file '(%s))
+    (load file nil :nomessage :nosuffix))
+  (let ((out %s))
+    (ert-run-tests
+     (read %s)
+     (lambda (event &rest args)
+       (when (eq event 'test-ended)
+         (let ((test (nth 1 args))
+               (result (nth 2 args)))
+           (write-region
+            (concat
+             (json-encode
+              (list
+               (cons 'name (symbol-name (ert-test-name test)))
+               (cons 'result
+                     (cond ((ert-test-passed-p result) "passed")
+                           ((ert-test-failed-p result) "failed")
+                           ((ert-test-skipped-p result) "skipped")
+                           ((ert-test-quit-p result) "quit")
+                           (t "aborted")))
+               (cons 'expected
+                     (if (ert-test-result-expected-p test result) t :json-false))
+               (cons 'duration
+                     (if (fboundp 'ert-test-result-duration)
+                         (ert-test-result-duration result)
+                       0))
+               (cons 'condition
+                     (if (ert-test-result-with-condition-p result)
+                         (prin1-to-string
+                          (ert-test-result-with-condition-condition result))
+                       ""))
+               (cons 'messages (or (ert-test-result-messages result) ""))))
+             "\n")
+            nil out :append :silent)))))))`,
+		strconv.Quote(module), strings.Join(quoted, " "), strconv.Quote(out.Name()), strconv.Quote(selector))
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, emacs, "--quick", "--batch", "--eval", form)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, fmt.Errorf("running Emacs: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	b, err := os.ReadFile(out.Name())
+	if err != nil {
+		return nil, err
+	}
+	return parse(b)
+}
+
+// parse parses the results written by the Lisp form in runTests.
+func parse(b []byte) ([]testResult, error) {
+	var results []testResult
+	dec := json.NewDecoder(bytes.NewReader(b))
+	for {
+		var r testResult
+		err := dec.Decode(&r)
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can’t parse test results: %w", err)
+		}
+		results = append(results, r)
+	}
+}
+
+// reporter prints test results.
+type reporter interface {
+	// test reports the result of a single test.
+	test(r testResult)
+
+	// done reports the overall result.  err is non-nil if the tests
+	// couldn’t be run.
+	done(ok bool, err error, elapsed time.Duration)
+}
+
+// report prints results using r and returns whether all tests had their
+// expected result.
+func report(r reporter, results []testResult, err error, elapsed time.Duration) bool {
+	ok := err == nil
+	for _, res := range results {
+		r.test(res)
+		ok = ok && res.Expected
+	}
+	r.done(ok, err, elapsed)
+	return ok
+}
+
+// textReporter prints test results in the format of go test.
+type textReporter struct {
+	w       io.Writer
+	pkg     string
+	verbose bool
+}
+
+func (r *textReporter) test(res testResult) {
+	action := res.action()
+	if !r.verbose && action != "fail" {
+		return
+	}
+	if r.verbose {
+		fmt.Fprintf(r.w, "=== RUN   %s\n", res.Name)
+	}
+	fmt.Fprintf(r.w, "--- %s: %s (%.2fs)\n", strings.ToUpper(action), res.Name, res.Duration)
+	for _, l := range res.output() {
+		fmt.Fprintf(r.w, "    %s\n", l)
+	}
+}
+
+func (r *textReporter) done(ok bool, err error, elapsed time.Duration) {
+	if err != nil {
+		fmt.Fprintln(r.w, err)
+	}
+	if ok {
+		if r.verbose {
+			fmt.Fprintln(r.w, "PASS")
+		}
+		fmt.Fprintf(r.w, "ok  \t%s\t%.3fs\n", r.pkg, elapsed.Seconds())
+	} else {
+		fmt.Fprintln(r.w, "FAIL")
+		fmt.Fprintf(r.w, "FAIL\t%s\t%.3fs\n", r.pkg, elapsed.Seconds())
+	}
+}
+
+// jsonReporter prints test results in the format of go test -json, see
+// go doc test2json.
+type jsonReporter struct {
+	enc *json.Encoder
+	pkg string
+	now func() time.Time
+}
+
+// event is a go test -json event.
+type event struct {
+	Time    time.Time `json:",omitempty"`
+	Action  string
+	Package string   `json:",omitempty"`
+	Test    string   `json:",omitempty"`
+	Elapsed *float64 `json:",omitempty"`
+	Output  string   `json:",omitempty"`
+}
+
+func (r *jsonReporter) emit(ev event) {
+	ev.Time = r.now()
+	ev.Package = r.pkg
+	if err := r.enc.Encode(ev); err != nil {
+		log.Print(err)
+	}
+}
+
+func (r *jsonReporter) test(res testResult) {
+	action := res.action()
+	r.emit(event{Action: "run", Test: res.Name})
+	r.emit(event{Action: "output", Test: res.Name, Output: fmt.Sprintf("=== RUN   %s\n", res.Name)})
+	for _, l := range res.output() {
+		r.emit(event{Action: "output", Test: res.Name, Output: fmt.Sprintf("    %s\n", l)})
+	}
+	r.emit(event{Action: "output", Test: res.Name, Output: fmt.Sprintf("--- %s: %s (%.2fs)\n", strings.ToUpper(action), res.Name, res.Duration)})
+	elapsed := res.Duration
+	r.emit(event{Action: action, Test: res.Name, Elapsed: &elapsed})
+}
+
+func (r *jsonReporter) done(ok bool, err error, elapsed time.Duration) {
+	if err != nil {
+		r.emit(event{Action: "output", Output: err.Error() + "\n"})
+	}
+	action := "pass"
+	if !ok {
+		action = "fail"
+	}
+	r.emit(event{Action: "output", Output: strings.ToUpper(action) + "\n"})
+	secs := elapsed.Seconds()
+	r.emit(event{Action: action, Elapsed: &secs})
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	output := `{"name":"go-pass","result":"passed","expected":true,"duration":0.5,"condition":"","messages":""}
+{"name":"go-fail","result":"failed","expected":false,"duration":0.25,"condition":"(ert-test-failed ((should (= 1 2)) :form (= 1 2) :value nil))","messages":"hello\n"}
+`
+	got, err := parse([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []testResult{
+		{Name: "go-pass", Result: "passed", Expected: true, Duration: 0.5},
+		{Name: "go-fail", Result: "failed", Duration: 0.25, Condition: "(ert-test-failed ((should (= 1 2)) :form (= 1 2) :value nil))", Messages: "hello\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parse: got %+v, want %+v", got, want)
+	}
+	if _, err := parse([]byte("{")); err == nil {
+		t.Error("parse: got no error for invalid output")
+	}
+}
+
+func TestAction(t *testing.T) {
+	for _, tc := range []struct {
+		result   string
+		expected bool
+		want     string
+	}{
+		{"passed", true, "pass"},
+		{"passed", false, "fail"},
+		{"failed", true, "pass"},
+		{"failed", false, "fail"},
+		{"skipped", true, "skip"},
+		{"quit", false, "fail"},
+		{"aborted", false, "fail"},
+	} {
+		r := testResult{Result: tc.result, Expected: tc.expected}
+		if got := r.action(); got != tc.want {
+			t.Errorf("action(%s, expected=%t): got %q, want %q", tc.result, tc.expected, got, tc.want)
+		}
+	}
+}
+
+var reportResults = []testResult{
+	{Name: "go-pass", Result: "passed", Expected: true, Duration: 0.5},
+	{Name: "go-fail", Result: "failed", Duration: 0.25, Condition: "(error \"boom\")", Messages: "hello\n"},
+}
+
+func TestTextReporter(t *testing.T) {
+	var b bytes.Buffer
+	ok := report(&textReporter{w: &b, pkg: "example"}, reportResults, nil, 1500*time.Millisecond)
+	if ok {
+		t.Error("report: got ok, want failure")
+	}
+	want := `--- FAIL: go-fail (0.25s)
+    failed: (error "boom")
+    hello
+FAIL
+FAIL	example	1.500s
+`
+	if got := b.String(); got != want {
+		t.Errorf("report: got\n%s\nwant\n%s", got, want)
+	}
+
+	b.Reset()
+	ok = report(&textReporter{w: &b, pkg: "example", verbose: true}, reportResults[:1], nil, time.Second)
+	if !ok {
+		t.Error("report: got failure, want ok")
+	}
+	want = `=== RUN   go-pass
+--- PASS: go-pass (0.50s)
+PASS
+ok  	example	1.000s
+`
+	if got := b.String(); got != want {
+		t.Errorf("report: got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	var b bytes.Buffer
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := &jsonReporter{enc: json.NewEncoder(&b), pkg: "example", now: func() time.Time { return now }}
+	if report(r, nil, errors.New("can’t load module"), time.Second) {
+		t.Error("report: got ok, want failure")
+	}
+	var got []string
+	dec := json.NewDecoder(&b)
+	for dec.More() {
+		var ev event
+		if err := dec.Decode(&ev); err != nil {
+			t.Fatal(err)
+		}
+		if !ev.Time.Equal(now) || ev.Package != "example" {
+			t.Errorf("event %+v: wrong time or package", ev)
+		}
+		got = append(got, ev.Action+" "+strings.TrimSpace(ev.Output))
+	}
+	want := []string{"output can’t load module", "output FAIL", "fail "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events: got %q, want %q", got, want)
+	}
+
+	b.Reset()
+	if report(r, reportResults, nil, time.Second) {
+		t.Error("report: got ok, want failure")
+	}
+	got = nil
+	dec = json.NewDecoder(&b)
+	for dec.More() {
+		var ev event
+		if err := dec.Decode(&ev); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, ev.Action+" "+ev.Test+" "+strings.TrimSpace(ev.Output))
+	}
+	want = []string{
+		"run go-pass ",
+		"output go-pass === RUN   go-pass",
+		"output go-pass --- PASS: go-pass (0.50s)",
+		"pass go-pass ",
+		"run go-fail ",
+		"output go-fail === RUN   go-fail",
+		`output go-fail failed: (error "boom")`,
+		"output go-fail hello",
+		"output go-fail --- FAIL: go-fail (0.25s)",
+		"fail go-fail ",
+		"output  FAIL",
+		"fail  ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events: got %q, want %q", got, want)
+	}
+}
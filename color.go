@@ -0,0 +1,112 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Color is an Emacs color specification, either a color name such as "red"
+// or a hexadecimal RGB specification such as "#ff0000".  See [Color Names].
+// Color is represented as a string in Emacs.
+//
+// [Color Names]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Color-Names.html
+type Color string
+
+// Emacs returns the color specification as an Emacs string.
+func (c Color) Emacs(e Env) (Value, error) {
+	return String(c).Emacs(e)
+}
+
+// FromEmacs sets *c to the Emacs string v.  It returns an error if v isn’t a
+// string.
+func (c *Color) FromEmacs(e Env, v Value) error {
+	s, err := e.Str(v)
+	if err != nil {
+		return err
+	}
+	*c = Color(s)
+	return nil
+}
+
+// RGB is a color given by its red, green, and blue components.  The
+// components range from 0 to 65535, as in the return value of the Emacs
+// function color-values.  RGB is represented as a hexadecimal color
+// specification in Emacs; see [RGB.Color].
+type RGB struct{ R, G, B uint16 }
+
+// Color returns the hexadecimal color specification for c.  If all
+// components are representable in 8 bits without loss of precision, the
+// result has the form #rrggbb, otherwise #rrrrggggbbbb.
+func (c RGB) Color() Color {
+	if c.R%0x101 == 0 && c.G%0x101 == 0 && c.B%0x101 == 0 {
+		return Color(fmt.Sprintf("#%02x%02x%02x", c.R/0x101, c.G/0x101, c.B/0x101))
+	}
+	return Color(fmt.Sprintf("#%04x%04x%04x", c.R, c.G, c.B))
+}
+
+// Emacs returns the hexadecimal color specification for c as an Emacs string.
+func (c RGB) Emacs(e Env) (Value, error) {
+	return c.Color().Emacs(e)
+}
+
+// FromEmacs sets *c from v, which must be either a list of three integers as
+// returned by color-values, or a color specification that color-values
+// accepts.
+func (c *RGB) FromEmacs(e Env, v Value) error {
+	var str Bool
+	if err := e.CallOut("stringp", &str, v); err != nil {
+		return err
+	}
+	if str {
+		r, err := e.Call("color-values", v)
+		if err != nil {
+			return err
+		}
+		if e.IsNil(r) {
+			return WrongTypeArgument("color-defined-p", v)
+		}
+		v = r
+	}
+	var r RGB
+	u := UnpackList{
+		reflectUint(reflect.ValueOf(&r.R)),
+		reflectUint(reflect.ValueOf(&r.G)),
+		reflectUint(reflect.ValueOf(&r.B)),
+	}
+	if err := u.FromEmacs(e, v); err != nil {
+		return err
+	}
+	if len(u) != 3 {
+		return WrongTypeArgument("listp", v)
+	}
+	*c = r
+	return nil
+}
+
+// ColorValues returns the RGB components of the color c on the selected frame
+// using color-values.  If c isn’t a defined color, ColorValues returns false.
+func (e Env) ColorValues(c Color) (RGB, bool, error) {
+	v, err := e.Call("color-values", c)
+	if err != nil || e.IsNil(v) {
+		return RGB{}, false, err
+	}
+	var r RGB
+	if err := r.FromEmacs(e, v); err != nil {
+		return RGB{}, false, err
+	}
+	return r, true, nil
+}
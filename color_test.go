@@ -0,0 +1,63 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	ERTTest(colorValues)
+}
+
+func TestRGBColor(t *testing.T) {
+	for _, tc := range []struct {
+		rgb  RGB
+		want Color
+	}{
+		{RGB{0xFFFF, 0, 0x8080}, "#ff0080"},
+		{RGB{0x1234, 0, 0}, "#123400000000"},
+	} {
+		if got := tc.rgb.Color(); got != tc.want {
+			t.Errorf("%+v.Color() = %q, want %q", tc.rgb, got, tc.want)
+		}
+	}
+}
+
+func colorValues(e Env) error {
+	got, ok, err := e.ColorValues("#00ff00")
+	if err != nil {
+		return err
+	}
+	if want := (RGB{0, 0xFFFF, 0}); !ok || got != want {
+		return fmt.Errorf("ColorValues: got %+v, %v; want %+v, true", got, ok, want)
+	}
+	if _, ok, err := e.ColorValues("no such color"); err != nil || ok {
+		return fmt.Errorf("ColorValues of undefined color: got %v, %v; want false", ok, err)
+	}
+	v, err := RGB{0x1234, 0x5678, 0x9ABC}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	var rgb RGB
+	if err := rgb.FromEmacs(e, v); err != nil {
+		return err
+	}
+	if want := (RGB{0x1234, 0x5678, 0x9ABC}); rgb != want {
+		return fmt.Errorf("RGB roundtrip: got %+v, want %+v", rgb, want)
+	}
+	return nil
+}
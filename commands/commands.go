@@ -0,0 +1,289 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package commands defines interactive Emacs commands from Go functions.  The
+// parameter types of the Go function determine the interactive specification
+// of the command: for example, a parameter of type [Region] receives the
+// region, and a parameter of type [ExistingFile] prompts for an existing file
+// name.  Parameters of type string prompt for an arbitrary string, and
+// parameters of type int prompt for a number.  See [Interactive Codes] for
+// the underlying mechanism.
+//
+//	func wordCount(e emacs.Env, r commands.Region) error { … }
+//
+//	func init() {
+//		commands.Register(commands.Command{
+//			Name: "my-word-count",
+//			Fun:  wordCount,
+//			Doc:  "Count the words in the region.",
+//		})
+//	}
+//
+// Defining commands requires Emacs 28 or later.
+//
+// [Interactive Codes]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Interactive-Codes.html
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/phst/emacs"
+)
+
+// Region is a parameter type that receives the start and end positions of
+// the region, smallest first.  It corresponds to the interactive code “r”.
+type Region struct{ Start, End int }
+
+// PrefixArg is a parameter type that receives the numeric prefix argument.
+// It corresponds to the interactive code “p”.
+type PrefixArg int
+
+// Point is a parameter type that receives the position of point.  It
+// corresponds to the interactive code “d”.
+type Point int
+
+// ExistingFile is a parameter type that prompts for the name of an existing
+// file.  It corresponds to the interactive code “f”.
+type ExistingFile string
+
+// FileName is a parameter type that prompts for a file name, which need not
+// exist.  It corresponds to the interactive code “F”.
+type FileName string
+
+// Directory is a parameter type that prompts for the name of an existing
+// directory.  It corresponds to the interactive code “D”.
+type Directory string
+
+// BufferName is a parameter type that prompts for the name of an existing
+// buffer.  It corresponds to the interactive code “b”.
+type BufferName string
+
+// Command describes an interactive command.
+type Command struct {
+	// Name is the name of the command.  It must not be empty.
+	Name emacs.Name
+
+	// Fun is the Go function that implements the command.  Optionally,
+	// the first parameter may be of type [emacs.Env].  All other
+	// parameters must have one of the types described in the package
+	// documentation.  The function may return nothing or an error.
+	Fun interface{}
+
+	// Doc is the documentation string of the command.
+	Doc emacs.Doc
+
+	// Prompts contains the prompts for the parameters of Fun, in order,
+	// not counting an initial [emacs.Env] parameter.  Missing or empty
+	// prompts are replaced by a default prompt for the parameter type.
+	// Parameters that don’t prompt ignore their prompts.
+	Prompts []string
+}
+
+// Register arranges for the command c to be defined once the module is
+// loaded.  Call Register in an init function.  Register panics if c is
+// invalid or its name is already registered.  Register returns c.Name so you
+// can assign it directly to a Go variable if you want.
+func Register(c Command) emacs.Name {
+	d, err := newDefinition(c)
+	if err != nil {
+		panic(err)
+	}
+	manager.MustEnqueue(c.Name, d)
+	return c.Name
+}
+
+// Define is like [Register], except that it requires a live environment,
+// defines the command immediately, and returns errors instead of panicking.
+func Define(e emacs.Env, c Command) error {
+	d, err := newDefinition(c)
+	if err != nil {
+		return err
+	}
+	return manager.RegisterAndDefine(e, c.Name, d)
+}
+
+var manager = emacs.NewManager(emacs.RequireName | emacs.RequireUniqueName | emacs.DefineOnInit)
+
+// definition is a command ready for definition.
+type definition struct {
+	name  emacs.Name
+	fun   reflect.Value
+	env   bool
+	args  []argument
+	spec  string
+	arity emacs.Arity
+	doc   emacs.Doc
+}
+
+func newDefinition(c Command) (*definition, error) {
+	if c.Name == "" {
+		return nil, fmt.Errorf("command for %T has no name", c.Fun)
+	}
+	fun := reflect.ValueOf(c.Fun)
+	if fun.Kind() != reflect.Func {
+		return nil, fmt.Errorf("command %s: %T is not a function", c.Name, c.Fun)
+	}
+	t := fun.Type()
+	if t.IsVariadic() {
+		return nil, fmt.Errorf("command %s: variadic functions aren’t supported", c.Name)
+	}
+	switch {
+	case t.NumOut() == 0:
+	case t.NumOut() == 1 && t.Out(0) == errorType:
+	default:
+		return nil, fmt.Errorf("command %s: function must return nothing or an error", c.Name)
+	}
+	d := &definition{name: c.Name, fun: fun, doc: c.Doc}
+	first := 0
+	if t.NumIn() > 0 && t.In(0) == envType {
+		d.env = true
+		first = 1
+	}
+	var codes []string
+	for i := first; i < t.NumIn(); i++ {
+		a, ok := argumentFor(t.In(i))
+		if !ok {
+			return nil, fmt.Errorf("command %s: unsupported parameter type %s", c.Name, t.In(i))
+		}
+		d.args = append(d.args, a)
+		code := a.code
+		if a.prompt != "" {
+			prompt := a.prompt
+			if j := i - first; j < len(c.Prompts) && c.Prompts[j] != "" {
+				prompt = c.Prompts[j]
+			}
+			if strings.Contains(prompt, "\n") {
+				return nil, fmt.Errorf("command %s: prompt %q contains a newline", c.Name, prompt)
+			}
+			code += prompt
+		}
+		codes = append(codes, code)
+		d.arity.Min += a.n
+	}
+	d.arity.Max = d.arity.Min
+	d.spec = strings.Join(codes, "\n")
+	return d, nil
+}
+
+// Define defines the command as a module function and sets its interactive
+// specification.
+func (d *definition) Define(e emacs.Env) error {
+	f, err := e.ExportFunc(d.name, d.call, d.arity, d.doc)
+	if err != nil {
+		return err
+	}
+	spec, err := emacs.String(d.spec).Emacs(e)
+	if err != nil {
+		return err
+	}
+	return e.MakeInteractive(f, spec)
+}
+
+func (d *definition) call(e emacs.Env, args []emacs.Value) (emacs.Value, error) {
+	var in []reflect.Value
+	if d.env {
+		in = append(in, reflect.ValueOf(e))
+	}
+	for _, a := range d.args {
+		v, err := a.convert(e, args[:a.n])
+		if err != nil {
+			return emacs.Value{}, err
+		}
+		in = append(in, v.Convert(a.typ))
+		args = args[a.n:]
+	}
+	out := d.fun.Call(in)
+	if len(out) == 1 && !out[0].IsNil() {
+		return emacs.Value{}, out[0].Interface().(error)
+	}
+	return e.Nil()
+}
+
+// argument describes how to obtain a parameter interactively.
+type argument struct {
+	typ     reflect.Type
+	code    string
+	prompt  string // default prompt; empty if the code doesn’t prompt
+	n       int    // number of Lisp arguments
+	convert func(emacs.Env, []emacs.Value) (reflect.Value, error)
+}
+
+func argumentFor(t reflect.Type) (argument, bool) {
+	a := argument{typ: t, n: 1}
+	switch t {
+	case regionType:
+		a.code = "r"
+		a.n = 2
+		a.convert = func(e emacs.Env, args []emacs.Value) (reflect.Value, error) {
+			start, err := e.Int(args[0])
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			end, err := e.Int(args[1])
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(Region{int(start), int(end)}), nil
+		}
+		return a, true
+	case prefixArgType:
+		a.code = "p"
+	case pointType:
+		a.code = "d"
+	case existingFileType:
+		a.code, a.prompt = "f", "File: "
+	case fileNameType:
+		a.code, a.prompt = "F", "File: "
+	case directoryType:
+		a.code, a.prompt = "D", "Directory: "
+	case bufferNameType:
+		a.code, a.prompt = "b", "Buffer: "
+	default:
+		switch t.Kind() {
+		case reflect.String:
+			a.code, a.prompt = "s", "String: "
+		case reflect.Int:
+			a.code, a.prompt = "n", "Number: "
+		default:
+			return argument{}, false
+		}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		a.convert = func(e emacs.Env, args []emacs.Value) (reflect.Value, error) {
+			s, err := e.Str(args[0])
+			return reflect.ValueOf(s), err
+		}
+	case reflect.Int:
+		a.convert = func(e emacs.Env, args []emacs.Value) (reflect.Value, error) {
+			i, err := e.Int(args[0])
+			return reflect.ValueOf(int(i)), err
+		}
+	}
+	return a, true
+}
+
+var (
+	envType          = reflect.TypeOf(emacs.Env{})
+	errorType        = reflect.TypeOf((*error)(nil)).Elem()
+	regionType       = reflect.TypeOf(Region{})
+	prefixArgType    = reflect.TypeOf(PrefixArg(0))
+	pointType        = reflect.TypeOf(Point(0))
+	existingFileType = reflect.TypeOf(ExistingFile(""))
+	fileNameType     = reflect.TypeOf(FileName(""))
+	directoryType    = reflect.TypeOf(Directory(""))
+	bufferNameType   = reflect.TypeOf(BufferName(""))
+)
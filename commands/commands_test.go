@@ -0,0 +1,72 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/phst/emacs"
+)
+
+func TestSpec(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		fun     interface{}
+		prompts []string
+		spec    string
+		arity   emacs.Arity
+	}{
+		{"empty", func() {}, nil, "", emacs.Arity{Min: 0, Max: 0}},
+		{
+			"region",
+			func(emacs.Env, Region, PrefixArg) error { return nil },
+			nil, "r\np", emacs.Arity{Min: 3, Max: 3},
+		},
+		{
+			"prompts",
+			func(ExistingFile, BufferName, string, int, Point) {},
+			[]string{"Input file: ", "", "Name: "},
+			"fInput file: \nbBuffer: \nsName: \nnNumber: \nd", emacs.Arity{Min: 5, Max: 5},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := newDefinition(Command{Name: "test", Fun: tc.fun, Prompts: tc.prompts})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.spec != tc.spec {
+				t.Errorf("spec: got %q, want %q", d.spec, tc.spec)
+			}
+			if d.arity != tc.arity {
+				t.Errorf("arity: got %+v, want %+v", d.arity, tc.arity)
+			}
+		})
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, c := range []Command{
+		{Fun: func() {}},
+		{Name: "test", Fun: 1},
+		{Name: "test", Fun: func(float64) {}},
+		{Name: "test", Fun: func() int { return 0 }},
+		{Name: "test", Fun: func(...string) {}},
+		{Name: "test", Fun: func(string) {}, Prompts: []string{"a\nb"}},
+	} {
+		if _, err := newDefinition(c); err == nil {
+			t.Errorf("newDefinition(%+v): got no error", c)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Candidate is a completion candidate returned by a [CompletionAtPoint]
+// function.
+type Candidate struct {
+	Text string
+
+	// Annotation is displayed directly after the candidate, so it
+	// typically starts with a space.  It’s returned by the
+	// :annotation-function of the completion.
+	Annotation string
+
+	// Kind is the kind of the candidate, such as function or variable.
+	// It’s returned by the :company-kind function of the completion,
+	// which some completion user interfaces use to display icons.
+	Kind Symbol
+}
+
+// Completion is the result of a [CompletionAtPoint] function.  It describes
+// the text to complete and the completion candidates.
+type Completion struct {
+	// Start and End are the buffer positions of the text to complete.
+	Start, End int
+
+	// Candidates are the completion candidates.  They are ignored if
+	// Async is non-nil.
+	Candidates []Candidate
+
+	// If Async is non-nil, it computes the candidates.  Emacs calls Async
+	// in a new goroutine as soon as the completion-at-point function
+	// returns, and waits for its result only when the candidates are
+	// needed.  While waiting, Emacs remains responsive to user input:
+	// completion user interfaces that compute candidates in the
+	// background, for example using while-no-input, can abort the wait.
+	// Async must not interact with Emacs.
+	Async func() ([]Candidate, error)
+
+	// If NonExclusive is true, Emacs tries other completion-at-point
+	// functions if none of the candidates match.
+	NonExclusive bool
+}
+
+// CompletionAtPoint describes a completion-at-point function.  See
+// [Completion in Buffers].
+//
+// [Completion in Buffers]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Completion-in-Buffers.html
+type CompletionAtPoint struct {
+	// Fun is called without arguments like all completion-at-point
+	// functions.  If completion isn’t possible at point, Fun should return
+	// false.
+	Fun func(Env) (Completion, bool, error)
+
+	// If Exit is non-nil, Emacs calls it after completion has finished.
+	// The status is one of the symbols finished, sole, or exact.
+	Exit func(e Env, candidate string, status Symbol) error
+
+	// Doc is the documentation string of the completion-at-point
+	// function.
+	Doc Doc
+}
+
+// DefineCompletionAtPoint arranges for a completion-at-point function with
+// the given name to be defined once the module is loaded.  Add the name to
+// completion-at-point-functions to use it.  In addition to name,
+// DefineCompletionAtPoint defines some internal helper functions whose names
+// start with name followed by two hyphens.  DefineCompletionAtPoint panics if
+// name is empty or already registered.  It returns name so you can assign it
+// directly to a Go variable if you want.
+func DefineCompletionAtPoint(name Name, c CompletionAtPoint) Name {
+	capf, err := newCapf(name, c)
+	if err != nil {
+		panic(err)
+	}
+	for _, f := range capf.functions() {
+		ExportFunc(f.name, f.Fun, f.Arity, f.Doc)
+	}
+	return name
+}
+
+// DefineCompletionAtPoint is like the global [DefineCompletionAtPoint]
+// function, except that it requires a live environment, defines the
+// functions immediately, and returns errors instead of panicking.
+func (e Env) DefineCompletionAtPoint(name Name, c CompletionAtPoint) error {
+	capf, err := newCapf(name, c)
+	if err != nil {
+		return err
+	}
+	for _, f := range capf.functions() {
+		if _, err := e.ExportFunc(f.name, f.Fun, f.Arity, f.Doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// capf implements a completion-at-point function.
+type capf struct {
+	name Name
+	c    CompletionAtPoint
+
+	// The most recent asynchronous candidate computation.  We only keep
+	// one to avoid accumulating abandoned computations.
+	mu      sync.Mutex
+	id      uint64
+	pending *pendingCandidates
+}
+
+type pendingCandidates struct {
+	done       chan struct{}
+	candidates []Candidate
+	err        error
+}
+
+type namedLambda struct {
+	name Name
+	Lambda
+}
+
+func newCapf(name Name, c CompletionAtPoint) (*capf, error) {
+	if name == "" {
+		return nil, errors.New("empty completion-at-point function name")
+	}
+	if c.Fun == nil {
+		return nil, fmt.Errorf("completion-at-point function %s has no Go function", name)
+	}
+	return &capf{name: name, c: c}, nil
+}
+
+func (c *capf) functions() []namedLambda {
+	r := []namedLambda{
+		{c.name, Lambda{c.call, Arity{0, 0}, c.c.Doc}},
+		{c.name + "--table", Lambda{c.table, Arity{4, 4}, "Completion table for asynchronous candidates.\n\n(fn ID STRING PREDICATE ACTION)"}},
+	}
+	if c.c.Exit != nil {
+		r = append(r, namedLambda{c.name + "--exit", Lambda{c.exit, Arity{2, 2}, "Exit function for completion.\n\n(fn CANDIDATE STATUS)"}})
+	}
+	return r
+}
+
+func (c *capf) call(e Env, args []Value) (Value, error) {
+	comp, ok, err := c.c.Fun(e)
+	if err != nil {
+		return Value{}, err
+	}
+	if !ok {
+		return e.Nil()
+	}
+	var collection In
+	if comp.Async == nil {
+		var list List
+		list, err = candidateList(e, comp.Candidates)
+		if err != nil {
+			return Value{}, err
+		}
+		collection = list
+	} else {
+		p := &pendingCandidates{done: make(chan struct{})}
+		c.mu.Lock()
+		c.id++
+		id := c.id
+		c.pending = p
+		c.mu.Unlock()
+		go func() {
+			defer close(p.done)
+			p.candidates, p.err = comp.Async()
+		}()
+		collection, err = e.Call("apply-partially", c.name+"--table", Uint(id))
+		if err != nil {
+			return Value{}, err
+		}
+	}
+	annotate, err := e.Eval(List{
+		Symbol("lambda"), List{Symbol("c")},
+		List{Symbol("get-text-property"), Int(0), List{Symbol("quote"), candidateAnnotation}, Symbol("c")},
+	})
+	if err != nil {
+		return Value{}, err
+	}
+	kind, err := e.Eval(List{
+		Symbol("lambda"), List{Symbol("c")},
+		List{Symbol("get-text-property"), Int(0), List{Symbol("quote"), candidateKind}, Symbol("c")},
+	})
+	if err != nil {
+		return Value{}, err
+	}
+	r := List{
+		Int(comp.Start), Int(comp.End), collection,
+		Symbol(":annotation-function"), annotate,
+		Symbol(":company-kind"), kind,
+	}
+	if c.c.Exit != nil {
+		r = append(r, Symbol(":exit-function"), c.name+"--exit")
+	}
+	if comp.NonExclusive {
+		r = append(r, Symbol(":exclusive"), Symbol("no"))
+	}
+	return r.Emacs(e)
+}
+
+func (c *capf) table(e Env, args []Value) (Value, error) {
+	id, err := e.Uint(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	c.mu.Lock()
+	var p *pendingCandidates
+	if id == c.id {
+		p = c.pending
+	}
+	c.mu.Unlock()
+	var cands []Candidate
+	if p != nil {
+	wait:
+		for {
+			select {
+			case <-p.done:
+				break wait
+			default:
+				// sit-for processes input, so while-no-input and
+				// quitting can interrupt the wait.
+				if _, err := e.Call("sit-for", Float(0.01)); err != nil {
+					return Value{}, err
+				}
+			}
+		}
+		if p.err != nil {
+			return Value{}, p.err
+		}
+		cands = p.candidates
+	}
+	list, err := candidateList(e, cands)
+	if err != nil {
+		return Value{}, err
+	}
+	return e.Call("complete-with-action", args[3], list, args[1], args[2])
+}
+
+func (c *capf) exit(e Env, args []Value) (Value, error) {
+	s, err := e.Str(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	status, err := e.Symbol(args[1])
+	if err != nil {
+		return Value{}, err
+	}
+	if err := c.c.Exit(e, s, status); err != nil {
+		return Value{}, err
+	}
+	return e.Nil()
+}
+
+// Text properties that store candidate annotations and kinds.
+const (
+	candidateAnnotation Symbol = "emacs-go-annotation"
+	candidateKind       Symbol = "emacs-go-kind"
+)
+
+// candidateList returns a list of candidate strings.  The strings carry
+// annotations and kinds as text properties.
+func candidateList(e Env, cands []Candidate) (List, error) {
+	r := make(List, len(cands))
+	for i, c := range cands {
+		var kind In = Nil
+		if c.Kind != "" {
+			kind = c.Kind
+		}
+		v, err := e.Call(
+			"propertize", String(c.Text),
+			candidateAnnotation, optionalString(c.Annotation),
+			candidateKind, kind,
+		)
+		if err != nil {
+			return nil, err
+		}
+		r[i] = v
+	}
+	return r, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func init() {
+	ERTTest(completionAtPoint)
+}
+
+func completionAtPoint(e Env) error {
+	cands := []Candidate{
+		{Text: "foo", Annotation: " (function)", Kind: "function"},
+		{Text: "foobar", Kind: "variable"},
+		{Text: "baz"},
+	}
+	for _, tc := range []struct {
+		name Name
+		comp Completion
+	}{
+		{"emacs-go-test--capf-sync", Completion{Start: 1, End: 1, Candidates: cands}},
+		{"emacs-go-test--capf-async", Completion{Start: 1, End: 1, Async: func() ([]Candidate, error) { return cands, nil }}},
+	} {
+		comp := tc.comp
+		if err := e.DefineCompletionAtPoint(tc.name, CompletionAtPoint{
+			Fun: func(Env) (Completion, bool, error) { return comp, true, nil },
+		}); err != nil {
+			return err
+		}
+		r, err := e.Call(tc.name)
+		if err != nil {
+			return err
+		}
+		var start, end Int
+		var collection Value
+		if err := (&UnpackList{&start, &end, &collection}).FromEmacs(e, r); err != nil {
+			return err
+		}
+		matches, err := e.Call("all-completions", String("foo"), collection)
+		if err != nil {
+			return err
+		}
+		var got []string
+		if err := e.Dolist(matches, func(v Value) error {
+			s, err := e.Str(v)
+			got = append(got, s)
+			return err
+		}); err != nil {
+			return err
+		}
+		if want := []string{"foo", "foobar"}; !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("%s: got candidates %q, want %q", tc.name, got, want)
+		}
+		props, err := e.Call("nthcdr", Int(3), r)
+		if err != nil {
+			return err
+		}
+		first, err := e.Car(matches)
+		if err != nil {
+			return err
+		}
+		for key, want := range map[Symbol]string{
+			":annotation-function": `" (function)"`,
+			":company-kind":        "function",
+		} {
+			f, err := e.Call("plist-get", props, key)
+			if err != nil {
+				return err
+			}
+			v, err := e.Call("funcall", f, first)
+			if err != nil {
+				return err
+			}
+			if got := e.FormatMessage("%S", v); got != want {
+				return fmt.Errorf("%s: %s returned %s, want %s", tc.name, key, got, want)
+			}
+		}
+	}
+	return nil
+}
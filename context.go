@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"context"
+	"time"
+)
+
+// Context returns a context derived from parent that’s canceled when the user
+// quits.  Call the returned stop function once the context is no longer
+// needed; stop cancels the context.  Because only the Emacs thread may
+// process input, the Emacs thread has to wait for the work using [Env.Wait],
+// which processes input periodically.  If the user quits while Wait runs,
+// Wait cancels the context; [context.Cause] then returns the quit error.  A
+// typical exported function starts the work in a separate goroutine:
+//
+//	func slowOperation(e emacs.Env) (string, error) {
+//		ctx, stop := e.Context(e.SpanContext())
+//		defer stop()
+//		var r string
+//		var err error
+//		done := make(chan struct{})
+//		go func() {
+//			defer close(done)
+//			r, err = compute(ctx)  // must not use e
+//		}()
+//		if err := e.Wait(ctx, done); err != nil {
+//			return "", err
+//		}
+//		return r, err
+//	}
+//
+// If parent is nil, Context uses [context.Background].
+func (e Env) Context(parent context.Context) (context.Context, func()) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	ctx = context.WithValue(ctx, quitCancelKey{}, cancel)
+	return ctx, func() { cancel(nil) }
+}
+
+// Wait blocks until done is closed or ctx is canceled.  While waiting, it
+// processes input periodically using [Env.ProcessInput].  If the user quits,
+// Wait cancels ctx if it was returned by [Env.Context], and returns the quit
+// error; return that error to Emacs as soon as possible.  If ctx is canceled
+// for another reason, Wait returns ctx.Err().  Otherwise, Wait returns nil
+// once done is closed.  Wait may only be called on the Emacs thread, i.e.,
+// from an exported function or module initializer that received e.
+func (e Env) Wait(ctx context.Context, done <-chan struct{}) error {
+	tick := time.NewTicker(processInputInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+			if err := e.ProcessInput(); err != nil {
+				if cancel, ok := ctx.Value(quitCancelKey{}).(context.CancelCauseFunc); ok {
+					cancel(err)
+				}
+				return err
+			}
+		}
+	}
+}
+
+// processInputInterval is the interval in which [Env.Wait] processes input.
+// It’s a compromise between responsiveness to quits and overhead.
+const processInputInterval = 100 * time.Millisecond
+
+type quitCancelKey struct{}
@@ -0,0 +1,57 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func init() {
+	ERTTest(contextWait)
+}
+
+func contextWait(e Env) error {
+	ctx, stop := e.Context(context.Background())
+	defer stop()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Wait long enough that Env.Wait processes input at least once.
+		time.Sleep(2 * processInputInterval)
+	}()
+	if err := e.Wait(ctx, done); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, stop = e.Context(parent)
+	defer stop()
+	cancel()
+	if err := e.Wait(ctx, make(chan struct{})); !errors.Is(err, context.Canceled) {
+		return errors.New("Wait didn’t return cancelation error")
+	}
+
+	ctx, stop = e.Context(context.Background())
+	stop()
+	if ctx.Err() == nil {
+		return errors.New("stop didn’t cancel context")
+	}
+	return nil
+}
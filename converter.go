@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// RegisterInFunc registers f as the conversion from Go values of type t to
+// Emacs values.  After registration, [InFuncFor] returns f for t, so values of
+// type t work everywhere autoconversion is used: as results of exported
+// functions, as arguments of imported functions, as elements of slices and
+// maps, in struct fields, and with [NewIn] and [Reflect].  This is useful for
+// types from other packages, which can’t implement [In].  A registered
+// conversion takes precedence over the built-in conversion for the kind of t,
+// but not over an implementation of [In] by t itself.
+//
+// Register conversions from an init function, before exporting or importing
+// functions that use the type.  RegisterInFunc panics if t is nil, f is nil,
+// or there’s already a conversion registered for t.  You can call
+// RegisterInFunc safely from multiple goroutines.
+func RegisterInFunc(t reflect.Type, f InFunc) {
+	if t == nil || f == nil {
+		panic("nil type or conversion function")
+	}
+	converters.mu.Lock()
+	defer converters.mu.Unlock()
+	if _, dup := converters.in[t]; dup {
+		panic(fmt.Errorf("duplicate conversion from type %s to Emacs", t))
+	}
+	if converters.in == nil {
+		converters.in = make(map[reflect.Type]InFunc)
+	}
+	converters.in[t] = f
+}
+
+// RegisterOutFunc registers f as the conversion from Emacs values to Go values
+// of type t.  f receives a pointer to a t value, like all [OutFunc]
+// functions.  After registration, [OutFuncFor] returns f for the pointer type
+// *t, so values of type t work everywhere autoconversion is used: as
+// arguments of exported functions, as results of imported functions, as
+// elements of slices and maps, in struct fields, and with [NewOut] and
+// [Reflect].  A registered conversion takes precedence over the built-in
+// conversion for the kind of t, but not over an implementation of [Out] by *t
+// itself.
+//
+// Register conversions from an init function, before exporting or importing
+// functions that use the type.  RegisterOutFunc panics if t is nil, f is nil,
+// or there’s already a conversion registered for t.  You can call
+// RegisterOutFunc safely from multiple goroutines.
+func RegisterOutFunc(t reflect.Type, f OutFunc) {
+	if t == nil || f == nil {
+		panic("nil type or conversion function")
+	}
+	converters.mu.Lock()
+	defer converters.mu.Unlock()
+	if _, dup := converters.out[t]; dup {
+		panic(fmt.Errorf("duplicate conversion from Emacs to type %s", t))
+	}
+	if converters.out == nil {
+		converters.out = make(map[reflect.Type]OutFunc)
+	}
+	converters.out[t] = f
+}
+
+// registeredInFunc returns the conversion registered for t using
+// [RegisterInFunc], or nil if there’s none.
+func registeredInFunc(t reflect.Type) InFunc {
+	converters.mu.RLock()
+	defer converters.mu.RUnlock()
+	return converters.in[t]
+}
+
+// registeredOutFunc returns the conversion registered for t using
+// [RegisterOutFunc], or nil if there’s none.  t is the element type, not the
+// pointer type.
+func registeredOutFunc(t reflect.Type) OutFunc {
+	converters.mu.RLock()
+	defer converters.mu.RUnlock()
+	return converters.out[t]
+}
+
+// converters contains the conversions registered using [RegisterInFunc] and
+// [RegisterOutFunc].
+var converters struct {
+	mu  sync.RWMutex
+	in  map[reflect.Type]InFunc
+	out map[reflect.Type]OutFunc
+}
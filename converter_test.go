@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// celsius is a type without exported fields, so there’s no built-in
+// conversion for it.
+type celsius struct{ degrees float64 }
+
+type celsiusOut struct{ *celsius }
+
+func (c celsiusOut) FromEmacs(e Env, v Value) error {
+	var f Float
+	if err := f.FromEmacs(e, v); err != nil {
+		return err
+	}
+	c.degrees = float64(f)
+	return nil
+}
+
+func init() {
+	celsiusType := reflect.TypeOf(celsius{})
+	RegisterInFunc(celsiusType, func(v reflect.Value) In {
+		return Float(v.Interface().(celsius).degrees)
+	})
+	RegisterOutFunc(celsiusType, func(v reflect.Value) Out {
+		return celsiusOut{v.Interface().(*celsius)}
+	})
+	ERTTest(registeredConversion)
+}
+
+func registeredConversion(e Env) error {
+	v, err := e.Emacs([]celsius{{21.5}})
+	if err != nil {
+		return err
+	}
+	var got []celsius
+	if err := e.Go(v, &got); err != nil {
+		return err
+	}
+	if want := []celsius{{21.5}}; !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("roundtrip: got %v, want %v", got, want)
+	}
+	return nil
+}
+
+func TestRegisterConversion(t *testing.T) {
+	celsiusType := reflect.TypeOf(celsius{})
+	if _, err := InFuncFor(celsiusType); err != nil {
+		t.Errorf("InFuncFor(%s): %s", celsiusType, err)
+	}
+	if _, err := OutFuncFor(reflect.PtrTo(celsiusType)); err != nil {
+		t.Errorf("OutFuncFor(*%s): %s", celsiusType, err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a duplicate conversion didn’t panic")
+		}
+	}()
+	RegisterInFunc(celsiusType, func(reflect.Value) In { return Nil })
+}
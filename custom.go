@@ -0,0 +1,176 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "fmt"
+
+// Custom arranges for an Emacs user option to be defined using defcustom once
+// the module is loaded.  See [Customization Settings].  Unlike variables
+// defined with [Var], user options show up in the Customize interface, which
+// also checks new values against their type.  init is the standard value of
+// the option; Custom quotes it, so it isn’t evaluated.  Use [CustomType],
+// [Group], and [CustomSet] options to specify the :type, :group, and :set
+// keywords of defcustom.  Options without a type or group work, but
+// Customize can’t check their values and byte compilation would warn about
+// them, so you should always specify both.  Custom panics if the name is
+// empty or already registered, or if an option is invalid.  Custom returns
+// name so you can assign it directly to a Go variable if you want.
+//
+// [Customization Settings]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Customization.html
+func Custom(name Name, init In, doc Doc, opts ...CustomOption) Name {
+	customs.MustEnqueue(name, newCustomVariable(name, init, doc, opts))
+	return name
+}
+
+// Custom is like the global [Custom] function, except that it requires a
+// live environment, defines the user option immediately, and returns errors
+// instead of panicking.
+func (e Env) Custom(name Name, init In, doc Doc, opts ...CustomOption) error {
+	return customs.RegisterAndDefine(e, name, newCustomVariable(name, init, doc, opts))
+}
+
+// CustomOption is an option for [Custom] and [Env.Custom].  Use
+// [CustomType], [Group], or [CustomSet] to create options.
+type CustomOption interface {
+	applyCustom(*customVariable)
+}
+
+// CustomType returns a [CustomOption] that specifies the type of a user
+// option, such as Symbol("integer") or
+// List{Symbol("choice"), Symbol("string"), List{Symbol("const"), Nil}}.  See
+// [Customization Types].  The type is passed unevaluated as the :type keyword
+// of defcustom.
+//
+// [Customization Types]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Customization-Types.html
+func CustomType(spec In) CustomOption {
+	return customType{spec}
+}
+
+type customType struct{ spec In }
+
+func (t customType) applyCustom(v *customVariable) { v.typ = t.spec }
+
+// Group is a [CustomOption] that adds a user option to a customization
+// group.  It corresponds to the :group keyword of defcustom.  You can
+// specify more than one group.
+type Group Name
+
+func (g Group) applyCustom(v *customVariable) { v.groups = append(v.groups, Name(g)) }
+
+// CustomSet returns a [CustomOption] that specifies a Go function to call
+// when the user changes the option using Customize.  It corresponds to the
+// :set keyword of defcustom.  fun is converted as described in
+// [AutoLambda]; it receives the option symbol and the new value.  The
+// function is responsible for setting the option, typically using
+// set-default, and can perform additional actions such as reconfiguring
+// the module:
+//
+//	emacs.CustomSet(func(e emacs.Env, sym emacs.Symbol, v emacs.Value) error {
+//		if _, err := e.Call("set-default", sym, v); err != nil {
+//			return err
+//		}
+//		return reconfigure(e, v)
+//	})
+//
+// The function stays defined as a function symbol whose name starts with
+// “go--custom-set-”, followed by a number.  Redefining the option reuses the
+// function.  CustomSet panics if fun isn’t
+// convertible or can’t accept two arguments.
+func CustomSet(fun interface{}) CustomOption {
+	l := AutoLambda(fun, Anonymous{})
+	if l.Arity.Min > 2 || (l.Arity.Max >= 0 && l.Arity.Max < 2) {
+		panic(fmt.Errorf(":set function must accept two arguments, but its arity is %v", l.Arity))
+	}
+	return customSet{l}
+}
+
+type customSet struct{ l Lambda }
+
+func (s customSet) applyCustom(v *customVariable) { v.set = &customSetter{l: s.l} }
+
+// customSetter is the :set function of a user option.  Copies of a
+// [customVariable] share it, so that the function is exported only once even
+// if the option is defined several times.  Its methods must be called on the
+// Emacs thread.
+type customSetter struct {
+	l   Lambda
+	fun numberedFunc // zero if not exported
+}
+
+// export exports the function unless that has already happened.  It returns
+// the function name and whether export has exported the function just now.
+func (s *customSetter) export(e Env) (Name, bool, error) {
+	if s.fun.name != "" {
+		return s.fun.name, false, nil
+	}
+	name, index, err := e.exportNumbered("go--custom-set-", s.l)
+	if err != nil {
+		return "", false, err
+	}
+	s.fun = numberedFunc{name, index}
+	return name, true, nil
+}
+
+// release releases the exported function, so that the next call to export
+// exports it again.
+func (s *customSetter) release(e Env) error {
+	fun := s.fun
+	s.fun = numberedFunc{}
+	return e.releaseNumbered(fun.name, fun.index)
+}
+
+// customVariable is a user option defined by [Custom] or [Env.Custom].
+type customVariable struct {
+	variable
+	typ    In
+	groups []Name
+	set    *customSetter
+}
+
+func newCustomVariable(name Name, init In, doc Doc, opts []CustomOption) customVariable {
+	v := customVariable{variable: variable{name, init, doc}}
+	for _, opt := range opts {
+		opt.applyCustom(&v)
+	}
+	return v
+}
+
+func (v customVariable) Define(e Env) error {
+	form := List{Symbol("defcustom"), v.name, List{Symbol("quote"), v.init}, v.doc}
+	if v.typ != nil {
+		form = append(form, Symbol(":type"), List{Symbol("quote"), v.typ})
+	}
+	for _, g := range v.groups {
+		form = append(form, Symbol(":group"), List{Symbol("quote"), g})
+	}
+	fresh := false
+	if v.set != nil {
+		name, exported, err := v.set.export(e)
+		if err != nil {
+			return err
+		}
+		fresh = exported
+		form = append(form, Symbol(":set"), List{Symbol("function"), name})
+	}
+	// Can’t use Call because defcustom is a macro.
+	_, err := e.Eval(form)
+	if err != nil && fresh {
+		// No user option refers to the function yet.
+		v.set.release(e)
+	}
+	return err
+}
+
+var customs = NewManagerOf[customVariable](RequireName | RequireUniqueName | DefineOnInit)
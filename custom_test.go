@@ -0,0 +1,123 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func ExampleCustom() {
+	Custom("go-custom-limit", Int(10), "Maximum number of results.",
+		CustomType(Symbol("natnum")), Group("go"))
+}
+
+func init() {
+	ExampleCustom()
+	ERTTest(customDefine)
+	ERTTest(customRedefine)
+}
+
+func customDefine(e Env) error {
+	var n Int
+	if err := e.CallOut("symbol-value", &n, Symbol("go-custom-limit")); err != nil {
+		return err
+	}
+	if n != 10 {
+		return fmt.Errorf("go-custom-limit: got %d, want 10", n)
+	}
+	var custom Bool
+	if err := e.CallOut("custom-variable-p", &custom, Symbol("go-custom-limit")); err != nil {
+		return err
+	}
+	if !custom {
+		return errors.New("go-custom-limit isn’t a user option")
+	}
+	var set []Value
+	err := e.Custom("go--custom-test", List{Int(1), Int(2)}, "Test option.",
+		CustomType(List{Symbol("repeat"), Symbol("integer")}), Group("go"),
+		CustomSet(func(e Env, sym Symbol, v Value) error {
+			set = append(set, v)
+			_, err := e.Call("set-default", sym, v)
+			return err
+		}))
+	if err != nil {
+		return err
+	}
+	if _, err := e.Call("customize-set-variable", Symbol("go--custom-test"), List{Int(3)}); err != nil {
+		return err
+	}
+	v, err := e.Call("symbol-value", Symbol("go--custom-test"))
+	if err != nil {
+		return err
+	}
+	var eq Bool
+	if err := e.CallOut("equal", &eq, v, List{Int(3)}); err != nil {
+		return err
+	}
+	if !eq {
+		return errors.New("go--custom-test: got unexpected value, want (3)")
+	}
+	// defcustom calls the :set function for the initial value, too.
+	if len(set) != 2 {
+		return fmt.Errorf(":set function called %d times, want 2", len(set))
+	}
+	if err := e.Custom("go--custom-test", Nil, ""); err == nil {
+		return errors.New("redefining go--custom-test succeeded")
+	}
+	return nil
+}
+
+func customRedefine(e Env) error {
+	const name Name = "go--custom-redefine-test"
+	v := newCustomVariable(name, Int(0), "Test option.", []CustomOption{
+		CustomType(Symbol("integer")), Group("go"),
+		CustomSet(func(e Env, sym Symbol, v Value) error {
+			_, err := e.Call("set-default", sym, v)
+			return err
+		}),
+	})
+	var funs [2]Symbol
+	for i := range funs {
+		if err := v.Define(e); err != nil {
+			return err
+		}
+		if err := e.CallOut("get", &funs[i], name, Symbol("custom-set")); err != nil {
+			return err
+		}
+	}
+	if funs[0] != funs[1] {
+		return fmt.Errorf("redefinition exported a new :set function: got %s, want %s", funs[1], funs[0])
+	}
+	return nil
+}
+
+func TestCustomSet(t *testing.T) {
+	CustomSet(func(Symbol, Value) {})
+	for _, fun := range []interface{}{
+		func() {},
+		func(Symbol, Value, int) {},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("CustomSet(%T) didn’t panic", fun)
+				}
+			}()
+			CustomSet(fun)
+		}()
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SetDebugMode enables or disables debug mode.  Debug mode is initially
+// enabled if the environment variable EMACS_GO_DEBUG is set to a nonempty
+// value other than “0” when the program starts.  In debug mode, errors and
+// panics that escape exported functions produce a [FailureReport].
+// Additionally, debug mode enables the following checks:
+//
+//   - Errors while converting between Go and Emacs values are wrapped in
+//     [ConversionError] values that describe where in a nested value the
+//     conversion failed, for example “argument 2 → map value for key a →
+//     vector element 0 of type int”.
+//   - Exported functions check the number of arguments against their arity
+//     before calling the Go function.
+//   - Using an [Env] after the exported function or module initializer that
+//     received it has returned panics instead of causing undefined behavior.
+//     The check is inactive while an environment is live that became live
+//     before debug mode was enabled, so preferably enable debug mode using
+//     the environment variable.  The emacsdebug build tag enables a stricter
+//     check that also covers values; see the package documentation.
+//
+// Debug mode slows down all calls.  You can call SetDebugMode safely from
+// multiple goroutines.
+func SetDebugMode(on bool) {
+	var i int32
+	if on {
+		i = 1
+	}
+	atomic.StoreInt32(&debugMode, i)
+}
+
+// DebugMode returns whether debug mode is enabled.  See [SetDebugMode].
+func DebugMode() bool {
+	return atomic.LoadInt32(&debugMode) != 0
+}
+
+var debugMode int32 = debugModeFromEnv()
+
+func debugModeFromEnv() int32 {
+	if v := os.Getenv("EMACS_GO_DEBUG"); v != "" && v != "0" {
+		return 1
+	}
+	return 0
+}
+
+// FailureReport describes an error or panic that escaped an exported function
+// while debug mode was enabled.  It combines the Go stack and the Lisp
+// backtrace at the time of the failure.
+type FailureReport struct {
+	Time time.Time
+
+	// Function is the name of the exported function.  It’s empty for
+	// anonymous functions and for panics during module initialization.
+	Function Name
+
+	// Message is the error message.
+	Message string
+
+	// GoStack is the stack trace of the goroutine that ran the exported
+	// function, as returned by [runtime/debug.Stack].  Only for panics
+	// does it show where the failure happened: if the exported function
+	// returns an error, the stack is captured after the function has
+	// returned, so it only shows how Emacs called the function.
+	GoStack string
+
+	// LispBacktrace contains one line per Lisp backtrace frame, innermost
+	// frame first, as returned by backtrace-frames.
+	LispBacktrace string
+}
+
+// String formats the report for human consumption.
+func (r FailureReport) String() string {
+	fun := string(r.Function)
+	if fun == "" {
+		fun = "Go function"
+	}
+	return fmt.Sprintf("%s failed at %s: %s\n\nLisp backtrace:\n%s\n\nGo stack:\n%s",
+		fun, r.Time.Format(time.RFC3339), r.Message, r.LispBacktrace, r.GoStack)
+}
+
+// LastFailureReport returns the most recent [FailureReport].  It returns false
+// if no failure has been reported yet.  You can call LastFailureReport safely
+// from multiple goroutines.
+func LastFailureReport() (FailureReport, bool) {
+	failures.mu.Lock()
+	defer failures.mu.Unlock()
+	return failures.last, failures.ok
+}
+
+// FailureReportBuffer is the name of the buffer that shows the most recent
+// [FailureReport].
+const FailureReportBuffer = "*Go module failure*"
+
+var failures struct {
+	mu   sync.Mutex
+	last FailureReport
+	ok   bool
+}
+
+// reportFailure creates a failure report and shows it in
+// [FailureReportBuffer].  It ignores errors, since it runs while handling
+// another error.
+func (e Env) reportFailure(name Name, err error, stack []byte) {
+	r := FailureReport{
+		Time:          time.Now(),
+		Function:      name,
+		Message:       e.Message(err),
+		GoStack:       string(stack),
+		LispBacktrace: e.lispBacktrace(),
+	}
+	failures.mu.Lock()
+	failures.last, failures.ok = r, true
+	failures.mu.Unlock()
+	if _, err := e.Eval(List{
+		Symbol("with-current-buffer"), List{Symbol("get-buffer-create"), String(FailureReportBuffer)},
+		List{Symbol("let"), List{List{Symbol("inhibit-read-only"), T}}, List{Symbol("erase-buffer")}},
+	}); err != nil {
+		return
+	}
+	e.appendToBuffer(FailureReportBuffer, r.String())
+}
+
+// lispBacktrace returns the current Lisp backtrace, one line per frame.
+func (e Env) lispBacktrace() string {
+	v, err := e.Eval(List{
+		Symbol("mapconcat"),
+		List{
+			Symbol("lambda"), List{Symbol("frame")},
+			List{
+				Symbol("truncate-string-to-width"),
+				List{
+					Symbol("prin1-to-string"),
+					List{Symbol("cons"), List{Symbol("nth"), Int(1), Symbol("frame")}, List{Symbol("nth"), Int(2), Symbol("frame")}},
+				},
+				Int(200), Nil, Nil, String("…"),
+			},
+		},
+		List{Symbol("backtrace-frames")},
+		String("\n"),
+	})
+	if err != nil {
+		return fmt.Sprintf("unavailable: %s", e.Message(err))
+	}
+	s, err := e.Str(v)
+	if err != nil {
+		return fmt.Sprintf("unavailable: %s", e.Message(err))
+	}
+	return strings.TrimSpace(s)
+}
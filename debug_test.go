@@ -0,0 +1,185 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func init() {
+	ERTTest(failureReport)
+	ERTTest(failureReportPanic)
+	ERTTest(conversionErrorPath)
+}
+
+func failureReport(e Env) error {
+	old := DebugMode()
+	SetDebugMode(true)
+	defer SetDebugMode(old)
+	const name Name = "emacs-go-test-failing-function"
+	if _, err := e.ExportFunc(name, func(Env, []Value) (Value, error) {
+		return Value{}, errors.New("expected failure")
+	}, Arity{0, 0}, ""); err != nil {
+		return err
+	}
+	defer e.Call("kill-buffer", String(FailureReportBuffer))
+	if _, err := e.Call(name); err == nil {
+		return fmt.Errorf("%s succeeded unexpectedly", name)
+	}
+	r, ok := LastFailureReport()
+	if !ok {
+		return errors.New("no failure report")
+	}
+	if r.Function != name {
+		return fmt.Errorf("failure report function: got %s, want %s", r.Function, name)
+	}
+	if !strings.Contains(r.Message, "expected failure") {
+		return fmt.Errorf("failure report message: got %q", r.Message)
+	}
+	if !strings.Contains(r.LispBacktrace, string(name)) {
+		return fmt.Errorf("Lisp backtrace doesn’t mention %s:\n%s", name, r.LispBacktrace)
+	}
+	if !strings.Contains(r.GoStack, "phst_emacs_trampoline") {
+		return fmt.Errorf("Go stack doesn’t mention the trampoline:\n%s", r.GoStack)
+	}
+	return nil
+}
+
+func failureReportPanic(e Env) error {
+	old := DebugMode()
+	SetDebugMode(true)
+	defer SetDebugMode(old)
+	const name Name = "emacs-go-test-panicking-function"
+	if _, err := e.ExportFunc(name, func(Env, []Value) (Value, error) {
+		panic("expected panic")
+	}, Arity{0, 0}, ""); err != nil {
+		return err
+	}
+	defer e.Call("kill-buffer", String(FailureReportBuffer))
+	if _, err := e.Call(name); err == nil {
+		return fmt.Errorf("%s succeeded unexpectedly", name)
+	}
+	r, ok := LastFailureReport()
+	if !ok {
+		return errors.New("no failure report")
+	}
+	if r.Function != name {
+		return fmt.Errorf("failure report function: got %s, want %s", r.Function, name)
+	}
+	if !strings.Contains(r.Message, "expected panic") {
+		return fmt.Errorf("failure report message: got %q", r.Message)
+	}
+	return nil
+}
+
+func conversionErrorPath(e Env) error {
+	old := DebugMode()
+	SetDebugMode(true)
+	defer SetDebugMode(old)
+	fun, del, err := e.Lambda(func(a int, b map[string][]int) {})
+	if err != nil {
+		return err
+	}
+	defer del()
+	arg, err := e.Eval(List{
+		Symbol("let"), List{List{Symbol("h"), List{Symbol("make-hash-table"), Symbol(":test"), Symbol("equal")}}},
+		List{Symbol("puthash"), String("a"), Vector{Int(1), String("x")}, Symbol("h")},
+		Symbol("h"),
+	})
+	if err != nil {
+		return err
+	}
+	one, err := Int(1).Emacs(e)
+	if err != nil {
+		return err
+	}
+	_, err = e.Funcall(fun, []Value{one, arg})
+	if !e.IsWrongTypeArgument(err) {
+		return fmt.Errorf("got error %v, want wrong-type-argument", err)
+	}
+	msg := e.Message(err)
+	const want = "argument 2 → map value for key a → vector element 1 of type int"
+	if !strings.Contains(msg, want) {
+		return fmt.Errorf("error message %q doesn’t contain %q", msg, want)
+	}
+	return nil
+}
+
+func TestConversionContext(t *testing.T) {
+	old := DebugMode()
+	defer SetDebugMode(old)
+
+	SetDebugMode(false)
+	orig := errors.New("original error")
+	if err := conversionContext(orig, "result", reflect.TypeOf(0)); err != orig {
+		t.Errorf("conversionContext in normal mode: got %v, want %v", err, orig)
+	}
+
+	SetDebugMode(true)
+	err := conversionContext(orig, "vector element 1", reflect.TypeOf(0))
+	err = conversionContext(err, "argument 2", reflect.TypeOf([]int(nil)))
+	got, ok := err.(*ConversionError)
+	if !ok {
+		t.Fatalf("conversionContext returned %#v, want *ConversionError", err)
+	}
+	if want := []string{"argument 2", "vector element 1"}; !reflect.DeepEqual(got.Path, want) {
+		t.Errorf("Path: got %q, want %q", got.Path, want)
+	}
+	if want := reflect.TypeOf(0); got.Type != want {
+		t.Errorf("Type: got %v, want %v", got.Type, want)
+	}
+	if !errors.Is(err, orig) {
+		t.Errorf("error %v doesn’t wrap %v", err, orig)
+	}
+	if want := "argument 2 → vector element 1 of type int: original error"; err.Error() != want {
+		t.Errorf("Error: got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestInFuncForPath(t *testing.T) {
+	old := DebugMode()
+	defer SetDebugMode(old)
+	SetDebugMode(true)
+	_, err := InFuncFor(reflect.TypeOf(map[string][]chan int(nil)))
+	if err == nil {
+		t.Fatal("InFuncFor succeeded unexpectedly")
+	}
+	const want = "map value → vector element of type chan int: "
+	if !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("error: got %q, want prefix %q", err.Error(), want)
+	}
+}
+
+func TestArityCheck(t *testing.T) {
+	for _, tc := range []struct {
+		arity Arity
+		n     int
+		ok    bool
+	}{
+		{Arity{1, 2}, 0, false},
+		{Arity{1, 2}, 1, true},
+		{Arity{1, 2}, 2, true},
+		{Arity{1, 2}, 3, false},
+		{Arity{1, -1}, 5, true},
+	} {
+		if err := tc.arity.check(tc.n); (err == nil) != tc.ok {
+			t.Errorf("%v.check(%d) = %v, want ok = %t", tc.arity, tc.n, err, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDeclarations writes Emacs Lisp declarations for all functions
+// registered using [Export] or [ExportFunc] and all variables registered using
+// [Var] to w.  Functions are declared using declare-function, variables using
+// defvar without a value.  file should be the name of the module file as
+// you’d pass it to load or require, for example "my-module".  Lisp code that
+// includes the declarations byte-compiles without warnings about unknown
+// functions or variables even if the module isn’t loaded at compile time.
+//
+// Because registration happens in init functions, you don’t need a running
+// Emacs to call WriteDeclarations.  A typical approach is a small Go program
+// that imports your module package and calls WriteDeclarations from its main
+// function; you can then run it with go generate to keep the declarations in
+// sync.  You can also call WriteDeclarations from an exported function.
+//
+// The argument lists in the function declarations are derived from the
+// [Usage] of each function if its argument names match its [Arity], and
+// consist of generic argument names otherwise.
+func WriteDeclarations(w io.Writer, file string) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, ";; Declarations for functions and variables defined by the Go module.")
+	fmt.Fprintln(b, ";; This file has been generated; don’t edit it by hand.")
+	fileLit := String(file).lisp()
+	for _, f := range funcs.base.Items() {
+		if f.name == "" {
+			continue
+		}
+		fmt.Fprintf(b, "(declare-function %s %s %s)\n", Symbol(f.name).lisp(), fileLit, arglist(f.Arity, f.Doc))
+	}
+	for _, v := range vars.Items() {
+		fmt.Fprintf(b, "(defvar %s)\n", Symbol(v.name).lisp())
+	}
+	return b.Flush()
+}
+
+// arglist returns a Lisp argument list for a function with the given arity
+// and documentation string.
+func arglist(arity Arity, doc Doc) string {
+	var names []string
+	if _, hasUsage, usage := doc.SplitUsage(); hasUsage {
+		for _, n := range strings.Fields(string(usage)) {
+			if !strings.HasPrefix(n, "&") {
+				names = append(names, strings.ToLower(n))
+			}
+		}
+	}
+	want := arity.Max
+	if arity.Variadic() {
+		want = arity.Min + 1
+	}
+	if len(names) != want {
+		names = make([]string, want)
+		for i := range names {
+			names[i] = fmt.Sprintf("arg%d", i+1)
+		}
+		if arity.Variadic() {
+			names[want-1] = "rest"
+		}
+	}
+	var parts []string
+	for i, n := range names {
+		switch {
+		case arity.Variadic() && i == arity.Min:
+			parts = append(parts, "&rest")
+		case !arity.Variadic() && i == arity.Min:
+			parts = append(parts, "&optional")
+		}
+		parts = append(parts, Symbol(n).lisp())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// lisp returns the printed representation of the symbol s, as prin1 would
+// print it.
+func (s Symbol) lisp() string {
+	if s == "" {
+		return "##"
+	}
+	if s == "." {
+		return `\.`
+	}
+	var b strings.Builder
+	for i, r := range s {
+		if r <= ' ' || strings.ContainsRune("\"';()[]#`,\\", r) || (i == 0 && r == '?') {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// lisp returns the printed representation of the string s, as prin1 would
+// print it.
+func (s String) lisp() string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteDeclarations(t *testing.T) {
+	var b strings.Builder
+	if err := WriteDeclarations(&b, "example-module"); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		`(declare-function mersenne-prime-p "example-module" (n))` + "\n",
+		"(defvar go-var)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("declarations %q don’t contain %q", got, want)
+		}
+	}
+}
+
+func TestArglist(t *testing.T) {
+	for _, tc := range []struct {
+		arity Arity
+		doc   Doc
+		want  string
+	}{
+		{Arity{0, 0}, "", "()"},
+		{Arity{2, 2}, "", "(arg1 arg2)"},
+		{Arity{1, 2}, "", "(arg1 &optional arg2)"},
+		{Arity{1, -1}, "", "(arg1 &rest rest)"},
+		{Arity{2, 2}, Doc("").WithUsage("A B"), "(a b)"},
+		{Arity{1, -1}, Doc("").WithUsage("A &rest B"), "(a &rest b)"},
+		{Arity{2, 2}, Doc("").WithUsage("A"), "(arg1 arg2)"},
+	} {
+		if got := arglist(tc.arity, tc.doc); got != tc.want {
+			t.Errorf("arglist(%v, %q) = %s, want %s", tc.arity, tc.doc, got, tc.want)
+		}
+	}
+}
+
+func TestSymbolLisp(t *testing.T) {
+	for _, tc := range []struct {
+		sym  Symbol
+		want string
+	}{
+		{"foo-bar", "foo-bar"},
+		{"", "##"},
+		{".", `\.`},
+		{"a b", `a\ b`},
+		{"?x", `\?x`},
+		{"x?", "x?"},
+		{"(x)", `\(x\)`},
+	} {
+		if got := tc.sym.lisp(); got != tc.want {
+			t.Errorf("Symbol(%q).lisp() = %s, want %s", tc.sym, got, tc.want)
+		}
+	}
+}
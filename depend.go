@@ -0,0 +1,227 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dependent is an optional interface for [QueuedItem] objects.  Items that
+// implement Dependent are defined after the items they depend on; see
+// [ManagerOf.DefineQueued].
+type Dependent interface {
+	// Dependencies returns the names of the items that have to be
+	// defined before this item.  The items can be registered with any
+	// manager.  Names that aren’t registered with any manager are
+	// ignored; they typically refer to entities defined by Emacs or Lisp
+	// packages.
+	Dependencies() []Name
+}
+
+// DependOn declares that the items with the given name depend on the items
+// named deps, in addition to the dependencies returned by their Dependencies
+// method, if any.  Use DependOn for items that don’t implement [Dependent].
+// DependOn only affects items that haven’t been defined yet.  It returns an
+// error if name is empty.
+func (m *ManagerOf[T]) DependOn(name Name, deps ...Name) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.deps == nil {
+		m.deps = make(map[Name][]Name)
+	}
+	m.deps[name] = append(m.deps[name], deps...)
+	return nil
+}
+
+// nodes converts entries to dependency graph nodes.
+func (m *ManagerOf[T]) nodes(entries []*queuedEntry[T]) []*depNode {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := make([]*depNode, len(entries))
+	for i, q := range entries {
+		n := &depNode{name: q.name, item: q.item, notify: m.notify, done: func() { m.markDefined(q) }}
+		if d, ok := interface{}(q.item).(Dependent); ok {
+			n.deps = d.Dependencies()
+		}
+		if q.name != "" {
+			n.deps = append(n.deps[:len(n.deps):len(n.deps)], m.deps[q.name]...)
+		}
+		r[i] = n
+	}
+	return r
+}
+
+// takeQueued returns the queued items with the given name as dependency
+// graph nodes.  The items stay queued until they have been defined, so items
+// that a failed definition run never reaches can still be defined later.
+func (m *ManagerOf[T]) takeQueued(name Name) []*depNode {
+	m.mu.Lock()
+	var taken []*queuedEntry[T]
+	for _, q := range m.byName[name] {
+		if !q.defined {
+			taken = append(taken, q)
+		}
+	}
+	m.mu.Unlock()
+	if taken == nil {
+		return nil
+	}
+	return m.nodes(taken)
+}
+
+// managers contains all managers that have named items, so that dependencies
+// can be resolved across managers.
+var managers managerList
+
+type managerList struct {
+	mu   sync.Mutex
+	list []queueTaker
+}
+
+type queueTaker interface {
+	takeQueued(Name) []*depNode
+}
+
+func (l *managerList) add(m queueTaker) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.list = append(l.list, m)
+}
+
+// takeQueued returns the queued items with the given name from all managers
+// as dependency graph nodes.  The items stay queued until they have been
+// defined; see [ManagerOf.takeQueued].
+func (l *managerList) takeQueued(name Name) []*depNode {
+	l.mu.Lock()
+	list := l.list
+	l.mu.Unlock()
+	var r []*depNode
+	for _, m := range list {
+		r = append(r, m.takeQueued(name)...)
+	}
+	return r
+}
+
+// depNode is a node in the dependency graph of managed items.
+type depNode struct {
+	name  Name
+	item  QueuedItem
+	deps  []Name
+	state depState
+
+	// notify calls the observers of the manager that owns the item.
+	notify func(Name, error)
+
+	// done records in the manager that owns the item that the item has
+	// been passed to Define.
+	done func()
+}
+
+type depState int
+
+const (
+	depNew depState = iota
+	depVisiting
+	depDone
+)
+
+// definer defines items in dependency order using a depth-first search.
+type definer struct {
+	env             Env
+	continueOnError bool
+	nodes           []*depNode
+	byName          map[Name][]*depNode
+	stack           []Name
+	errs            []error
+}
+
+func newDefiner(e Env, continueOnError bool, nodes []*depNode) *definer {
+	d := &definer{env: e, continueOnError: continueOnError, nodes: nodes, byName: make(map[Name][]*depNode)}
+	for _, n := range nodes {
+		if n.name != "" {
+			d.byName[n.name] = append(d.byName[n.name], n)
+		}
+	}
+	return d
+}
+
+// run defines all nodes.  See [ManagerOf.DefineQueued] for the error
+// semantics.
+func (d *definer) run() error {
+	for _, n := range d.nodes {
+		if err := d.visit(n); err != nil {
+			return err
+		}
+	}
+	return errors.Join(d.errs...)
+}
+
+func (d *definer) visit(n *depNode) error {
+	switch n.state {
+	case depDone:
+		return nil
+	case depVisiting:
+		return d.cycle(n.name)
+	}
+	n.state = depVisiting
+	d.stack = append(d.stack, n.name)
+	for _, name := range n.deps {
+		deps, ok := d.byName[name]
+		if !ok {
+			deps = managers.takeQueued(name)
+			d.byName[name] = deps
+		}
+		for _, dep := range deps {
+			if err := d.visit(dep); err != nil {
+				return err
+			}
+		}
+	}
+	d.stack = d.stack[:len(d.stack)-1]
+	n.state = depDone
+	start := time.Now()
+	err := n.item.Define(d.env)
+	n.done()
+	definitionTimes.record(n.name, time.Since(start), err)
+	n.notify(n.name, err)
+	if err != nil {
+		if !d.continueOnError {
+			return err
+		}
+		d.errs = append(d.errs, definitionError{n.name, d.env.Message(err), err})
+	}
+	return nil
+}
+
+// cycle returns an error describing the dependency cycle that ends in name.
+func (d *definer) cycle(name Name) error {
+	i := len(d.stack) - 1
+	for i > 0 && d.stack[i] != name {
+		i--
+	}
+	path := make([]string, 0, len(d.stack)-i+1)
+	for _, n := range d.stack[i:] {
+		path = append(path, string(n))
+	}
+	path = append(path, string(name))
+	return fmt.Errorf("dependency cycle: %s", strings.Join(path, " → "))
+}
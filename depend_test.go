@@ -0,0 +1,71 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestManagerDependencies(t *testing.T) {
+	var log []string
+	var e Env // not used by the items
+	item := func(name string, deps ...Name) dependentItem {
+		return dependentItem{&loggingItem{&log, name}, deps}
+	}
+	m1 := NewManager(RequireUniqueName)
+	m2 := NewManager(RequireUniqueName)
+	m1.MustEnqueue("test-dep-a", item("a", "test-dep-b", "test-dep-c", "emacs-version"))
+	m1.MustEnqueue("test-dep-b", item("b"))
+	m1.MustEnqueue("test-dep-d", &loggingItem{&log, "d"})
+	if err := m1.DependOn("test-dep-d", "test-dep-b"); err != nil {
+		t.Fatal(err)
+	}
+	m2.MustEnqueue("test-dep-c", item("c", "test-dep-b"))
+	m2.MustEnqueue("test-dep-e", item("e"))
+	if err := m1.DefineQueued(e); err != nil {
+		t.Fatal(err)
+	}
+	if err := m2.DefineQueued(e); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"define b", "define c", "define a", "define d", "define e"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("definitions: got %q, want %q", log, want)
+	}
+}
+
+func TestManagerDependencyCycle(t *testing.T) {
+	var log []string
+	var e Env // not used by the items
+	m := NewManager(RequireUniqueName | ContinueOnError)
+	m.MustEnqueue("test-cycle-a", dependentItem{&loggingItem{&log, "a"}, []Name{"test-cycle-b"}})
+	m.MustEnqueue("test-cycle-b", dependentItem{&loggingItem{&log, "b"}, []Name{"test-cycle-c"}})
+	m.MustEnqueue("test-cycle-c", dependentItem{&loggingItem{&log, "c"}, []Name{"test-cycle-a"}})
+	err := m.DefineQueued(e)
+	want := "dependency cycle: test-cycle-a → test-cycle-b → test-cycle-c → test-cycle-a"
+	if err == nil || err.Error() != want {
+		t.Errorf("DefineQueued: got error %v, want %q", err, want)
+	}
+	if len(log) != 0 {
+		t.Errorf("definitions: got %q, want none", log)
+	}
+}
+
+type dependentItem struct {
+	*loggingItem
+	deps []Name
+}
+
+func (i dependentItem) Dependencies() []Name { return i.deps }
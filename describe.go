@@ -0,0 +1,183 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "sort"
+
+// ModuleDescription lists the entities that the module has registered with
+// Emacs.  Use [DescribeModule] to create a ModuleDescription.
+type ModuleDescription struct {
+	// Functions contains the named functions exported using [Export],
+	// [ExportFunc], [Env.Export], or [Env.ExportFunc].
+	Functions []FunctionDescription
+
+	// Variables contains the variables defined using [Var] or
+	// [Env.Var].
+	Variables []EntityDescription
+
+	// Errors contains the error symbols defined using [DefineError] or
+	// [Env.DefineError].
+	Errors []ErrorDescription
+
+	// ERTTests contains the ERT tests defined using [ERTTest] or
+	// [Env.ERTTest].
+	ERTTests []EntityDescription
+
+	// HashTests contains the hash table tests registered using
+	// [RegisterHashTest].
+	HashTests []EntityDescription
+
+	// Modes contains the major and minor modes defined by the module.
+	Modes []EntityDescription
+
+	// Features contains the features provided using [Provide].
+	Features []EntityDescription
+}
+
+// EntityDescription describes a named entity.  See [ModuleDescription].
+type EntityDescription struct {
+	// Name is the name of the entity.
+	Name Name
+
+	// Doc is the documentation string of the entity, if known.
+	Doc Doc
+
+	// Defined specifies whether the entity has already been defined in
+	// Emacs.  Entities registered during initialization are defined
+	// once Emacs has loaded the module.
+	Defined bool
+}
+
+// FunctionDescription describes an exported function.  See
+// [ModuleDescription].
+type FunctionDescription struct {
+	EntityDescription
+	Arity Arity
+}
+
+// ErrorDescription describes an error symbol.  See [ModuleDescription].
+type ErrorDescription struct {
+	EntityDescription
+	Message string
+	Parents []Name
+}
+
+// DescribeModule returns a description of all entities that the module has
+// registered, in registration order.  Tools on the Lisp side can use this
+// information for discoverability or documentation generation; see
+// [ExportDescribeModule].  You can call DescribeModule safely from multiple
+// goroutines.
+func DescribeModule() ModuleDescription {
+	var d ModuleDescription
+	queued := make(map[funcIndex]struct{})
+	for _, q := range funcs.base.entries() {
+		f := q.item
+		queued[f.index] = struct{}{}
+		d.Functions = append(d.Functions, FunctionDescription{EntityDescription{f.name, f.Doc, q.defined}, f.Arity})
+	}
+	// Functions exported after initialization don’t go through the
+	// manager.
+	var late []FunctionDescription
+	funcs.mu.RLock()
+	var indices []funcIndex
+	for i, f := range funcs.funcs {
+		if _, ok := queued[i]; !ok && f.name != "" && f.created == nil {
+			indices = append(indices, i)
+		}
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	for _, i := range indices {
+		f := funcs.funcs[i]
+		late = append(late, FunctionDescription{EntityDescription{f.name, f.Doc, true}, f.Arity})
+	}
+	funcs.mu.RUnlock()
+	d.Functions = append(d.Functions, late...)
+	for _, q := range vars.entries() {
+		d.Variables = append(d.Variables, EntityDescription{q.name, q.item.doc, q.defined})
+	}
+	for _, q := range errorSymbols.entries() {
+		d.Errors = append(d.Errors, ErrorDescription{EntityDescription{q.name, "", q.defined}, q.item.message, q.item.Dependencies()})
+	}
+	for _, q := range ertTests.entries() {
+		d.ERTTests = append(d.ERTTests, EntityDescription{q.name, q.item.doc, q.defined})
+	}
+	for _, q := range customHashTests.entries() {
+		d.HashTests = append(d.HashTests, EntityDescription{q.name, "", q.defined})
+	}
+	for _, q := range modes.entries() {
+		d.Modes = append(d.Modes, EntityDescription{q.name, "", q.defined})
+	}
+	for _, q := range provides.entries() {
+		d.Features = append(d.Features, EntityDescription{q.name, "", q.defined})
+	}
+	return d
+}
+
+// Emacs returns a property list with the keys :functions, :variables,
+// :errors, :ert-tests, :hash-tests, :modes, and :features.  Each value is a
+// list of property lists, one per entity, with the keys :name (a symbol),
+// :doc (a string or nil), and :defined (a boolean).  The property lists for
+// functions additionally contain the key :arity, whose value is a cons cell
+// as returned by func-arity.  The property lists for error symbols
+// additionally contain the keys :message (a string) and :parents (a list of
+// symbols).
+func (d ModuleDescription) Emacs(e Env) (Value, error) {
+	var fns, errs List
+	for _, f := range d.Functions {
+		fns = append(fns, append(f.plist(), Symbol(":arity"), f.Arity.cons()))
+	}
+	for _, s := range d.Errors {
+		parents := make(List, len(s.Parents))
+		for i, p := range s.Parents {
+			parents[i] = p
+		}
+		errs = append(errs, append(s.plist(), Symbol(":message"), String(s.Message), Symbol(":parents"), parents))
+	}
+	return List{
+		Symbol(":functions"), fns,
+		Symbol(":variables"), entityPlists(d.Variables),
+		Symbol(":errors"), errs,
+		Symbol(":ert-tests"), entityPlists(d.ERTTests),
+		Symbol(":hash-tests"), entityPlists(d.HashTests),
+		Symbol(":modes"), entityPlists(d.Modes),
+		Symbol(":features"), entityPlists(d.Features),
+	}.Emacs(e)
+}
+
+func (d EntityDescription) plist() List {
+	var doc In = Nil
+	if d.Doc != "" {
+		doc = String(d.Doc)
+	}
+	return List{Symbol(":name"), d.Name, Symbol(":doc"), doc, Symbol(":defined"), Bool(d.Defined)}
+}
+
+func entityPlists(ds []EntityDescription) List {
+	r := make(List, len(ds))
+	for i, d := range ds {
+		r[i] = d.plist()
+	}
+	return r
+}
+
+// ExportDescribeModule arranges for a function named prefix-describe-module
+// to be defined once the module is loaded.  The function takes no arguments
+// and returns the result of [DescribeModule] as a property list; see
+// [ModuleDescription.Emacs].  Call ExportDescribeModule in an init function.
+func ExportDescribeModule(prefix Name) {
+	ExportFunc(prefix+"-describe-module", func(e Env, _ []Value) (Value, error) {
+		return DescribeModule().Emacs(e)
+	}, Arity{0, 0}, "Return a description of the entities defined by the Go module.")
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+func init() {
+	ExportDescribeModule("emacs-test")
+	ERTTest(describeModule)
+}
+
+func describeModule(e Env) error {
+	var fns List
+	for _, f := range DescribeModule().Functions {
+		fns = append(fns, f.Name)
+	}
+	if !containsName(fns, "emacs-test-describe-module") {
+		return fmt.Errorf("describe function missing from module description %v", fns)
+	}
+	v, err := e.Call("emacs-test-describe-module")
+	if err != nil {
+		return err
+	}
+	tests, err := e.Call("plist-get", v, Symbol(":ert-tests"))
+	if err != nil {
+		return err
+	}
+	found, err := e.Eval(List{
+		Symbol("seq-find"),
+		List{Symbol("lambda"), List{Symbol("test")}, List{Symbol("eq"), List{Symbol("plist-get"), Symbol("test"), Symbol(":name")}, List{Symbol("quote"), Symbol("describe-module")}}},
+		List{Symbol("quote"), tests},
+	})
+	if err != nil {
+		return err
+	}
+	if !e.IsNotNil(found) {
+		return errors.New("ERT test describe-module missing from module description")
+	}
+	return nil
+}
+
+func containsName(l List, n Name) bool {
+	for _, x := range l {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}
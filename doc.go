@@ -19,7 +19,15 @@ background on Emacs modules.
 
 To build an Emacs module, you have to build your Go code as a shared C library,
 e.g., using go build ‑buildmode=c‑shared.  If you import the emacs package, the
-shared library is loadable as an Emacs module.
+shared library is loadable as an Emacs module.  The emacsbuild command
+(github.com/phst/emacs/cmd/emacsbuild) takes care of the platform-specific
+details, including cross-compilation.  To distribute the module, the
+emacspkg command (github.com/phst/emacs/cmd/emacspkg) bundles compiled modules
+for several platforms together with a Lisp loader into a package that
+package-install-file can install.  The emacsmoduleload command
+(github.com/phst/emacs/cmd/emacsmoduleload) checks that the module loads in
+one or more Emacs versions, which is useful as a smoke test in continuous
+integration.
 
 This package contains high-level as well as lower-level functions.  The
 high-level functions help reducing boilerplate when exporting functions to
@@ -40,19 +48,57 @@ the Emacs symbols nil and t.  When converting to Go bool, only nil becomes
 false, any other value becomes true.  This matches the Emacs convention that
 all non-nil values represent a logically true value.  Go integral values become
 Emacs integer values and vice versa.  Go floating-point values become Emacs
-floating-point values and vice versa.  Go strings become Emacs strings and vice
+floating-point values and vice versa; use [SetFloatPolicy] to control how NaN
+and infinities are handled.  [*big.Int] values become Emacs integers, and
+[*big.Float] values become the nearest Emacs floating-point values.
+[*big.Rat] values become integers or lists of the form (frac NUM DEN), as
+used by Calc.  Go strings become Emacs strings and vice
 versa.  Go []byte arrays and slices become Emacs unibyte strings.  Emacs
 unibyte strings become Go []byte slices.  Other Go arrays and slices become
 Emacs vectors.  Emacs vectors become Go slices.  Go maps become Emacs hash
-tables and vice versa.  All types that implement [In] can be converted to
-Emacs.  All types that implement [Out] can be converted from Emacs.  You can
+tables and vice versa.  Go structs become Emacs property lists and vice versa.
+Each exported field corresponds to the property whose key is the field name in
+Lisp style as a keyword, for example :foo-bar for the field FooBar.  A struct
+tag of the form emacs:"name" changes the key to :name, and the tag emacs:"-"
+excludes the field.  With the tag option omitempty, as in
+emacs:"name,omitempty", fields with zero values are left out of the property
+list.  When converting a property list to a struct, fields whose key is
+missing keep their values, and keys that don’t correspond to fields are
+ignored.  Non-numeric types that implement [encoding.TextMarshaler] become
+Emacs strings, and non-numeric types whose pointers implement
+[encoding.TextUnmarshaler] are parsed from Emacs strings; this takes
+precedence over the conversions for slices and structs.  All types that
+implement [In] can be converted to Emacs.  All types that implement [Out] can be converted from Emacs.  You can
 implement [In] or [Out] yourself to extend the type conversion machinery.  A
-[reflect.Value] behaves like its underlying value.
+[reflect.Value] behaves like its underlying value.  Emacs functions become
+values of callback interface types, which are interface types with a Call
+method that [*Callback] implements; see [Callback].
 
 Functions exported via [Export] don’t have a documentation string by default.
 To add one, pass a [Doc] value to [Export].  Since argument names aren’t
 available at runtime, the documentation by default lacks argument names.  Use
-[Usage] to add argument names.
+[Usage] to add argument names.  Trailing parameters of type [Optional] become
+&optional arguments that Lisp callers can omit, and the fields of a final
+struct parameter that embeds [Keywords] become &key arguments.  To make an
+exported function an interactive command, pass an [Interactive] option.  To
+define interactive commands whose interactive specification is derived from
+the parameter types of a Go function, use package
+github.com/phst/emacs/commands.  Package github.com/phst/emacs/transient
+defines transient menus whose suffix commands are Go functions.
+
+To avoid writing many [Import] calls by hand, you can use the emacsimportgen
+command (github.com/phst/emacs/cmd/emacsimportgen) together with go generate.
+It generates the function variables and [Import] calls from //emacs:import
+comments and copies the documentation strings from Emacs.  To generate
+bindings for many existing Emacs functions at once, use the emacsbindgen
+command (github.com/phst/emacs/cmd/emacsbindgen), which derives the Go
+signatures from the Lisp argument lists.  The emacsstructgen command
+(github.com/phst/emacs/cmd/emacsstructgen) generates Go types that mirror
+structures defined using cl-defstruct.
+
+To avoid byte-compiler warnings in Lisp code that uses the functions and
+variables of a module that isn’t loaded at compile time, use
+[WriteDeclarations] to generate matching declare-function and defvar forms.
 
 As an alternative to [Import], you can call functions directly using
 [Env.Invoke].  [Env.Invoke] uses the same autoconversion rules as [Import], but
@@ -66,14 +112,40 @@ like [Int] and [String] instead.  On the other hand, [Env.Call] and
 [Env.CallOut] are more type-safe than [Invoke].  If you use [Call] or
 [CallOut], the compiler will detect unsupported types.  By contrast, when using
 [Export], [Import], or [Invoke], they will only be detected at runtime and
-cause runtime panics or errors.
+cause runtime panics or errors.  The emacsvet command
+(github.com/phst/emacs/cmd/emacsvet) detects many of these problems at build
+time; you can run it using go vet -vettool.
 
 To reduce boilerplate when using [Env.Call] and [Env.CallOut], this package
 contains several convenience types that implement [In] or [Out].  Most
 primitive types have corresponding wrapper types, such as [Int], [Float], or
-[String].  Types such as [List], [Cons], or [Hash] allow you to pass common
-Lisp structures without much boilerplate.  There are also some destructuring
-types such as [ListOut] or [Uncons].
+[String].  Types such as [List], [Cons], [Alist], or [Hash] allow you to pass
+common Lisp structures without much boilerplate.  There are also some
+destructuring types such as [ListOut], [AlistOut], or [Uncons].  [JSON] and
+[JSONOut] convert Go values via their JSON encoding using [Env.ParseJSON] and
+[Env.SerializeJSON], which preserves the distinction between JSON null and
+false.  [EIEIO] and [EIEIOOut] convert between Go structs and EIEIO objects,
+and [DefineEIEIOClass] defines an EIEIO class that mirrors a Go struct type.
+Likewise, [Record] and [RecordOut] convert between Go structs and records,
+including structures defined using cl‑defstruct.
+Package github.com/phst/emacs/emacsproto converts protocol buffer messages.
+[Image] creates image descriptors from encoded image data, and [ImageWriter]
+displays images written by Go image encoders in a buffer.  [Keymap] and
+[KeySequence] create keymaps and convert key descriptions, and [BindKeys]
+and [DefineMenu] add key bindings and menus to existing keymaps.  [Color],
+[RGB], and [FaceAttributes] provide structured access to colors and faces.
+[OrgTable] and [OrgTableOut] convert between slices of structs and Org tables,
+and [TabulatedList] displays them in tabulated-list-mode buffers.  [Bindat]
+and [BindatSpec] describe binary layouts once for both Go and bindat.
+[Env.ReadString], [Env.CompletingRead], and similar methods prompt the user
+in the minibuffer.  [DefineCompletionAtPoint] defines completion-at-point
+functions whose candidates are computed in Go, optionally in the background.
+[DefineMinorMode] defines minor modes that call back into Go when they are
+enabled or disabled, and [DefineDerivedMode] defines major modes including
+their syntax tables, font-lock keywords, and file name patterns.
+[Hook.Add] adds Go functions to hooks and keeps track of them so that they can
+be removed later.  Likewise, [Env.AdviceAdd] and [Env.AdviceAddAround] advise
+Emacs functions with Go functions.
 
 At an even lower level, you can use [ExportFunc], [ImportFunc], and
 [Env.Funcall] as alternatives to [Export], [Import], and [Env.Call],
@@ -91,7 +163,12 @@ Values].  As a best practice, don’t let these values escape exported functions
 You also can’t interact with Emacs from other threads, cf. [Writing Module
 Functions].  These rules are a bit subtle, but you are usually on the safe side
 if you don’t store [Env] and [Value] values in struct fields or global
-variables, and don’t pass them to other goroutines.
+variables, and don’t pass them to other goroutines.  The emacsvet command
+reports violations of these rules that it can detect statically.
+
+Each call from Go into Emacs has some overhead.  If your module creates large
+data structures element by element, record the operations in a [Batch] and
+execute them using [Env.Batch], which calls into Emacs only once.
 
 # Error handling
 
@@ -117,15 +194,19 @@ couple of factory functions for builtin errors such as [WrongTypeArgument] and
 
 # Variables
 
-You can use [Var] to define a dynamic variable.
+You can use [Var] to define a dynamic variable, and [Custom] to define a user
+option that shows up in the Customize interface.
 
 # User pointers and handles
 
 This package intentionally doesn’t support wrapping pointers to arbitrary Go
 values in Emacs user pointer objects.  Attempting to do that wouldn’t work well
 with Go’s garbage collection and CGo’s pointer-passing rules; see [Passing
-pointers].  Instead, prefer using handles, e.g. simple integers as map keys.
-See the “Handles” example.
+pointers].  Instead, prefer using handles, i.e. integers that refer to Go
+values stored in a [HandleRegistry].  See the “Handles” example.  If the lifetime of a Go value should follow the
+lifetime of a Lisp object, use [Env.MakeUserPointer], which stores such a
+handle in a user pointer object and releases the Go value once Emacs
+garbage-collects the object.
 
 # Long-running operations
 
@@ -138,18 +219,90 @@ As an alternative, this package provides limited support for asynchronous
 operations.  Such operations are represented using the [AsyncHandle] type.  You
 can use the [Async] type to create and manage asynchronous operations.  [Async]
 requires a way to notify Emacs about a pending asynchronous result; this
-package supports notification using pipes or sockets.
+package supports notification using pipes or sockets.  [Env.NewAsync] sets up
+notification using a pipe process and passes results to a Lisp function.
+Emacs can cancel pending operations using [Async.Cancel]; operations started
+with [Async.StartContext] receive a context that’s canceled at that point.
+Streaming operations started with [Async.StartStream] can send intermediate
+results before the final one.  [Async.Progress] publishes progress information,
+and [ExportAsyncProgressFunction] defines a Lisp function that displays it
+using standard Emacs progress reporters.  [ExportAsync] exports a Go function
+that runs in its own goroutine and returns an [AsyncHandle], without any
+further boilerplate.  [NewAsyncShim] defines the Lisp side: functions that register
+callbacks for, wait for, and cancel asynchronous operations, and that wrap
+them in promises of the “aio” package.  A [Pool] limits the number of
+concurrently running operations and queues the rest.
+
+# Logging and diagnostics
+
+Output of the standard log package normally goes to standard error, where
+users of graphical Emacs sessions never see it.  [LogHandler] is a
+[log/slog] handler that forwards log records to Emacs from any goroutine,
+using the same notification mechanism as [Async].
+
+To diagnose performance and ordering problems, [StartTracing] records all
+calls between Go and Emacs; use [TraceEvents] or [ExportTraceCommands] to
+inspect them.  To integrate with tracing libraries such as OpenTelemetry,
+install a [SpanTracer] using [SetSpanTracer].  [NewCounter], [NewGauge], and
+[NewHistogram] create metrics that you can update from any goroutine and
+query using [Metrics], [ExportMetrics], or [PublishMetrics].
+[ExportProfilingCommands] defines commands that capture Go profiles and
+execution traces from within Emacs.
+[Health] and [ExportHealthCommand] report counts of exported functions,
+goroutines, pending asynchronous operations, and memory statistics.
+[Leaks] and [ExportLeakCommand] report functions created using [Env.Lambda]
+and similar functions that were never deleted; [SetLeakTracking] records
+where they were created.
+
+In debug mode (see [SetDebugMode]), errors and panics that escape exported
+functions produce a [FailureReport] that combines the Go stack and the Lisp
+backtrace and is shown in the buffer named by [FailureReportBuffer].  Debug
+mode also reports the location of failed conversions within nested values
+using [ConversionError], checks arities, and detects environments used after
+they’ve become invalid.
+
+Building a module with the emacsdebug build tag (go build ‑tags emacsdebug)
+enables stricter lifetime checks independent of debug mode: each environment
+gets a serial number, and each [Value] remembers the environment that created
+it.  Using an environment or a value after that environment has become invalid
+then panics with a message that names the environment, instead of crashing
+Emacs.  These checks slow down all calls, so only use the build tag during
+development.
+
+To reproduce bugs that occur in interactive sessions, [StartRecording] or
+[ExportRecordingCommands] record the calls of exported functions as an Emacs
+Lisp file, which [Env.Replay] or a batch Emacs can replay later.
 
 # Initialization
 
 If you want to run code while Emacs is loading the module, use [OnInit] to
 register initialization functions.  Loading the module will call all
-initialization functions in order.
+initialization functions in order.  If loading the module is slow, use
+[DefinitionTimings] and [LogSlowDefinitions] to find out which definitions take
+the most time.
+
+Use [RequireEmacs] and [RequireCapability] to declare the minimum Emacs version
+and optional Emacs features that your module needs.  If some requirements
+aren’t met, loading the module fails with a single error that lists all of
+them.  To define functions or ERT tests only if some [Capability] is
+available, pass a [Requires] option.
+
+To clean up when the user unloads the module using unload-feature, register
+unload functions using [OnUnload].  Unload functions only run if the module
+provides a feature using [Provide].
 
 # ERT tests
 
 You can use [ERTTest] to define ERT tests backed by Go functions.  This works
 similar to [Export], but defines ERT tests instead of functions.
+[ERTBenchmark] defines ERT tests that run benchmarks; they report the time and
+the number of calls into Emacs per iteration.  The ertrunner command
+(github.com/phst/emacs/cmd/ertrunner) builds a module, runs its ERT tests in
+batch mode, and reports the results in the same format as go test.
+
+To test conversion logic and exported functions with plain go test, without
+starting Emacs, use the fake environment in package
+github.com/phst/emacs/emacstest.
 
 [Emacs Dynamic Modules]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Dynamic-Modules.html
 [Writing Dynamically-Loaded Modules]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Writing-Dynamic-Modules.html
@@ -0,0 +1,347 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EIEIO is an [In] that creates a new instance of the EIEIO class Class and
+// initializes its slots from the fields of the Go struct Data.  Data must be a
+// struct or a pointer to a struct.  See [EIEIOOut] for how fields map to
+// slots.
+type EIEIO struct {
+	Class Symbol
+	Data  interface{}
+}
+
+// Emacs creates a new instance of the class and sets its slots using
+// eieio-oset.
+func (o EIEIO) Emacs(e Env) (Value, error) {
+	v, err := structValue(reflect.ValueOf(o.Data))
+	if err != nil {
+		return Value{}, err
+	}
+	slots, err := structFields(v.Type())
+	if err != nil {
+		return Value{}, err
+	}
+	if err := requireEIEIO(e); err != nil {
+		return Value{}, err
+	}
+	obj, err := e.Call("make-instance", o.Class)
+	if err != nil {
+		return Value{}, err
+	}
+	for _, s := range slots {
+		fv, ok := fieldValue(v, s.index)
+		if !ok {
+			continue
+		}
+		if _, err := e.Call("eieio-oset", obj, s.name, s.in(fv)); err != nil {
+			return Value{}, err
+		}
+	}
+	return obj, nil
+}
+
+// EIEIOOut is an [Out] that reads the slots of an EIEIO object into the
+// fields of the Go struct that Data points to.  If Class is nonempty, the
+// object must be an instance of Class or one of its subclasses.
+//
+// Each exported field of the struct corresponds to the slot with the same
+// name in Lisp style: the field FooBar corresponds to the slot foo-bar.  To
+// use a different slot name, add a struct tag of the form emacs:"slot-name" to
+// the field.  The tag emacs:"-" excludes the field.  Fields whose slot is
+// unbound keep their values.
+type EIEIOOut struct {
+	Class Symbol
+	Data  interface{}
+}
+
+// FromEmacs sets the fields of o.Data from the slots of the object v using
+// eieio-oref.
+func (o EIEIOOut) FromEmacs(e Env, v Value) error {
+	p := reflect.ValueOf(o.Data)
+	if p.Kind() != reflect.Ptr || p.IsNil() || p.Elem().Kind() != reflect.Struct {
+		return WrongTypeArgument("go-struct-pointer-p", String(fmt.Sprintf("%T", o.Data)))
+	}
+	s := p.Elem()
+	slots, err := structFields(s.Type())
+	if err != nil {
+		return err
+	}
+	if err := requireEIEIO(e); err != nil {
+		return err
+	}
+	class, ok, err := e.EIEIOClass(v)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return WrongTypeArgument("eieio-object-p", v)
+	}
+	if o.Class != "" && class != o.Class {
+		var sub Bool
+		if err := e.CallOut("object-of-class-p", &sub, v, o.Class); err != nil {
+			return err
+		}
+		if !sub {
+			return WrongTypeArgument(o.Class+"-p", v)
+		}
+	}
+	for _, f := range slots {
+		var bound Bool
+		if err := e.CallOut("slot-boundp", &bound, v, f.name); err != nil {
+			return err
+		}
+		if !bound {
+			continue
+		}
+		fp, err := fieldPointer(s, f.index)
+		if err != nil {
+			return err
+		}
+		if err := e.CallOut("eieio-oref", f.out(fp), v, f.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EIEIOClass returns the class of the EIEIO object v.  If v isn’t an EIEIO
+// object, EIEIOClass returns false.
+func (e Env) EIEIOClass(v Value) (Symbol, bool, error) {
+	if err := requireEIEIO(e); err != nil {
+		return "", false, err
+	}
+	var ok Bool
+	if err := e.CallOut("eieio-object-p", &ok, v); err != nil || !ok {
+		return "", false, err
+	}
+	var class Symbol
+	err := e.CallOut("eieio-object-class", &class, v)
+	return class, err == nil, err
+}
+
+// DefineEIEIOClass arranges for an EIEIO class to be defined once the module
+// is loaded.  The class has one slot for each field of the struct type of
+// prototype, using the mapping described in [EIEIOOut].  Each slot has an
+// initarg that is the slot name prefixed with a colon, and the Lisp
+// representation of the field’s value in prototype as initial value.  parents
+// are the superclasses of the new class.  DefineEIEIOClass panics if
+// prototype isn’t a struct or the class name is empty or already registered.
+// DefineEIEIOClass returns name so you can assign it directly to a Go
+// variable if you want.
+func DefineEIEIOClass(name Name, prototype interface{}, doc Doc, parents ...Symbol) Name {
+	c, err := newEIEIOClass(name, prototype, doc, parents)
+	if err != nil {
+		panic(err)
+	}
+	eieioClasses.MustEnqueue(name, c)
+	return name
+}
+
+// DefineEIEIOClass is like the global [DefineEIEIOClass] function, except that
+// it requires a live environment, defines the class immediately, and returns
+// errors instead of panicking.
+func (e Env) DefineEIEIOClass(name Name, prototype interface{}, doc Doc, parents ...Symbol) error {
+	c, err := newEIEIOClass(name, prototype, doc, parents)
+	if err != nil {
+		return err
+	}
+	return eieioClasses.RegisterAndDefine(e, name, c)
+}
+
+var eieioClasses = NewManager(RequireName | RequireUniqueName | DefineOnInit)
+
+type eieioClass struct {
+	name    Name
+	value   reflect.Value
+	slots   []structField
+	doc     Doc
+	parents []Symbol
+}
+
+func newEIEIOClass(name Name, prototype interface{}, doc Doc, parents []Symbol) (eieioClass, error) {
+	v, err := structValue(reflect.ValueOf(prototype))
+	if err != nil {
+		return eieioClass{}, err
+	}
+	slots, err := structFields(v.Type())
+	if err != nil {
+		return eieioClass{}, fmt.Errorf("can’t define EIEIO class %s: %w", name, err)
+	}
+	return eieioClass{name, v, slots, doc, parents}, nil
+}
+
+func (c eieioClass) Define(e Env) error {
+	if err := requireEIEIO(e); err != nil {
+		return err
+	}
+	parents := make(List, len(c.parents))
+	for i, p := range c.parents {
+		parents[i] = p
+	}
+	slots := make(List, len(c.slots))
+	for i, s := range c.slots {
+		slot := List{s.name, Symbol(":initarg"), Symbol(":" + s.name)}
+		if fv, ok := fieldValue(c.value, s.index); ok {
+			slot = append(slot, Symbol(":initform"), List{Symbol("quote"), s.in(fv)})
+		}
+		slots[i] = slot
+	}
+	form := List{Symbol("defclass"), c.name, parents, slots}
+	if c.doc != "" {
+		form = append(form, c.doc)
+	}
+	_, err := e.Eval(form)
+	return err
+}
+
+func requireEIEIO(e Env) error {
+	_, err := e.Call("require", Symbol("eieio"))
+	return err
+}
+
+// structValue dereferences v if it’s a pointer and checks that the result is
+// a struct.
+func structValue(v reflect.Value) (reflect.Value, error) {
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, WrongTypeArgument("go-struct-p", String(fmt.Sprintf("%#v", v)))
+	}
+	return v, nil
+}
+
+// structField describes the mapping between a Go struct field and the
+// corresponding Lisp slot or property.
+type structField struct {
+	index     []int
+	name      Symbol
+	in        InFunc
+	out       OutFunc // takes a pointer to the field
+	omitEmpty bool
+	slot      int // record slot index, or zero if unspecified
+}
+
+// structFields returns the mapping between the exported fields of the struct
+// type t and Lisp names.  Fields with a struct tag of the form emacs:"name"
+// use the given name; the tag emacs:"-" excludes a field.  All other fields
+// use their name converted to Lisp style, see [lispStyle].  The tag may
+// contain options after the name, separated by commas: omitempty, and
+// slot=N for a positive record slot index N, see [Record].
+func structFields(t reflect.Type) ([]structField, error) {
+	var r []structField
+	seen := make(map[Symbol]string)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		tag, opts, _ := strings.Cut(f.Tag.Get("emacs"), ",")
+		if tag == "-" {
+			continue
+		}
+		omitEmpty := false
+		slot := 0
+		for _, o := range strings.Split(opts, ",") {
+			switch {
+			case o == "":
+			case o == "omitempty":
+				omitEmpty = true
+			case strings.HasPrefix(o, "slot="):
+				n, err := strconv.Atoi(strings.TrimPrefix(o, "slot="))
+				if err != nil || n <= 0 {
+					return nil, fmt.Errorf("field %s of type %s: invalid slot index in struct tag option %q", f.Name, t, o)
+				}
+				slot = n
+			default:
+				return nil, fmt.Errorf("field %s of type %s: unknown struct tag option %q", f.Name, t, o)
+			}
+		}
+		name := Symbol(tag)
+		if name == "" {
+			name = Symbol(lispStyle(f.Name))
+		}
+		if other, dup := seen[name]; dup {
+			return nil, fmt.Errorf("fields %s and %s of type %s both map to %s", other, f.Name, t, name)
+		}
+		seen[name] = f.Name
+		in, err := InFuncFor(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of type %s: %w", f.Name, t, err)
+		}
+		out, err := OutFuncFor(reflect.PtrTo(f.Type))
+		if err != nil {
+			return nil, fmt.Errorf("field %s of type %s: %w", f.Name, t, err)
+		}
+		r = append(r, structField{f.Index, name, in, out, omitEmpty, slot})
+	}
+	return r, nil
+}
+
+// fieldValue returns the field of the struct v with the given index.  It
+// returns false if the field is promoted through a nil embedded pointer;
+// callers should treat such fields as omitted.
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	f, err := v.FieldByIndexErr(index)
+	return f, err == nil
+}
+
+// fieldPointer returns a pointer to the field of the struct s with the given
+// index.  s must be addressable.  fieldPointer allocates nil embedded
+// pointers on the way to the field.  It returns an error if such a pointer
+// can’t be set because its type is unexported.
+func fieldPointer(s reflect.Value, index []int) (reflect.Value, error) {
+	v := s
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("can’t set embedded pointer to unexported struct type %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v.Addr(), nil
+}
+
+// lispStyle converts a Go identifier in CamelCase to a Lisp-style name with
+// lowercase words separated by hyphens.  For example, lispStyle("HTTPServer")
+// returns "http-server".
+func lispStyle(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type eieioPerson struct {
+	Name     string
+	Age      int    `emacs:"years"`
+	Internal string `emacs:"-"`
+}
+
+func init() {
+	DefineEIEIOClass("go-eieio-person", eieioPerson{Name: "nobody"}, "A person.")
+	ERTTest(eieioRoundtrip)
+}
+
+func eieioRoundtrip(e Env) error {
+	in := eieioPerson{Name: "Alice", Age: 42, Internal: "ignored"}
+	obj, err := EIEIO{"go-eieio-person", &in}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	class, ok, err := e.EIEIOClass(obj)
+	if err != nil {
+		return err
+	}
+	if !ok || class != "go-eieio-person" {
+		return fmt.Errorf("EIEIOClass: got %q, %t; want go-eieio-person", class, ok)
+	}
+	var years Int
+	if err := e.CallOut("eieio-oref", &years, obj, Symbol("years")); err != nil {
+		return err
+	}
+	if years != 42 {
+		return fmt.Errorf("slot years: got %d, want 42", years)
+	}
+	var out eieioPerson
+	if err := (EIEIOOut{"go-eieio-person", &out}).FromEmacs(e, obj); err != nil {
+		return err
+	}
+	if want := (eieioPerson{Name: "Alice", Age: 42}); out != want {
+		return fmt.Errorf("EIEIO roundtrip: got %+v, want %+v", out, want)
+	}
+	def, err := e.Call("make-instance", Symbol("go-eieio-person"))
+	if err != nil {
+		return err
+	}
+	if err := (EIEIOOut{Data: &out}).FromEmacs(e, def); err != nil {
+		return err
+	}
+	if want := (eieioPerson{Name: "nobody"}); out != want {
+		return fmt.Errorf("default instance: got %+v, want %+v", out, want)
+	}
+	t, err := T.Emacs(e)
+	if err != nil {
+		return err
+	}
+	if _, ok, err := e.EIEIOClass(t); err != nil || ok {
+		return fmt.Errorf("EIEIOClass(t): got %t, %v; want false, nil", ok, err)
+	}
+	return nil
+}
+
+func TestStructFields(t *testing.T) {
+	type embedded struct{ Inner int }
+	type s struct {
+		embedded
+		HTTPServer string
+		FooBar     bool `emacs:"baz"`
+		Skipped    int  `emacs:"-"`
+		unexported int
+	}
+	fields, err := structFields(reflect.TypeOf(s{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Symbol
+	for _, f := range fields {
+		got = append(got, f.name)
+	}
+	if want := []Symbol{"inner", "http-server", "baz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("structFields: got %q, want %q", got, want)
+	}
+}
+
+// Embedded is exported so that fieldPointer can allocate it.
+type Embedded struct{ Inner int }
+
+type withEmbeddedPointer struct {
+	*Embedded
+	Outer int
+}
+
+func TestFieldNilEmbedded(t *testing.T) {
+	fields, err := structFields(reflect.TypeOf(withEmbeddedPointer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 2 || fields[0].name != "inner" {
+		t.Fatalf("structFields: got %+v, want fields inner and outer", fields)
+	}
+	var s withEmbeddedPointer
+	v := reflect.ValueOf(&s).Elem()
+	if f, ok := fieldValue(v, fields[0].index); ok {
+		t.Errorf("fieldValue: got %v, want omitted field", f)
+	}
+	if f, ok := fieldValue(v, fields[1].index); !ok || f.Int() != 0 {
+		t.Errorf("fieldValue: got %v, %v, want 0, true", f, ok)
+	}
+	p, err := fieldPointer(v, fields[0].index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Elem().SetInt(5)
+	if s.Embedded == nil || s.Inner != 5 {
+		t.Errorf("fieldPointer didn’t allocate embedded struct: got %+v", s)
+	}
+
+	type unexported struct{ Inner int }
+	type withUnexported struct{ *unexported }
+	fields, err = structFields(reflect.TypeOf(withUnexported{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u withUnexported
+	if _, err := fieldPointer(reflect.ValueOf(&u).Elem(), fields[0].index); err == nil {
+		t.Error("fieldPointer succeeded for unexported embedded pointer")
+	}
+}
+
+func TestLispStyle(t *testing.T) {
+	for in, want := range map[string]string{
+		"Name":       "name",
+		"FooBar":     "foo-bar",
+		"HTTPServer": "http-server",
+		"UserID":     "user-id",
+		"X":          "x",
+	} {
+		if got := lispStyle(in); got != want {
+			t.Errorf("lispStyle(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
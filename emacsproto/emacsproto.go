@@ -0,0 +1,399 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package emacsproto converts protocol buffer messages to and from Emacs Lisp
+// data.  It lives in a separate package so that modules that don’t use
+// protocol buffers don’t depend on the protobuf runtime.
+//
+// A message becomes a property list or association list keyed by field name,
+// depending on [Options.Format].  In property lists, the keys are keywords
+// such as :user_id; in association lists, they are symbols such as user_id.
+// If [Options.LispNames] is set, underscores in field names become hyphens,
+// for example :user-id.  Field values map as follows:
+//
+//   - bool fields become nil or t.
+//   - Integer fields become integers, and floating-point fields become floats.
+//   - string fields become multibyte strings, and bytes fields become unibyte
+//     strings.
+//   - Enum fields become symbols named after the enum value, for example
+//     STATUS_OK.  Numeric values without a name become integers.
+//   - Message fields become nested property or association lists.
+//   - Repeated fields become vectors, so that an empty repeated field is
+//     distinguishable from an empty message.
+//   - Map fields become hash tables with test equal.
+//
+// When converting to Lisp, only populated fields appear in the result unless
+// [Options.EmitUnpopulated] is set; this preserves field presence for fields
+// that track it.  When converting from Lisp, keys may use the field name, its
+// Lisp-style variant, or the JSON name, with or without leading colon.
+// Enum values may be given as symbols or integers.  A nil value for a
+// message, repeated, or map field leaves the field cleared.
+package emacsproto
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/phst/emacs"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Format specifies the Lisp representation of messages.
+type Format int
+
+// Possible message representations.
+const (
+	Plist Format = iota
+	Alist
+)
+
+// Options control the conversion between messages and Lisp data.  The zero
+// Options value represents messages as property lists with the original field
+// names and omits unpopulated fields.
+type Options struct {
+	Format          Format
+	LispNames       bool
+	EmitUnpopulated bool
+}
+
+// Message is an [emacs.In] that converts the protocol buffer message M to a
+// Lisp object.
+type Message struct {
+	M       proto.Message
+	Options Options
+}
+
+// Emacs converts m.M to a property list or association list.
+func (m Message) Emacs(e emacs.Env) (emacs.Value, error) {
+	if m.M == nil {
+		return emacs.Value{}, emacs.WrongTypeArgument("go-not-nil-p", emacs.String("nil proto.Message"))
+	}
+	return m.Options.message(m.M.ProtoReflect()).Emacs(e)
+}
+
+// MessageOut is an [emacs.Out] that converts a Lisp object to the protocol
+// buffer message M.  M must be mutable, i.e., typically a non-nil pointer to a
+// generated message type.  MessageOut first resets M, so fields that don’t
+// appear in the Lisp object end up cleared.
+type MessageOut struct {
+	M       proto.Message
+	Options Options
+}
+
+// FromEmacs sets m.M from the property list or association list v.
+func (m MessageOut) FromEmacs(e emacs.Env, v emacs.Value) error {
+	if m.M == nil {
+		return emacs.WrongTypeArgument("go-not-nil-p", emacs.String("nil proto.Message"))
+	}
+	proto.Reset(m.M)
+	return m.Options.setMessage(e, m.M.ProtoReflect(), v)
+}
+
+// in is an [emacs.In] implemented by a function.
+type in func(emacs.Env) (emacs.Value, error)
+
+func (f in) Emacs(e emacs.Env) (emacs.Value, error) { return f(e) }
+
+// key returns the Lisp key for the field fd.
+func (o Options) key(fd protoreflect.FieldDescriptor) emacs.Symbol {
+	n := string(fd.Name())
+	if o.LispNames {
+		n = strings.ReplaceAll(n, "_", "-")
+	}
+	if o.Format == Plist {
+		n = ":" + n
+	}
+	return emacs.Symbol(n)
+}
+
+// field returns the field of md that the Lisp key k denotes.
+func field(md protoreflect.MessageDescriptor, k string) protoreflect.FieldDescriptor {
+	k = strings.TrimPrefix(k, ":")
+	fields := md.Fields()
+	if fd := fields.ByName(protoreflect.Name(k)); fd != nil {
+		return fd
+	}
+	if fd := fields.ByName(protoreflect.Name(strings.ReplaceAll(k, "-", "_"))); fd != nil {
+		return fd
+	}
+	return fields.ByJSONName(k)
+}
+
+func (o Options) message(m protoreflect.Message) emacs.In {
+	return in(func(e emacs.Env) (emacs.Value, error) {
+		var elems []emacs.In
+		add := func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			val := o.fieldValue(fd, v)
+			if o.Format == Plist {
+				elems = append(elems, o.key(fd), val)
+			} else {
+				elems = append(elems, emacs.Cons{Car: o.key(fd), Cdr: val})
+			}
+			return true
+		}
+		if o.EmitUnpopulated {
+			fields := m.Descriptor().Fields()
+			for i := 0; i < fields.Len(); i++ {
+				fd := fields.Get(i)
+				if fd.Message() != nil && !fd.IsList() && !fd.IsMap() && !m.Has(fd) {
+					elems = append(elems, o.unset(fd)...)
+					continue
+				}
+				add(fd, m.Get(fd))
+			}
+		} else {
+			m.Range(add)
+		}
+		return e.List(elems...)
+	})
+}
+
+// unset returns the Lisp representation of the unset message field fd.
+func (o Options) unset(fd protoreflect.FieldDescriptor) []emacs.In {
+	if o.Format == Plist {
+		return []emacs.In{o.key(fd), emacs.Nil}
+	}
+	return []emacs.In{emacs.Cons{Car: o.key(fd), Cdr: emacs.Nil}}
+}
+
+func (o Options) fieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) emacs.In {
+	switch {
+	case fd.IsList():
+		l := v.List()
+		r := make(emacs.Vector, l.Len())
+		for i := range r {
+			r[i] = o.singular(fd, l.Get(i))
+		}
+		return r
+	case fd.IsMap():
+		h := emacs.Hash{Test: emacs.Equal, Data: make(map[emacs.In]emacs.In)}
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			h.Data[o.singular(fd.MapKey(), k.Value())] = o.singular(fd.MapValue(), v)
+			return true
+		})
+		return h
+	default:
+		return o.singular(fd, v)
+	}
+}
+
+func (o Options) singular(fd protoreflect.FieldDescriptor, v protoreflect.Value) emacs.In {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return emacs.Bool(v.Bool())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return emacs.Int(v.Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return emacs.Uint(v.Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return emacs.Float(v.Float())
+	case protoreflect.StringKind:
+		return emacs.String(v.String())
+	case protoreflect.BytesKind:
+		return emacs.Bytes(v.Bytes())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return emacs.Symbol(ev.Name())
+		}
+		return emacs.Int(v.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return o.message(v.Message())
+	default:
+		panic(fmt.Errorf("unknown field kind %v", fd.Kind()))
+	}
+}
+
+// setMessage sets the fields of m from the Lisp object v.
+func (o Options) setMessage(e emacs.Env, m protoreflect.Message, v emacs.Value) error {
+	md := m.Descriptor()
+	set := func(k, val emacs.Value) error {
+		var name emacs.Symbol
+		if err := name.FromEmacs(e, k); err != nil {
+			return err
+		}
+		fd := field(md, string(name))
+		if fd == nil {
+			return fmt.Errorf("message %s has no field %s", md.FullName(), name)
+		}
+		return o.setField(e, m, fd, val)
+	}
+	if o.Format == Plist {
+		var elems []emacs.Value
+		if err := e.Dolist(v, func(x emacs.Value) error {
+			elems = append(elems, x)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if len(elems)%2 != 0 {
+			return emacs.WrongTypeArgument("plistp", v)
+		}
+		for i := 0; i < len(elems); i += 2 {
+			if err := set(elems[i], elems[i+1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return e.Dolist(v, func(x emacs.Value) error {
+		k, val, err := e.Uncons(x)
+		if err != nil {
+			return err
+		}
+		return set(k, val)
+	})
+}
+
+func (o Options) setField(e emacs.Env, m protoreflect.Message, fd protoreflect.FieldDescriptor, v emacs.Value) error {
+	switch {
+	case fd.IsList():
+		if e.IsNil(v) {
+			return nil
+		}
+		n, err := e.VecSize(v)
+		if err != nil {
+			return err
+		}
+		l := m.Mutable(fd).List()
+		for i := 0; i < n; i++ {
+			x, err := e.VecGet(v, i)
+			if err != nil {
+				return err
+			}
+			if fd.Message() != nil {
+				elem := l.NewElement()
+				if err := o.setMessage(e, elem.Message(), x); err != nil {
+					return err
+				}
+				l.Append(elem)
+				continue
+			}
+			pv, err := o.scalar(e, fd, x)
+			if err != nil {
+				return err
+			}
+			l.Append(pv)
+		}
+		return nil
+	case fd.IsMap():
+		if e.IsNil(v) {
+			return nil
+		}
+		mp := m.Mutable(fd).Map()
+		return e.Maphash(func(k, val emacs.Value) error {
+			pk, err := o.scalar(e, fd.MapKey(), k)
+			if err != nil {
+				return err
+			}
+			if fd.MapValue().Message() != nil {
+				elem := mp.NewValue()
+				if err := o.setMessage(e, elem.Message(), val); err != nil {
+					return err
+				}
+				mp.Set(pk.MapKey(), elem)
+				return nil
+			}
+			pv, err := o.scalar(e, fd.MapValue(), val)
+			if err != nil {
+				return err
+			}
+			mp.Set(pk.MapKey(), pv)
+			return nil
+		}, v)
+	case fd.Message() != nil:
+		if e.IsNil(v) {
+			return nil
+		}
+		return o.setMessage(e, m.Mutable(fd).Message(), v)
+	default:
+		pv, err := o.scalar(e, fd, v)
+		if err != nil {
+			return err
+		}
+		m.Set(fd, pv)
+		return nil
+	}
+}
+
+// scalar converts v to a protobuf value for the non-message field fd.
+func (o Options) scalar(e emacs.Env, fd protoreflect.FieldDescriptor, v emacs.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(e.IsNotNil(v)), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		i, err := e.Int(v)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		if int64(int32(i)) != i {
+			return protoreflect.Value{}, emacs.OverflowError(fmt.Sprint(i))
+		}
+		return protoreflect.ValueOfInt32(int32(i)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := e.Int(v)
+		return protoreflect.ValueOfInt64(i), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		i, err := e.Uint(v)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		if uint64(uint32(i)) != i {
+			return protoreflect.Value{}, emacs.OverflowError(fmt.Sprint(i))
+		}
+		return protoreflect.ValueOfUint32(uint32(i)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		i, err := e.Uint(v)
+		return protoreflect.ValueOfUint64(i), err
+	case protoreflect.FloatKind:
+		f, err := e.Float(v)
+		return protoreflect.ValueOfFloat32(float32(f)), err
+	case protoreflect.DoubleKind:
+		f, err := e.Float(v)
+		return protoreflect.ValueOfFloat64(f), err
+	case protoreflect.StringKind:
+		s, err := e.Str(v)
+		return protoreflect.ValueOfString(s), err
+	case protoreflect.BytesKind:
+		b, err := e.Bytes(v)
+		return protoreflect.ValueOfBytes(b), err
+	case protoreflect.EnumKind:
+		var isInt emacs.Bool
+		if err := e.CallOut("integerp", &isInt, v); err != nil {
+			return protoreflect.Value{}, err
+		}
+		if isInt {
+			i, err := e.Int(v)
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			if int64(int32(i)) != i {
+				return protoreflect.Value{}, emacs.OverflowError(fmt.Sprint(i))
+			}
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(i)), nil
+		}
+		var s emacs.Symbol
+		if err := s.FromEmacs(e, v); err != nil {
+			return protoreflect.Value{}, err
+		}
+		ev := fd.Enum().Values().ByName(protoreflect.Name(s))
+		if ev == nil {
+			return protoreflect.Value{}, fmt.Errorf("enum %s has no value %s", fd.Enum().FullName(), s)
+		}
+		return protoreflect.ValueOfEnum(ev.Number()), nil
+	default:
+		return protoreflect.Value{}, fmt.Errorf("field %s isn’t scalar", fd.FullName())
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacsproto
+
+import (
+	"testing"
+
+	"github.com/phst/emacs"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestKey(t *testing.T) {
+	fd := (&descriptorpb.FieldDescriptorProto{}).ProtoReflect().Descriptor().Fields().ByName("type_name")
+	for _, tc := range []struct {
+		opts Options
+		want emacs.Symbol
+	}{
+		{Options{}, ":type_name"},
+		{Options{LispNames: true}, ":type-name"},
+		{Options{Format: Alist}, "type_name"},
+		{Options{Format: Alist, LispNames: true}, "type-name"},
+	} {
+		if got := tc.opts.key(fd); got != tc.want {
+			t.Errorf("key with options %+v: got %q, want %q", tc.opts, got, tc.want)
+		}
+	}
+}
+
+func TestField(t *testing.T) {
+	md := (&descriptorpb.FieldDescriptorProto{}).ProtoReflect().Descriptor()
+	for _, k := range []string{"type_name", ":type_name", "type-name", ":type-name", "typeName"} {
+		fd := field(md, k)
+		if fd == nil || fd.Name() != "type_name" {
+			t.Errorf("field(%q): got %v, want type_name", k, fd)
+		}
+	}
+	if fd := field(md, "nonexistent"); fd != nil {
+		t.Errorf("field(nonexistent): got %v, want nil", fd)
+	}
+}
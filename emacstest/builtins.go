@@ -0,0 +1,1266 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacstest
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// many is the maximum arity of &rest functions.
+const many = -1
+
+// builtins lists the primitive functions that the fake defines.
+var builtins = []builtin{
+	// Equality and type predicates
+	{"eq", 2, 2, func(f *Fake, a []object) (object, error) { return f.bool(eq(a[0], a[1])), nil }},
+	{"eql", 2, 2, func(f *Fake, a []object) (object, error) { return f.bool(eql(a[0], a[1])), nil }},
+	{"equal", 2, 2, func(f *Fake, a []object) (object, error) { return f.bool(equal(a[0], a[1])), nil }},
+	{"null", 1, 1, func(f *Fake, a []object) (object, error) { return f.bool(a[0] == f.nilSym), nil }},
+	{"not", 1, 1, func(f *Fake, a []object) (object, error) { return f.bool(a[0] == f.nilSym), nil }},
+	{"type-of", 1, 1, func(f *Fake, a []object) (object, error) { return f.intern(typeOf(a[0])), nil }},
+	{"identity", 1, 1, func(f *Fake, a []object) (object, error) { return a[0], nil }},
+	{"ignore", 0, many, func(f *Fake, a []object) (object, error) { return f.nilSym, nil }},
+
+	// Conses and lists
+	{"cons", 2, 2, func(f *Fake, a []object) (object, error) { return &cons{a[0], a[1]}, nil }},
+	{"list", 0, many, func(f *Fake, a []object) (object, error) { return f.list(a...), nil }},
+	{"consp", 1, 1, isType[*cons]},
+	{"atom", 1, 1, func(f *Fake, a []object) (object, error) { _, ok := a[0].(*cons); return f.bool(!ok), nil }},
+	{"listp", 1, 1, func(f *Fake, a []object) (object, error) { return f.bool(f.isList(a[0])), nil }},
+	{"nlistp", 1, 1, func(f *Fake, a []object) (object, error) { return f.bool(!f.isList(a[0])), nil }},
+	{"car", 1, 1, func(f *Fake, a []object) (object, error) { return f.car(a[0]) }},
+	{"cdr", 1, 1, func(f *Fake, a []object) (object, error) { return f.cdr(a[0]) }},
+	{"car-safe", 1, 1, func(f *Fake, a []object) (object, error) {
+		if c, ok := a[0].(*cons); ok {
+			return c.car, nil
+		}
+		return f.nilSym, nil
+	}},
+	{"cdr-safe", 1, 1, func(f *Fake, a []object) (object, error) {
+		if c, ok := a[0].(*cons); ok {
+			return c.cdr, nil
+		}
+		return f.nilSym, nil
+	}},
+	{"setcar", 2, 2, func(f *Fake, a []object) (object, error) {
+		c, ok := a[0].(*cons)
+		if !ok {
+			return nil, f.wrongType("consp", a[0])
+		}
+		c.car = a[1]
+		return a[1], nil
+	}},
+	{"setcdr", 2, 2, func(f *Fake, a []object) (object, error) {
+		c, ok := a[0].(*cons)
+		if !ok {
+			return nil, f.wrongType("consp", a[0])
+		}
+		c.cdr = a[1]
+		return a[1], nil
+	}},
+	{"nthcdr", 2, 2, func(f *Fake, a []object) (object, error) { return f.nthcdr(a[0], a[1]) }},
+	{"nth", 2, 2, func(f *Fake, a []object) (object, error) {
+		l, err := f.nthcdr(a[0], a[1])
+		if err != nil {
+			return nil, err
+		}
+		return f.car(l)
+	}},
+	{"length", 1, 1, func(f *Fake, a []object) (object, error) {
+		if s, ok := a[0].(*lstring); ok {
+			return int64(s.length()), nil
+		}
+		if h, ok := a[0].(*hashTable); ok {
+			return nil, f.wrongType("sequencep", h)
+		}
+		s, err := f.sequence(a[0])
+		return int64(len(s)), err
+	}},
+	{"reverse", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, err := f.sequence(a[0])
+		if err != nil {
+			return nil, err
+		}
+		r := make([]object, len(s))
+		for i, e := range s {
+			r[len(s)-1-i] = e
+		}
+		if _, ok := a[0].(*vector); ok {
+			return &vector{r}, nil
+		}
+		return f.list(r...), nil
+	}},
+	{"nreverse", 1, 1, func(f *Fake, a []object) (object, error) {
+		if v, ok := a[0].(*vector); ok {
+			for i, j := 0, len(v.elems)-1; i < j; i, j = i+1, j-1 {
+				v.elems[i], v.elems[j] = v.elems[j], v.elems[i]
+			}
+			return v, nil
+		}
+		s, err := f.slice(a[0])
+		if err != nil {
+			return nil, err
+		}
+		r := make([]object, len(s))
+		for i, e := range s {
+			r[len(s)-1-i] = e
+		}
+		return f.list(r...), nil
+	}},
+	{"append", 0, many, func(f *Fake, a []object) (object, error) {
+		if len(a) == 0 {
+			return f.nilSym, nil
+		}
+		var elems []object
+		for _, s := range a[:len(a)-1] {
+			e, err := f.sequence(s)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, e...)
+		}
+		r := a[len(a)-1]
+		for i := len(elems) - 1; i >= 0; i-- {
+			r = &cons{elems[i], r}
+		}
+		return r, nil
+	}},
+	{"memq", 2, 2, func(f *Fake, a []object) (object, error) { return f.member(a[0], a[1], eq) }},
+	{"memql", 2, 2, func(f *Fake, a []object) (object, error) { return f.member(a[0], a[1], eql) }},
+	{"member", 2, 2, func(f *Fake, a []object) (object, error) { return f.member(a[0], a[1], equal) }},
+	{"assq", 2, 2, func(f *Fake, a []object) (object, error) { return f.assoc(a[0], a[1], eq) }},
+	{"assoc", 2, 2, func(f *Fake, a []object) (object, error) { return f.assoc(a[0], a[1], equal) }},
+	{"plist-get", 2, 3, func(f *Fake, a []object) (object, error) {
+		if l := f.plistMember(a[0], a[1]); l != f.nilSym {
+			return f.car(l.(*cons).cdr)
+		}
+		return f.nilSym, nil
+	}},
+	{"plist-member", 2, 3, func(f *Fake, a []object) (object, error) { return f.plistMember(a[0], a[1]), nil }},
+	{"plist-put", 3, 4, func(f *Fake, a []object) (object, error) {
+		if l := f.plistMember(a[0], a[1]); l != f.nilSym {
+			if c, ok := l.(*cons).cdr.(*cons); ok {
+				c.car = a[2]
+				return a[0], nil
+			}
+		}
+		s, err := f.slice(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return f.list(append(s, a[1], a[2])...), nil
+	}},
+	{"mapcar", 2, 2, func(f *Fake, a []object) (object, error) {
+		r, err := f.mapcar(a[0], a[1])
+		if err != nil {
+			return nil, err
+		}
+		return f.list(r...), nil
+	}},
+	{"mapc", 2, 2, func(f *Fake, a []object) (object, error) {
+		_, err := f.mapcar(a[0], a[1])
+		return a[1], err
+	}},
+
+	// Symbols, variables, and features
+	{"symbolp", 1, 1, isType[*symbol]},
+	{"keywordp", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, ok := a[0].(*symbol)
+		return f.bool(ok && strings.HasPrefix(s.name, ":") && f.obarray[s.name] == s), nil
+	}},
+	{"symbol-name", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return &lstring{s.name, true}, nil
+	}},
+	{"intern", 1, 2, func(f *Fake, a []object) (object, error) {
+		s, err := f.stringArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return f.intern(s.s), nil
+	}},
+	{"intern-soft", 1, 2, func(f *Fake, a []object) (object, error) {
+		var name string
+		switch o := a[0].(type) {
+		case *symbol:
+			name = o.name
+		case *lstring:
+			name = o.s
+		default:
+			return nil, f.wrongType("stringp", o)
+		}
+		if s, ok := f.obarray[name]; ok {
+			return s, nil
+		}
+		return f.nilSym, nil
+	}},
+	{"make-symbol", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, err := f.stringArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return &symbol{name: s.s}, nil
+	}},
+	{"fboundp", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return f.bool(s.function != nil && s.function != f.nilSym), nil
+	}},
+	{"symbol-function", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		if s.function == nil {
+			return f.nilSym, nil
+		}
+		return s.function, nil
+	}},
+	{"indirect-function", 1, 2, func(f *Fake, a []object) (object, error) {
+		o := a[0]
+		for i := 0; i < 100; i++ {
+			s, ok := o.(*symbol)
+			if !ok || o == f.nilSym {
+				return o, nil
+			}
+			if s.function == nil {
+				return f.nilSym, nil
+			}
+			o = s.function
+		}
+		return f.nilSym, nil
+	}},
+	{"fset", 2, 2, func(f *Fake, a []object) (object, error) { return f.fset(a[0], a[1]) }},
+	{"defalias", 2, 3, func(f *Fake, a []object) (object, error) {
+		_, err := f.fset(a[0], a[1])
+		return a[0], err
+	}},
+	{"fmakunbound", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		s.function = nil
+		return s, nil
+	}},
+	{"boundp", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return f.bool(s.value != nil), nil
+	}},
+	{"symbol-value", 1, 1, func(f *Fake, a []object) (object, error) { return f.symbolValue(a[0]) }},
+	{"default-value", 1, 1, func(f *Fake, a []object) (object, error) { return f.symbolValue(a[0]) }},
+	{"set", 2, 2, func(f *Fake, a []object) (object, error) { return f.set(a[0], a[1]) }},
+	{"set-default", 2, 2, func(f *Fake, a []object) (object, error) { return f.set(a[0], a[1]) }},
+	{"makunbound", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		if s.constant {
+			return nil, f.signal("setting-constant", s)
+		}
+		s.value = nil
+		return s, nil
+	}},
+	{"symbol-plist", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return f.plist(s), nil
+	}},
+	{"get", 2, 2, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return f.get(s, a[1]), nil
+	}},
+	{"put", 3, 3, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		f.put(s, a[1], a[2])
+		return a[2], nil
+	}},
+	{"provide", 1, 2, func(f *Fake, a []object) (object, error) {
+		if _, err := f.symbolArg(a[0]); err != nil {
+			return nil, err
+		}
+		features := f.intern("features")
+		if m, _ := f.member(a[0], features.value, eq); m == f.nilSym {
+			features.value = &cons{a[0], features.value}
+		}
+		return a[0], nil
+	}},
+	{"featurep", 1, 2, func(f *Fake, a []object) (object, error) {
+		m, err := f.member(a[0], f.intern("features").value, eq)
+		return f.bool(m != f.nilSym), err
+	}},
+	{"require", 1, 3, func(f *Fake, a []object) (object, error) {
+		if m, _ := f.member(a[0], f.intern("features").value, eq); m != f.nilSym {
+			return a[0], nil
+		}
+		if len(a) > 2 && a[2] != f.nilSym {
+			return f.nilSym, nil
+		}
+		return nil, f.signal("file-missing", &lstring{"Cannot open load file", true}, &lstring{"No such file or directory", true}, &lstring{f.print(a[0], false), true})
+	}},
+
+	// Numbers
+	{"integerp", 1, 1, func(f *Fake, a []object) (object, error) { _, ok := toBig(a[0]); return f.bool(ok), nil }},
+	{"fixnump", 1, 1, isType[int64]},
+	{"bignump", 1, 1, isType[*big.Int]},
+	{"natnump", 1, 1, func(f *Fake, a []object) (object, error) {
+		b, ok := toBig(a[0])
+		return f.bool(ok && b.Sign() >= 0), nil
+	}},
+	{"floatp", 1, 1, isType[float64]},
+	{"numberp", 1, 1, func(f *Fake, a []object) (object, error) { return f.bool(isNumber(a[0])), nil }},
+	{"zerop", 1, 1, func(f *Fake, a []object) (object, error) {
+		c, err := f.compare(a[0], int64(0))
+		return f.bool(c == 0), err
+	}},
+	{"+", 0, many, func(f *Fake, a []object) (object, error) {
+		return f.arith(a, int64(0), (*big.Int).Add, func(x, y float64) float64 { return x + y })
+	}},
+	{"*", 0, many, func(f *Fake, a []object) (object, error) {
+		return f.arith(a, int64(1), (*big.Int).Mul, func(x, y float64) float64 { return x * y })
+	}},
+	{"-", 0, many, func(f *Fake, a []object) (object, error) {
+		if len(a) == 1 {
+			a = []object{int64(0), a[0]}
+		}
+		return f.arith(a, nil, (*big.Int).Sub, func(x, y float64) float64 { return x - y })
+	}},
+	{"/", 1, many, func(f *Fake, a []object) (object, error) {
+		if len(a) == 1 {
+			a = []object{int64(1), a[0]}
+		}
+		for _, x := range a[1:] {
+			if b, ok := toBig(x); ok && b.Sign() == 0 && !f.anyFloat(a) {
+				return nil, f.signal("arith-error")
+			}
+		}
+		return f.arith(a, nil, (*big.Int).Quo, func(x, y float64) float64 { return x / y })
+	}},
+	{"%", 2, 2, func(f *Fake, a []object) (object, error) {
+		x, err := f.intArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		y, err := f.intArg(a[1])
+		if err != nil {
+			return nil, err
+		}
+		if y.Sign() == 0 {
+			return nil, f.signal("arith-error")
+		}
+		return normalize(new(big.Int).Rem(x, y)), nil
+	}},
+	{"1+", 1, 1, func(f *Fake, a []object) (object, error) {
+		return f.arith([]object{a[0], int64(1)}, nil, (*big.Int).Add, func(x, y float64) float64 { return x + y })
+	}},
+	{"1-", 1, 1, func(f *Fake, a []object) (object, error) {
+		return f.arith([]object{a[0], int64(1)}, nil, (*big.Int).Sub, func(x, y float64) float64 { return x - y })
+	}},
+	{"=", 1, many, compareAll(func(c int) bool { return c == 0 })},
+	{"<", 1, many, compareAll(func(c int) bool { return c < 0 })},
+	{">", 1, many, compareAll(func(c int) bool { return c > 0 })},
+	{"<=", 1, many, compareAll(func(c int) bool { return c <= 0 })},
+	{">=", 1, many, compareAll(func(c int) bool { return c >= 0 })},
+	{"/=", 2, 2, func(f *Fake, a []object) (object, error) {
+		c, err := f.compare(a[0], a[1])
+		return f.bool(c != 0), err
+	}},
+	{"float", 1, 1, func(f *Fake, a []object) (object, error) { return f.float(a[0]) }},
+	{"truncate", 1, 1, func(f *Fake, a []object) (object, error) {
+		switch x := a[0].(type) {
+		case int64, *big.Int:
+			return x, nil
+		case float64:
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				return nil, f.signal("overflow-error")
+			}
+			b, _ := big.NewFloat(math.Trunc(x)).Int(nil)
+			return normalize(b), nil
+		default:
+			return nil, f.wrongType("numberp", x)
+		}
+	}},
+	{"number-to-string", 1, 1, func(f *Fake, a []object) (object, error) {
+		if !isNumber(a[0]) {
+			return nil, f.wrongType("numberp", a[0])
+		}
+		return &lstring{f.print(a[0], false), false}, nil
+	}},
+
+	// Strings and printing
+	{"stringp", 1, 1, isType[*lstring]},
+	{"multibyte-string-p", 1, 1, func(f *Fake, a []object) (object, error) {
+		s, ok := a[0].(*lstring)
+		return f.bool(ok && s.multibyte), nil
+	}},
+	{"string=", 2, 2, stringEqual},
+	{"string-equal", 2, 2, stringEqual},
+	{"concat", 0, many, func(f *Fake, a []object) (object, error) {
+		var b strings.Builder
+		multibyte := false
+		for _, s := range a {
+			if s, ok := s.(*lstring); ok {
+				b.WriteString(s.s)
+				multibyte = multibyte || s.multibyte
+				continue
+			}
+			chars, err := f.sequence(s)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range chars {
+				n, ok := c.(int64)
+				if !ok || n < 0 || n > utf8.MaxRune {
+					return nil, f.wrongType("characterp", c)
+				}
+				b.WriteRune(rune(n))
+				multibyte = multibyte || n >= utf8.RuneSelf
+			}
+		}
+		return &lstring{b.String(), multibyte}, nil
+	}},
+	{"prin1-to-string", 1, 3, func(f *Fake, a []object) (object, error) {
+		escape := len(a) < 2 || a[1] == f.nilSym
+		return &lstring{f.print(a[0], escape), true}, nil
+	}},
+	{"format", 1, many, func(f *Fake, a []object) (object, error) { return f.format(a) }},
+	{"format-message", 1, many, func(f *Fake, a []object) (object, error) { return f.format(a) }},
+	{"message", 1, many, func(f *Fake, a []object) (object, error) {
+		if a[0] == f.nilSym {
+			return f.nilSym, nil
+		}
+		s, err := f.format(a)
+		if err != nil {
+			return nil, err
+		}
+		f.messages = append(f.messages, s.(*lstring).s)
+		return s, nil
+	}},
+
+	// Vectors
+	{"vectorp", 1, 1, isType[*vector]},
+	{"vector", 0, many, func(f *Fake, a []object) (object, error) { return &vector{append([]object(nil), a...)}, nil }},
+	{"make-vector", 2, 2, func(f *Fake, a []object) (object, error) {
+		n, ok := a[0].(int64)
+		if !ok || n < 0 {
+			return nil, f.wrongType("wholenump", a[0])
+		}
+		v := &vector{make([]object, n)}
+		for i := range v.elems {
+			v.elems[i] = a[1]
+		}
+		return v, nil
+	}},
+	{"vconcat", 0, many, func(f *Fake, a []object) (object, error) {
+		var r []object
+		for _, s := range a {
+			e, err := f.sequence(s)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, e...)
+		}
+		return &vector{r}, nil
+	}},
+	{"aref", 2, 2, func(f *Fake, a []object) (object, error) {
+		i, ok := a[1].(int64)
+		if !ok {
+			return nil, f.wrongType("fixnump", a[1])
+		}
+		if s, ok := a[0].(*lstring); ok {
+			chars, _ := f.sequence(s)
+			if i < 0 || i >= int64(len(chars)) {
+				return nil, f.signal("args-out-of-range", s, i)
+			}
+			return chars[i], nil
+		}
+		v, err := f.vectorIndex(a[0], i)
+		if err != nil {
+			return nil, err
+		}
+		return v.elems[i], nil
+	}},
+	{"aset", 3, 3, func(f *Fake, a []object) (object, error) {
+		i, ok := a[1].(int64)
+		if !ok {
+			return nil, f.wrongType("fixnump", a[1])
+		}
+		v, err := f.vectorIndex(a[0], i)
+		if err != nil {
+			return nil, err
+		}
+		v.elems[i] = a[2]
+		return a[2], nil
+	}},
+
+	// Hash tables
+	{"make-hash-table", 0, many, func(f *Fake, a []object) (object, error) {
+		h := &hashTable{test: f.intern("eql")}
+		for i := 0; i+1 < len(a); i += 2 {
+			if a[i] != f.intern(":test") {
+				continue
+			}
+			test, ok := a[i+1].(*symbol)
+			if !ok || (test.name != "eq" && test.name != "eql" && test.name != "equal") {
+				return nil, f.signal("error", &lstring{"Invalid hash table test", true}, a[i+1])
+			}
+			h.test = test
+		}
+		return h, nil
+	}},
+	{"hash-table-p", 1, 1, isType[*hashTable]},
+	{"hash-table-count", 1, 1, func(f *Fake, a []object) (object, error) {
+		h, err := f.hashTableArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return int64(len(h.keys)), nil
+	}},
+	{"hash-table-test", 1, 1, func(f *Fake, a []object) (object, error) {
+		h, err := f.hashTableArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return h.test, nil
+	}},
+	{"gethash", 2, 3, func(f *Fake, a []object) (object, error) {
+		h, err := f.hashTableArg(a[1])
+		if err != nil {
+			return nil, err
+		}
+		if i := h.find(a[0]); i >= 0 {
+			return h.values[i], nil
+		}
+		if len(a) > 2 {
+			return a[2], nil
+		}
+		return f.nilSym, nil
+	}},
+	{"puthash", 3, 3, func(f *Fake, a []object) (object, error) {
+		h, err := f.hashTableArg(a[2])
+		if err != nil {
+			return nil, err
+		}
+		if i := h.find(a[0]); i >= 0 {
+			h.values[i] = a[1]
+		} else {
+			h.keys = append(h.keys, a[0])
+			h.values = append(h.values, a[1])
+		}
+		return a[1], nil
+	}},
+	{"remhash", 2, 2, func(f *Fake, a []object) (object, error) {
+		h, err := f.hashTableArg(a[1])
+		if err != nil {
+			return nil, err
+		}
+		if i := h.find(a[0]); i >= 0 {
+			h.keys = append(h.keys[:i], h.keys[i+1:]...)
+			h.values = append(h.values[:i], h.values[i+1:]...)
+		}
+		return f.nilSym, nil
+	}},
+	{"clrhash", 1, 1, func(f *Fake, a []object) (object, error) {
+		h, err := f.hashTableArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		h.keys, h.values = nil, nil
+		return h, nil
+	}},
+	{"maphash", 2, 2, func(f *Fake, a []object) (object, error) {
+		h, err := f.hashTableArg(a[1])
+		if err != nil {
+			return nil, err
+		}
+		keys := append([]object(nil), h.keys...)
+		values := append([]object(nil), h.values...)
+		for i, k := range keys {
+			if _, err := f.funcall(a[0], []object{k, values[i]}); err != nil {
+				return nil, err
+			}
+		}
+		return f.nilSym, nil
+	}},
+
+	// Functions
+	{"funcall", 1, many, func(f *Fake, a []object) (object, error) { return f.funcall(a[0], a[1:]) }},
+	{"apply", 1, many, func(f *Fake, a []object) (object, error) {
+		if len(a) == 1 {
+			l, err := f.slice(a[0])
+			if err != nil || len(l) == 0 {
+				return nil, f.wrongType("consp", a[0])
+			}
+			return f.funcall(l[0], l[1:])
+		}
+		rest, err := f.slice(a[len(a)-1])
+		if err != nil {
+			return nil, err
+		}
+		return f.funcall(a[0], append(append([]object(nil), a[1:len(a)-1]...), rest...))
+	}},
+	{"functionp", 1, 1, func(f *Fake, a []object) (object, error) {
+		return f.bool(f.function(a[0]) != nil), nil
+	}},
+	{"commandp", 1, 2, func(f *Fake, a []object) (object, error) {
+		m, ok := f.function(a[0]).(*moduleFunction)
+		return f.bool(ok && m.interactive != nil), nil
+	}},
+	{"interactive-form", 1, 1, func(f *Fake, a []object) (object, error) {
+		if m, ok := f.function(a[0]).(*moduleFunction); ok && m.interactive != nil {
+			return f.list(f.intern("interactive"), m.interactive), nil
+		}
+		return f.nilSym, nil
+	}},
+	{"func-arity", 1, 1, func(f *Fake, a []object) (object, error) {
+		var min, max int
+		switch fun := f.function(a[0]).(type) {
+		case *builtin:
+			min, max = fun.min, fun.max
+		case *moduleFunction:
+			min, max = fun.min, fun.max
+		default:
+			return nil, f.signal("invalid-function", a[0])
+		}
+		var m object = int64(max)
+		if max < 0 {
+			m = f.intern("many")
+		}
+		return &cons{int64(min), m}, nil
+	}},
+	{"documentation", 1, 2, func(f *Fake, a []object) (object, error) {
+		switch fun := f.function(a[0]).(type) {
+		case *builtin:
+			return f.nilSym, nil
+		case *moduleFunction:
+			if fun.doc == "" {
+				return f.nilSym, nil
+			}
+			return &lstring{fun.doc, true}, nil
+		default:
+			return nil, f.signal("invalid-function", a[0])
+		}
+	}},
+
+	// Errors and nonlocal exits
+	{"signal", 2, 2, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		return nil, &signal{s, a[1]}
+	}},
+	{"throw", 2, 2, func(f *Fake, a []object) (object, error) { return nil, &throw{a[0], a[1]} }},
+	{"error", 1, many, func(f *Fake, a []object) (object, error) {
+		s, err := f.format(a)
+		if err != nil {
+			return nil, err
+		}
+		return nil, f.signal("error", s)
+	}},
+	{"user-error", 1, many, func(f *Fake, a []object) (object, error) {
+		s, err := f.format(a)
+		if err != nil {
+			return nil, err
+		}
+		return nil, f.signal("user-error", s)
+	}},
+	{"define-error", 2, 3, func(f *Fake, a []object) (object, error) {
+		s, err := f.symbolArg(a[0])
+		if err != nil {
+			return nil, err
+		}
+		msg, err := f.stringArg(a[1])
+		if err != nil {
+			return nil, err
+		}
+		parents := []object{f.intern("error")}
+		if len(a) > 2 && a[2] != f.nilSym {
+			if _, ok := a[2].(*cons); ok {
+				if parents, err = f.slice(a[2]); err != nil {
+					return nil, err
+				}
+			} else {
+				parents = []object{a[2]}
+			}
+		}
+		conds := []object{s}
+		for _, p := range parents {
+			p, err := f.symbolArg(p)
+			if err != nil {
+				return nil, err
+			}
+			pc, err := f.slice(f.get(p, f.intern("error-conditions")))
+			if err != nil {
+				return nil, err
+			}
+			if len(pc) == 0 {
+				pc = []object{p}
+			}
+			for _, c := range pc {
+				if m, _ := f.member(c, f.list(conds...), eq); m == f.nilSym {
+					conds = append(conds, c)
+				}
+			}
+		}
+		f.put(s, f.intern("error-conditions"), f.list(conds...))
+		f.put(s, f.intern("error-message"), msg)
+		return f.nilSym, nil
+	}},
+	{"error-message-string", 1, 1, func(f *Fake, a []object) (object, error) {
+		return &lstring{f.errorMessage(a[0]), true}, nil
+	}},
+}
+
+// setup defines the builtin functions, error symbols, and variables.
+func (f *Fake) setup() {
+	for i := range builtins {
+		b := &builtins[i]
+		f.intern(b.name).function = b
+	}
+	for _, e := range []struct{ name, message string }{
+		{"error", "error"},
+		{"quit", "Quit"},
+		{"user-error", ""},
+		{"args-out-of-range", "Args out of range"},
+		{"arith-error", "Arithmetic error"},
+		{"overflow-error", "Arithmetic overflow error"},
+		{"invalid-function", "Invalid function"},
+		{"no-catch", "No catch for tag"},
+		{"setting-constant", "Attempt to set a constant symbol"},
+		{"void-function", "Symbol’s function definition is void"},
+		{"void-variable", "Symbol’s value as variable is void"},
+		{"wrong-number-of-arguments", "Wrong number of arguments"},
+		{"wrong-type-argument", "Wrong type argument"},
+		{"file-error", "File error"},
+		{"file-missing", "No such file or directory"},
+	} {
+		s := f.intern(e.name)
+		conds := []object{s}
+		switch e.name {
+		case "error", "quit":
+		case "overflow-error":
+			conds = append(conds, f.intern("arith-error"), f.intern("error"))
+		case "file-missing":
+			conds = append(conds, f.intern("file-error"), f.intern("error"))
+		default:
+			conds = append(conds, f.intern("error"))
+		}
+		f.put(s, f.intern("error-conditions"), f.list(conds...))
+		f.put(s, f.intern("error-message"), &lstring{e.message, true})
+	}
+	for name, value := range map[string]object{
+		"emacs-major-version":  int64(majorVersion),
+		"emacs-minor-version":  int64(1),
+		"emacs-version":        &lstring{fmt.Sprintf("%d.1", majorVersion), false},
+		"most-positive-fixnum": int64(1<<61 - 1),
+		"most-negative-fixnum": int64(-1 << 61),
+		"features":             f.nilSym,
+	} {
+		f.intern(name).value = value
+	}
+}
+
+// majorVersion is the Emacs major version that the fake pretends to be.
+const majorVersion = 30
+
+func isType[T object](f *Fake, a []object) (object, error) {
+	_, ok := a[0].(T)
+	return f.bool(ok), nil
+}
+
+func isNumber(o object) bool {
+	switch o.(type) {
+	case int64, *big.Int, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f *Fake) isList(o object) bool {
+	_, ok := o.(*cons)
+	return ok || o == f.nilSym
+}
+
+func (f *Fake) car(o object) (object, error) {
+	if c, ok := o.(*cons); ok {
+		return c.car, nil
+	}
+	if o == f.nilSym {
+		return o, nil
+	}
+	return nil, f.wrongType("listp", o)
+}
+
+func (f *Fake) cdr(o object) (object, error) {
+	if c, ok := o.(*cons); ok {
+		return c.cdr, nil
+	}
+	if o == f.nilSym {
+		return o, nil
+	}
+	return nil, f.wrongType("listp", o)
+}
+
+func (f *Fake) nthcdr(n, l object) (object, error) {
+	i, ok := n.(int64)
+	if !ok {
+		return nil, f.wrongType("integerp", n)
+	}
+	for ; i > 0 && l != f.nilSym; i-- {
+		var err error
+		if l, err = f.cdr(l); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+func (f *Fake) member(elt, l object, pred func(a, b object) bool) (object, error) {
+	for o := l; o != f.nilSym; {
+		c, ok := o.(*cons)
+		if !ok {
+			return nil, f.wrongType("listp", l)
+		}
+		if pred(elt, c.car) {
+			return c, nil
+		}
+		o = c.cdr
+	}
+	return f.nilSym, nil
+}
+
+func (f *Fake) assoc(key, l object, pred func(a, b object) bool) (object, error) {
+	for o := l; o != f.nilSym; {
+		c, ok := o.(*cons)
+		if !ok {
+			return nil, f.wrongType("listp", l)
+		}
+		if e, ok := c.car.(*cons); ok && pred(key, e.car) {
+			return e, nil
+		}
+		o = c.cdr
+	}
+	return f.nilSym, nil
+}
+
+// plistMember returns the tail of plist that starts with prop, or nil.
+func (f *Fake) plistMember(plist, prop object) object {
+	for o := plist; ; {
+		c, ok := o.(*cons)
+		if !ok {
+			return f.nilSym
+		}
+		if eq(c.car, prop) {
+			return c
+		}
+		next, ok := c.cdr.(*cons)
+		if !ok {
+			return f.nilSym
+		}
+		o = next.cdr
+	}
+}
+
+func (f *Fake) plist(s *symbol) object {
+	if s.plist == nil {
+		s.plist = f.nilSym
+	}
+	return s.plist
+}
+
+func (f *Fake) get(s *symbol, prop object) object {
+	if l, ok := f.plistMember(f.plist(s), prop).(*cons); ok {
+		if c, ok := l.cdr.(*cons); ok {
+			return c.car
+		}
+	}
+	return f.nilSym
+}
+
+func (f *Fake) put(s *symbol, prop, value object) {
+	if l, ok := f.plistMember(f.plist(s), prop).(*cons); ok {
+		if c, ok := l.cdr.(*cons); ok {
+			c.car = value
+			return
+		}
+	}
+	s.plist = &cons{prop, &cons{value, f.plist(s)}}
+}
+
+func (f *Fake) mapcar(fun, seq object) ([]object, error) {
+	elems, err := f.sequence(seq)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]object, len(elems))
+	for i, e := range elems {
+		if r[i], err = f.funcall(fun, []object{e}); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// function returns the function definition of o after following symbol
+// indirections, or nil if o isn’t a function.
+func (f *Fake) function(o object) object {
+	for i := 0; i < 100; i++ {
+		switch fun := o.(type) {
+		case *symbol:
+			if fun == f.nilSym || fun.function == nil {
+				return nil
+			}
+			o = fun.function
+		case *builtin, *moduleFunction:
+			return fun
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (f *Fake) fset(sym, def object) (object, error) {
+	s, err := f.symbolArg(sym)
+	if err != nil {
+		return nil, err
+	}
+	if s == f.nilSym && def != f.nilSym {
+		return nil, f.signal("setting-constant", s)
+	}
+	s.function = def
+	return def, nil
+}
+
+func (f *Fake) symbolValue(o object) (object, error) {
+	s, err := f.symbolArg(o)
+	if err != nil {
+		return nil, err
+	}
+	if s.value == nil {
+		return nil, f.signal("void-variable", s)
+	}
+	return s.value, nil
+}
+
+func (f *Fake) set(sym, value object) (object, error) {
+	s, err := f.symbolArg(sym)
+	if err != nil {
+		return nil, err
+	}
+	if s.constant {
+		return nil, f.signal("setting-constant", s)
+	}
+	s.value = value
+	return value, nil
+}
+
+func (f *Fake) symbolArg(o object) (*symbol, error) {
+	s, ok := o.(*symbol)
+	if !ok {
+		return nil, f.wrongType("symbolp", o)
+	}
+	return s, nil
+}
+
+func (f *Fake) stringArg(o object) (*lstring, error) {
+	s, ok := o.(*lstring)
+	if !ok {
+		return nil, f.wrongType("stringp", o)
+	}
+	return s, nil
+}
+
+func (f *Fake) intArg(o object) (*big.Int, error) {
+	b, ok := toBig(o)
+	if !ok {
+		return nil, f.wrongType("integerp", o)
+	}
+	return b, nil
+}
+
+func (f *Fake) hashTableArg(o object) (*hashTable, error) {
+	h, ok := o.(*hashTable)
+	if !ok {
+		return nil, f.wrongType("hash-table-p", o)
+	}
+	return h, nil
+}
+
+// find returns the index of key in h, or −1 if key isn’t present.
+func (h *hashTable) find(key object) int {
+	var pred func(a, b object) bool
+	switch h.test.name {
+	case "eq":
+		pred = eq
+	case "equal":
+		pred = equal
+	default:
+		pred = eql
+	}
+	for i, k := range h.keys {
+		if pred(key, k) {
+			return i
+		}
+	}
+	return -1
+}
+
+func stringEqual(f *Fake, a []object) (object, error) {
+	var s [2]string
+	for i, o := range a {
+		switch o := o.(type) {
+		case *lstring:
+			s[i] = o.s
+		case *symbol:
+			s[i] = o.name
+		default:
+			return nil, f.wrongType("stringp", o)
+		}
+	}
+	return f.bool(s[0] == s[1]), nil
+}
+
+func (f *Fake) anyFloat(a []object) bool {
+	for _, x := range a {
+		if _, ok := x.(float64); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Fake) float(o object) (object, error) {
+	switch x := o.(type) {
+	case int64:
+		return float64(x), nil
+	case *big.Int:
+		r, _ := new(big.Float).SetInt(x).Float64()
+		return r, nil
+	case float64:
+		return x, nil
+	default:
+		return nil, f.wrongType("number-or-marker-p", o)
+	}
+}
+
+// arith folds the numbers in a using the given operations.  If init is
+// non-nil, the fold starts with init, otherwise with the first element of a.
+// If any number is a float, arith uses floating-point arithmetic.
+func (f *Fake) arith(a []object, init object, intOp func(z, x, y *big.Int) *big.Int, floatOp func(x, y float64) float64) (object, error) {
+	if init != nil {
+		a = append([]object{init}, a...)
+	}
+	for _, x := range a {
+		if !isNumber(x) {
+			return nil, f.wrongType("number-or-marker-p", x)
+		}
+	}
+	if f.anyFloat(a) {
+		r, _ := f.float(a[0])
+		for _, x := range a[1:] {
+			y, _ := f.float(x)
+			r = floatOp(r.(float64), y.(float64))
+		}
+		return r, nil
+	}
+	r, _ := toBig(a[0])
+	r = new(big.Int).Set(r)
+	for _, x := range a[1:] {
+		y, _ := toBig(x)
+		intOp(r, r, y)
+	}
+	return normalize(r), nil
+}
+
+// compare returns −1, 0, or +1 depending on whether a is less than, equal
+// to, or greater than b.  NaNs compare as unequal to everything.
+func (f *Fake) compare(a, b object) (int, error) {
+	for _, x := range []object{a, b} {
+		if !isNumber(x) {
+			return 0, f.wrongType("number-or-marker-p", x)
+		}
+	}
+	if f.anyFloat([]object{a, b}) {
+		x, _ := f.float(a)
+		y, _ := f.float(b)
+		switch {
+		case x.(float64) < y.(float64):
+			return -1, nil
+		case x.(float64) > y.(float64):
+			return 1, nil
+		case x.(float64) == y.(float64):
+			return 0, nil
+		default:
+			return 2, nil
+		}
+	}
+	x, _ := toBig(a)
+	y, _ := toBig(b)
+	return x.Cmp(y), nil
+}
+
+func compareAll(ok func(int) bool) func(f *Fake, a []object) (object, error) {
+	return func(f *Fake, a []object) (object, error) {
+		r := true
+		for i := 0; i+1 < len(a); i++ {
+			c, err := f.compare(a[i], a[i+1])
+			if err != nil {
+				return nil, err
+			}
+			r = r && c != 2 && ok(c)
+		}
+		if len(a) == 1 && !isNumber(a[0]) {
+			return nil, f.wrongType("number-or-marker-p", a[0])
+		}
+		return f.bool(r), nil
+	}
+}
+
+// format implements the format function.  It supports the %s, %S, %d, %o,
+// %x, %X, %c, %e, %f, %g, and %% directives with flags, width, and precision.
+func (f *Fake) format(a []object) (object, error) {
+	str, err := f.stringArg(a[0])
+	if err != nil {
+		return nil, err
+	}
+	args := a[1:]
+	var b strings.Builder
+	multibyte := str.multibyte
+	s := str.s
+	for {
+		i := strings.IndexByte(s, '%')
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		s = s[i+1:]
+		j := strings.IndexAny(s, "sSdoxXcefg%")
+		if j < 0 {
+			return nil, f.signal("error", &lstring{"Format string ends in middle of format specifier", true})
+		}
+		spec, verb := s[:j], s[j]
+		s = s[j+1:]
+		if verb == '%' {
+			b.WriteByte('%')
+			continue
+		}
+		if len(args) == 0 {
+			return nil, f.signal("error", &lstring{"Not enough arguments for format string", true})
+		}
+		arg := args[0]
+		args = args[1:]
+		switch verb {
+		case 's', 'S':
+			p := f.print(arg, verb == 'S')
+			if s, ok := arg.(*lstring); ok && s.multibyte {
+				multibyte = true
+			}
+			fmt.Fprintf(&b, "%"+spec+"s", p)
+		case 'd', 'o', 'x', 'X':
+			var n *big.Int
+			switch x := arg.(type) {
+			case float64:
+				n, _ = big.NewFloat(math.Trunc(x)).Int(nil)
+			default:
+				var ok bool
+				if n, ok = toBig(x); !ok {
+					return nil, f.signal("error", &lstring{"Format specifier doesn’t match argument type", true})
+				}
+			}
+			fmt.Fprintf(&b, "%"+spec+string(verb), n)
+		case 'c':
+			n, ok := arg.(int64)
+			if !ok {
+				return nil, f.signal("error", &lstring{"Format specifier doesn’t match argument type", true})
+			}
+			b.WriteRune(rune(n))
+			multibyte = multibyte || n >= utf8.RuneSelf
+		case 'e', 'f', 'g':
+			x, err := f.float(arg)
+			if err != nil {
+				return nil, f.signal("error", &lstring{"Format specifier doesn’t match argument type", true})
+			}
+			fmt.Fprintf(&b, "%"+spec+string(verb), x)
+		}
+	}
+	return &lstring{b.String(), multibyte}, nil
+}
+
+// errorMessage implements error-message-string.
+func (f *Fake) errorMessage(o object) string {
+	c, ok := o.(*cons)
+	if !ok {
+		return "peculiar error"
+	}
+	sym, ok := c.car.(*symbol)
+	if !ok {
+		return "peculiar error"
+	}
+	var msg object
+	data := c.cdr
+	if sym.name == "error" {
+		if d, ok := data.(*cons); ok {
+			msg, data = d.car, d.cdr
+		} else {
+			msg = f.nilSym
+		}
+	} else {
+		msg = f.get(sym, f.intern("error-message"))
+	}
+	var b strings.Builder
+	if s, ok := msg.(*lstring); ok {
+		b.WriteString(s.s)
+	} else {
+		b.WriteString("peculiar error")
+	}
+	escape := sym.name != "user-error"
+	sep := ": "
+	for {
+		d, ok := data.(*cons)
+		if !ok {
+			break
+		}
+		b.WriteString(sep)
+		sep = ", "
+		b.WriteString(f.print(d.car, escape))
+		data = d.cdr
+	}
+	return b.String()
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacstest_test
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phst/emacs"
+	"github.com/phst/emacs/emacstest"
+)
+
+func TestConversions(t *testing.T) {
+	fake := emacstest.New()
+	defer fake.Close()
+	e := fake.Env()
+	for _, tc := range []struct {
+		name string
+		in   emacs.In
+		out  emacs.Out
+		want interface{}
+	}{
+		{"Int", emacs.Int(-123), new(emacs.Int), emacs.Int(-123)},
+		{"Float", emacs.Float(0.5), new(emacs.Float), emacs.Float(0.5)},
+		{"String", emacs.String("Hallo Welt ✓"), new(emacs.String), emacs.String("Hallo Welt ✓")},
+		{"Bool", emacs.Bool(true), new(emacs.Bool), emacs.Bool(true)},
+		{"BigInt", (*emacs.BigInt)(new(big.Int).Lsh(big.NewInt(-3), 100)), new(emacs.BigInt), emacs.BigInt(*new(big.Int).Lsh(big.NewInt(-3), 100))},
+		{"Time", emacs.Time(time.Unix(1600000000, 123456789)), new(emacs.Time), emacs.Time(time.Unix(1600000000, 123456789))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := e.CallOut("identity", tc.out, tc.in); err != nil {
+				t.Fatal(err)
+			}
+			got := reflect.ValueOf(tc.out).Elem().Interface()
+			if g, ok := got.(emacs.Time); ok {
+				if !time.Time(g).Equal(time.Time(tc.want.(emacs.Time))) {
+					t.Errorf("got %v, want %v", g, tc.want)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestListAndHash(t *testing.T) {
+	fake := emacstest.New()
+	defer fake.Close()
+	e := fake.Env()
+	list, err := e.Call("list", emacs.Int(1), emacs.Int(2), emacs.Int(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n emacs.Int
+	if err := e.CallOut("length", &n, list); err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("length: got %d, want 3", n)
+	}
+	vec := emacs.VectorOut{New: func() emacs.Out { return new(emacs.Int) }}
+	if err := e.CallOut("vconcat", &vec, list); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := vec.Data, []emacs.Out{ptr(emacs.Int(1)), ptr(emacs.Int(2)), ptr(emacs.Int(3))}; !reflect.DeepEqual(got, want) {
+		t.Errorf("vconcat: got %v, want %v", got, want)
+	}
+	strs := emacs.ListOut{New: func() emacs.Out { return new(emacs.String) }}
+	if err := e.CallOut("identity", &strs, list); !e.IsWrongTypeArgument(err) {
+		t.Errorf("converting list of integers to strings: got error %v, want wrong-type-argument", err)
+	}
+	var s emacs.String
+	if err := e.CallOut("format", &s, emacs.String("%S %s %d"), list, emacs.String("x"), emacs.Float(4.5)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "(1 2 3) x 4"; string(s) != want {
+		t.Errorf("format: got %q, want %q", s, want)
+	}
+
+	table, err := e.Call("make-hash-table", emacs.Symbol(":test"), emacs.Symbol("equal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Call("puthash", emacs.String("key"), emacs.Int(42), table); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.CallOut("gethash", &n, emacs.String("key"), table); err != nil {
+		t.Fatal(err)
+	}
+	if n != 42 {
+		t.Errorf("gethash: got %d, want 42", n)
+	}
+}
+
+func TestExport(t *testing.T) {
+	fake := emacstest.New()
+	defer fake.Close()
+	e := fake.Env()
+	fun, del, err := e.Lambda(func(s string, n int) (string, error) {
+		if n < 0 {
+			return "", errors.New("negative count")
+		}
+		return strings.Repeat(s, n), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer del()
+	var got emacs.String
+	if err := e.CallOut("funcall", &got, fun, emacs.String("ab"), emacs.Int(3)); err != nil {
+		t.Fatal(err)
+	}
+	if got != "ababab" {
+		t.Errorf("got %q, want %q", got, "ababab")
+	}
+	_, err = e.Call("funcall", fun, emacs.String("ab"), emacs.Int(-1))
+	var sig emacs.Signal
+	if !errors.As(err, &sig) {
+		t.Errorf("got error %#v, want signal", err)
+	}
+	if _, err := e.Call("funcall", fun, emacs.Int(1), emacs.Int(1)); !e.IsWrongTypeArgument(err) {
+		t.Errorf("got error %v, want wrong-type-argument", err)
+	}
+	if _, err := e.Call("funcall", fun); err == nil {
+		t.Error("calling with too few arguments succeeded")
+	}
+}
+
+func TestDefineFunction(t *testing.T) {
+	fake := emacstest.New()
+	defer fake.Close()
+	e := fake.Env()
+	err := fake.DefineFunction("my-message", func(e emacs.Env, args []emacs.Value) (emacs.Value, error) {
+		return e.Call("message", emacs.String("Hello %s"), args[0])
+	}, emacs.Arity{Min: 1, Max: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Call("my-message", emacs.String("world")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fake.Messages(), []string{"Hello world"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("messages: got %q, want %q", got, want)
+	}
+	if _, err := e.Call("no-such-function"); err == nil {
+		t.Error("calling undefined function succeeded")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	fake := emacstest.New()
+	defer fake.Close()
+	e := fake.Env()
+	_, err := e.Call("car", emacs.Int(1))
+	if !e.IsWrongTypeArgument(err) {
+		t.Fatalf("got error %v, want wrong-type-argument", err)
+	}
+	var msg emacs.String
+	if err := e.CallOut("error-message-string", &msg, emacs.List{emacs.Symbol("wrong-type-argument"), emacs.Symbol("listp"), emacs.Int(1)}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Wrong type argument: listp, 1"; string(msg) != want {
+		t.Errorf("error message: got %q, want %q", msg, want)
+	}
+	_, err = e.Call("throw", emacs.Symbol("tag"), emacs.Int(5))
+	var thr emacs.Throw
+	if !errors.As(err, &thr) {
+		t.Errorf("got error %#v, want throw", err)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	fake := emacstest.New()
+	defer fake.Close()
+	e := fake.Env()
+	var b emacs.Batch
+	table := b.Call("make-hash-table", b.Symbol(":test"), b.Symbol("equal"))
+	for i := 0; i < 100; i++ {
+		b.Call("puthash", b.String(strconv.Itoa(i)), b.Int(int64(i)), table)
+	}
+	count := b.Call("hash-table-count", table)
+	vals, err := e.Batch(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n emacs.Int
+	if err := n.FromEmacs(e, vals[count]); err != nil {
+		t.Fatal(err)
+	}
+	if n != 100 {
+		t.Errorf("hash-table-count: got %d, want 100", n)
+	}
+	if err := e.CallOut("gethash", &n, emacs.String("42"), vals[table]); err != nil {
+		t.Fatal(err)
+	}
+	if n != 42 {
+		t.Errorf("gethash: got %d, want 42", n)
+	}
+}
+
+func ptr[T any](x T) *T { return &x }
@@ -0,0 +1,230 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+#include "fake.h"
+
+#include <stdbool.h>
+#include <stddef.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <time.h>
+
+#include "_cgo_export.h"
+#include "emacs-module.h"
+
+struct fake_env {
+  // Must be the first member so that we can convert between pointers to
+  // emacs_env and struct fake_env.
+  emacs_env env;
+  uintptr_t handle;
+};
+
+static uintptr_t handle(emacs_env *env) {
+  return ((struct fake_env *)env)->handle;
+}
+
+static emacs_value make_global_ref(emacs_env *env, emacs_value value) {
+  // The fake never collects garbage, so all values are global.
+  return value;
+}
+
+static void free_global_ref(emacs_env *env, emacs_value value) {}
+
+static enum emacs_funcall_exit non_local_exit_check(emacs_env *env) {
+  return emacstest_non_local_exit_get(handle(env), NULL, NULL);
+}
+
+static void non_local_exit_clear(emacs_env *env) {
+  emacstest_non_local_exit_clear(handle(env));
+}
+
+static enum emacs_funcall_exit non_local_exit_get(emacs_env *env,
+                                                  emacs_value *symbol,
+                                                  emacs_value *data) {
+  return emacstest_non_local_exit_get(handle(env), symbol, data);
+}
+
+static void non_local_exit_signal(emacs_env *env, emacs_value symbol,
+                                  emacs_value data) {
+  emacstest_non_local_exit_signal(handle(env), symbol, data);
+}
+
+static void non_local_exit_throw(emacs_env *env, emacs_value tag,
+                                 emacs_value value) {
+  emacstest_non_local_exit_throw(handle(env), tag, value);
+}
+
+static emacs_value make_function(emacs_env *env, ptrdiff_t min_arity,
+                                 ptrdiff_t max_arity, emacs_function function,
+                                 const char *documentation, void *data) {
+  return emacstest_make_function(handle(env), min_arity, max_arity, function,
+                                 (char *)documentation, (uintptr_t)data);
+}
+
+static emacs_value funcall(emacs_env *env, emacs_value function,
+                           ptrdiff_t nargs, emacs_value *args) {
+  return emacstest_funcall(handle(env), function, nargs, args);
+}
+
+static emacs_value intern(emacs_env *env, const char *name) {
+  return emacstest_intern(handle(env), (char *)name);
+}
+
+static emacs_value type_of(emacs_env *env, emacs_value value) {
+  return emacstest_type_of(handle(env), value);
+}
+
+static bool is_not_nil(emacs_env *env, emacs_value value) {
+  return emacstest_is_not_nil(handle(env), value);
+}
+
+static bool eq(emacs_env *env, emacs_value a, emacs_value b) {
+  return emacstest_eq(handle(env), a, b);
+}
+
+static intmax_t extract_integer(emacs_env *env, emacs_value value) {
+  return emacstest_extract_integer(handle(env), value);
+}
+
+static emacs_value make_integer(emacs_env *env, intmax_t value) {
+  return emacstest_make_integer(handle(env), value);
+}
+
+static double extract_float(emacs_env *env, emacs_value value) {
+  return emacstest_extract_float(handle(env), value);
+}
+
+static emacs_value make_float(emacs_env *env, double value) {
+  return emacstest_make_float(handle(env), value);
+}
+
+static bool copy_string_contents(emacs_env *env, emacs_value value,
+                                 char *buffer, ptrdiff_t *size) {
+  return emacstest_copy_string_contents(handle(env), value, buffer, size);
+}
+
+static emacs_value make_string(emacs_env *env, const char *str,
+                               ptrdiff_t len) {
+  return emacstest_make_string(handle(env), (char *)str, len, true);
+}
+
+static emacs_value vec_get(emacs_env *env, emacs_value vector,
+                           ptrdiff_t index) {
+  return emacstest_vec_get(handle(env), vector, index);
+}
+
+static void vec_set(emacs_env *env, emacs_value vector, ptrdiff_t index,
+                    emacs_value value) {
+  emacstest_vec_set(handle(env), vector, index, value);
+}
+
+static ptrdiff_t vec_size(emacs_env *env, emacs_value vector) {
+  return emacstest_vec_size(handle(env), vector);
+}
+
+static bool should_quit(emacs_env *env) { return false; }
+
+static enum emacs_process_input_result process_input(emacs_env *env) {
+  return emacs_process_input_continue;
+}
+
+static struct timespec extract_time(emacs_env *env, emacs_value value) {
+  return emacstest_extract_time(handle(env), value);
+}
+
+static emacs_value make_time(emacs_env *env, struct timespec time) {
+  return emacstest_make_time(handle(env), time);
+}
+
+static bool extract_big_integer(emacs_env *env, emacs_value value, int *sign,
+                                ptrdiff_t *count, emacs_limb_t *magnitude) {
+  return emacstest_extract_big_integer(handle(env), value, sign, count,
+                                       magnitude);
+}
+
+static emacs_value make_big_integer(emacs_env *env, int sign, ptrdiff_t count,
+                                    const emacs_limb_t *magnitude) {
+  return emacstest_make_big_integer(handle(env), sign, count,
+                                    (emacs_limb_t *)magnitude);
+}
+
+static void set_function_finalizer(emacs_env *env, emacs_value function,
+                                   void (*finalizer)(void *)) {
+  // The fake never collects garbage, so finalizers never run.
+}
+
+static int open_channel(emacs_env *env, emacs_value process) {
+  return emacstest_open_channel(handle(env), process);
+}
+
+static void make_interactive(emacs_env *env, emacs_value function,
+                             emacs_value spec) {
+  emacstest_make_interactive(handle(env), function, spec);
+}
+
+static emacs_value make_unibyte_string(emacs_env *env, const char *str,
+                                       ptrdiff_t len) {
+  return emacstest_make_string(handle(env), (char *)str, len, false);
+}
+
+emacs_env *emacstest_new_env(uintptr_t handle) {
+  struct fake_env *fake = calloc(1, sizeof *fake);
+  if (fake == NULL) {
+    return NULL;
+  }
+  emacs_env *env = &fake->env;
+  env->size = sizeof *env;
+  env->make_global_ref = make_global_ref;
+  env->free_global_ref = free_global_ref;
+  env->non_local_exit_check = non_local_exit_check;
+  env->non_local_exit_clear = non_local_exit_clear;
+  env->non_local_exit_get = non_local_exit_get;
+  env->non_local_exit_signal = non_local_exit_signal;
+  env->non_local_exit_throw = non_local_exit_throw;
+  env->make_function = make_function;
+  env->funcall = funcall;
+  env->intern = intern;
+  env->type_of = type_of;
+  env->is_not_nil = is_not_nil;
+  env->eq = eq;
+  env->extract_integer = extract_integer;
+  env->make_integer = make_integer;
+  env->extract_float = extract_float;
+  env->make_float = make_float;
+  env->copy_string_contents = copy_string_contents;
+  env->make_string = make_string;
+  env->vec_get = vec_get;
+  env->vec_set = vec_set;
+  env->vec_size = vec_size;
+  env->should_quit = should_quit;
+  env->process_input = process_input;
+  env->extract_time = extract_time;
+  env->make_time = make_time;
+  env->extract_big_integer = extract_big_integer;
+  env->make_big_integer = make_big_integer;
+  env->set_function_finalizer = set_function_finalizer;
+  env->open_channel = open_channel;
+  env->make_interactive = make_interactive;
+  env->make_unibyte_string = make_unibyte_string;
+  fake->handle = handle;
+  return env;
+}
+
+void emacstest_free_env(emacs_env *env) { free(env); }
+
+emacs_value emacstest_call_function(emacs_function function, emacs_env *env,
+                                    ptrdiff_t nargs, emacs_value *args,
+                                    uintptr_t data) {
+  return function(env, nargs, args, (void *)data);
+}
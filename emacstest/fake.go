@@ -0,0 +1,679 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package emacstest provides a fake Emacs environment for unit tests.  The
+// fake simulates the core of the module API and a small Lisp runtime: symbols
+// and their value and function cells, integers and floats, strings, cons
+// cells and lists, vectors, hash tables, nonlocal exits, and calling
+// functions.  This is enough to test conversion logic and exported functions
+// with plain go test, without starting Emacs:
+//
+//	func TestDouble(t *testing.T) {
+//		fake := emacstest.New()
+//		defer fake.Close()
+//		e := fake.Env()
+//		fun, del, err := e.Lambda(func(i int) int { return 2 * i })
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		defer del()
+//		var got emacs.Int
+//		if err := e.CallOut("funcall", &got, fun, emacs.Int(21)); err != nil {
+//			t.Fatal(err)
+//		}
+//		if got != 42 {
+//			t.Errorf("got %d, want 42", got)
+//		}
+//	}
+//
+// The fake only knows a fixed set of Lisp functions, such as car, list,
+// gethash, format, and signal; calling any other function signals
+// void-function.  Use [Fake.DefineFunction] to define more functions.  The
+// fake has no Lisp interpreter, so functions such as eval aren’t available.
+// Module functions never get garbage-collected, so their finalizers never
+// run.  For everything else, test with a real Emacs using [emacs.ERTTest].
+package emacstest
+
+// #include <stdint.h>
+// #include <stdlib.h>
+// #include "emacs-module.h"
+// #include "fake.h"
+import "C"
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/phst/emacs"
+	"github.com/phst/emacs/internal/rawenv"
+)
+
+// Fake is a fake Emacs instance.  Use [New] to create Fake objects.  A Fake
+// isn’t safe for concurrent use; like Emacs itself, use it from one goroutine
+// at a time.
+type Fake struct {
+	handle  uintptr
+	env     *C.emacs_env
+	e       emacs.Env
+	release func()
+
+	obarray map[string]*symbol
+	nilSym  *symbol
+	tSym    *symbol
+
+	// values maps value handles to objects.  Symbols always use the same
+	// handle, stored in symbolHandles.
+	values        map[C.emacs_value]object
+	symbolHandles map[*symbol]C.emacs_value
+	blocks        []unsafe.Pointer
+	used          int // number of handles used in the last block
+
+	// Pending nonlocal exit, nil if none.
+	exit error
+
+	messages []string
+	deletes  []emacs.DeleteFunc
+}
+
+// New returns a new fake Emacs instance.  Call [Fake.Close] when done.
+func New() *Fake {
+	f := &Fake{
+		handle:        uintptr(atomic.AddUint64(&lastHandle, 1)),
+		obarray:       make(map[string]*symbol),
+		values:        make(map[C.emacs_value]object),
+		symbolHandles: make(map[*symbol]C.emacs_value),
+	}
+	f.nilSym = f.intern("nil")
+	f.nilSym.value = f.nilSym
+	f.nilSym.constant = true
+	f.nilSym.plist = f.nilSym
+	f.tSym = f.intern("t")
+	f.tSym.value = f.tSym
+	f.tSym.constant = true
+	f.setup()
+	f.env = C.emacstest_new_env(C.uintptr_t(f.handle))
+	if f.env == nil {
+		panic("out of memory")
+	}
+	fakes.Store(f.handle, f)
+	env, release := rawenv.NewEnv(unsafe.Pointer(f.env))
+	f.e = env.(emacs.Env)
+	f.release = release
+	return f
+}
+
+var (
+	lastHandle uint64 // accessed atomically
+	fakes      sync.Map
+)
+
+// Env returns an environment for f.  The environment stays valid until you
+// call [Fake.Close].
+func (f *Fake) Env() emacs.Env {
+	return f.e
+}
+
+// Close releases the resources held by f.  It deletes the functions defined
+// by [Fake.DefineFunction].  After calling Close, you can’t use f or its
+// environments any more.
+func (f *Fake) Close() {
+	for _, del := range f.deletes {
+		del()
+	}
+	f.deletes = nil
+	f.release()
+	fakes.Delete(f.handle)
+	C.emacstest_free_env(f.env)
+	f.env = nil
+	for _, b := range f.blocks {
+		C.free(b)
+	}
+	f.blocks = nil
+	f.values = nil
+}
+
+// Init runs the module initializers registered with [emacs.OnInit],
+// [emacs.Export], and similar functions in the environment of f, like Emacs
+// does when loading the module.  Because module initialization is global,
+// only one Init call per process can succeed; later calls return an error.
+// In particular, functions exported using [emacs.Export] are only defined in
+// the first Fake that calls Init.
+func (f *Fake) Init() error {
+	if !atomic.CompareAndSwapInt32(&initialized, 0, 1) {
+		return errors.New("emacstest: module already initialized")
+	}
+	return rawenv.Init(unsafe.Pointer(f.env))
+}
+
+var initialized int32 // accessed atomically
+
+// DefineFunction defines a function with the given name in f.  Unlike
+// [emacs.Env.ExportFunc], the definition only affects f and doesn’t register
+// the name globally, so several Fake objects can define functions with the
+// same name.  Use DefineFunction to stub out Lisp functions that the code
+// under test calls.
+func (f *Fake) DefineFunction(name emacs.Name, fun emacs.Func, arity emacs.Arity) error {
+	v, del, err := f.Env().LambdaFunc(fun, arity, "")
+	if err != nil {
+		return err
+	}
+	f.deletes = append(f.deletes, del)
+	f.intern(string(name)).function = f.object(C.emacs_value(rawenv.ValueHandle(v)))
+	return nil
+}
+
+// Messages returns the messages logged using the message function, oldest
+// first.
+func (f *Fake) Messages() []string {
+	return append([]string(nil), f.messages...)
+}
+
+func fake(handle C.uintptr_t) *Fake {
+	f, ok := fakes.Load(uintptr(handle))
+	if !ok {
+		panic("emacstest: environment used after Close")
+	}
+	return f.(*Fake)
+}
+
+func (f *Fake) intern(name string) *symbol {
+	s := f.obarray[name]
+	if s == nil {
+		s = &symbol{name: name, constant: len(name) > 0 && name[0] == ':'}
+		if s.constant {
+			s.value = s
+		}
+		f.obarray[name] = s
+	}
+	return s
+}
+
+// object returns the object for the value handle v.
+func (f *Fake) object(v C.emacs_value) object {
+	o, ok := f.values[v]
+	if !ok {
+		panic("emacstest: invalid value")
+	}
+	return o
+}
+
+// value returns a value handle for o.
+func (f *Fake) value(o object) C.emacs_value {
+	if s, ok := o.(*symbol); ok {
+		if v, ok := f.symbolHandles[s]; ok {
+			return v
+		}
+		v := f.newHandle()
+		f.symbolHandles[s] = v
+		f.values[v] = s
+		return v
+	}
+	v := f.newHandle()
+	f.values[v] = o
+	return v
+}
+
+// newHandle returns a new unique value handle.  The fake never reuses
+// handles; all handles are valid until the Fake is closed.
+func (f *Fake) newHandle() C.emacs_value {
+	const size = 1 << 16
+	if len(f.blocks) == 0 || f.used == size {
+		b := C.malloc(size)
+		if b == nil {
+			panic("out of memory")
+		}
+		f.blocks = append(f.blocks, b)
+		f.used = 0
+	}
+	v := C.emacs_value(unsafe.Add(f.blocks[len(f.blocks)-1], f.used))
+	f.used++
+	return v
+}
+
+// moduleFunction is a function created by make_function.
+type moduleFunction struct {
+	min, max    int
+	fun         C.emacs_function
+	data        C.uintptr_t
+	doc         string
+	interactive object // nil if not a command
+}
+
+// funcall calls fun with the given arguments.
+func (f *Fake) funcall(fun object, args []object) (object, error) {
+	orig := fun
+	for i := 0; ; i++ {
+		s, ok := fun.(*symbol)
+		if !ok {
+			break
+		}
+		if s.function == nil || s.function == f.nilSym || i > 100 {
+			return nil, f.signal("void-function", orig)
+		}
+		fun = s.function
+	}
+	switch fun := fun.(type) {
+	case *builtin:
+		if len(args) < fun.min || (fun.max >= 0 && len(args) > fun.max) {
+			return nil, f.signal("wrong-number-of-arguments", orig, int64(len(args)))
+		}
+		return fun.fun(f, args)
+	case *moduleFunction:
+		if len(args) < fun.min || (fun.max >= 0 && len(args) > fun.max) {
+			return nil, f.signal("wrong-number-of-arguments", orig, int64(len(args)))
+		}
+		return f.callModuleFunction(fun, args)
+	default:
+		return nil, f.signal("invalid-function", orig)
+	}
+}
+
+func (f *Fake) callModuleFunction(fun *moduleFunction, args []object) (object, error) {
+	var ptr *C.emacs_value
+	if n := len(args); n > 0 {
+		ptr = (*C.emacs_value)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.emacs_value(nil)))))
+		if ptr == nil {
+			panic("out of memory")
+		}
+		defer C.free(unsafe.Pointer(ptr))
+		s := unsafe.Slice(ptr, n)
+		for i, a := range args {
+			s[i] = f.value(a)
+		}
+	}
+	r := C.emacstest_call_function(fun.fun, f.env, C.ptrdiff_t(len(args)), ptr, fun.data)
+	if err := f.exit; err != nil {
+		f.exit = nil
+		return nil, err
+	}
+	return f.object(r), nil
+}
+
+// setExit stores a pending nonlocal exit.  Like Emacs, it ignores the new
+// exit if there’s already one pending.
+func (f *Fake) setExit(err error) {
+	if f.exit == nil {
+		f.exit = err
+	}
+}
+
+//export emacstest_non_local_exit_get
+func emacstest_non_local_exit_get(handle C.uintptr_t, sym, data *C.emacs_value) C.enum_emacs_funcall_exit {
+	f := fake(handle)
+	switch err := f.exit.(type) {
+	case nil:
+		return C.emacs_funcall_exit_return
+	case *signal:
+		if sym != nil && data != nil {
+			*sym = f.value(err.sym)
+			*data = f.value(err.data)
+		}
+		return C.emacs_funcall_exit_signal
+	case *throw:
+		if sym != nil && data != nil {
+			*sym = f.value(err.tag)
+			*data = f.value(err.value)
+		}
+		return C.emacs_funcall_exit_throw
+	default:
+		panic(err)
+	}
+}
+
+//export emacstest_non_local_exit_clear
+func emacstest_non_local_exit_clear(handle C.uintptr_t) {
+	fake(handle).exit = nil
+}
+
+//export emacstest_non_local_exit_signal
+func emacstest_non_local_exit_signal(handle C.uintptr_t, sym, data C.emacs_value) {
+	f := fake(handle)
+	s, ok := f.object(sym).(*symbol)
+	if !ok {
+		s = f.intern("error")
+	}
+	f.setExit(&signal{s, f.object(data)})
+}
+
+//export emacstest_non_local_exit_throw
+func emacstest_non_local_exit_throw(handle C.uintptr_t, tag, value C.emacs_value) {
+	f := fake(handle)
+	f.setExit(&throw{f.object(tag), f.object(value)})
+}
+
+//export emacstest_make_function
+func emacstest_make_function(handle C.uintptr_t, min, max C.ptrdiff_t, fun C.emacs_function, doc *C.char, data C.uintptr_t) C.emacs_value {
+	f := fake(handle)
+	m := &moduleFunction{min: int(min), max: int(max), fun: fun, data: data}
+	if doc != nil {
+		m.doc = C.GoString(doc)
+	}
+	return f.value(m)
+}
+
+//export emacstest_funcall
+func emacstest_funcall(handle C.uintptr_t, fun C.emacs_value, nargs C.ptrdiff_t, args *C.emacs_value) C.emacs_value {
+	f := fake(handle)
+	if f.exit != nil {
+		return f.value(f.nilSym)
+	}
+	var in []object
+	if nargs > 0 {
+		for _, a := range unsafe.Slice(args, int(nargs)) {
+			in = append(in, f.object(a))
+		}
+	}
+	r, err := f.funcall(f.object(fun), in)
+	if err != nil {
+		f.setExit(err)
+		return f.value(f.nilSym)
+	}
+	return f.value(r)
+}
+
+//export emacstest_intern
+func emacstest_intern(handle C.uintptr_t, name *C.char) C.emacs_value {
+	f := fake(handle)
+	return f.value(f.intern(C.GoString(name)))
+}
+
+//export emacstest_type_of
+func emacstest_type_of(handle C.uintptr_t, v C.emacs_value) C.emacs_value {
+	f := fake(handle)
+	return f.value(f.intern(typeOf(f.object(v))))
+}
+
+//export emacstest_is_not_nil
+func emacstest_is_not_nil(handle C.uintptr_t, v C.emacs_value) C.bool {
+	f := fake(handle)
+	return C.bool(f.object(v) != f.nilSym)
+}
+
+//export emacstest_eq
+func emacstest_eq(handle C.uintptr_t, a, b C.emacs_value) C.bool {
+	f := fake(handle)
+	return C.bool(eq(f.object(a), f.object(b)))
+}
+
+//export emacstest_extract_integer
+func emacstest_extract_integer(handle C.uintptr_t, v C.emacs_value) C.intmax_t {
+	f := fake(handle)
+	switch o := f.object(v).(type) {
+	case int64:
+		return C.intmax_t(o)
+	case *big.Int:
+		f.setExit(f.signal("overflow-error"))
+	default:
+		f.setExit(f.wrongType("integerp", o))
+	}
+	return 0
+}
+
+//export emacstest_make_integer
+func emacstest_make_integer(handle C.uintptr_t, i C.intmax_t) C.emacs_value {
+	return fake(handle).value(int64(i))
+}
+
+//export emacstest_extract_float
+func emacstest_extract_float(handle C.uintptr_t, v C.emacs_value) C.double {
+	f := fake(handle)
+	o := f.object(v)
+	x, ok := o.(float64)
+	if !ok {
+		f.setExit(f.wrongType("floatp", o))
+		return 0
+	}
+	return C.double(x)
+}
+
+//export emacstest_make_float
+func emacstest_make_float(handle C.uintptr_t, x C.double) C.emacs_value {
+	return fake(handle).value(float64(x))
+}
+
+//export emacstest_copy_string_contents
+func emacstest_copy_string_contents(handle C.uintptr_t, v C.emacs_value, buffer *C.char, size *C.ptrdiff_t) C.bool {
+	f := fake(handle)
+	o := f.object(v)
+	s, ok := o.(*lstring)
+	if !ok {
+		f.setExit(f.wrongType("stringp", o))
+		return false
+	}
+	n := len(s.s) + 1
+	if buffer == nil {
+		*size = C.ptrdiff_t(n)
+		return true
+	}
+	if int(*size) < n {
+		*size = C.ptrdiff_t(n)
+		f.setExit(f.signal("args-out-of-range"))
+		return false
+	}
+	b := unsafe.Slice((*byte)(unsafe.Pointer(buffer)), n)
+	copy(b, s.s)
+	b[n-1] = 0
+	*size = C.ptrdiff_t(n)
+	return true
+}
+
+//export emacstest_make_string
+func emacstest_make_string(handle C.uintptr_t, str *C.char, n C.ptrdiff_t, multibyte C.bool) C.emacs_value {
+	f := fake(handle)
+	return f.value(&lstring{C.GoStringN(str, C.int(n)), bool(multibyte)})
+}
+
+//export emacstest_vec_get
+func emacstest_vec_get(handle C.uintptr_t, v C.emacs_value, i C.ptrdiff_t) C.emacs_value {
+	f := fake(handle)
+	vec, err := f.vectorIndex(f.object(v), int64(i))
+	if err != nil {
+		f.setExit(err)
+		return f.value(f.nilSym)
+	}
+	return f.value(vec.elems[i])
+}
+
+//export emacstest_vec_set
+func emacstest_vec_set(handle C.uintptr_t, v C.emacs_value, i C.ptrdiff_t, elem C.emacs_value) {
+	f := fake(handle)
+	vec, err := f.vectorIndex(f.object(v), int64(i))
+	if err != nil {
+		f.setExit(err)
+		return
+	}
+	vec.elems[i] = f.object(elem)
+}
+
+//export emacstest_vec_size
+func emacstest_vec_size(handle C.uintptr_t, v C.emacs_value) C.ptrdiff_t {
+	f := fake(handle)
+	o := f.object(v)
+	vec, ok := o.(*vector)
+	if !ok {
+		f.setExit(f.wrongType("vectorp", o))
+		return 0
+	}
+	return C.ptrdiff_t(len(vec.elems))
+}
+
+//export emacstest_extract_time
+func emacstest_extract_time(handle C.uintptr_t, v C.emacs_value) C.struct_timespec {
+	f := fake(handle)
+	sec, nsec, err := f.time(f.object(v))
+	if err != nil {
+		f.setExit(err)
+		return C.struct_timespec{}
+	}
+	return C.struct_timespec{C.time_t(sec), C.long(nsec)}
+}
+
+//export emacstest_make_time
+func emacstest_make_time(handle C.uintptr_t, t C.struct_timespec) C.emacs_value {
+	f := fake(handle)
+	ticks := new(big.Int).Mul(big.NewInt(int64(t.tv_sec)), big.NewInt(1e9))
+	ticks.Add(ticks, big.NewInt(int64(t.tv_nsec)))
+	return f.value(&cons{normalize(ticks), int64(1e9)})
+}
+
+//export emacstest_extract_big_integer
+func emacstest_extract_big_integer(handle C.uintptr_t, v C.emacs_value, sign *C.int, count *C.ptrdiff_t, magnitude *C.emacs_limb_t) C.bool {
+	f := fake(handle)
+	o := f.object(v)
+	b, ok := toBig(o)
+	if !ok {
+		f.setExit(f.wrongType("integerp", o))
+		return false
+	}
+	if sign != nil {
+		*sign = C.int(b.Sign())
+	}
+	// Split the magnitude into limbs, least significant limb first.
+	const limbBits = uint(unsafe.Sizeof(C.emacs_limb_t(0))) * 8
+	var limbs []C.emacs_limb_t
+	var acc big.Int
+	acc.Abs(b)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), limbBits), big.NewInt(1))
+	for acc.Sign() > 0 {
+		var limb big.Int
+		limb.And(&acc, mask)
+		limbs = append(limbs, C.emacs_limb_t(limb.Uint64()))
+		acc.Rsh(&acc, limbBits)
+	}
+	if count == nil {
+		return true
+	}
+	if magnitude == nil {
+		*count = C.ptrdiff_t(len(limbs))
+		return true
+	}
+	if int(*count) < len(limbs) {
+		*count = C.ptrdiff_t(len(limbs))
+		f.setExit(f.signal("args-out-of-range"))
+		return false
+	}
+	copy(unsafe.Slice(magnitude, len(limbs)), limbs)
+	*count = C.ptrdiff_t(len(limbs))
+	return true
+}
+
+//export emacstest_make_big_integer
+func emacstest_make_big_integer(handle C.uintptr_t, sign C.int, count C.ptrdiff_t, magnitude *C.emacs_limb_t) C.emacs_value {
+	f := fake(handle)
+	const limbBits = uint(unsafe.Sizeof(C.emacs_limb_t(0))) * 8
+	r := new(big.Int)
+	if count > 0 {
+		limbs := unsafe.Slice(magnitude, int(count))
+		for i := len(limbs) - 1; i >= 0; i-- {
+			r.Lsh(r, limbBits)
+			r.Or(r, new(big.Int).SetUint64(uint64(limbs[i])))
+		}
+	}
+	if sign < 0 {
+		r.Neg(r)
+	}
+	return f.value(normalize(r))
+}
+
+//export emacstest_open_channel
+func emacstest_open_channel(handle C.uintptr_t, process C.emacs_value) C.int {
+	f := fake(handle)
+	f.setExit(f.signal("error", &lstring{"Processes aren’t supported in the fake environment", true}))
+	return -1
+}
+
+//export emacstest_make_interactive
+func emacstest_make_interactive(handle C.uintptr_t, fun, spec C.emacs_value) {
+	f := fake(handle)
+	o := f.object(fun)
+	m, ok := o.(*moduleFunction)
+	if !ok {
+		f.setExit(f.wrongType("module-function-p", o))
+		return
+	}
+	m.interactive = f.object(spec)
+}
+
+func (f *Fake) vectorIndex(o object, i int64) (*vector, error) {
+	vec, ok := o.(*vector)
+	if !ok {
+		return nil, f.wrongType("vectorp", o)
+	}
+	if i < 0 || i >= int64(len(vec.elems)) {
+		return nil, f.signal("args-out-of-range", vec, i)
+	}
+	return vec, nil
+}
+
+// time converts a Lisp timestamp to seconds and nanoseconds.  It supports
+// integers, floats, (TICKS . HZ) pairs, and (HIGH LOW USEC PSEC) lists.
+func (f *Fake) time(o object) (sec, nsec int64, err error) {
+	var ticks, hz *big.Int
+	switch o := o.(type) {
+	case int64, *big.Int:
+		ticks, _ = toBig(o)
+		hz = big.NewInt(1)
+	case float64:
+		if math.IsNaN(o) || math.IsInf(o, 0) {
+			return 0, 0, f.signal("overflow-error")
+		}
+		s := math.Floor(o)
+		return int64(s), int64((o - s) * 1e9), nil
+	case *cons:
+		if t, ok := toBig(o.car); ok {
+			if h, ok := toBig(o.cdr); ok && h.Sign() > 0 {
+				ticks, hz = t, h
+				break
+			}
+		}
+		elems, err := f.slice(o)
+		if err != nil || len(elems) < 2 || len(elems) > 4 {
+			return 0, 0, f.signal("error", &lstring{"Invalid time specification", true})
+		}
+		parts := [4]int64{}
+		for i, e := range elems {
+			n, ok := e.(int64)
+			if !ok {
+				return 0, 0, f.signal("error", &lstring{"Invalid time specification", true})
+			}
+			parts[i] = n
+		}
+		ticks = big.NewInt(parts[0]<<16 + parts[1])
+		ticks.Mul(ticks, big.NewInt(1e12))
+		ticks.Add(ticks, big.NewInt(parts[2]*1e6+parts[3]))
+		hz = big.NewInt(1e12)
+	default:
+		return 0, 0, f.signal("error", &lstring{"Invalid time specification", true})
+	}
+	var s, rem big.Int
+	s.DivMod(ticks, hz, &rem)
+	if !s.IsInt64() {
+		return 0, 0, f.signal("overflow-error")
+	}
+	rem.Mul(&rem, big.NewInt(1e9))
+	rem.Quo(&rem, hz)
+	return s.Int64(), rem.Int64(), nil
+}
+
+func eq(a, b object) bool {
+	if x, ok := a.(int64); ok {
+		y, ok := b.(int64)
+		return ok && x == y
+	}
+	return a == b
+}
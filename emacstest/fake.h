@@ -0,0 +1,34 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+#ifndef PHST_EMACS_GO_EMACSTEST_FAKE_H
+#define PHST_EMACS_GO_EMACSTEST_FAKE_H
+
+#include <stdint.h>
+
+#include "emacs-module.h"
+
+// Returns a new fake environment whose functions forward to the Go fake
+// identified by handle.  Free it using emacstest_free_env.
+emacs_env *emacstest_new_env(uintptr_t handle);
+
+void emacstest_free_env(emacs_env *env);
+
+// Calls a module function.  data is the data pointer passed to
+// make_function, converted to an integer so that Go code can store it.
+emacs_value emacstest_call_function(emacs_function function, emacs_env *env,
+                                    ptrdiff_t nargs, emacs_value *args,
+                                    uintptr_t data);
+
+#endif
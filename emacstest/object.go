@@ -0,0 +1,333 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacstest
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// object is a simulated Lisp object.  The dynamic type is one of *symbol,
+// *cons, *lstring, *vector, *hashTable, *builtin, *moduleFunction, int64
+// (fixnums), *big.Int (bignums outside the int64 range), or float64.
+type object interface{}
+
+type symbol struct {
+	name     string
+	value    object // nil if unbound
+	function object // nil if unbound
+	plist    object // nil before first use
+	constant bool
+}
+
+type cons struct{ car, cdr object }
+
+// lstring is a Lisp string.  s contains UTF-8 text for multibyte strings and
+// raw bytes for unibyte strings.
+type lstring struct {
+	s         string
+	multibyte bool
+}
+
+type vector struct{ elems []object }
+
+// hashTable is a simple hash table that uses linear search.  That’s fast
+// enough for unit tests.
+type hashTable struct {
+	test   *symbol
+	keys   []object
+	values []object
+}
+
+// builtin is a primitive function implemented in Go.
+type builtin struct {
+	name     string
+	min, max int // max is −1 for &rest functions
+	fun      func(f *Fake, args []object) (object, error)
+}
+
+// signal is a pending signal.  It implements the error interface so that
+// builtins can return it.
+type signal struct {
+	sym  *symbol
+	data object
+}
+
+func (s *signal) Error() string { return "signal " + s.sym.name }
+
+// throw is a pending throw.
+type throw struct{ tag, value object }
+
+func (*throw) Error() string { return "throw" }
+
+func (f *Fake) signal(name string, data ...object) error {
+	return &signal{f.intern(name), f.list(data...)}
+}
+
+func (f *Fake) wrongType(pred string, v object) error {
+	return f.signal("wrong-type-argument", f.intern(pred), v)
+}
+
+func (f *Fake) list(elems ...object) object {
+	var r object = f.nilSym
+	for i := len(elems) - 1; i >= 0; i-- {
+		r = &cons{elems[i], r}
+	}
+	return r
+}
+
+func (f *Fake) bool(b bool) object {
+	if b {
+		return f.tSym
+	}
+	return f.nilSym
+}
+
+// slice converts the proper list l to a slice.
+func (f *Fake) slice(l object) ([]object, error) {
+	var r []object
+	for o := l; o != f.nilSym; {
+		c, ok := o.(*cons)
+		if !ok {
+			return nil, f.wrongType("listp", l)
+		}
+		r = append(r, c.car)
+		o = c.cdr
+	}
+	return r, nil
+}
+
+// sequence returns the elements of the sequence s.
+func (f *Fake) sequence(s object) ([]object, error) {
+	switch s := s.(type) {
+	case *vector:
+		return s.elems, nil
+	case *lstring:
+		var r []object
+		if s.multibyte {
+			for _, c := range s.s {
+				r = append(r, int64(c))
+			}
+		} else {
+			for i := 0; i < len(s.s); i++ {
+				r = append(r, int64(s.s[i]))
+			}
+		}
+		return r, nil
+	case *symbol, *cons:
+		if s == f.nilSym {
+			return nil, nil
+		}
+		if _, ok := s.(*cons); !ok {
+			return nil, f.wrongType("sequencep", s)
+		}
+		return f.slice(s)
+	default:
+		return nil, f.wrongType("sequencep", s)
+	}
+}
+
+// normalize returns a fixnum if b fits into an int64.
+func normalize(b *big.Int) object {
+	if b.IsInt64() {
+		return b.Int64()
+	}
+	return b
+}
+
+func toBig(o object) (*big.Int, bool) {
+	switch o := o.(type) {
+	case int64:
+		return big.NewInt(o), true
+	case *big.Int:
+		return o, true
+	default:
+		return nil, false
+	}
+}
+
+func eql(a, b object) bool {
+	if x, ok := toBig(a); ok {
+		y, ok := toBig(b)
+		return ok && x.Cmp(y) == 0
+	}
+	if x, ok := a.(float64); ok {
+		y, ok := b.(float64)
+		return ok && math.Float64bits(x) == math.Float64bits(y)
+	}
+	return a == b
+}
+
+func equal(a, b object) bool {
+	switch a := a.(type) {
+	case *cons:
+		c, ok := b.(*cons)
+		return ok && equal(a.car, c.car) && equal(a.cdr, c.cdr)
+	case *lstring:
+		s, ok := b.(*lstring)
+		return ok && a.s == s.s
+	case *vector:
+		v, ok := b.(*vector)
+		if !ok || len(a.elems) != len(v.elems) {
+			return false
+		}
+		for i, x := range a.elems {
+			if !equal(x, v.elems[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return eql(a, b)
+	}
+}
+
+// typeOf returns the name of the type of o, as returned by type-of.
+func typeOf(o object) string {
+	switch o := o.(type) {
+	case *symbol:
+		if o.name == "nil" || o.name == "t" {
+			return "boolean"
+		}
+		return "symbol"
+	case *cons:
+		return "cons"
+	case *lstring:
+		return "string"
+	case *vector:
+		return "vector"
+	case *hashTable:
+		return "hash-table"
+	case *builtin:
+		return "subr"
+	case *moduleFunction:
+		return "module-function"
+	case int64, *big.Int:
+		return "integer"
+	case float64:
+		return "float"
+	default:
+		panic(fmt.Errorf("unknown object type %T", o))
+	}
+}
+
+// print returns the printed representation of o.  If escape is true, it
+// behaves like prin1, otherwise like princ.
+func (f *Fake) print(o object, escape bool) string {
+	var b strings.Builder
+	f.printTo(&b, o, escape)
+	return b.String()
+}
+
+func (f *Fake) printTo(b *strings.Builder, o object, escape bool) {
+	switch o := o.(type) {
+	case *symbol:
+		b.WriteString(o.name)
+	case *cons:
+		if o.car == f.intern("quote") {
+			if c, ok := o.cdr.(*cons); ok && c.cdr == f.nilSym {
+				b.WriteByte('\'')
+				f.printTo(b, c.car, escape)
+				return
+			}
+		}
+		b.WriteByte('(')
+		var tail object = o
+		for first := true; ; first = false {
+			c, ok := tail.(*cons)
+			if !ok {
+				break
+			}
+			if !first {
+				b.WriteByte(' ')
+			}
+			f.printTo(b, c.car, escape)
+			tail = c.cdr
+		}
+		if tail != f.nilSym {
+			b.WriteString(" . ")
+			f.printTo(b, tail, escape)
+		}
+		b.WriteByte(')')
+	case *lstring:
+		if !escape {
+			b.WriteString(o.s)
+			return
+		}
+		b.WriteByte('"')
+		for _, c := range o.s {
+			if c == '"' || c == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(c)
+		}
+		b.WriteByte('"')
+	case *vector:
+		b.WriteByte('[')
+		for i, e := range o.elems {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			f.printTo(b, e, escape)
+		}
+		b.WriteByte(']')
+	case *hashTable:
+		fmt.Fprintf(b, "#<hash-table %s %d/%d>", o.test.name, len(o.keys), len(o.keys))
+	case *builtin:
+		fmt.Fprintf(b, "#<subr %s>", o.name)
+	case *moduleFunction:
+		b.WriteString("#<module function>")
+	case int64:
+		b.WriteString(strconv.FormatInt(o, 10))
+	case *big.Int:
+		b.WriteString(o.String())
+	case float64:
+		b.WriteString(formatFloat(o))
+	default:
+		panic(fmt.Errorf("unknown object type %T", o))
+	}
+}
+
+// formatFloat formats x like Emacs does.
+func formatFloat(x float64) string {
+	switch {
+	case math.IsNaN(x):
+		if math.Signbit(x) {
+			return "-0.0e+NaN"
+		}
+		return "0.0e+NaN"
+	case math.IsInf(x, 1):
+		return "1.0e+INF"
+	case math.IsInf(x, -1):
+		return "-1.0e+INF"
+	}
+	s := strconv.FormatFloat(x, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// length returns the number of characters in s.
+func (s *lstring) length() int {
+	if s.multibyte {
+		return utf8.RuneCountInString(s.s)
+	}
+	return len(s.s)
+}
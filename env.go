@@ -21,6 +21,11 @@ package emacs
 // }
 import "C"
 
+import (
+	"sync"
+	"sync/atomic"
+)
+
 // Env represents an Emacs module environment.  The zero Env is not valid.
 // Exported functions and module initializers will receive a valid Env value.
 // That Env value only remains valid (or “live”) while the exported function or
@@ -29,12 +34,15 @@ import "C"
 // them or pass them to other goroutines.  See
 // https://www.gnu.org/software/emacs/manual/html_node/elisp/Module-Functions.html
 // for details.
-type Env struct{ ptr *C.emacs_env }
+type Env struct {
+	ptr *C.emacs_env
+	gen generation
+}
 
 // Eq returns true if and only if the two values represent the same Emacs
 // object.
 func (e Env) Eq(a, b Value) bool {
-	return a == b || bool(C.phst_emacs_eq(e.raw(), a.r, b.r))
+	return a == b || bool(C.phst_emacs_eq(e.raw(), a.raw(), b.raw()))
 }
 
 // Eval evaluates form using the Emacs function eval.  The binding is always
@@ -47,5 +55,94 @@ func (e Env) raw() *C.emacs_env {
 	if e.ptr == nil {
 		panic("nil environment")
 	}
+	if DebugMode() && !liveEnvs.live(e.ptr) {
+		panic("environment used after the exported function or module initializer that received it returned")
+	}
+	e.gen.check()
+	if atomic.LoadInt32(&countCrossings) != 0 {
+		atomic.AddUint64(&crossings, 1)
+	}
 	return e.ptr
 }
+
+// enter marks env as live and returns a corresponding [Env].  Pass the
+// returned Boolean value to [Env.exit] once env is no longer live.
+func enter(env *C.emacs_env) (Env, bool) {
+	tracked := liveEnvs.enter(env)
+	return Env{env, newGeneration()}, tracked
+}
+
+// exit undoes the effect of the call to enter that returned e.
+func (e Env) exit(tracked bool) {
+	e.gen.end()
+	liveEnvs.exit(e.ptr, tracked)
+}
+
+// value returns a [Value] for v that was created in e.
+func (e Env) value(v C.emacs_value) Value {
+	return Value{v, e.gen}
+}
+
+// crossings counts the calls from Go into Emacs, for benchmarks; see
+// [BenchmarkResult].  Each such call passes through [Env.raw].  Accessed
+// atomically.
+var crossings uint64
+
+// countCrossings is the number of benchmark runs in progress.  [Env.raw]
+// only increments crossings if it’s nonzero, so that calls outside of
+// benchmarks don’t pay for the counting.  Accessed atomically.
+var countCrossings int32
+
+// liveEnvs tracks the environments that are currently live, so that debug
+// mode can detect environments that are used after they’ve become invalid.
+var liveEnvs envRegistry
+
+type envRegistry struct {
+	// untracked is the number of live environments that aren’t in envs
+	// because debug mode was disabled when they became live.  While it’s
+	// nonzero, we can’t detect misuse.  Accessed atomically.
+	untracked int32
+
+	mu   sync.Mutex
+	envs map[*C.emacs_env]int
+}
+
+// enter marks env as live.  It returns whether env is tracked; pass the
+// result to exit.  enter is cheap if debug mode is disabled.
+func (r *envRegistry) enter(env *C.emacs_env) bool {
+	if !DebugMode() {
+		atomic.AddInt32(&r.untracked, 1)
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.envs == nil {
+		r.envs = make(map[*C.emacs_env]int)
+	}
+	// Emacs can reuse the same environment for nested calls.
+	r.envs[env]++
+	return true
+}
+
+// exit undoes the effect of the corresponding call to enter.
+func (r *envRegistry) exit(env *C.emacs_env, tracked bool) {
+	if !tracked {
+		atomic.AddInt32(&r.untracked, -1)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.envs[env]--; r.envs[env] <= 0 {
+		delete(r.envs, env)
+	}
+}
+
+// live returns whether env might be live.
+func (r *envRegistry) live(env *C.emacs_env) bool {
+	if atomic.LoadInt32(&r.untracked) != 0 {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.envs[env] > 0
+}
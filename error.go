@@ -20,6 +20,7 @@ import "C"
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -111,6 +112,9 @@ func (s ErrorSymbol) match(e Env, err error) bool {
 		return err == nil && e.Eq(x.Symbol, want)
 	case Error:
 		return s == x.Symbol
+	case interface{ Unwrap() error }:
+		// For example, ConversionError or LoadError.
+		return s.match(e, x.Unwrap())
 	default:
 		return false
 	}
@@ -188,6 +192,83 @@ func (e Env) IsOverflowError(err error) bool {
 
 var overflowError = ErrorSymbol{"overflow-error", "Arithmetic overflow error"}
 
+var wrongNumberOfArguments = ErrorSymbol{"wrong-number-of-arguments", "Wrong number of arguments"}
+
+var argsOutOfRange = ErrorSymbol{"args-out-of-range", "Args out of range"}
+
+// ConversionError is an error that occurred while converting a value between
+// Go and Emacs.  In debug mode, conversion functions wrap errors in
+// ConversionError values so that the error message says where within a nested
+// value the conversion failed.  See [SetDebugMode].  When signaled to Emacs, a
+// ConversionError keeps the error symbol of the underlying error and prepends
+// the location to the error data.
+type ConversionError struct {
+	// Path describes the location of the failing conversion, outermost
+	// step first, for example “argument 2”, “map value”, “vector element
+	// 3”.
+	Path []string
+
+	// Type is the Go type whose conversion failed.
+	Type reflect.Type
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (c *ConversionError) Error() string {
+	return fmt.Sprintf("%s: %s", c.location(), c.Err)
+}
+
+// Unwrap returns c.Err.
+func (c *ConversionError) Unwrap() error {
+	return c.Err
+}
+
+func (c *ConversionError) location() string {
+	s := strings.Join(c.Path, " → ")
+	if c.Type != nil {
+		s += " of type " + c.Type.String()
+	}
+	return s
+}
+
+func (c *ConversionError) signal(e Env) C.struct_result_base_with_optional_error_info {
+	loc := String(c.location())
+	switch x := c.Err.(type) {
+	case Error:
+		return Error{x.Symbol, append(List{loc}, x.Data...)}.signal(e)
+	case Signal:
+		data, err := e.Call("cons", loc, x.Data)
+		if err != nil {
+			return x.signal(e)
+		}
+		return Signal{x.Symbol, data}.signal(e)
+	case nonlocalExit:
+		return x.signal(e)
+	default:
+		return Error{baseError, List{loc, String(c.Err.Error())}}.signal(e)
+	}
+}
+
+// conversionContext adds step to the conversion path of err if err is non-nil
+// and debug mode is enabled.  t is the Go type being converted; it’s only
+// used if err isn’t already a [ConversionError].  conversionContext never
+// wraps a [Throw].
+func conversionContext(err error, step string, t reflect.Type) error {
+	if err == nil || !DebugMode() {
+		return err
+	}
+	switch x := err.(type) {
+	case Throw:
+		return err
+	case *ConversionError:
+		return &ConversionError{append([]string{step}, x.Path...), x.Type, x.Err}
+	default:
+		return &ConversionError{[]string{step}, t, err}
+	}
+}
+
 type nonlocalExit interface {
 	// signal returns a C representation of this nonlocal exit.
 	signal(Env) C.struct_result_base_with_optional_error_info
@@ -211,11 +292,11 @@ func (x Error) signal(e Env) C.struct_result_base_with_optional_error_info {
 }
 
 func (s Signal) signal(e Env) C.struct_result_base_with_optional_error_info {
-	return C.struct_result_base_with_optional_error_info{C.emacs_funcall_exit_signal, true, s.Symbol.r, s.Data.r}
+	return C.struct_result_base_with_optional_error_info{C.emacs_funcall_exit_signal, true, s.Symbol.raw(), s.Data.raw()}
 }
 
 func (t Throw) signal(e Env) C.struct_result_base_with_optional_error_info {
-	return C.struct_result_base_with_optional_error_info{C.emacs_funcall_exit_throw, true, t.Tag.r, t.Value.r}
+	return C.struct_result_base_with_optional_error_info{C.emacs_funcall_exit_throw, true, t.Tag.raw(), t.Value.raw()}
 }
 
 // signal returns a C representation of err.
@@ -238,9 +319,9 @@ func (e Env) check(r C.struct_phst_emacs_result_base) error {
 	case C.emacs_funcall_exit_return:
 		return nil
 	case C.emacs_funcall_exit_signal:
-		return Signal{Value{r.error_symbol}, Value{r.error_data}}
+		return Signal{e.value(r.error_symbol), e.value(r.error_data)}
 	case C.emacs_funcall_exit_throw:
-		return Throw{Value{r.error_symbol}, Value{r.error_data}}
+		return Throw{e.value(r.error_symbol), e.value(r.error_data)}
 	default:
 		// This cannot really happen, but better safe than sorry.
 		return WrongTypeArgument("module-funcall-exit-p", Int(r.exit))
@@ -255,7 +336,7 @@ func (e Env) checkVoid(r C.struct_phst_emacs_void_result) error {
 // checkValue is like check, but takes a struct value_result and returns v for
 // convenience.
 func (e Env) checkValue(r C.struct_phst_emacs_value_result) (Value, error) {
-	return Value{r.value}, e.check(r.base)
+	return e.value(r.value), e.check(r.base)
 }
 
 var (
@@ -278,4 +359,15 @@ func (s errorSymbol) Define(e Env) error {
 	return err
 }
 
-var errorSymbols = NewManager(RequireName | RequireUniqueName | DefineOnInit)
+// Dependencies returns the names of the parent error symbols.  Emacs needs the
+// parents to be defined before the child so that the child inherits the
+// parents’ error conditions.
+func (s errorSymbol) Dependencies() []Name {
+	r := make([]Name, len(s.parents))
+	for i, p := range s.parents {
+		r[i] = p.name
+	}
+	return r
+}
+
+var errorSymbols = NewManagerOf[errorSymbol](RequireName | RequireUniqueName | DefineOnInit)
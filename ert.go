@@ -1,4 +1,4 @@
-// Copyright 2019, 2023 Google LLC
+// Copyright 2019, 2023, 2026 Google LLC
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -14,6 +14,8 @@
 
 package emacs
 
+import "fmt"
+
 // ERTTestFunc is a function that implements an ERT test.  Use [ERTTest] to
 // register ERTTestFunc functions.  If the function returns an error, the ERT
 // test fails.
@@ -33,10 +35,17 @@ type ERTTestFunc func(Env) error
 // By default, the ERT test has no documentation string.  To add one, pass a
 // [Doc] option.
 //
+// If you pass a [Requires] option and some of the capabilities aren’t
+// available, the ERT test will be skipped.
+//
+// To tag the test or declare it as a known failure, pass [ERTTags] or
+// [ERTExpectedResult] options.  To run setup and teardown code around the
+// test, pass [ERTFixture] options.
+//
 // You can call ERTTest safely from multiple goroutines.
 func ERTTest(fun ERTTestFunc, opts ...Option) {
-	name, fn, _, doc := AutoFunc(fun, opts...)
-	ertTests.MustEnqueue(name, ertTest{name, fn, doc})
+	d, _ := autoFunc(fun, opts)
+	ertTests.MustEnqueue(d.name, ertTest{d.name, d.call, d.doc, d.requires, d.ertTags, d.ertExpected, d.ertFixtures})
 }
 
 // ERTTest exports a Go function as an ERT test.  Unlike the global [ERTTest]
@@ -50,16 +59,89 @@ func ERTTest(fun ERTTestFunc, opts ...Option) {
 //
 // By default, the ERT test has no documentation string.  To add one, pass a
 // [Doc] option.
+//
+// If you pass a [Requires] option and some of the capabilities aren’t
+// available, the ERT test will be skipped.
+//
+// To tag the test or declare it as a known failure, pass [ERTTags] or
+// [ERTExpectedResult] options.  To run setup and teardown code around the
+// test, pass [ERTFixture] options.
 func (e Env) ERTTest(fun ERTTestFunc, opts ...Option) error {
-	name, fn, _, doc := AutoFunc(fun, opts...)
-	t := ertTest{name, fn, doc}
-	return ertTests.RegisterAndDefine(e, name, t)
+	d, _ := autoFunc(fun, opts)
+	t := ertTest{d.name, d.call, d.doc, d.requires, d.ertTags, d.ertExpected, d.ertFixtures}
+	return ertTests.RegisterAndDefine(e, d.name, t)
+}
+
+// ERTTags is an [Option] for [ERTTest] and [Env.ERTDeftest] that sets the
+// tags of an ERT test, like the :tags keyword of ert-deftest.  You can select
+// tests by tag, for example using ert-run-tests-batch with a selector of the
+// form (tag TAG).  Passing ERTTags more than once accumulates the tags.
+type ERTTags []Symbol
+
+func (t ERTTags) apply(o *exportAuto) { o.ertTags = append(o.ertTags, t...) }
+
+// ERTExpectedResult is an [Option] for [ERTTest] and [Env.ERTDeftest] that
+// sets the expected result of an ERT test, like the :expected-result keyword
+// of ert-deftest.  Typical values are :passed, the default, and :failed for
+// known failures.
+type ERTExpectedResult Symbol
+
+func (r ERTExpectedResult) apply(o *exportAuto) { o.ertExpected = r }
+
+// ERTFixture is an [Option] for [ERTTest] and [Env.ERTDeftest] that runs
+// Setup before the test and Teardown after it.  Use fixtures to create and
+// clean up resources that several tests share, such as temporary buffers,
+// files, or handle registries.  Setup and Teardown may be nil.
+//
+// If Setup returns an error, the test fails without running the test function
+// or Teardown.  Otherwise, Teardown runs even if the test function returns an
+// error or panics.  If the test function succeeds and Teardown returns an
+// error, the test fails.  With multiple fixtures, the Setup functions run in
+// order and the Teardown functions in reverse order, and Teardown runs only
+// for fixtures whose Setup has succeeded.  Setup, the test function, and
+// Teardown run in the same environment, so they can share [Value] objects,
+// for example using variables captured by closures.
+type ERTFixture struct {
+	Setup    func(Env) error
+	Teardown func(Env) error
+}
+
+func (f ERTFixture) apply(o *exportAuto) { o.ertFixtures = append(o.ertFixtures, f) }
+
+// wrap returns a function that runs fun between f.Setup and f.Teardown.
+func (f ERTFixture) wrap(fun Func) Func {
+	return func(e Env, args []Value) (r Value, err error) {
+		if f.Setup != nil {
+			if err := f.Setup(e); err != nil {
+				return Value{}, err
+			}
+		}
+		if f.Teardown != nil {
+			defer func() {
+				if tErr := f.Teardown(e); err == nil {
+					err = tErr
+				}
+			}()
+		}
+		return fun(e, args)
+	}
 }
 
 // ERTDeftest defines an ERT test with the given name and documentation string.
 // The test calls the Go function fun.  It succeeds if fun returns nil.  This
-// is the Go equivalent of the ert-deftest macro.
-func (e Env) ERTDeftest(name Name, fun Func, doc Doc) error {
+// is the Go equivalent of the ert-deftest macro.  ERTDeftest uses [ERTTags],
+// [ERTExpectedResult], and [ERTFixture] options and ignores all other
+// options.
+func (e Env) ERTDeftest(name Name, fun Func, doc Doc, opts ...Option) error {
+	var o exportAuto
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	// Wrap in reverse order so that the first fixture is the outermost
+	// one.
+	for i := len(o.ertFixtures) - 1; i >= 0; i-- {
+		fun = o.ertFixtures[i].wrap(fun)
+	}
 	// Make sure the ERT library is available.
 	if _, err := e.Call("require", Symbol("ert")); err != nil {
 		return err
@@ -76,6 +158,16 @@ func (e Env) ERTDeftest(name Name, fun Func, doc Doc) error {
 	if doc != "" {
 		args = append(args, Symbol(":documentation"), doc)
 	}
+	if len(o.ertTags) > 0 {
+		tags := make(List, len(o.ertTags))
+		for i, t := range o.ertTags {
+			tags[i] = t
+		}
+		args = append(args, Symbol(":tags"), tags)
+	}
+	if o.ertExpected != "" {
+		args = append(args, Symbol(":expected-result-type"), Symbol(o.ertExpected))
+	}
 	t, err := e.Call("make-ert-test", args...)
 	if err != nil {
 		return err
@@ -86,13 +178,34 @@ func (e Env) ERTDeftest(name Name, fun Func, doc Doc) error {
 }
 
 type ertTest struct {
-	name Name
-	fun  Func
-	doc  Doc
+	name     Name
+	fun      Func
+	doc      Doc
+	requires Requires
+	tags     ERTTags
+	expected ERTExpectedResult
+	fixtures []ERTFixture
 }
 
 func (t ertTest) Define(e Env) error {
-	return e.ERTDeftest(t.name, t.fun, t.doc)
+	c, err := t.requires.missing(e)
+	if err != nil {
+		return err
+	}
+	fun := t.fun
+	opts := []Option{t.tags, t.expected}
+	if c != nil {
+		reason := String(fmt.Sprintf("test requires %s", c))
+		fun = func(e Env, _ []Value) (Value, error) {
+			return e.Call("ert-skip", reason)
+		}
+	} else {
+		for _, f := range t.fixtures {
+			opts = append(opts, f)
+		}
+	}
+	return e.ERTDeftest(t.name, fun, t.doc, opts...)
 }
 
-var ertTests = NewManager(RequireName | RequireUniqueName | DefineOnInit)
+// A broken test shouldn’t prevent the other tests from being defined.
+var ertTests = NewManagerOf[ertTest](RequireName | RequireUniqueName | DefineOnInit | ContinueOnError)
@@ -14,7 +14,13 @@
 
 package emacs
 
-import "log"
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"testing"
+)
 
 func ExampleERTTest() {
 	ERTTest(exampleERTTest, Name("example-ert-test"), Doc("Run an example ERT test."))
@@ -30,4 +36,130 @@ func exampleERTTest(e Env) error {
 func init() {
 	// We would normally call ExampleERTTest here, but the test runner
 	// already calls it for us.
+	ERTTest(ertKnownFailure, ERTTags{"go-known-failure"}, ERTExpectedResult(":failed"))
+	ERTTest(ertTagsAndExpectedResult)
+	ERTTest(ertFixtureBuffer, ERTFixture{
+		Setup: func(e Env) error {
+			var err error
+			fixtureBuffer, err = e.Call("generate-new-buffer", String("*go-fixture*"))
+			return err
+		},
+		Teardown: func(e Env) error {
+			_, err := e.Call("kill-buffer", fixtureBuffer)
+			fixtureBuffer = Value{}
+			return err
+		},
+	})
+}
+
+// fixtureBuffer is only valid while ertFixtureBuffer runs.
+var fixtureBuffer Value
+
+func ertFixtureBuffer(e Env) error {
+	var live Bool
+	if err := e.CallOut("buffer-live-p", &live, fixtureBuffer); err != nil {
+		return err
+	}
+	if !live {
+		return errors.New("fixture buffer isn’t live")
+	}
+	return nil
+}
+
+func TestERTFixture(t *testing.T) {
+	var calls []string
+	fixture := func(name string, setupErr, teardownErr error) ERTFixture {
+		return ERTFixture{
+			Setup: func(Env) error {
+				calls = append(calls, "setup "+name)
+				return setupErr
+			},
+			Teardown: func(Env) error {
+				calls = append(calls, "teardown "+name)
+				return teardownErr
+			},
+		}
+	}
+	errBody := errors.New("body")
+	errSetup := errors.New("setup")
+	errTeardown := errors.New("teardown")
+	for _, tc := range []struct {
+		name      string
+		fixtures  []ERTFixture
+		bodyErr   error
+		wantErr   error
+		wantCalls []string
+	}{
+		{
+			name:      "success",
+			fixtures:  []ERTFixture{fixture("a", nil, nil), fixture("b", nil, nil)},
+			wantCalls: []string{"setup a", "setup b", "body", "teardown b", "teardown a"},
+		},
+		{
+			name:      "body fails",
+			fixtures:  []ERTFixture{fixture("a", nil, errTeardown)},
+			bodyErr:   errBody,
+			wantErr:   errBody,
+			wantCalls: []string{"setup a", "body", "teardown a"},
+		},
+		{
+			name:      "setup fails",
+			fixtures:  []ERTFixture{fixture("a", nil, nil), fixture("b", errSetup, nil)},
+			wantErr:   errSetup,
+			wantCalls: []string{"setup a", "setup b", "teardown a"},
+		},
+		{
+			name:      "teardown fails",
+			fixtures:  []ERTFixture{fixture("a", nil, errTeardown)},
+			wantErr:   errTeardown,
+			wantCalls: []string{"setup a", "body", "teardown a"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = nil
+			fun := func(Env, []Value) (Value, error) {
+				calls = append(calls, "body")
+				return Value{}, tc.bodyErr
+			}
+			for i := len(tc.fixtures) - 1; i >= 0; i-- {
+				fun = tc.fixtures[i].wrap(fun)
+			}
+			if _, err := fun(Env{}, nil); err != tc.wantErr {
+				t.Errorf("got error %v, want %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(calls, tc.wantCalls) {
+				t.Errorf("got calls %q, want %q", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func ertKnownFailure(Env) error {
+	return errors.New("this test is expected to fail")
+}
+
+func ertTagsAndExpectedResult(e Env) error {
+	test, err := e.Call("ert-get-test", Symbol("ert-known-failure"))
+	if err != nil {
+		return err
+	}
+	var tags String
+	tagsVal, err := e.Call("ert-test-tags", test)
+	if err != nil {
+		return err
+	}
+	if err := e.CallOut("prin1-to-string", &tags, tagsVal); err != nil {
+		return err
+	}
+	if tags != "(go-known-failure)" {
+		return fmt.Errorf("tags: got %s, want (go-known-failure)", tags)
+	}
+	var expected Symbol
+	if err := e.CallOut("ert-test-expected-result-type", &expected, test); err != nil {
+		return err
+	}
+	if expected != ":failed" {
+		return fmt.Errorf("expected result: got %s, want :failed", expected)
+	}
+	return nil
 }
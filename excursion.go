@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// WithTempBuffer creates a temporary buffer, makes it current, and calls body
+// with it, like with-temp-buffer.  Once body returns, WithTempBuffer kills
+// the buffer and makes the previous buffer current again.  The Lisp side
+// uses unwind-protect, so this happens even if body returns an error, panics,
+// or the user quits.  WithTempBuffer returns the error returned by body, if
+// any, unchanged.  body must not keep the buffer after it returns.
+func (e Env) WithTempBuffer(body func(Buffer) error) error {
+	return e.wrapBody(Symbol("with-temp-buffer"), func() error {
+		b, err := e.CurrentBuffer()
+		if err != nil {
+			return err
+		}
+		return body(b)
+	})
+}
+
+// SaveExcursion calls body and then restores the current buffer and the
+// position of point in it, like save-excursion.  The Lisp side uses
+// unwind-protect, so this happens even if body returns an error, panics, or
+// the user quits.  SaveExcursion returns the error returned by body, if any,
+// unchanged.
+func (e Env) SaveExcursion(body func() error) error {
+	return e.wrapBody(Symbol("save-excursion"), body)
+}
+
+// wrapBody evaluates (macro (funcall fun)), where fun calls body.  If body
+// returns an error, wrapBody returns it unchanged instead of the signal that
+// Emacs sees.
+func (e Env) wrapBody(macro Symbol, body func() error) error {
+	var bodyErr error
+	fun, del, err := e.Lambda(func() error {
+		bodyErr = body()
+		return bodyErr
+	})
+	if err != nil {
+		return err
+	}
+	defer del()
+	_, err = e.Eval(List{macro, List{Symbol("funcall"), fun}})
+	if bodyErr != nil {
+		return bodyErr
+	}
+	return err
+}
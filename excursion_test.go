@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+func init() {
+	ERTTest(excursionTempBuffer)
+	ERTTest(excursionSave)
+}
+
+func excursionTempBuffer(e Env) error {
+	old, err := e.CurrentBuffer()
+	if err != nil {
+		return err
+	}
+	var temp Buffer
+	if err := e.WithTempBuffer(func(b Buffer) error {
+		temp = b
+		cur, err := e.CurrentBuffer()
+		if err != nil {
+			return err
+		}
+		if !e.Eq(cur.Value(), b.Value()) {
+			return errors.New("temporary buffer isn’t current")
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if live, err := temp.Live(e); err != nil || live {
+		return fmt.Errorf("temporary buffer still live: %v, %v", live, err)
+	}
+	want := errors.New("failure")
+	if err := e.WithTempBuffer(func(b Buffer) error {
+		temp = b
+		return want
+	}); err != want {
+		return fmt.Errorf("WithTempBuffer: got error %v, want %v", err, want)
+	}
+	if live, err := temp.Live(e); err != nil || live {
+		return fmt.Errorf("temporary buffer still live after error: %v, %v", live, err)
+	}
+	cur, err := e.CurrentBuffer()
+	if err != nil {
+		return err
+	}
+	if !e.Eq(cur.Value(), old.Value()) {
+		return errors.New("WithTempBuffer didn’t restore current buffer")
+	}
+	return nil
+}
+
+func excursionSave(e Env) error {
+	return withTestBuffer(e, "hello world", func() error {
+		if err := e.GotoChar(3); err != nil {
+			return err
+		}
+		want := errors.New("failure")
+		err := e.SaveExcursion(func() error {
+			if err := e.GotoChar(8); err != nil {
+				return err
+			}
+			return want
+		})
+		if err != want {
+			return fmt.Errorf("SaveExcursion: got error %v, want %v", err, want)
+		}
+		if pos, err := e.Point(); err != nil || pos != 3 {
+			return fmt.Errorf("Point after SaveExcursion: got %d, %v, want 3, nil", pos, err)
+		}
+		var name string
+		err = e.SaveExcursion(func() error {
+			return e.WithTempBuffer(func(b Buffer) error {
+				var err error
+				name, err = b.Name(e)
+				return err
+			})
+		})
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			return errors.New("temporary buffer has no name")
+		}
+		return nil
+	})
+}
@@ -1,4 +1,4 @@
-// Copyright 2019, 2023 Google LLC
+// Copyright 2019, 2023, 2026 Google LLC
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,6 +15,7 @@
 package emacs
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -23,6 +24,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 )
 
@@ -37,9 +39,11 @@ import (
 //
 // The function may accept any number of arguments.  Optionally, the first
 // argument may be of type [Env].  In this case, Emacs passes a live
-// environment value that you can use to interact with Emacs.  All other
-// arguments are converted from Emacs as described in the package
-// documentation.  If not all arguments are convertible from Emacs values,
+// environment value that you can use to interact with Emacs.  Optionally, the
+// next argument may be of type [context.Context].  In this case, the function
+// receives the context returned by [Env.SpanContext].  All other arguments
+// are converted from Emacs as described in the package documentation.  If not
+// all arguments are convertible from Emacs values,
 // Export panics.
 //
 // The function must return either zero, one, or two results.  If the last or
@@ -58,9 +62,29 @@ import (
 // By default, the function has no documentation string.  To add one, pass a
 // [Doc] option.
 //
+// To define the function only if Emacs provides some capabilities, pass a
+// [Requires] option.
+//
+// To make the function an interactive command, pass an [Interactive] or
+// [InteractiveForm] option.
+//
+// If you have called [SetFunctionPrefix], Export applies the prefix to
+// derived names and checks explicit names against it.  To export a single
+// function outside of the namespace, pass an [Unprefixed] option.
+//
 // You can call Export safely from multiple goroutines.
 func Export(fun interface{}, opts ...Option) {
-	ExportFunc(AutoFunc(fun, opts...))
+	d, arity := autoFunc(fun, opts)
+	if d.name == "" {
+		panic("empty function name")
+	}
+	switch {
+	case d.flag&exportUnprefixed != 0:
+		funcs.base.Exempt(d.name)
+	case d.flag&exportDerivedName != 0:
+		d.name = funcs.base.Qualify(d.name)
+	}
+	funcs.mustEnqueue(&function{Lambda{d.call, arity, d.doc}, d.name, 0, d.requires, d.interactive, nil, 0})
 }
 
 // ExportFunc arranges for a Go function to be exported to Emacs.  Call
@@ -77,7 +101,35 @@ func ExportFunc(name Name, fun Func, arity Arity, doc Doc) {
 	if name == "" {
 		panic("empty function name")
 	}
-	funcs.mustEnqueue(&function{Lambda{fun, arity, doc}, name, 0})
+	funcs.mustEnqueue(&function{Lambda{fun, arity, doc}, name, 0, nil, nil, nil, 0})
+}
+
+// SetFunctionPrefix sets a namespace prefix for the functions exported using
+// [Export] and [ExportFunc].  Once set, Export prepends the prefix and a
+// hyphen to function names that it derives from Go names, so that MyFunc
+// becomes prefix-my-func.  Functions with explicit names must follow the
+// conventions described in [ManagerOf.SetPrefix]; otherwise, Export and
+// ExportFunc panic.  Pass an [Unprefixed] option to [Export] to exempt a
+// single function.  SetFunctionPrefix only affects functions exported after
+// calling it, so call it before any Export or ExportFunc call, for example in
+// a package-level variable initializer:
+//
+//	var _ = emacs.SetFunctionPrefix("my-package")
+//
+// SetFunctionPrefix returns prefix.
+func SetFunctionPrefix(prefix Name) Name {
+	funcs.base.SetPrefix(prefix)
+	return prefix
+}
+
+// RedefineFunctions defines all functions exported using [Export] and
+// [ExportFunc] again.  This restores their definitions in case Lisp code has
+// redefined or unbound them, for example by loading a file that defines
+// functions with the same names.  Functions whose [Requires] option wasn’t
+// satisfied during module initialization are defined if the requirements are
+// satisfied now.  See [ManagerOf.Redefine] for details.
+func RedefineFunctions(e Env) error {
+	return funcs.base.Redefine(e)
 }
 
 // Export exports a Go function to Emacs.  Unlike the global [Export] function,
@@ -90,9 +142,11 @@ func ExportFunc(name Name, fun Func, arity Arity, doc Doc) {
 //
 // The function may accept any number of arguments.  Optionally, the first
 // argument may be of type [Env].  In this case, Emacs passes a live
-// environment value that you can use to interact with Emacs.  All other
-// arguments are converted from Emacs as described in the package
-// documentation.  If not all arguments are convertible from Emacs values,
+// environment value that you can use to interact with Emacs.  Optionally, the
+// next argument may be of type [context.Context].  In this case, the function
+// receives the context returned by [Env.SpanContext].  All other arguments
+// are converted from Emacs as described in the package documentation.  If not
+// all arguments are convertible from Emacs values,
 // Export panics.
 //
 // The function must return either zero, one, or two results.  If the last or
@@ -112,8 +166,26 @@ func ExportFunc(name Name, fun Func, arity Arity, doc Doc) {
 //
 // By default, the function has no documentation string.  To add one, pass a
 // [Doc] option.
+//
+// If you pass a [Requires] option and some of the capabilities aren’t
+// available, Export returns an error.
+//
+// To make the function an interactive command, pass an [Interactive] or
+// [InteractiveForm] option.
 func (e Env) Export(fun interface{}, opts ...Option) (Value, error) {
-	return e.ExportFunc(AutoFunc(fun, opts...))
+	d, arity := autoFunc(fun, opts)
+	c, err := d.requires.missing(e)
+	if err != nil {
+		return Value{}, err
+	}
+	if c != nil {
+		return Value{}, fmt.Errorf("function %s requires %s", d.name, c)
+	}
+	f := &function{Lambda{d.call, arity, d.doc}, d.name, 0, nil, d.interactive, nil, 0}
+	if err := funcs.register(f); err != nil {
+		return Value{}, err
+	}
+	return f.define(e)
 }
 
 // ExportFunc exports a Go function to Emacs.  Unlike the global [ExportFunc]
@@ -125,7 +197,7 @@ func (e Env) Export(fun interface{}, opts ...Option) (Value, error) {
 // bound to the new function.  If doc is empty, the function won’t have a
 // documentation string.
 func (e Env) ExportFunc(name Name, fun Func, arity Arity, doc Doc) (Value, error) {
-	f := &function{Lambda{fun, arity, doc}, name, 0}
+	f := &function{Lambda{fun, arity, doc}, name, 0, nil, nil, nil, 0}
 	if err := funcs.register(f); err != nil {
 		return Value{}, err
 	}
@@ -138,9 +210,11 @@ func (e Env) ExportFunc(name Name, fun Func, arity Arity, doc Doc) (Value, error
 //
 // The function may accept any number of arguments.  Optionally, the first
 // argument may be of type [Env].  In this case, Emacs passes a live
-// environment value that you can use to interact with Emacs.  All other
-// arguments are converted from Emacs as described in the package
-// documentation.  If not all arguments are convertible from Emacs values,
+// environment value that you can use to interact with Emacs.  Optionally, the
+// next argument may be of type [context.Context].  In this case, the function
+// receives the context returned by [Env.SpanContext].  All other arguments
+// are converted from Emacs as described in the package documentation.  If not
+// all arguments are convertible from Emacs values,
 // AutoFunc panics.
 //
 // The function must return either zero, one, or two results.  If the last or
@@ -160,8 +234,17 @@ func (e Env) ExportFunc(name Name, fun Func, arity Arity, doc Doc) (Value, error
 // By default, the function has no documentation string.  To add one, pass a
 // [Doc] option.
 //
+// AutoFunc ignores [Requires] options.
+//
 // You can call AutoFunc safely from multiple goroutines.
 func AutoFunc(fun interface{}, opts ...Option) (Name, Func, Arity, Doc) {
+	d, arity := autoFunc(fun, opts)
+	return d.name, d.call, arity, d.doc
+}
+
+// autoFunc implements [AutoFunc].  It returns the full export description,
+// including options that don’t fit into the return values of AutoFunc.
+func autoFunc(fun interface{}, opts []Option) (exportAuto, Arity) {
 	v := reflect.ValueOf(fun)
 	if v.Kind() != reflect.Func {
 		panic(fmt.Errorf("%s is not a function", v))
@@ -173,6 +256,7 @@ func AutoFunc(fun interface{}, opts ...Option) (Name, Func, Arity, Doc) {
 	anon := d.flag&exportAnonymous != 0
 	if !anon && d.name == "" {
 		d.name = lispName(v)
+		d.flag |= exportDerivedName
 	}
 	if anon && d.name != "" {
 		panic(fmt.Errorf("function %s declared as anonymous, but has a name", d.name))
@@ -184,6 +268,10 @@ func AutoFunc(fun interface{}, opts ...Option) (Name, Func, Arity, Doc) {
 	if hasEnv {
 		offset = 1
 	}
+	hasContext := numIn > offset && t.In(offset) == contextType
+	if hasContext {
+		offset++
+	}
 	var arity Arity
 	var hasErr bool
 	if t.IsVariadic() {
@@ -195,6 +283,15 @@ func AutoFunc(fun interface{}, opts ...Option) (Name, Func, Arity, Doc) {
 			panic(fmt.Errorf("function %s: don’t know how to convert variadic type: %s", d.name, err))
 		}
 		d.varConv = conv
+	} else if numIn > offset && hasKeywords(t.In(numIn-1)) {
+		numIn--
+		arity.Min = numIn - offset
+		arity.Max = -1
+		codec, err := structCodecFor(t.In(numIn))
+		if err != nil {
+			panic(fmt.Errorf("function %s: don’t know how to convert keyword arguments: %s", d.name, err))
+		}
+		d.keys = codec
 	} else {
 		arity.Min = numIn - offset
 		arity.Max = arity.Min
@@ -205,7 +302,14 @@ func AutoFunc(fun interface{}, opts ...Option) (Name, Func, Arity, Doc) {
 			panic(fmt.Errorf("function %s: don’t know how to convert argument %d: %s", d.name, i, err))
 		}
 		d.inConv = append(d.inConv, conv)
+		switch {
+		case isOptional(t.In(i)):
+			d.optional++
+		case d.optional > 0:
+			panic(fmt.Errorf("function %s: required argument %d follows optional argument", d.name, i))
+		}
 	}
+	arity.Min -= d.optional
 	hasRet := false
 	switch t.NumOut() {
 	case 0:
@@ -224,6 +328,9 @@ func AutoFunc(fun interface{}, opts ...Option) (Name, Func, Arity, Doc) {
 	if hasEnv {
 		d.flag |= exportHasEnv
 	}
+	if hasContext {
+		d.flag |= exportHasContext
+	}
 	if hasRet {
 		conv, err := InFuncFor(t.Out(0))
 		if err != nil {
@@ -234,7 +341,7 @@ func AutoFunc(fun interface{}, opts ...Option) (Name, Func, Arity, Doc) {
 	if hasErr {
 		d.flag |= exportHasErr
 	}
-	return d.name, d.call, arity, d.doc
+	return d, arity
 }
 
 // AutoLambda returns a [Lambda] object that exports the given function to
@@ -244,9 +351,11 @@ func AutoFunc(fun interface{}, opts ...Option) (Name, Func, Arity, Doc) {
 //
 // The function may accept any number of arguments.  Optionally, the first
 // argument may be of type [Env].  In this case, Emacs passes a live
-// environment value that you can use to interact with Emacs.  All other
-// arguments are converted from Emacs as described in the package
-// documentation.  If not all arguments are convertible from Emacs values,
+// environment value that you can use to interact with Emacs.  Optionally, the
+// next argument may be of type [context.Context].  In this case, the function
+// receives the context returned by [Env.SpanContext].  All other arguments
+// are converted from Emacs as described in the package documentation.  If not
+// all arguments are convertible from Emacs values,
 // AutoLambda panics.
 //
 // The function must return either zero, one, or two results.  If the last or
@@ -319,7 +428,7 @@ func (e Env) Lambda(fun interface{}, opts ...Option) (Value, DeleteFunc, error)
 //
 // You can call LambdaFunc safely from multiple goroutines.
 func (e Env) LambdaFunc(fun Func, arity Arity, doc Doc) (Value, DeleteFunc, error) {
-	f := &function{Lambda{fun, arity, doc}, "", 0}
+	f := &function{Lambda{fun, arity, doc}, "", 0, nil, nil, newCreation(), 0}
 	if err := funcs.register(f); err != nil {
 		return Value{}, nil, err
 	}
@@ -330,13 +439,39 @@ func (e Env) LambdaFunc(fun Func, arity Arity, doc Doc) (Value, DeleteFunc, erro
 	return v, func() { funcs.delete(f.index) }, nil
 }
 
+// exportNumbered exports l as a named function.  The name is prefix followed
+// by the function index, which makes it unique.  Use releaseNumbered to
+// release the function.
+func (e Env) exportNumbered(prefix string, l Lambda) (Name, funcIndex, error) {
+	f := &function{Lambda: l, created: newCreation()}
+	if err := funcs.register(f); err != nil {
+		return "", 0, err
+	}
+	f.name = Name(fmt.Sprintf("%s%d", prefix, f.index))
+	if _, err := f.define(e); err != nil {
+		funcs.delete(f.index)
+		return "", 0, err
+	}
+	return f.name, f.index, nil
+}
+
+// releaseNumbered releases a function exported by exportNumbered.  It
+// replaces the function definition with ignore, so that remaining references
+// to the function name don’t fail.
+func (e Env) releaseNumbered(name Name, index funcIndex) error {
+	funcs.delete(index)
+	_, err := e.Call("defalias", name, Symbol("ignore"))
+	return err
+}
+
 // DeleteFunc is a function returned by [Env.Lambda] and [Env.LambdaFunc].
 // Call this function to delete the created function.  After deletion the
 // function can’t be called any more from Emacs.
 type DeleteFunc func()
 
 // Option is an option for [Export], [AutoFunc], [AutoLambda], and [ERTTest].
-// Its implementations are [Name], [Anonymous], [Doc], and [Usage].
+// Its implementations are [Name], [Anonymous], [Unprefixed], [Doc], [Usage],
+// [Requires], [Interactive], and [InteractiveForm].
 type Option interface {
 	apply(*exportAuto)
 }
@@ -351,14 +486,47 @@ func (n Name) apply(o *exportAuto)    { o.name = n }
 func (d Doc) apply(o *exportAuto)     { o.doc = d }
 func (u Usage) apply(o *exportAuto)   { o.doc = o.doc.WithUsage(u) }
 
+// Unprefixed is an [Option] that exempts a function exported using [Export]
+// from the prefix set by [SetFunctionPrefix].  Export neither applies the
+// prefix to the function name nor checks the name against it.
+type Unprefixed struct{}
+
+func (Unprefixed) apply(o *exportAuto) { o.flag |= exportUnprefixed }
+
+// Interactive is an [Option] that makes a function exported using [Export] or
+// [Env.Export] an interactive command.  The value is the interactive
+// specification string, as in the interactive special form; for example,
+// Interactive("P") passes the raw prefix argument.  Interactive("") defines a
+// command that receives no arguments.  See [InteractiveForm] for other kinds of
+// specifications.  [AutoFunc] and [AutoLambda] ignore Interactive options.
+type Interactive string
+
+func (i Interactive) apply(o *exportAuto) { o.interactive = String(i) }
+
+// InteractiveForm is an [Option] like [Interactive], but with an arbitrary
+// interactive specification, typically a Lisp form that returns the list of
+// arguments.  For example, InteractiveForm{List{Symbol("list"),
+// Symbol("current-prefix-arg")}} passes the raw prefix argument.
+type InteractiveForm struct{ Spec In }
+
+func (i InteractiveForm) apply(o *exportAuto) { o.interactive = i.Spec }
+
 type exportAuto struct {
-	fun     reflect.Value
-	flag    exportFlag
-	name    Name
-	doc     Doc
-	inConv  []OutFunc
-	varConv OutFunc
-	outConv InFunc
+	fun         reflect.Value
+	flag        exportFlag
+	name        Name
+	doc         Doc
+	requires    Requires
+	interactive In
+	ertTags     ERTTags
+	ertExpected ERTExpectedResult
+	ertFixtures []ERTFixture
+	benchTime   time.Duration
+	inConv      []OutFunc
+	optional    int          // number of trailing Optional parameters
+	keys        *structCodec // for keyword arguments, see Keywords
+	varConv     OutFunc
+	outConv     InFunc
 }
 
 type exportFlag uint
@@ -366,7 +534,10 @@ type exportFlag uint
 const (
 	exportAnonymous exportFlag = 1 << iota
 	exportHasEnv
+	exportHasContext
 	exportHasErr
+	exportDerivedName
+	exportUnprefixed
 )
 
 func lispName(fun reflect.Value) Name {
@@ -389,21 +560,61 @@ func lispName(fun reflect.Value) Name {
 var goIdentPattern = regexp.MustCompile(`[\p{L}_][\p{L}_\p{Nd}]*$`)
 
 var (
-	envType   = reflect.TypeOf(Env{})
-	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	envType     = reflect.TypeOf(Env{})
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
 )
 
 func (d exportAuto) call(e Env, args []Value) (Value, error) {
 	t := d.fun.Type()
 	offset := 0
 	if d.flag&exportHasEnv != 0 {
-		offset = 1
+		offset++
 	}
-	in := make([]reflect.Value, len(args)+offset)
-	if offset == 1 {
-		in[0] = reflect.ValueOf(e)
+	if d.flag&exportHasContext != 0 {
+		offset++
 	}
 	numIn := len(d.inConv)
+	var keys reflect.Value
+	if d.keys != nil {
+		keys = reflect.New(t.In(t.NumIn() - 1)).Elem()
+		if len(args) > numIn {
+			if err := d.keys.setKeywords(e, keys, args[numIn:]); err != nil {
+				return Value{}, err
+			}
+			args = args[:numIn]
+		}
+	}
+	n := len(args)
+	if n < numIn {
+		// Some optional arguments are missing.
+		n = numIn
+	}
+	if keys.IsValid() {
+		n++
+	}
+	in := make([]reflect.Value, n+offset)
+	for i := len(args); i < numIn; i++ {
+		in[i+offset] = reflect.Zero(t.In(i + offset))
+	}
+	if keys.IsValid() {
+		in[len(in)-1] = keys
+	}
+	if d.flag&exportHasEnv != 0 {
+		in[0] = reflect.ValueOf(e)
+	}
+	if d.flag&exportHasContext != 0 {
+		in[offset-1] = reflect.ValueOf(e.SpanContext())
+	}
+	if DebugMode() {
+		max := numIn
+		if d.varConv != nil || d.keys != nil {
+			max = -1
+		}
+		if err := (Arity{numIn - d.optional, max}).check(len(args)); err != nil {
+			return Value{}, err
+		}
+	}
 	for i, a := range args {
 		j := i + offset
 		var conv OutFunc
@@ -417,7 +628,7 @@ func (d exportAuto) call(e Env, args []Value) (Value, error) {
 		}
 		r := reflect.New(u)
 		if err := conv(r).FromEmacs(e, a); err != nil {
-			return Value{}, err
+			return Value{}, conversionContext(err, fmt.Sprintf("argument %d", i+1), u)
 		}
 		in[j] = r.Elem()
 	}
@@ -428,19 +639,43 @@ func (d exportAuto) call(e Env, args []Value) (Value, error) {
 		}
 	}
 	if d.outConv != nil {
-		return d.outConv(out[0]).Emacs(e)
+		v, err := d.outConv(out[0]).Emacs(e)
+		return v, conversionContext(err, "result", out[0].Type())
 	}
 	return e.Nil()
 }
 
 type function struct {
 	Lambda
-	name  Name
-	index funcIndex
+	name     Name
+	index    funcIndex
+	requires Requires
+
+	// interactive is the interactive specification for commands, nil
+	// for functions that aren’t commands.
+	interactive In
+
+	// created is non-nil for functions that are supposed to be deleted
+	// eventually.  See [Leaks].
+	created *creation
+
+	// objects is the number of Emacs function objects for this function
+	// that haven’t been garbage-collected yet.  Usually there’s only
+	// one, but [ManagerOf.Redefine] creates new function objects with the
+	// same index.  Protected by funcManager.mu.
+	objects int
 }
 
 func (f *function) Define(e Env) error {
-	_, err := f.define(e)
+	c, err := f.requires.missing(e)
+	if err != nil {
+		return err
+	}
+	if c != nil {
+		// The function is optional; silently skip it.
+		return nil
+	}
+	_, err = f.define(e)
 	return err
 }
 
@@ -449,6 +684,16 @@ func (f *function) define(e Env) (Value, error) {
 	if err != nil {
 		return Value{}, err
 	}
+	funcs.retain(f.index)
+	if f.interactive != nil {
+		spec, err := f.interactive.Emacs(e)
+		if err != nil {
+			return Value{}, err
+		}
+		if err := e.MakeInteractive(v, spec); err != nil {
+			return Value{}, err
+		}
+	}
 	if f.name != "" {
 		if err := e.Defalias(f.name, v); err != nil {
 			return Value{}, err
@@ -464,8 +709,8 @@ type funcIndex uint64
 
 type funcManager struct {
 	mu    sync.RWMutex
-	base  *Manager
-	funcs map[funcIndex]Func
+	base  *ManagerOf[*function]
+	funcs map[funcIndex]*function
 	next  funcIndex
 }
 
@@ -509,9 +754,9 @@ func (m *funcManager) registerLocked(f *function) {
 	index := m.next
 	m.next++
 	if m.funcs == nil {
-		m.funcs = make(map[funcIndex]Func)
+		m.funcs = make(map[funcIndex]*function)
 	}
-	m.funcs[index] = f.Fun
+	m.funcs[index] = f
 	f.index = index
 }
 
@@ -521,7 +766,7 @@ func (m *funcManager) mustEnqueue(f *function) {
 	}
 }
 
-func (m *funcManager) get(i funcIndex) Func {
+func (m *funcManager) get(i funcIndex) *function {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	fun, ok := m.funcs[i]
@@ -531,10 +776,62 @@ func (m *funcManager) get(i funcIndex) Func {
 	return fun
 }
 
+// counts returns the number of named and anonymous functions.
+func (m *funcManager) counts() (named, anonymous int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, f := range m.funcs {
+		if f.name == "" {
+			anonymous++
+		} else {
+			named++
+		}
+	}
+	return
+}
+
+// leaks returns the deletable functions created before t.
+func (m *funcManager) leaks(t time.Time) []*function {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var r []*function
+	for _, f := range m.funcs {
+		if f.created != nil && f.created.time.Before(t) {
+			r = append(r, f)
+		}
+	}
+	return r
+}
+
 func (m *funcManager) delete(i funcIndex) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	delete(m.funcs, i)
 }
 
-var funcs = funcManager{base: NewManager(RequireUniqueName | DefineOnInit)}
+// retain records that a new Emacs function object for the function with the
+// given index has been created.
+func (m *funcManager) retain(i funcIndex) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f, ok := m.funcs[i]; ok {
+		f.objects++
+	}
+}
+
+// release records that an Emacs function object for the function with the
+// given index has been garbage-collected.  It deletes the function once no
+// function objects are left.
+func (m *funcManager) release(i funcIndex) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.funcs[i]
+	if !ok {
+		return
+	}
+	if f.objects--; f.objects <= 0 {
+		delete(m.funcs, i)
+	}
+}
+
+var funcs = funcManager{base: NewManagerOf[*function](RequireUniqueName | DefineOnInit)}
@@ -15,6 +15,7 @@
 package emacs
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -39,4 +40,33 @@ func goUppercase(s ...string) string {
 func init() {
 	// We would normally call ExampleExport here, but the test runner
 	// already calls it for us.
+	Export(goDouble, Doc("Return twice the numeric prefix argument N."), Usage("N"), Interactive("p"))
+	ERTTest(exportInteractive)
+}
+
+func goDouble(n int) int { return 2 * n }
+
+func exportInteractive(e Env) error {
+	if ok, err := e.CommandP(Symbol("go-double")); err != nil || !ok {
+		return fmt.Errorf("go-double isn’t a command: %v", err)
+	}
+	fun, err := e.Export(goDouble, Anonymous{}, InteractiveForm{List{Symbol("list"), Int(21)}})
+	if err != nil {
+		return err
+	}
+	var got Int
+	if err := e.CallOut("call-interactively", &got, fun); err != nil {
+		return err
+	}
+	if got != 42 {
+		return fmt.Errorf("call-interactively: got %d, want 42", got)
+	}
+	fun, err = e.Export(goDouble, Anonymous{})
+	if err != nil {
+		return err
+	}
+	if ok, err := e.CommandP(fun); err != nil || ok {
+		return errors.New("function exported without Interactive option is a command")
+	}
+	return nil
 }
@@ -0,0 +1,259 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "reflect"
+
+// FaceAttributes contains a subset of the attributes of an Emacs face.  See
+// [Face Attributes].  Zero values represent unspecified attributes.  In
+// Emacs, FaceAttributes is represented as a property list such as
+// (:foreground "red" :weight bold) that contains only the specified
+// attributes.
+//
+// [Face Attributes]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Face-Attributes.html
+type FaceAttributes struct {
+	Family  string
+	Foundry string
+
+	// Width, Weight, and Slant are symbols such as condensed, bold, or
+	// italic.
+	Width  Symbol
+	Weight Symbol
+	Slant  Symbol
+
+	// Height is the absolute font height in units of 1/10 point.
+	// RelativeHeight is a font height relative to the underlying face.
+	// At most one of them should be nonzero.
+	Height         int
+	RelativeHeight float64
+
+	Foreground        Color
+	Background        Color
+	DistantForeground Color
+
+	// The following attributes can only be turned on.  When converting
+	// from Emacs, any non-nil value such as a color or a property list
+	// turns the attribute on.
+	Underline     bool
+	Overline      bool
+	StrikeThrough bool
+	InverseVideo  bool
+	Extend        bool
+
+	// Inherit lists the faces from which the face inherits attributes.
+	Inherit []Symbol
+}
+
+// Emacs returns a new property list with the specified attributes.
+func (a FaceAttributes) Emacs(e Env) (Value, error) {
+	return a.plist().Emacs(e)
+}
+
+func (a FaceAttributes) plist() List {
+	var r List
+	add := func(key Symbol, v In) { r = append(r, key, v) }
+	for _, s := range []struct {
+		key   Symbol
+		value string
+	}{
+		{":family", a.Family},
+		{":foundry", a.Foundry},
+		{":foreground", string(a.Foreground)},
+		{":background", string(a.Background)},
+		{":distant-foreground", string(a.DistantForeground)},
+	} {
+		if s.value != "" {
+			add(s.key, String(s.value))
+		}
+	}
+	for _, s := range []struct{ key, value Symbol }{
+		{":width", a.Width},
+		{":weight", a.Weight},
+		{":slant", a.Slant},
+	} {
+		if s.value != "" {
+			add(s.key, s.value)
+		}
+	}
+	switch {
+	case a.Height != 0:
+		add(":height", Int(a.Height))
+	case a.RelativeHeight != 0:
+		add(":height", Float(a.RelativeHeight))
+	}
+	for _, b := range []struct {
+		key   Symbol
+		value bool
+	}{
+		{":underline", a.Underline},
+		{":overline", a.Overline},
+		{":strike-through", a.StrikeThrough},
+		{":inverse-video", a.InverseVideo},
+		{":extend", a.Extend},
+	} {
+		if b.value {
+			add(b.key, T)
+		}
+	}
+	switch len(a.Inherit) {
+	case 0:
+	case 1:
+		add(":inherit", a.Inherit[0])
+	default:
+		l := make(List, len(a.Inherit))
+		for i, s := range a.Inherit {
+			l[i] = s
+		}
+		add(":inherit", l)
+	}
+	return r
+}
+
+// FromEmacs sets *a from the property list v.  It ignores unknown attributes
+// and attributes whose value is the symbol unspecified.
+func (a *FaceAttributes) FromEmacs(e Env, v Value) error {
+	var r FaceAttributes
+	var key Value
+	even := true
+	err := e.Dolist(v, func(elem Value) error {
+		defer func() { even = !even }()
+		if even {
+			key = elem
+			return nil
+		}
+		k, err := e.Symbol(key)
+		if err != nil {
+			return err
+		}
+		return r.set(e, k, elem)
+	})
+	if err != nil {
+		return err
+	}
+	*a = r
+	return nil
+}
+
+// set sets the attribute key to v.
+func (a *FaceAttributes) set(e Env, key Symbol, v Value) error {
+	var sym Bool
+	if err := e.CallOut("symbolp", &sym, v); err != nil {
+		return err
+	}
+	if sym {
+		s, err := e.Symbol(v)
+		if err != nil {
+			return err
+		}
+		if s == "unspecified" {
+			return nil
+		}
+	}
+	switch key {
+	case ":family":
+		return (*String)(&a.Family).FromEmacs(e, v)
+	case ":foundry":
+		return (*String)(&a.Foundry).FromEmacs(e, v)
+	case ":width":
+		return a.Width.FromEmacs(e, v)
+	case ":weight":
+		return a.Weight.FromEmacs(e, v)
+	case ":slant":
+		return a.Slant.FromEmacs(e, v)
+	case ":height":
+		var integer Bool
+		if err := e.CallOut("integerp", &integer, v); err != nil {
+			return err
+		}
+		if integer {
+			return reflectInt(reflect.ValueOf(&a.Height)).FromEmacs(e, v)
+		}
+		var float Bool
+		if err := e.CallOut("floatp", &float, v); err != nil {
+			return err
+		}
+		if float {
+			return (*Float)(&a.RelativeHeight).FromEmacs(e, v)
+		}
+		// Heights can also be functions, which we don’t support.
+		return nil
+	case ":foreground":
+		return a.Foreground.FromEmacs(e, v)
+	case ":background":
+		return a.Background.FromEmacs(e, v)
+	case ":distant-foreground":
+		return a.DistantForeground.FromEmacs(e, v)
+	case ":underline":
+		a.Underline = e.IsNotNil(v)
+	case ":overline":
+		a.Overline = e.IsNotNil(v)
+	case ":strike-through":
+		a.StrikeThrough = e.IsNotNil(v)
+	case ":inverse-video":
+		a.InverseVideo = e.IsNotNil(v)
+	case ":extend":
+		a.Extend = e.IsNotNil(v)
+	case ":inherit":
+		if sym {
+			if e.IsNil(v) {
+				a.Inherit = nil
+				return nil
+			}
+			s, err := e.Symbol(v)
+			if err != nil {
+				return err
+			}
+			a.Inherit = []Symbol{s}
+			return nil
+		}
+		var faces []Symbol
+		if err := e.Dolist(v, func(f Value) error {
+			s, err := e.Symbol(f)
+			faces = append(faces, s)
+			return err
+		}); err != nil {
+			return err
+		}
+		a.Inherit = faces
+	}
+	return nil
+}
+
+// FaceAttributes returns the attributes of face on the selected frame using
+// face-all-attributes.
+func (e Env) FaceAttributes(face Symbol) (FaceAttributes, error) {
+	alist, err := e.Call("face-all-attributes", face)
+	if err != nil {
+		return FaceAttributes{}, err
+	}
+	var r FaceAttributes
+	err = e.Dolist(alist, func(elem Value) error {
+		var key Symbol
+		var value Value
+		if err := e.UnconsOut(elem, &key, &value); err != nil {
+			return err
+		}
+		return r.set(e, key, value)
+	})
+	return r, err
+}
+
+// SetFaceAttributes sets the specified attributes of face for all frames and
+// for future frames using set-face-attribute.  It leaves unspecified
+// attributes alone.
+func (e Env) SetFaceAttributes(face Symbol, attrs FaceAttributes) error {
+	_, err := e.Call("apply", Symbol("set-face-attribute"), face, Nil, attrs.plist())
+	return err
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func init() {
+	ERTTest(faceAttributes)
+}
+
+func faceAttributes(e Env) error {
+	const face Symbol = "go-face-attributes-test"
+	if _, err := e.Call("make-face", face); err != nil {
+		return err
+	}
+	want := FaceAttributes{
+		Weight:     "bold",
+		Height:     120,
+		Foreground: "#ff0000",
+		Underline:  true,
+		Inherit:    []Symbol{"default", "fixed-pitch"},
+	}
+	if err := e.SetFaceAttributes(face, want); err != nil {
+		return err
+	}
+	got, err := e.FaceAttributes(face)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("FaceAttributes: got %+v, want %+v", got, want)
+	}
+	v, err := want.Emacs(e)
+	if err != nil {
+		return err
+	}
+	var plist FaceAttributes
+	if err := plist.FromEmacs(e, v); err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(plist, want) {
+		return fmt.Errorf("FaceAttributes roundtrip: got %+v, want %+v", plist, want)
+	}
+	return nil
+}
@@ -17,15 +17,25 @@ package emacs
 // #include "wrappers.h"
 import "C"
 
-import "reflect"
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
 
 // Float is a type with underlying type float64 that knows how to convert
 // itself into an Emacs value.
 type Float float64
 
 // Emacs creates an Emacs value representing the given floating-point number.
+// The current [FloatPolicy] determines how Emacs handles NaN and infinities.
 func (f Float) Emacs(e Env) (Value, error) {
-	return e.checkValue(C.phst_emacs_make_float(e.raw(), C.double(f)))
+	g, err := applyFloatPolicy(float64(f))
+	if err != nil {
+		return Value{}, err
+	}
+	return e.checkValue(C.phst_emacs_make_float(e.raw(), C.double(g)))
 }
 
 // FromEmacs sets *f to the floating-point number stored in v.  It returns an
@@ -40,15 +50,86 @@ func (f *Float) FromEmacs(e Env, v Value) error {
 }
 
 // Float returns the floating-point number stored in v.  It returns an error if
-// v is not a floating-point value.
+// v is not a floating-point value.  The current [FloatPolicy] determines how
+// Float handles NaN and infinities.
 func (e Env) Float(v Value) (float64, error) {
-	r := C.phst_emacs_extract_float(e.raw(), v.r)
+	r := C.phst_emacs_extract_float(e.raw(), v.raw())
 	if err := e.check(r.base); err != nil {
 		return 0, err
 	}
-	return float64(r.value), nil
+	return applyFloatPolicy(float64(r.value))
+}
+
+// FloatPolicy specifies how conversions between Go and Emacs handle the
+// special floating-point values NaN, positive infinity, and negative
+// infinity.  Use [SetFloatPolicy] to change the policy.
+type FloatPolicy int32
+
+const (
+	// PassSpecialFloats passes special values through unchanged.  This
+	// is the default.  Note that Emacs preserves the sign and payload of
+	// NaN values, so that a NaN produced by Go code might print as
+	// -0.0e+NaN or 1.0e+NaN instead of 0.0e+NaN.
+	PassSpecialFloats FloatPolicy = iota
+
+	// RejectSpecialFloats causes conversions of special values to fail
+	// with an error of type domain-error.  Use this policy if the values
+	// are passed to Emacs functions that don’t expect them.
+	RejectSpecialFloats
+
+	// CanonicalSpecialFloats converts all NaN values to the NaN value
+	// that the Emacs reader produces for 0.0e+NaN.  Infinities are
+	// passed through unchanged; they are the same as the values that
+	// the Emacs reader produces for 1.0e+INF and -1.0e+INF.
+	CanonicalSpecialFloats
+)
+
+// SetFloatPolicy sets the policy for special floating-point values.  The
+// policy applies to [Float.Emacs], [Env.Float], and all conversions that use
+// them, for example the conversions of float32 and float64 values described
+// in the package documentation.  You can call SetFloatPolicy safely from
+// multiple goroutines.
+func SetFloatPolicy(p FloatPolicy) {
+	atomic.StoreInt32(&floatPolicy, int32(p))
 }
 
+var floatPolicy int32
+
+// canonicalNaN is the NaN that the Emacs reader produces for 0.0e+NaN.  Note
+// that math.NaN returns a NaN with a different payload.
+var canonicalNaN = math.Float64frombits(0x7FF8_0000_0000_0000)
+
+// applyFloatPolicy applies the current FloatPolicy to f.
+func applyFloatPolicy(f float64) (float64, error) {
+	if !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f, nil
+	}
+	switch FloatPolicy(atomic.LoadInt32(&floatPolicy)) {
+	case RejectSpecialFloats:
+		return 0, DomainError(strconv.FormatFloat(f, 'g', -1, 64))
+	case CanonicalSpecialFloats:
+		if math.IsNaN(f) {
+			return canonicalNaN, nil
+		}
+	}
+	return f, nil
+}
+
+// DomainError returns an error that will cause Emacs to signal an error of
+// type domain-error.  val is the string representation of the value outside
+// of the domain.
+func DomainError(val string) error {
+	return domainError.Error(String(val))
+}
+
+// IsDomainError returns whether err is an Emacs signal of type domain-error.
+// This function detects both [Error] and [Signal].
+func (e Env) IsDomainError(err error) bool {
+	return domainError.match(e, err)
+}
+
+var domainError = ErrorSymbol{"domain-error", "Arithmetic domain error"}
+
 func floatIn(v reflect.Value) In   { return Float(v.Float()) }
 func floatOut(v reflect.Value) Out { return reflectFloat(v) }
 
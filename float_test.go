@@ -0,0 +1,85 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+func init() {
+	ERTTest(floatPolicies)
+}
+
+func floatPolicies(e Env) error {
+	old := atomic.LoadInt32(&floatPolicy)
+	defer atomic.StoreInt32(&floatPolicy, old)
+
+	weird := math.Float64frombits(0xFFF8_0000_0000_0001)
+	SetFloatPolicy(CanonicalSpecialFloats)
+	var s String
+	if err := e.CallOut("number-to-string", &s, Float(weird)); err != nil {
+		return err
+	}
+	if s != "0.0e+NaN" {
+		return fmt.Errorf("canonical NaN: got %s, want 0.0e+NaN", s)
+	}
+
+	SetFloatPolicy(RejectSpecialFloats)
+	if _, err := Float(math.Inf(1)).Emacs(e); !e.IsDomainError(err) {
+		return fmt.Errorf("converting infinity: got error %v, want domain-error", err)
+	}
+	v, err := e.Call("string-to-number", String("1.0e+INF"))
+	if err != nil {
+		return err
+	}
+	if _, err := e.Float(v); !e.IsDomainError(err) {
+		return fmt.Errorf("extracting infinity: got error %v, want domain-error", err)
+	}
+	return nil
+}
+
+func TestApplyFloatPolicy(t *testing.T) {
+	old := atomic.LoadInt32(&floatPolicy)
+	defer atomic.StoreInt32(&floatPolicy, old)
+
+	weird := math.Float64frombits(0xFFF8_0000_0000_0001)
+	for _, tc := range []struct {
+		policy FloatPolicy
+		in     float64
+		want   uint64
+		err    bool
+	}{
+		{PassSpecialFloats, 1.5, math.Float64bits(1.5), false},
+		{PassSpecialFloats, weird, math.Float64bits(weird), false},
+		{RejectSpecialFloats, 1.5, math.Float64bits(1.5), false},
+		{RejectSpecialFloats, weird, 0, true},
+		{RejectSpecialFloats, math.Inf(-1), 0, true},
+		{CanonicalSpecialFloats, weird, 0x7FF8_0000_0000_0000, false},
+		{CanonicalSpecialFloats, math.Inf(-1), math.Float64bits(math.Inf(-1)), false},
+	} {
+		SetFloatPolicy(tc.policy)
+		got, err := applyFloatPolicy(tc.in)
+		if (err != nil) != tc.err {
+			t.Errorf("policy %d, value %v: got error %v, want error %t", tc.policy, tc.in, err, tc.err)
+			continue
+		}
+		if err == nil && math.Float64bits(got) != tc.want {
+			t.Errorf("policy %d, value %v: got %#x, want %#x", tc.policy, tc.in, math.Float64bits(got), tc.want)
+		}
+	}
+}
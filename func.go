@@ -124,6 +124,24 @@ func (a Arity) Variadic() bool {
 	return a.Max < 0
 }
 
+// check returns an error of type wrong-number-of-arguments if a function with
+// arity a can’t accept n arguments.
+func (a Arity) check(n int) error {
+	if n >= a.Min && (a.Variadic() || n <= a.Max) {
+		return nil
+	}
+	return wrongNumberOfArguments.Error(a.cons(), Int(n))
+}
+
+// cons returns the arity in the format used by func-arity.
+func (a Arity) cons() Cons {
+	var max In = Int(a.Max)
+	if a.Variadic() {
+		max = Symbol("many")
+	}
+	return Cons{Car: Int(a.Min), Cdr: max}
+}
+
 // Func is a Go function exported to Emacs.  It has access to a live
 // environment, takes arguments as a slice, and can return a value or an error.
 type Func func(Env, []Value) (Value, error)
@@ -134,6 +152,69 @@ func (e Env) Defalias(name Name, def Value) error {
 	return err
 }
 
+// Documentation returns the documentation string of fun by calling the Emacs
+// function documentation.  fun can be a function symbol or object.  The
+// documentation string is empty if fun has no documentation.
+func (e Env) Documentation(fun In) (Doc, error) {
+	v, err := e.Call("documentation", fun)
+	if err != nil {
+		return "", err
+	}
+	if !e.IsNotNil(v) {
+		return "", nil
+	}
+	s, err := e.Str(v)
+	return Doc(s), err
+}
+
+// FuncArity returns the number of arguments that fun accepts by calling the
+// Emacs function func-arity.  fun can be a function symbol or object.
+// FuncArity reports special forms, whose arguments aren’t evaluated, as
+// variadic.
+func (e Env) FuncArity(fun In) (Arity, error) {
+	v, err := e.Call("func-arity", fun)
+	if err != nil {
+		return Arity{}, err
+	}
+	min, maxV, err := e.Uncons(v)
+	if err != nil {
+		return Arity{}, err
+	}
+	lo, err := e.Int(min)
+	if err != nil {
+		return Arity{}, err
+	}
+	r := Arity{int(lo), -1}
+	var isInt Bool
+	if err := e.CallOut("integerp", &isInt, maxV); err != nil {
+		return Arity{}, err
+	}
+	if isInt {
+		hi, err := e.Int(maxV)
+		if err != nil {
+			return Arity{}, err
+		}
+		r.Max = int(hi)
+	}
+	return r, nil
+}
+
+// CommandP returns whether fun is an interactive command by calling the Emacs
+// function commandp.
+func (e Env) CommandP(fun In) (bool, error) {
+	var r Bool
+	err := e.CallOut("commandp", &r, fun)
+	return bool(r), err
+}
+
+// FunctionP returns whether fun is a function by calling the Emacs function
+// functionp.  Note that functionp returns nil for special forms and macros.
+func (e Env) FunctionP(fun In) (bool, error) {
+	var r Bool
+	err := e.CallOut("functionp", &r, fun)
+	return bool(r), err
+}
+
 func (e Env) makeFunction(arity Arity, doc Doc, data uint64) (Value, error) {
 	min := C.int64_t(arity.Min)
 	var max C.int64_t
@@ -154,21 +235,33 @@ func (e Env) makeFunction(arity Arity, doc Doc, data uint64) (Value, error) {
 // Funcall calls the Emacs function fun with the given arguments.  Both
 // function and arguments must be Emacs values.  Use [Env.Call] or [Env.Invoke]
 // if you want them to be autoconverted.
-func (e Env) Funcall(fun Value, args []Value) (Value, error) {
+func (e Env) Funcall(fun Value, args []Value) (r Value, err error) {
+	if tracer.active() {
+		s := tracer.begin(TraceFuncall, e.traceSummary(fun), e.traceSummaries(args))
+		defer func() { tracer.end(s, err) }()
+	}
+	if span := startSpan(TraceFuncall, func() string { return e.traceSummary(fun) }); span != nil {
+		defer func() { endSpan(span, err) }()
+	}
+	return e.funcall(fun, args)
+}
+
+// funcall is like [Env.Funcall], but never traces the call.
+func (e Env) funcall(fun Value, args []Value) (Value, error) {
 	nargs := len(args)
 	var ptr *C.emacs_value
 	if nargs > 0 {
 		rawArgs := make([]C.emacs_value, nargs)
 		for i, a := range args {
-			rawArgs[i] = a.r
+			rawArgs[i] = a.raw()
 		}
 		ptr = &rawArgs[0]
 	}
-	return e.checkValue(C.phst_emacs_funcall(e.raw(), fun.r, C.int64_t(nargs), ptr))
+	return e.checkValue(C.phst_emacs_funcall(e.raw(), fun.raw(), C.int64_t(nargs), ptr))
 }
 
 // MakeInteractive sets the interactive specification of the given function.
 // The function must refer to a module function.
 func (e Env) MakeInteractive(fun, spec Value) error {
-	return e.checkVoid(C.phst_emacs_make_interactive(e.raw(), fun.r, spec.r))
+	return e.checkVoid(C.phst_emacs_make_interactive(e.raw(), fun.raw(), spec.raw()))
 }
@@ -14,7 +14,11 @@
 
 package emacs
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
 
 func TestDoc(t *testing.T) {
 	for _, tc := range []struct {
@@ -49,3 +53,52 @@ func TestDoc(t *testing.T) {
 		})
 	}
 }
+
+func init() {
+	ERTTest(functionIntrospection)
+}
+
+func functionIntrospection(e Env) error {
+	for _, tc := range []struct {
+		fun      Name
+		arity    Arity
+		function bool
+		command  bool
+	}{
+		{"car", Arity{1, 1}, true, false},
+		{"list", Arity{0, -1}, true, false},
+		{"substring", Arity{1, 3}, true, false},
+		{"if", Arity{2, -1}, false, false},
+		{"forward-char", Arity{0, 1}, true, true},
+	} {
+		arity, err := e.FuncArity(tc.fun)
+		if err != nil {
+			return err
+		}
+		if arity != tc.arity {
+			return fmt.Errorf("arity of %s: got %+v, want %+v", tc.fun, arity, tc.arity)
+		}
+		function, err := e.FunctionP(tc.fun)
+		if err != nil {
+			return err
+		}
+		if function != tc.function {
+			return fmt.Errorf("functionp %s: got %t, want %t", tc.fun, function, tc.function)
+		}
+		command, err := e.CommandP(tc.fun)
+		if err != nil {
+			return err
+		}
+		if command != tc.command {
+			return fmt.Errorf("commandp %s: got %t, want %t", tc.fun, command, tc.command)
+		}
+	}
+	doc, err := e.Documentation(Name("car"))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(doc), "car of LIST") {
+		return fmt.Errorf("unexpected documentation of car: %q", doc)
+	}
+	return nil
+}
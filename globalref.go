@@ -0,0 +1,125 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// #include "emacs-module.h"
+// #include "wrappers.h"
+import "C"
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Global is a global reference to an Emacs value.  Normal [Value] objects are
+// only valid as long as the environment that created them; a Global stays
+// valid until it’s freed using [Env.FreeGlobalRef], even across calls into the
+// module.  This allows long-lived Go code such as caches or handle registries
+// to retain Emacs objects.  Emacs counts global references: each call to
+// [Env.GlobalRef] must be balanced by exactly one call to [Env.FreeGlobalRef].
+// The zero Global is not a valid reference.  Globals are comparable; two
+// Globals are equal if they refer to the same Emacs object.
+type Global struct{ ref Value }
+
+// GlobalRef returns a new global reference to v.  You must eventually free
+// the reference using [Env.FreeGlobalRef], otherwise the Emacs object will
+// never be garbage-collected.  See [Persistent] for a wrapper that does this
+// automatically.
+func (e Env) GlobalRef(v Value) (Global, error) {
+	ref, err := e.makeGlobalRef(v)
+	if err != nil {
+		return Global{}, err
+	}
+	return Global{ref}, nil
+}
+
+// FreeGlobalRef frees a global reference returned by [Env.GlobalRef].  After
+// FreeGlobalRef returns, you must no longer use g, unless you have obtained
+// other references to the same object.
+func (e Env) FreeGlobalRef(g Global) error {
+	if !g.Valid() {
+		return errors.New("invalid global reference")
+	}
+	return e.freeGlobalRef(g.ref)
+}
+
+// Valid returns whether g is a global reference, i.e. not the zero Global.
+func (g Global) Valid() bool { return g.ref.r != nil }
+
+// Value returns the referenced value.  You can use it in any environment
+// until the global reference is freed.
+func (g Global) Value() Value { return g.ref }
+
+// Emacs returns the referenced value.  This allows passing a Global to Emacs.
+// Emacs returns an error if g is the zero Global.
+func (g Global) Emacs(Env) (Value, error) {
+	if !g.Valid() {
+		return Value{}, errors.New("invalid global reference")
+	}
+	return g.ref, nil
+}
+
+// makeGlobalRef returns a global reference to v.  Unlike v, the global
+// reference stays valid after the current environment is gone, until it’s
+// passed to freeGlobalRef.
+func (e Env) makeGlobalRef(v Value) (Value, error) {
+	r := C.phst_emacs_make_global_ref(e.raw(), v.raw())
+	// Global references don’t belong to an environment, so they don’t get
+	// a generation.
+	return Value{r: r.value}, e.check(r.base)
+}
+
+// freeGlobalRef frees a global reference created by makeGlobalRef.
+func (e Env) freeGlobalRef(v Value) error {
+	return e.checkVoid(C.phst_emacs_free_global_ref(e.raw(), v.raw()))
+}
+
+// pendingRefs contains global references whose Go owners have been garbage
+// collected.  Finalizers run on arbitrary goroutines without access to an
+// environment, so they can’t free the references themselves.  Instead, they
+// add them to pendingRefs, and the next call into the module frees them.
+var pendingRefs struct {
+	count int32 // accessed atomically
+
+	mu   sync.Mutex
+	refs []Value
+}
+
+// releaseLater schedules the global reference v to be freed during the next
+// call into the module.  You can call releaseLater safely from multiple
+// goroutines.
+func releaseLater(v Value) {
+	pendingRefs.mu.Lock()
+	defer pendingRefs.mu.Unlock()
+	pendingRefs.refs = append(pendingRefs.refs, v)
+	atomic.StoreInt32(&pendingRefs.count, int32(len(pendingRefs.refs)))
+}
+
+// freePendingRefs frees the global references scheduled using releaseLater.
+func (e Env) freePendingRefs() {
+	if atomic.LoadInt32(&pendingRefs.count) == 0 {
+		return
+	}
+	pendingRefs.mu.Lock()
+	refs := pendingRefs.refs
+	pendingRefs.refs = nil
+	atomic.StoreInt32(&pendingRefs.count, 0)
+	pendingRefs.mu.Unlock()
+	for _, v := range refs {
+		// There’s nothing we could do about an error here.
+		e.freeGlobalRef(v)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+func init() {
+	ERTTest(globalRef)
+}
+
+func globalRef(e Env) error {
+	var g Global
+	store, del, err := e.Lambda(func(e Env, v Value) error {
+		var err error
+		g, err = e.GlobalRef(v)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer del()
+	// Create the global reference in one call and use it in a later one,
+	// after the environment of the first call is gone.
+	if _, err := e.Call("funcall", store, List{Symbol("a"), String("b")}); err != nil {
+		return err
+	}
+	if !g.Valid() {
+		return errors.New("global reference not stored")
+	}
+	defer e.FreeGlobalRef(g)
+	var got String
+	if err := e.CallOut("cadr", &got, g); err != nil {
+		return err
+	}
+	if got != "b" {
+		return fmt.Errorf("(cadr global): got %q, want %q", got, "b")
+	}
+	if err := e.FreeGlobalRef(Global{}); err == nil {
+		return errors.New("freeing the zero Global succeeded")
+	}
+	return nil
+}
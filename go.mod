@@ -1,3 +1,13 @@
 module github.com/phst/emacs
 
-go 1.21
+go 1.22.0
+
+require (
+	golang.org/x/tools v0.26.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+)
@@ -0,0 +1,260 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Handle is an opaque integer that refers to a Go value stored in a
+// [HandleRegistry].  In Emacs, handles are represented as positive integers.
+// Handle implements [In] and [Out], so exported functions can accept and
+// return handles directly.  The zero Handle is never valid.
+type Handle uint64
+
+// Emacs implements [In.Emacs].  It returns h as an Emacs integer.
+func (h Handle) Emacs(e Env) (Value, error) {
+	return Uint(h).Emacs(e)
+}
+
+// FromEmacs implements [Out.FromEmacs].  It sets *h to the integer stored in
+// v.  It returns an error if v isn’t a positive integer that fits into a
+// uint64.  FromEmacs doesn’t check whether the handle is registered; use
+// [HandleRegistry.Get] for that.
+func (h *Handle) FromEmacs(e Env, v Value) error {
+	var u Uint
+	if err := u.FromEmacs(e, v); err != nil {
+		return err
+	}
+	if u == 0 {
+		return WrongTypeArgument("natnump", v)
+	}
+	*h = Handle(u)
+	return nil
+}
+
+// HandleRegistry stores Go values and hands out integer handles for them.
+// Emacs can’t store arbitrary Go values, so exported functions typically
+// register a Go value, return its handle to Emacs, and look the value up
+// again when Emacs passes the handle back.  See the “Handles” example.  If
+// the lifetime of the Go value should be tied to a Lisp object, consider
+// [Env.MakeUserPointer] instead.
+//
+// The zero HandleRegistry is empty and ready to use.  Set the exported fields
+// before calling any method; don’t change them afterwards.  A HandleRegistry
+// is safe for concurrent use.  It must not be copied after first use.
+type HandleRegistry[T any] struct {
+	// Name describes the kind of values in the registry, such as “file”.
+	// It’s only used in error messages.  If empty, error messages use
+	// “handle”.
+	Name string
+
+	// MaxAge is the duration after which unused handles expire.  A handle
+	// is used whenever it’s registered or passed to [HandleRegistry.Get].
+	// Expired handles are removed lazily, when calling any other method.
+	// If MaxAge is zero or negative, handles never expire.
+	MaxAge time.Duration
+
+	// Release is called for values whose handles have expired, outside of
+	// any lock.  Use it to close files or other resources.  If nil, expired
+	// values are simply dropped.
+	Release func(T)
+
+	mu      sync.Mutex
+	last    Handle
+	entries map[Handle]*handleEntry[T]
+	expired uint64
+	now     func() time.Time // for testing
+}
+
+type handleEntry[T any] struct {
+	value    T
+	created  time.Time
+	lastUsed time.Time
+}
+
+// HandleInfo contains diagnostic information about a registered handle.
+type HandleInfo struct {
+	Handle   Handle
+	Created  time.Time
+	LastUsed time.Time
+}
+
+// HandleStats contains diagnostic information about a [HandleRegistry].
+type HandleStats struct {
+	// Live is the number of currently registered handles.
+	Live int
+
+	// Registered is the total number of handles ever registered.
+	Registered uint64
+
+	// Expired is the total number of handles that have expired.
+	Expired uint64
+}
+
+// Register stores v in the registry and returns a new handle for it.  The
+// handle is never zero and never reused.  Register returns an error only if
+// the registry has run out of handles.
+func (r *HandleRegistry[T]) Register(v T) (Handle, error) {
+	r.mu.Lock()
+	now := r.clock()
+	exp := r.expireLocked(now)
+	if r.last == math.MaxUint64 {
+		r.mu.Unlock()
+		r.release(exp)
+		return 0, fmt.Errorf("too many %ss", r.name())
+	}
+	r.last++
+	h := r.last
+	if r.entries == nil {
+		r.entries = make(map[Handle]*handleEntry[T])
+	}
+	r.entries[h] = &handleEntry[T]{v, now, now}
+	r.mu.Unlock()
+	r.release(exp)
+	return h, nil
+}
+
+// Get returns the value stored for h and marks h as used.  If h isn’t
+// registered, Get returns an error that signals go-invalid-handle.
+func (r *HandleRegistry[T]) Get(h Handle) (T, error) {
+	r.mu.Lock()
+	now := r.clock()
+	exp := r.expireLocked(now)
+	ent, ok := r.entries[h]
+	if ok {
+		ent.lastUsed = now
+	}
+	r.mu.Unlock()
+	r.release(exp)
+	if !ok {
+		var zero T
+		return zero, r.invalid(h)
+	}
+	return ent.value, nil
+}
+
+// Pop removes h from the registry and returns the value stored for it.  If h
+// isn’t registered, Pop returns an error that signals go-invalid-handle.
+// Pop doesn’t call [HandleRegistry.Release]; the caller is responsible for
+// releasing the value.
+func (r *HandleRegistry[T]) Pop(h Handle) (T, error) {
+	r.mu.Lock()
+	exp := r.expireLocked(r.clock())
+	ent, ok := r.entries[h]
+	delete(r.entries, h)
+	r.mu.Unlock()
+	r.release(exp)
+	if !ok {
+		var zero T
+		return zero, r.invalid(h)
+	}
+	return ent.value, nil
+}
+
+// Len returns the number of registered handles, after removing expired
+// ones.
+func (r *HandleRegistry[T]) Len() int {
+	r.mu.Lock()
+	exp := r.expireLocked(r.clock())
+	n := len(r.entries)
+	r.mu.Unlock()
+	r.release(exp)
+	return n
+}
+
+// Handles returns diagnostic information about all registered handles,
+// sorted by handle.
+func (r *HandleRegistry[T]) Handles() []HandleInfo {
+	r.mu.Lock()
+	exp := r.expireLocked(r.clock())
+	infos := make([]HandleInfo, 0, len(r.entries))
+	for h, ent := range r.entries {
+		infos = append(infos, HandleInfo{h, ent.created, ent.lastUsed})
+	}
+	r.mu.Unlock()
+	r.release(exp)
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Handle < infos[j].Handle })
+	return infos
+}
+
+// Stats returns diagnostic counters for the registry.
+func (r *HandleRegistry[T]) Stats() HandleStats {
+	r.mu.Lock()
+	exp := r.expireLocked(r.clock())
+	s := HandleStats{len(r.entries), uint64(r.last), r.expired}
+	r.mu.Unlock()
+	r.release(exp)
+	return s
+}
+
+// expireLocked removes expired entries and returns their values.  r.mu must
+// be locked.
+func (r *HandleRegistry[T]) expireLocked(now time.Time) []T {
+	if r.MaxAge <= 0 {
+		return nil
+	}
+	var exp []T
+	for h, ent := range r.entries {
+		if now.Sub(ent.lastUsed) > r.MaxAge {
+			exp = append(exp, ent.value)
+			delete(r.entries, h)
+		}
+	}
+	r.expired += uint64(len(exp))
+	return exp
+}
+
+// release calls r.Release for the given values.  r.mu must not be locked.
+func (r *HandleRegistry[T]) release(vals []T) {
+	if r.Release == nil {
+		return
+	}
+	for _, v := range vals {
+		r.Release(v)
+	}
+}
+
+func (r *HandleRegistry[T]) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+func (r *HandleRegistry[T]) name() string {
+	if r.Name == "" {
+		return "handle"
+	}
+	return r.Name
+}
+
+func (r *HandleRegistry[T]) invalid(h Handle) error {
+	return invalidHandle.Error(String(r.name()), Uint(h))
+}
+
+// IsInvalidHandle returns whether err was returned by a [HandleRegistry]
+// method for a handle that isn’t registered.
+func IsInvalidHandle(err error) bool {
+	var x Error
+	return errors.As(err, &x) && x.Symbol == invalidHandle
+}
+
+var invalidHandle = DefineError("go-invalid-handle", "Invalid handle", baseError)
@@ -15,11 +15,9 @@
 package emacs
 
 import (
-	"errors"
-	"fmt"
-	"math"
 	"os"
-	"sync"
+	"testing"
+	"time"
 )
 
 func Example_handles() {
@@ -32,14 +30,12 @@ HANDLE must be opened using ‘create-go-file’.
 CONTENTS must be a unibyte string.`), Usage("HANDLE CONTENTS"))
 }
 
-type fileHandle Uint
-
-func createGoFile(name string) (fileHandle, error) {
+func createGoFile(name string) (Handle, error) {
 	fd, err := os.Create(name)
 	if err != nil {
 		return 0, err
 	}
-	h, err := registerFile(fd)
+	h, err := files.Register(fd)
 	if err != nil {
 		fd.Close()
 		return 0, err
@@ -47,55 +43,84 @@ func createGoFile(name string) (fileHandle, error) {
 	return h, nil
 }
 
-func closeGoFile(h fileHandle) error {
-	fd := popFile(h)
-	if fd == nil {
-		return fmt.Errorf("close-go-file: invalid file handle %d", h)
+func closeGoFile(h Handle) error {
+	fd, err := files.Pop(h)
+	if err != nil {
+		return err
 	}
 	return fd.Close()
 }
 
-func writeGoFile(h fileHandle, b []byte) (int, error) {
-	fd := getFile(h)
-	if fd == nil {
-		return 0, fmt.Errorf("write-go-file: invalid file handle %d", h)
+func writeGoFile(h Handle, b []byte) (int, error) {
+	fd, err := files.Get(h)
+	if err != nil {
+		return 0, err
 	}
 	return fd.Write(b)
 }
 
-var (
-	fileMu   sync.Mutex
-	files    = make(map[fileHandle]*os.File)
-	nextFile fileHandle
-)
+var files = HandleRegistry[*os.File]{Name: "file"}
 
-func registerFile(fd *os.File) (fileHandle, error) {
-	fileMu.Lock()
-	defer fileMu.Unlock()
-	h := nextFile
-	if h == math.MaxUint64 {
-		fd.Close()
-		return 0, errors.New("too many files")
-	}
-	nextFile++
-	files[h] = fd
-	return h, nil
-}
-
-func getFile(h fileHandle) *os.File {
-	fileMu.Lock()
-	defer fileMu.Unlock()
-	return files[h]
+func init() {
+	Example_handles()
 }
 
-func popFile(h fileHandle) *os.File {
-	fileMu.Lock()
-	defer fileMu.Unlock()
-	fd := files[h]
-	delete(files, h)
-	return fd
+func TestHandleRegistry(t *testing.T) {
+	var r HandleRegistry[string]
+	a, err := r.Register("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := r.Register("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == 0 || b == 0 || a == b {
+		t.Errorf("got handles %d and %d, want distinct nonzero handles", a, b)
+	}
+	if got, err := r.Get(a); err != nil || got != "a" {
+		t.Errorf("Get(%d) = %q, %v; want %q, nil", a, got, err, "a")
+	}
+	if got, err := r.Pop(b); err != nil || got != "b" {
+		t.Errorf("Pop(%d) = %q, %v; want %q, nil", b, got, err, "b")
+	}
+	if _, err := r.Get(b); !IsInvalidHandle(err) {
+		t.Errorf("Get(%d) after Pop: got error %v, want invalid handle", b, err)
+	}
+	if _, err := r.Pop(0); !IsInvalidHandle(err) {
+		t.Errorf("Pop(0): got error %v, want invalid handle", err)
+	}
+	if got, want := r.Stats(), (HandleStats{Live: 1, Registered: 2}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
 }
 
-func init() {
-	Example_handles()
+func TestHandleRegistryExpiry(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	var released []string
+	r := HandleRegistry[string]{
+		MaxAge:  time.Minute,
+		Release: func(s string) { released = append(released, s) },
+		now:     func() time.Time { return now },
+	}
+	a, _ := r.Register("a")
+	b, _ := r.Register("b")
+	now = now.Add(50 * time.Second)
+	if _, err := r.Get(a); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(50 * time.Second)
+	infos := r.Handles()
+	if len(infos) != 1 || infos[0].Handle != a {
+		t.Errorf("Handles() = %+v, want only handle %d", infos, a)
+	}
+	if _, err := r.Get(b); !IsInvalidHandle(err) {
+		t.Errorf("Get(%d) after expiry: got error %v, want invalid handle", b, err)
+	}
+	if len(released) != 1 || released[0] != "b" {
+		t.Errorf("released %q, want [b]", released)
+	}
+	if got, want := r.Stats(), (HandleStats{Live: 1, Registered: 2, Expired: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
 }
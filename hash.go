@@ -14,7 +14,10 @@
 
 package emacs
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 // HashTest represents a test function for an Emacs hash table.
 type HashTest Symbol
@@ -212,7 +215,15 @@ func (m makeHash) Emacs(e Env) (Value, error) {
 	}
 	for _, key := range m.MapKeys() {
 		val := m.MapIndex(key)
-		if err := e.Puthash(m.key(key), m.value(val), r); err != nil {
+		k, err := m.key(key).Emacs(e)
+		if err != nil {
+			return Value{}, conversionContext(err, "map key", key.Type())
+		}
+		v, err := m.value(val).Emacs(e)
+		if err != nil {
+			return Value{}, conversionContext(err, fmt.Sprintf("map value for key %v", key), val.Type())
+		}
+		if err := e.Puthash(k, v, r); err != nil {
 			return Value{}, err
 		}
 	}
@@ -234,26 +245,34 @@ func (g getHash) FromEmacs(e Env, v Value) error {
 	u := g.Elem()
 	t := u.Type()
 	m := reflect.MakeMap(t)
+	// Keep the original conversion error, since passing it through
+	// maphash would turn it into a Signal.
+	var convErr error
 	f := func(rawKey, rawVal Value) error {
 		key := reflect.New(t.Key())
 		if err := g.key(key).FromEmacs(e, rawKey); err != nil {
-			return err
+			convErr = conversionContext(err, "map key", t.Key())
+			return convErr
 		}
 		val := reflect.New(t.Elem())
 		if err := g.value(val).FromEmacs(e, rawVal); err != nil {
-			return err
+			convErr = conversionContext(err, fmt.Sprintf("map value for key %v", key.Elem()), t.Elem())
+			return convErr
 		}
 		m.SetMapIndex(key.Elem(), val.Elem())
 		return nil
 	}
 	if err := e.Maphash(f, v); err != nil {
+		if convErr != nil {
+			return convErr
+		}
 		return err
 	}
 	u.Set(m)
 	return nil
 }
 
-var customHashTests = NewManager(RequireName | RequireUniqueName | DefineOnInit)
+var customHashTests = NewManagerOf[customHashTest](RequireName | RequireUniqueName | DefineOnInit)
 
 type customHashTest struct {
 	name HashTest
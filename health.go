@@ -0,0 +1,179 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// HealthReport is a snapshot of the runtime state of the module.  It’s meant
+// as a first diagnostic step if a module becomes slow or leaks memory.  Use
+// [Health] to create a HealthReport.
+type HealthReport struct {
+	// Functions is the number of named exported functions.
+	Functions int
+
+	// Lambdas is the number of live anonymous functions, for example
+	// created by [Env.Lambda].  A steadily growing number indicates that
+	// a [DeleteFunc] isn’t called.
+	Lambdas int
+
+	// HookFunctions and Advice are the numbers of Go functions added
+	// using [Hook.Add] and [Env.AdviceAdd], respectively, that haven’t
+	// been removed yet.
+	HookFunctions, Advice int
+
+	// UserPointers is the number of Go values represented by user
+	// pointers created by [Env.MakeUserPointer] that Emacs hasn’t
+	// garbage-collected yet.
+	UserPointers int
+
+	// CachedSymbols is the number of interned symbols cached in global
+	// references; see [Env.Intern].
+	CachedSymbols int
+
+	// Goroutines is the number of goroutines, as returned by
+	// [runtime.NumGoroutine].
+	Goroutines int
+
+	// AsyncRunning is the number of asynchronous operations started
+	// using [Async.Start] that haven’t produced a result yet.
+	// AsyncQueued is the number of results that haven’t been returned
+	// from [Async.Flush] yet.
+	AsyncRunning, AsyncQueued int64
+
+	// HeapAlloc, HeapObjects, Sys, NumGC, and PauseTotal are taken from
+	// [runtime.MemStats].
+	HeapAlloc, HeapObjects, Sys uint64
+	NumGC                       uint32
+	PauseTotal                  time.Duration
+}
+
+// Health returns a snapshot of the runtime state of the module.  Health
+// stops the world briefly to read memory statistics; see
+// [runtime.ReadMemStats].  You can call Health safely from multiple
+// goroutines.
+func Health() HealthReport {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	named, anon := funcs.counts()
+	return HealthReport{
+		Functions:     named,
+		Lambdas:       anon,
+		HookFunctions: len(hooks.get("")),
+		Advice:        len(advices.all()),
+		UserPointers:  userPtrs.len(),
+		CachedSymbols: symbols.len(),
+		Goroutines:    runtime.NumGoroutine(),
+		AsyncRunning:  atomic.LoadInt64(&asyncStats.running),
+		AsyncQueued:   atomic.LoadInt64(&asyncStats.queued),
+		HeapAlloc:     m.HeapAlloc,
+		HeapObjects:   m.HeapObjects,
+		Sys:           m.Sys,
+		NumGC:         m.NumGC,
+		PauseTotal:    time.Duration(m.PauseTotalNs),
+	}
+}
+
+// Emacs returns a property list with the keys :functions, :lambdas,
+// :hook-functions, :advice, :user-pointers, :cached-symbols, :goroutines,
+// :async-running, :async-queued, :heap-alloc, :heap-objects, :sys, :num-gc,
+// and :pause-total.  The pause total is a floating-point number of seconds; all
+// other values are integers.
+func (r HealthReport) Emacs(e Env) (Value, error) {
+	return List{
+		Symbol(":functions"), Int(r.Functions),
+		Symbol(":lambdas"), Int(r.Lambdas),
+		Symbol(":hook-functions"), Int(r.HookFunctions),
+		Symbol(":advice"), Int(r.Advice),
+		Symbol(":user-pointers"), Int(r.UserPointers),
+		Symbol(":cached-symbols"), Int(r.CachedSymbols),
+		Symbol(":goroutines"), Int(r.Goroutines),
+		Symbol(":async-running"), Int(r.AsyncRunning),
+		Symbol(":async-queued"), Int(r.AsyncQueued),
+		Symbol(":heap-alloc"), Uint(r.HeapAlloc),
+		Symbol(":heap-objects"), Uint(r.HeapObjects),
+		Symbol(":sys"), Uint(r.Sys),
+		Symbol(":num-gc"), Uint(r.NumGC),
+		Symbol(":pause-total"), Float(r.PauseTotal.Seconds()),
+	}.Emacs(e)
+}
+
+// String formats the report for human consumption, one item per line.
+func (r HealthReport) String() string {
+	var b strings.Builder
+	for _, item := range []struct {
+		label string
+		value interface{}
+	}{
+		{"Named functions", r.Functions},
+		{"Anonymous functions", r.Lambdas},
+		{"Hook functions", r.HookFunctions},
+		{"Advice", r.Advice},
+		{"User pointers", r.UserPointers},
+		{"Cached symbols", r.CachedSymbols},
+		{"Goroutines", r.Goroutines},
+		{"Running async operations", r.AsyncRunning},
+		{"Queued async results", r.AsyncQueued},
+		{"Heap bytes allocated", r.HeapAlloc},
+		{"Heap objects", r.HeapObjects},
+		{"Bytes obtained from OS", r.Sys},
+		{"GC cycles", r.NumGC},
+		{"Total GC pause", r.PauseTotal},
+	} {
+		fmt.Fprintf(&b, "%-26s %v\n", item.label+":", item.value)
+	}
+	return b.String()
+}
+
+// ExportHealthCommand arranges for an interactive command with the given name
+// to be defined once the module is loaded.  The command returns the result of
+// [Health] as a property list; see [HealthReport.Emacs].  When called
+// interactively, it also displays the report in a buffer.  Call
+// ExportHealthCommand in an init function.
+func ExportHealthCommand(name Name) {
+	OnInit(func(e Env) error {
+		return e.exportCommands([]command{
+			{name, Arity{0, 1}, func(e Env, args []Value) (Value, error) {
+				r := Health()
+				if len(args) > 0 && e.IsNotNil(args[0]) {
+					if _, err := e.showReport("*"+string(name)+"*", r.String()); err != nil {
+						return Value{}, err
+					}
+				}
+				return r.Emacs(e)
+			}, "Return a report about the runtime state of the Go module.\nIf DISPLAY is non-nil, also display the report in a buffer.\n\n(fn &optional DISPLAY)", List{Symbol("list"), T}},
+		})
+	})
+}
+
+// showReport displays text in the buffer with the given name, replacing its
+// previous contents.  It returns the window displaying the buffer.
+func (e Env) showReport(buffer, text string) (Value, error) {
+	if _, err := e.Eval(List{
+		Symbol("with-current-buffer"), List{Symbol("get-buffer-create"), String(buffer)},
+		List{Symbol("let"), List{List{Symbol("inhibit-read-only"), T}}, List{Symbol("erase-buffer")}},
+	}); err != nil {
+		return Value{}, err
+	}
+	if err := e.appendToBuffer(buffer, text); err != nil {
+		return Value{}, err
+	}
+	return e.Call("display-buffer", String(buffer))
+}
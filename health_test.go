@@ -0,0 +1,52 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHealthAsync(t *testing.T) {
+	notify := make(chan struct{}, 1)
+	a := NewAsync(notify)
+	before := Health()
+	_, ch := a.Start()
+	if got, want := Health().AsyncRunning, before.AsyncRunning+1; got != want {
+		t.Errorf("AsyncRunning after Start: got %d, want %d", got, want)
+	}
+	ch <- Result{Value: Int(1)}
+	<-notify
+	r := Health()
+	if r.AsyncRunning != before.AsyncRunning {
+		t.Errorf("AsyncRunning after result: got %d, want %d", r.AsyncRunning, before.AsyncRunning)
+	}
+	if got, want := r.AsyncQueued, before.AsyncQueued+1; got != want {
+		t.Errorf("AsyncQueued after result: got %d, want %d", got, want)
+	}
+	if n := len(a.Flush()); n != 1 {
+		t.Fatalf("Flush returned %d results, want 1", n)
+	}
+	if got := Health().AsyncQueued; got != before.AsyncQueued {
+		t.Errorf("AsyncQueued after Flush: got %d, want %d", got, before.AsyncQueued)
+	}
+}
+
+func TestHealthReportString(t *testing.T) {
+	s := HealthReport{Lambdas: 42}.String()
+	if !strings.Contains(s, "Anonymous functions:") || !strings.Contains(s, " 42\n") {
+		t.Errorf("String() = %q, want it to mention 42 anonymous functions", s)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Hook is the name of a hook variable, such as after-save-hook.  See
+// [Hooks].
+//
+// Use [Hook.Add] to add Go functions to a hook.  Unlike adding a function
+// returned by [Env.Lambda] with add-hook, Hook.Add keeps track of the Go
+// function, so that [Hook.Remove] and [RemoveAllHooks] can remove it again
+// and release the Go function.
+//
+// [Hooks]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Hooks.html
+type Hook Name
+
+// HookOptions contains optional arguments for [Hook.Add].
+type HookOptions struct {
+	// Depth determines the position of the function in the hook.  It
+	// should be between -100 and 100.  Functions with lower depths run
+	// first.  See add-hook for details.
+	Depth int
+
+	// If Local is true, add the function to the buffer-local value of the
+	// hook in the current buffer.
+	Local bool
+}
+
+// HookFunc identifies a Go function added to a hook.  The zero HookFunc
+// doesn’t identify any function.
+type HookFunc struct {
+	hook  Hook
+	name  Name
+	index funcIndex
+	local bool
+}
+
+// Name returns the name of the function symbol that represents f in the hook.
+func (f HookFunc) Name() Name {
+	return f.name
+}
+
+// Add adds the Go function fun to the hook h.  fun is converted as described
+// in [AutoLambda].  Hook functions are typically functions without arguments,
+// but abnormal hooks pass arguments to their functions.  Add defines a
+// function symbol whose name starts with h, followed by “--go-” and a number,
+// and adds that symbol to the hook.  This makes the hook value readable and
+// allows removing the function later.  Add returns a [HookFunc] that you can
+// pass to [Hook.Remove].
+func (h Hook) Add(e Env, fun interface{}, opts HookOptions) (HookFunc, error) {
+	return h.add(e, AutoLambda(fun), opts)
+}
+
+func (h Hook) add(e Env, l Lambda, opts HookOptions) (HookFunc, error) {
+	name, index, err := e.exportNumbered(string(h)+"--go-", l)
+	if err != nil {
+		return HookFunc{}, err
+	}
+	r := HookFunc{h, name, index, opts.Local}
+	if _, err := e.Call("add-hook", Name(h), name, Int(opts.Depth), Bool(opts.Local)); err != nil {
+		e.releaseNumbered(name, index)
+		return HookFunc{}, err
+	}
+	hooks.add(r)
+	return r, nil
+}
+
+// HookOption is an option for [Env.AddHook] and [AddHook].  Its
+// implementations are [HookDepth] and [HookLocal].
+type HookOption interface {
+	applyHook(*HookOptions)
+}
+
+// HookDepth is a [HookOption] that determines the position of the function in
+// the hook; see [HookOptions.Depth].
+type HookDepth int
+
+// HookLocal is a [HookOption] that adds the function to the buffer-local value
+// of the hook in the current buffer; see [HookOptions.Local].
+type HookLocal struct{}
+
+func (d HookDepth) applyHook(o *HookOptions) { o.Depth = int(d) }
+func (HookLocal) applyHook(o *HookOptions)   { o.Local = true }
+
+// AddHook adds the Go function fun to the hook variable name.  It’s a
+// shorthand for [Hook.Add] that accepts options as [HookOption] values.  Use
+// [Env.RemoveHook] to remove the function again.
+func (e Env) AddHook(name Name, fun interface{}, opts ...HookOption) (HookFunc, error) {
+	return Hook(name).add(e, AutoLambda(fun), hookOptions(opts))
+}
+
+// RemoveHook removes a function added by [Env.AddHook], [AddHook], or
+// [Hook.Add] from its hook and releases the Go function, like [Hook.Remove].
+func (e Env) RemoveHook(f HookFunc) error {
+	return f.hook.Remove(e, f)
+}
+
+// AddHook arranges for the Go function fun to be added to the hook variable
+// name once the module is loaded, like [Env.AddHook].  Call AddHook in an init
+// function.  AddHook panics if fun isn’t convertible as described in
+// [AutoLambda].  Use [Hook.Functions] to find the added function later, for
+// example to remove it.  A [HookLocal] option refers to the buffer that’s
+// current while Emacs loads the module, which is rarely useful.
+func AddHook(name Name, fun interface{}, opts ...HookOption) {
+	l := AutoLambda(fun)
+	o := hookOptions(opts)
+	OnInit(func(e Env) error {
+		_, err := Hook(name).add(e, l, o)
+		return err
+	})
+}
+
+func hookOptions(opts []HookOption) HookOptions {
+	var r HookOptions
+	for _, opt := range opts {
+		opt.applyHook(&r)
+	}
+	return r
+}
+
+// Remove removes a function added by [Hook.Add] from the hook h and releases
+// the Go function.  If the function was added to the buffer-local value of
+// the hook, Remove removes it from the current buffer, which should be the
+// buffer that was current when adding the function.  Remove replaces the
+// function definition with ignore, so that copies of the function remaining
+// in other buffers don’t fail.  Removing a function that has already been
+// removed does nothing.
+func (h Hook) Remove(e Env, f HookFunc) error {
+	if f.hook != h {
+		return fmt.Errorf("function %s doesn’t belong to hook %s", f.name, h)
+	}
+	if !hooks.remove(f) {
+		return nil
+	}
+	_, err := e.Call("remove-hook", Name(h), f.name, Bool(f.local))
+	if relErr := e.releaseNumbered(f.name, f.index); err == nil {
+		err = relErr
+	}
+	return err
+}
+
+// Functions returns all functions that have been added to h using
+// [Hook.Add] and not yet removed, in the order in which they were added.
+func (h Hook) Functions() []HookFunc {
+	return hooks.get(h)
+}
+
+// RemoveAllHooks removes all functions that have been added using
+// [Hook.Add] from their hooks, as if calling [Hook.Remove] for each of them.
+// This is useful when unloading a module.  RemoveAllHooks returns the first
+// error encountered, but tries to remove all functions.
+func RemoveAllHooks(e Env) error {
+	var err error
+	for _, f := range hooks.get("") {
+		if rmErr := f.hook.Remove(e, f); err == nil {
+			err = rmErr
+		}
+	}
+	return err
+}
+
+// hookRegistry keeps track of functions added to hooks.
+type hookRegistry struct {
+	mu    sync.Mutex
+	funcs map[funcIndex]HookFunc
+}
+
+func (r *hookRegistry) add(f HookFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.funcs == nil {
+		r.funcs = make(map[funcIndex]HookFunc)
+	}
+	r.funcs[f.index] = f
+}
+
+// remove removes f from the registry and returns whether it was present.
+func (r *hookRegistry) remove(f HookFunc) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.funcs[f.index]; !ok {
+		return false
+	}
+	delete(r.funcs, f.index)
+	return true
+}
+
+// get returns the registered functions for hook h, or all registered
+// functions if h is empty, in the order in which they were added.
+func (r *hookRegistry) get(h Hook) []HookFunc {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var s []HookFunc
+	for _, f := range r.funcs {
+		if h == "" || f.hook == h {
+			s = append(s, f)
+		}
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].index < s[j].index })
+	return s
+}
+
+var hooks hookRegistry
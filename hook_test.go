@@ -0,0 +1,166 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	ERTTest(hookAddRemove)
+	ERTTest(hookLocal)
+	ERTTest(hookEnvAdd)
+	AddHook("emacs-go-test-queued-hook", func() { queuedHookCalls++ }, HookDepth(50))
+	ERTTest(hookQueued)
+}
+
+var queuedHookCalls int
+
+func hookAddRemove(e Env) error {
+	const hook Hook = "emacs-go-test-hook"
+	if _, err := e.Eval(List{Symbol("defvar"), Name(hook), Nil}); err != nil {
+		return err
+	}
+	var order []string
+	second, err := hook.Add(e, func() { order = append(order, "second") }, HookOptions{Depth: 10})
+	if err != nil {
+		return err
+	}
+	first, err := hook.Add(e, func() { order = append(order, "first") }, HookOptions{})
+	if err != nil {
+		return err
+	}
+	if n := len(hook.Functions()); n != 2 {
+		return fmt.Errorf("got %d registered functions, want 2", n)
+	}
+	if _, err := e.Call("run-hooks", Name(hook)); err != nil {
+		return err
+	}
+	if fmt.Sprint(order) != "[first second]" {
+		return fmt.Errorf("hook functions ran in order %v, want [first second]", order)
+	}
+	if err := hook.Remove(e, first); err != nil {
+		return err
+	}
+	if err := hook.Remove(e, second); err != nil {
+		return err
+	}
+	// Removing a function twice is allowed.
+	if err := hook.Remove(e, second); err != nil {
+		return err
+	}
+	order = nil
+	if _, err := e.Call("run-hooks", Name(hook)); err != nil {
+		return err
+	}
+	if len(order) != 0 {
+		return fmt.Errorf("removed hook functions still ran: %v", order)
+	}
+	if n := len(hook.Functions()); n != 0 {
+		return fmt.Errorf("got %d registered functions after removal, want 0", n)
+	}
+	return nil
+}
+
+func hookLocal(e Env) error {
+	const hook Hook = "emacs-go-test-local-hook"
+	if _, err := e.Eval(List{Symbol("defvar"), Name(hook), Nil}); err != nil {
+		return err
+	}
+	calls := 0
+	buffer, err := e.Call("generate-new-buffer", String("emacs-go-test-hook"))
+	if err != nil {
+		return err
+	}
+	defer e.Call("kill-buffer", buffer)
+	old, err := e.Call("current-buffer")
+	if err != nil {
+		return err
+	}
+	defer e.Call("set-buffer", old)
+	if _, err := e.Call("set-buffer", buffer); err != nil {
+		return err
+	}
+	f, err := hook.Add(e, func() { calls++ }, HookOptions{Local: true})
+	if err != nil {
+		return err
+	}
+	var local Bool
+	if err := e.CallOut("local-variable-p", &local, Name(hook)); err != nil {
+		return err
+	}
+	if !local {
+		return fmt.Errorf("%s isn’t buffer-local", hook)
+	}
+	if err := hook.Remove(e, f); err != nil {
+		return err
+	}
+	if _, err := e.Call("run-hooks", Name(hook)); err != nil {
+		return err
+	}
+	if calls != 0 {
+		return fmt.Errorf("removed hook function ran %d times", calls)
+	}
+	return nil
+}
+
+func hookEnvAdd(e Env) error {
+	const hook = "emacs-go-test-env-hook"
+	if _, err := e.Eval(List{Symbol("defvar"), Name(hook), Nil}); err != nil {
+		return err
+	}
+	calls := 0
+	f, err := e.AddHook(hook, func() { calls++ }, HookDepth(-10))
+	if err != nil {
+		return err
+	}
+	if _, err := e.Call("run-hooks", Name(hook)); err != nil {
+		return err
+	}
+	if err := e.RemoveHook(f); err != nil {
+		return err
+	}
+	if _, err := e.Call("run-hooks", Name(hook)); err != nil {
+		return err
+	}
+	if calls != 1 {
+		return fmt.Errorf("hook function called %d times, want 1", calls)
+	}
+	return nil
+}
+
+func hookQueued(e Env) error {
+	const hook Hook = "emacs-go-test-queued-hook"
+	before := queuedHookCalls
+	if _, err := e.Call("run-hooks", Name(hook)); err != nil {
+		return err
+	}
+	if got, want := queuedHookCalls, before+1; got != want {
+		return fmt.Errorf("queued hook function called %d times, want %d", got, want)
+	}
+	fs := hook.Functions()
+	if len(fs) != 1 {
+		return fmt.Errorf("got %d registered functions, want 1", len(fs))
+	}
+	return e.RemoveHook(fs[0])
+}
+
+func TestHookOptions(t *testing.T) {
+	got := hookOptions([]HookOption{HookDepth(90), HookLocal{}})
+	if want := (HookOptions{Depth: 90, Local: true}); got != want {
+		t.Errorf("hookOptions: got %+v, want %+v", got, want)
+	}
+}
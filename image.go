@@ -0,0 +1,123 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "bytes"
+
+// Image is an [In] that creates an Emacs image descriptor from image data in
+// memory using create-image.  See [Defining Images].
+//
+// [Defining Images]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Defining-Images.html
+type Image struct {
+	// Data contains the encoded image, for example in PNG or SVG format.
+	Data []byte
+
+	// Type is the image type, for example png or svg.  If empty, Emacs
+	// determines the type from the data.
+	Type Symbol
+
+	// Scale is the scaling factor for the image.  If zero, Emacs uses its
+	// default scaling.
+	Scale float64
+
+	// Properties are additional image properties, as alternating keywords
+	// and values.
+	Properties []In
+}
+
+// Emacs calls create-image and returns the resulting image descriptor.
+func (i Image) Emacs(e Env) (Value, error) {
+	var typ In = Nil
+	if i.Type != "" {
+		typ = i.Type
+	}
+	args := []In{Bytes(i.Data), typ, T}
+	if i.Scale != 0 {
+		args = append(args, Symbol(":scale"), Float(i.Scale))
+	}
+	args = append(args, i.Properties...)
+	return e.Call("create-image", args...)
+}
+
+// InsertImage inserts the image img at point in the current buffer using
+// insert-image.  img can be an [Image] or an existing image descriptor.  alt
+// is the text that Emacs displays if it can’t display images; if empty, Emacs
+// uses a default.
+func (e Env) InsertImage(img In, alt string) error {
+	var s In = Nil
+	if alt != "" {
+		s = String(alt)
+	}
+	_, err := e.Call("insert-image", img, s)
+	return err
+}
+
+// ImageWriter is an [io.Writer] that collects encoded image data, for example
+// from [image/png.Encode], and displays it in an Emacs buffer.  Because Go
+// functions can’t interact with Emacs outside of an environment, writing only
+// collects the data; call [ImageWriter.Display] to show the image.  The zero
+// ImageWriter isn’t usable; set at least the Buffer field.
+type ImageWriter struct {
+	// Buffer is the name of the buffer that displays the image.  Display
+	// creates the buffer if necessary.
+	Buffer string
+
+	// Type and Scale are as in [Image].
+	Type  Symbol
+	Scale float64
+
+	data bytes.Buffer
+}
+
+// Write appends p to the image data.  It never returns an error.
+func (w *ImageWriter) Write(p []byte) (int, error) {
+	return w.data.Write(p)
+}
+
+// Reset discards the image data written so far.
+func (w *ImageWriter) Reset() {
+	w.data.Reset()
+}
+
+// Display replaces the contents of the buffer named w.Buffer with the image
+// written so far, displays the buffer using display-buffer, and then resets
+// the image data.  Display returns the buffer object.
+func (w *ImageWriter) Display(e Env) (Value, error) {
+	img, err := Image{Data: w.data.Bytes(), Type: w.Type, Scale: w.Scale}.Emacs(e)
+	if err != nil {
+		return Value{}, err
+	}
+	buf, err := e.Call("get-buffer-create", String(w.Buffer))
+	if err != nil {
+		return Value{}, err
+	}
+	// Can’t use Call because with-current-buffer is a macro.
+	if _, err := e.Eval(List{
+		Symbol("with-current-buffer"), buf,
+		List{
+			Symbol("let"), List{List{Symbol("inhibit-read-only"), T}},
+			List{Symbol("erase-buffer")},
+			List{Symbol("insert-image"), List{Symbol("quote"), img}},
+			List{Symbol("goto-char"), List{Symbol("point-min")}},
+		},
+	}); err != nil {
+		return Value{}, err
+	}
+	if _, err := e.Call("display-buffer", buf); err != nil {
+		return Value{}, err
+	}
+	w.data.Reset()
+	return buf, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+func init() {
+	ERTTest(createImage)
+	ERTTest(imageWriter)
+}
+
+func createImage(e Env) error {
+	img, err := Image{Data: []byte("<svg/>"), Type: "svg", Scale: 2}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	var head Symbol
+	if err := e.CallOut("car-safe", &head, img); err != nil {
+		return err
+	}
+	if head != "image" {
+		return fmt.Errorf("create-image: got %s, want image descriptor", e.FormatMessage("%S", img))
+	}
+	props, err := e.Call("cdr", img)
+	if err != nil {
+		return err
+	}
+	var typ Symbol
+	if err := e.CallOut("plist-get", &typ, props, Symbol(":type")); err != nil {
+		return err
+	}
+	if typ != "svg" {
+		return fmt.Errorf("image type: got %s, want svg", typ)
+	}
+	var scale Float
+	if err := e.CallOut("plist-get", &scale, props, Symbol(":scale")); err != nil {
+		return err
+	}
+	if scale != 2 {
+		return fmt.Errorf("image scale: got %v, want 2", scale)
+	}
+	return nil
+}
+
+func imageWriter(e Env) error {
+	const name = " *go-image-writer-test*"
+	m := image.NewGray(image.Rect(0, 0, 4, 4))
+	m.Set(1, 1, color.White)
+	w := &ImageWriter{Buffer: name, Type: "png"}
+	if err := png.Encode(w, m); err != nil {
+		return err
+	}
+	buf, err := w.Display(e)
+	if err != nil {
+		return err
+	}
+	defer e.Call("kill-buffer", buf)
+	display, err := e.Eval(List{
+		Symbol("with-current-buffer"), buf,
+		List{Symbol("get-text-property"), List{Symbol("point-min")}, List{Symbol("quote"), Symbol("display")}},
+	})
+	if err != nil {
+		return err
+	}
+	var head Symbol
+	if err := e.CallOut("car-safe", &head, display); err != nil {
+		return err
+	}
+	if head != "image" {
+		return fmt.Errorf("display property: got %s, want image descriptor", e.FormatMessage("%S", display))
+	}
+	return nil
+}
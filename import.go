@@ -50,6 +50,9 @@ func Import(name Name, fp interface{}) {
 	r := importAuto{name: name}
 	fun := reflect.ValueOf(fp).Elem()
 	t := fun.Type()
+	if t.Kind() != reflect.Func {
+		panic(fmt.Errorf("can’t import %s: %s isn’t a function type", name, t))
+	}
 	numIn := t.NumIn()
 	if numIn <= 0 || t.In(0) != envType {
 		panic(fmt.Errorf("can’t import %s: function doesn’t accept an Env argument", name))
@@ -87,6 +90,20 @@ func Import(name Name, fp interface{}) {
 	fun.Set(reflect.MakeFunc(t, r.call))
 }
 
+// ImportT is like [Import], but returns the imported function instead of
+// setting a variable.  F must be a function type of one of the forms described
+// for Import; otherwise, ImportT panics.  Typically you should initialize a
+// global variable with the return value of ImportT:
+//
+//	var insert = emacs.ImportT[func(emacs.Env, string) error]("insert")
+//
+// You can call ImportT safely from multiple goroutines.
+func ImportT[F any](name Name) F {
+	var f F
+	Import(name, &f)
+	return f
+}
+
 // ImportFunc imports an Emacs function as a Go function.  name must be the
 // Emacs symbol name of the function.  ImportFunc returns a new [Func] that
 // calls the Emacs function name.  Unlike [Import], there is no type
@@ -115,13 +132,16 @@ type importAuto struct {
 func (i importAuto) call(in []reflect.Value) (out []reflect.Value) {
 	e := in[0].Interface().(Env)
 	var args []In
+	var types []reflect.Type
 	for i, conv := range i.inConv {
 		args = append(args, conv(in[i+1]))
+		types = append(types, in[i+1].Type())
 	}
 	if conv := i.varConv; conv != nil {
 		last := in[len(in)-1]
 		for i := 0; i < last.Len(); i++ {
 			args = append(args, conv(last.Index(i)))
+			types = append(types, last.Type().Elem())
 		}
 	}
 	var ret Out
@@ -132,10 +152,41 @@ func (i importAuto) call(in []reflect.Value) (out []reflect.Value) {
 		out = append(out, o.Elem())
 		ret = i.outConv(o)
 	}
+	if DebugMode() {
+		for j, a := range args {
+			args[j] = conversionIn{a, fmt.Sprintf("argument %d", j+1), types[j]}
+		}
+		ret = conversionOut{ret, "result", i.retType}
+	}
 	err := e.CallOut(i.name, ret, args...)
 	return append(out, reflect.ValueOf(&err).Elem())
 }
 
+// conversionIn wraps an [In] so that conversion errors get context in debug
+// mode.  See [conversionContext].
+type conversionIn struct {
+	In
+	step string
+	t    reflect.Type
+}
+
+func (c conversionIn) Emacs(e Env) (Value, error) {
+	v, err := c.In.Emacs(e)
+	return v, conversionContext(err, c.step, c.t)
+}
+
+// conversionOut wraps an [Out] so that conversion errors get context in debug
+// mode.  See [conversionContext].
+type conversionOut struct {
+	Out
+	step string
+	t    reflect.Type
+}
+
+func (c conversionOut) FromEmacs(e Env, v Value) error {
+	return conversionContext(c.Out.FromEmacs(e, v), c.step, c.t)
+}
+
 type importFunc struct{ name Name }
 
 func (i importFunc) call(e Env, args []Value) (Value, error) {
@@ -37,6 +37,24 @@ var (
 	invalidType func(Env, chan int) error
 )
 
+func ExampleImportT() {
+	Export(goPrintFormattedNow)
+
+	// ImportT panics if its type argument isn’t a function type.
+	defer func() { fmt.Println("panic:", recover()) }()
+	ImportT[int]("format-time-string")
+	// Output:
+	// panic: can’t import format-time-string: int isn’t a function type
+}
+
+// formatTime is the same as formatTimeString, but without a separate
+// assignment.
+var formatTime = ImportT[func(Env, string, time.Time, bool) (string, error)]("format-time-string")
+
+func goPrintFormattedNow(e Env, format string) (string, error) {
+	return formatTime(e, format, time.Now(), true)
+}
+
 func goPrintNow(e Env, format string) (string, error) {
 	// Functions that have access to a live environment can now call the
 	// Emacs message function, like so:
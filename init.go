@@ -1,4 +1,4 @@
-// Copyright 2019, 2021, 2023 Google LLC
+// Copyright 2019, 2021, 2023, 2026 Google LLC
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -54,12 +54,16 @@ func phst_emacs_init(env *C.emacs_env) (r C.struct_phst_emacs_init_result) {
 	// https://www.gnu.org/software/emacs/manual/html_node/elisp/Module-Functions.html.
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
-	e := Env{env}
+	e, tracked := enter(env)
+	defer e.exit(tracked)
 	// Don’t allow Go panics to crash Emacs.
-	defer protect(e, &r.base)
+	defer protect(e, nil, &r.base)
 	if err := majorVersion.init(e); err != nil {
 		return C.struct_phst_emacs_init_result{e.signal(err)}
 	}
+	if err := requirements.check(e, majorVersion.load()); err != nil {
+		return C.struct_phst_emacs_init_result{e.signal(err)}
+	}
 	err := inits.DefineQueued(e)
 	return C.struct_phst_emacs_init_result{e.signal(err)}
 }
@@ -23,6 +23,7 @@ import (
 	"math"
 	"math/big"
 	"reflect"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -50,14 +51,14 @@ func (i *Int) FromEmacs(e Env, v Value) error {
 // Int returns the integer stored in v.  It returns an error if v is not an
 // integer, or if it doesn’t fit into an int64.
 func (e Env) Int(v Value) (int64, error) {
-	i := C.phst_emacs_extract_integer(e.raw(), v.r)
+	i := C.phst_emacs_extract_integer(e.raw(), v.raw())
 	return int64(i.value), e.check(i.base)
 }
 
 // BigInt sets z to the integer stored in v.  It returns an error if v is not
 // an integer.
 func (e Env) BigInt(v Value, z *big.Int) error {
-	r := C.phst_emacs_extract_big_integer(e.raw(), v.r)
+	r := C.phst_emacs_extract_big_integer(e.raw(), v.raw())
 	if err := e.check(r.base); err != nil {
 		return err
 	}
@@ -142,6 +143,9 @@ func intOut(v reflect.Value) Out { return reflectInt(v) }
 type reflectInt reflect.Value
 
 func (r reflectInt) FromEmacs(e Env, v Value) error {
+	if clampIntegers() {
+		return clampFromEmacs(e, v, reflect.Value(r).Elem())
+	}
 	i, err := e.Int(v)
 	if err != nil {
 		return err
@@ -160,6 +164,9 @@ func uintOut(v reflect.Value) Out { return reflectUint(v) }
 type reflectUint reflect.Value
 
 func (r reflectUint) FromEmacs(e Env, v Value) error {
+	if clampIntegers() {
+		return clampFromEmacs(e, v, reflect.Value(r).Elem())
+	}
 	i, err := e.Uint(v)
 	if err != nil {
 		return err
@@ -171,3 +178,125 @@ func (r reflectUint) FromEmacs(e Env, v Value) error {
 	s.SetUint(i)
 	return nil
 }
+
+// IntegerPolicy specifies how conversions from Emacs integers to sized Go
+// integer types such as int8 or uint32 handle values that don’t fit into the
+// target type.  Use [SetIntegerPolicy] to change the policy.
+type IntegerPolicy int32
+
+const (
+	// RejectOverflow causes such conversions to fail with an error of
+	// type overflow-error.  This is the default.
+	RejectOverflow IntegerPolicy = iota
+
+	// ClampOverflow causes such conversions to clamp the value to the
+	// range of the target type.  For example, converting 300 to an int8
+	// results in 127, and converting -1 to a uint results in 0.
+	ClampOverflow
+)
+
+// SetIntegerPolicy sets the policy for integers that don’t fit into their
+// target type.  The policy applies to the conversions of Go integer types
+// described in the package documentation, but not to [Int], [Uint],
+// [Env.Int], and [Env.Uint], which always reject values that don’t fit.  To
+// clamp a single value and find out whether clamping was necessary, use
+// [Clamped] instead.  You can call SetIntegerPolicy safely from multiple
+// goroutines.
+func SetIntegerPolicy(p IntegerPolicy) {
+	atomic.StoreInt32(&integerPolicy, int32(p))
+}
+
+var integerPolicy int32
+
+func clampIntegers() bool {
+	return IntegerPolicy(atomic.LoadInt32(&integerPolicy)) == ClampOverflow
+}
+
+// Clamped is an [Out] that converts an Emacs integer to a Go integer of type
+// T, clamping the value to the range of T if necessary.  For example:
+//
+//	var c emacs.Clamped[uint8]
+//	if err := e.CallOut("length", &c, list); err != nil {
+//		return err
+//	}
+//	if c.Clamped {
+//		// The list has more than 255 elements.
+//	}
+type Clamped[T integer] struct {
+	// Value is the converted value.
+	Value T
+
+	// Clamped specifies whether the Emacs integer didn’t fit into T.
+	Clamped bool
+}
+
+// FromEmacs sets c.Value to the integer stored in v, clamped to the range of
+// T, and c.Clamped to whether clamping was necessary.  It returns an error if
+// v is not an integer.
+func (c *Clamped[T]) FromEmacs(e Env, v Value) error {
+	var z big.Int
+	if err := e.BigInt(v, &z); err != nil {
+		return err
+	}
+	c.Clamped = setClamped(reflect.ValueOf(&c.Value).Elem(), &z)
+	return nil
+}
+
+// integer is the type constraint for [Clamped].
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+func clampFromEmacs(e Env, v Value, s reflect.Value) error {
+	var z big.Int
+	if err := e.BigInt(v, &z); err != nil {
+		return err
+	}
+	setClamped(s, &z)
+	return nil
+}
+
+// setClamped sets the integer s to z, clamped to the range of the type of s.
+// It returns whether clamping was necessary.
+func setClamped(s reflect.Value, z *big.Int) bool {
+	bits := uint(s.Type().Bits())
+	switch s.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		max := int64(math.MaxInt64 >> (64 - bits))
+		min := -max - 1
+		switch {
+		case !z.IsInt64():
+			if z.Sign() > 0 {
+				s.SetInt(max)
+			} else {
+				s.SetInt(min)
+			}
+			return true
+		case z.Int64() > max:
+			s.SetInt(max)
+			return true
+		case z.Int64() < min:
+			s.SetInt(min)
+			return true
+		default:
+			s.SetInt(z.Int64())
+			return false
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		max := uint64(math.MaxUint64 >> (64 - bits))
+		switch {
+		case z.Sign() < 0:
+			s.SetUint(0)
+			return true
+		case !z.IsUint64() || z.Uint64() > max:
+			s.SetUint(max)
+			return true
+		default:
+			s.SetUint(z.Uint64())
+			return false
+		}
+	default:
+		panic(fmt.Errorf("setClamped: invalid type %s", s.Type()))
+	}
+}
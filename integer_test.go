@@ -15,16 +15,21 @@
 package emacs
 
 import (
+	"fmt"
 	"log"
+	"math"
 	"math/big"
 	"math/rand"
 	"reflect"
+	"sync/atomic"
+	"testing"
 	"testing/quick"
 )
 
 func init() {
 	ERTTest(intRoundtrip)
 	ERTTest(bigIntRoundtrip)
+	ERTTest(clampedIntegers)
 }
 
 func intRoundtrip(e Env) error {
@@ -86,3 +91,57 @@ func (*BigInt) Generate(rand *rand.Rand, size int) reflect.Value {
 	}
 	return reflect.ValueOf((*BigInt)(z))
 }
+
+func clampedIntegers(e Env) error {
+	old := atomic.LoadInt32(&integerPolicy)
+	defer atomic.StoreInt32(&integerPolicy, old)
+
+	var c Clamped[int8]
+	if err := e.Invoke("+", &c, 100, 200); err != nil {
+		return err
+	}
+	if c.Value != 127 || !c.Clamped {
+		return fmt.Errorf("clamped int8: got %+v, want {Value:127 Clamped:true}", c)
+	}
+
+	var i int8
+	SetIntegerPolicy(RejectOverflow)
+	if err := e.Invoke("+", &i, 100, 200); !e.IsOverflowError(err) {
+		return fmt.Errorf("converting 300 to int8: got error %v, want overflow-error", err)
+	}
+	SetIntegerPolicy(ClampOverflow)
+	var u uint16
+	if err := e.Invoke("-", &u, 5); err != nil {
+		return err
+	}
+	if u != 0 {
+		return fmt.Errorf("converting -5 to uint16: got %d, want 0", u)
+	}
+	return nil
+}
+
+func TestSetClamped(t *testing.T) {
+	huge := new(big.Int).Lsh(big.NewInt(1), 100)
+	for _, tc := range []struct {
+		target  interface{}
+		z       *big.Int
+		want    interface{}
+		clamped bool
+	}{
+		{new(int8), big.NewInt(-5), int8(-5), false},
+		{new(int8), big.NewInt(128), int8(127), true},
+		{new(int8), big.NewInt(-129), int8(-128), true},
+		{new(int64), huge, int64(math.MaxInt64), true},
+		{new(int64), new(big.Int).Neg(huge), int64(math.MinInt64), true},
+		{new(uint8), big.NewInt(-1), uint8(0), true},
+		{new(uint8), big.NewInt(255), uint8(255), false},
+		{new(uint32), big.NewInt(1 << 32), uint32(math.MaxUint32), true},
+		{new(uint64), huge, uint64(math.MaxUint64), true},
+	} {
+		s := reflect.ValueOf(tc.target).Elem()
+		clamped := setClamped(s, tc.z)
+		if got := s.Interface(); got != tc.want || clamped != tc.clamped {
+			t.Errorf("setClamped(%T, %s): got %v, %t; want %v, %t", tc.want, tc.z, got, clamped, tc.want, tc.clamped)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rawenv connects package github.com/phst/emacs/emacstest to package
+// github.com/phst/emacs.  The emacs package sets the variables in this
+// package during initialization.  This avoids exposing raw environment
+// pointers in the public API of the emacs package.
+package rawenv
+
+import "unsafe"
+
+var (
+	// NewEnv returns an emacs.Env value for the raw emacs_env pointer p
+	// and marks the environment as live.  Call the returned function to
+	// mark it as no longer live.
+	NewEnv func(p unsafe.Pointer) (env interface{}, release func())
+
+	// Init runs the module initializers in the environment p, like
+	// emacs_module_init does when Emacs loads the module.
+	Init func(p unsafe.Pointer) error
+
+	// ValueHandle returns the raw emacs_value handle of the emacs.Value v.
+	ValueHandle func(v interface{}) unsafe.Pointer
+)
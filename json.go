@@ -0,0 +1,135 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "encoding/json"
+
+// JSONObjectType specifies how [Env.ParseJSON] represents JSON objects.
+type JSONObjectType Symbol
+
+// Possible representations of JSON objects.  See [Parsing and generating
+// JSON values].
+//
+// [Parsing and generating JSON values]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Parsing-JSON.html
+const (
+	JSONHashTable JSONObjectType = "hash-table"
+	JSONAlist     JSONObjectType = "alist"
+	JSONPlist     JSONObjectType = "plist"
+)
+
+// JSONArrayType specifies how [Env.ParseJSON] represents JSON arrays.
+type JSONArrayType Symbol
+
+// Possible representations of JSON arrays.
+const (
+	JSONArray JSONArrayType = "array"
+	JSONList  JSONArrayType = "list"
+)
+
+// JSONOptions specify how JSON values are represented as Lisp objects.  The
+// zero JSONOptions value selects the Emacs defaults: objects become hash
+// tables, arrays become vectors, null becomes the keyword :null, and false
+// becomes the keyword :false.
+type JSONOptions struct {
+	// ObjectType specifies the representation of JSON objects.  If empty,
+	// use [JSONHashTable].  [Env.SerializeJSON] ignores ObjectType and
+	// accepts all representations.
+	ObjectType JSONObjectType
+
+	// ArrayType specifies the representation of JSON arrays.  If empty,
+	// use [JSONArray].  [Env.SerializeJSON] ignores ArrayType and only
+	// accepts vectors.
+	ArrayType JSONArrayType
+
+	// Null is the Lisp object that represents JSON null.  If nil, use the
+	// keyword :null.
+	Null In
+
+	// False is the Lisp object that represents JSON false.  If nil, use the
+	// keyword :false.
+	False In
+}
+
+func (o JSONOptions) args(parse bool) []In {
+	var r []In
+	if parse && o.ObjectType != "" {
+		r = append(r, Symbol(":object-type"), Symbol(o.ObjectType))
+	}
+	if parse && o.ArrayType != "" {
+		r = append(r, Symbol(":array-type"), Symbol(o.ArrayType))
+	}
+	if o.Null != nil {
+		r = append(r, Symbol(":null-object"), o.Null)
+	}
+	if o.False != nil {
+		r = append(r, Symbol(":false-object"), o.False)
+	}
+	return r
+}
+
+// ParseJSON parses the JSON text s using the Emacs function json-parse-string
+// and returns the resulting Lisp object.  opts specifies how JSON values are
+// represented in Lisp.  ParseJSON requires native JSON support; see
+// [NativeJSON].
+func (e Env) ParseJSON(s string, opts JSONOptions) (Value, error) {
+	return e.Call("json-parse-string", append([]In{String(s)}, opts.args(true)...)...)
+}
+
+// SerializeJSON converts the Lisp object v to JSON text using the Emacs
+// function json-serialize.  opts specifies how JSON null and false are
+// represented in Lisp.  SerializeJSON requires native JSON support; see
+// [NativeJSON].
+func (e Env) SerializeJSON(v In, opts JSONOptions) (string, error) {
+	var r String
+	err := e.CallOut("json-serialize", &r, append([]In{v}, opts.args(false)...)...)
+	return string(r), err
+}
+
+// JSON is an [In] that converts an arbitrary Go value to the Lisp
+// representation of its JSON encoding.  It first marshals Data using
+// [json.Marshal] and then parses the result using [Env.ParseJSON].  This
+// preserves the distinction between JSON null, false, and empty arrays and
+// objects, which the default conversion rules of this package don’t.
+type JSON struct {
+	Data    interface{}
+	Options JSONOptions
+}
+
+// Emacs marshals j.Data to JSON and parses the result in Emacs.
+func (j JSON) Emacs(e Env) (Value, error) {
+	b, err := json.Marshal(j.Data)
+	if err != nil {
+		return Value{}, err
+	}
+	return e.ParseJSON(string(b), j.Options)
+}
+
+// JSONOut is an [Out] that converts a Lisp object that represents a JSON
+// value to a Go value.  It serializes the Lisp object using
+// [Env.SerializeJSON] and then unmarshals the result into Data using
+// [json.Unmarshal].  Data must therefore be a non-nil pointer.
+type JSONOut struct {
+	Data    interface{}
+	Options JSONOptions
+}
+
+// FromEmacs serializes v to JSON and unmarshals the result into j.Data.
+func (j JSONOut) FromEmacs(e Env, v Value) error {
+	s, err := e.SerializeJSON(v, j.Options)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(s), j.Data)
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func init() {
+	ERTTest(parseJSON, Requires{NativeJSON})
+	ERTTest(jsonRoundtrip, Requires{NativeJSON})
+}
+
+func parseJSON(e Env) error {
+	v, err := e.ParseJSON(`{"a": [1, null, false]}`, JSONOptions{ObjectType: JSONAlist, ArrayType: JSONList, Null: Symbol("nil")})
+	if err != nil {
+		return err
+	}
+	want, err := e.Call("read", String("((a 1 nil :false))"))
+	if err != nil {
+		return err
+	}
+	var equal Bool
+	if err := e.CallOut("equal", &equal, v, want); err != nil {
+		return err
+	}
+	if !equal {
+		return fmt.Errorf("ParseJSON: got %s, want %s", e.FormatMessage("%S", v), e.FormatMessage("%S", want))
+	}
+	s, err := e.SerializeJSON(List{Cons{Symbol("a"), Vector{Int(1), Symbol(":null"), Symbol(":false")}}}, JSONOptions{})
+	if err != nil {
+		return err
+	}
+	if want := `{"a":[1,null,false]}`; s != want {
+		return fmt.Errorf("SerializeJSON: got %s, want %s", s, want)
+	}
+	return nil
+}
+
+func jsonRoundtrip(e Env) error {
+	type record struct {
+		Name  string         `json:"name"`
+		Tags  []string       `json:"tags"`
+		Ok    *bool          `json:"ok"`
+		Extra map[string]int `json:"extra"`
+	}
+	f := false
+	for _, opts := range []JSONOptions{
+		{},
+		{ObjectType: JSONAlist},
+		{ObjectType: JSONPlist, Null: Symbol(":nothing"), False: Symbol(":no")},
+	} {
+		for _, in := range []record{
+			{"foo", []string{"a", "b"}, &f, map[string]int{"x": 1}},
+			{"", []string{}, nil, map[string]int{}},
+		} {
+			v, err := JSON{in, opts}.Emacs(e)
+			if err != nil {
+				return err
+			}
+			var out record
+			if err := (JSONOut{&out, opts}).FromEmacs(e, v); err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(out, in) {
+				return fmt.Errorf("JSON roundtrip with options %+v: got %+v, want %+v", opts, out, in)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"sync"
+)
+
+// KeySequence is a key sequence in the textual format that the Emacs function
+// kbd accepts, for example "C-c C-f" or "<f5> a".  See [Key Sequences].
+//
+// [Key Sequences]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Key-Sequences.html
+type KeySequence string
+
+// Emacs converts the key sequence to its internal Emacs representation, a
+// string or vector, using kbd.  If Emacs provides the function key-valid-p
+// (Emacs 29 and later), Emacs first checks that k is in the strict format
+// that key-valid-p accepts and returns a wrong-type-argument error otherwise.
+func (k KeySequence) Emacs(e Env) (Value, error) {
+	ok, err := keyValidCapability.Detect(e)
+	if err != nil {
+		return Value{}, err
+	}
+	if ok {
+		var valid Bool
+		if err := e.CallOut("key-valid-p", &valid, String(k)); err != nil {
+			return Value{}, err
+		}
+		if !valid {
+			return Value{}, WrongTypeArgument("key-valid-p", String(k))
+		}
+	}
+	return e.Call("kbd", String(k))
+}
+
+// FromEmacs sets *k to the description of the key sequence v as returned by
+// key-description.  v can be a string or vector in the internal Emacs
+// representation of key sequences.
+func (k *KeySequence) FromEmacs(e Env, v Value) error {
+	var s String
+	if err := e.CallOut("key-description", &s, v); err != nil {
+		return err
+	}
+	*k = KeySequence(s)
+	return nil
+}
+
+var keyValidCapability = FunctionCapability("key-valid-p")
+
+// KeyBinding is a single binding in a [Keymap].
+type KeyBinding struct {
+	Key KeySequence
+
+	// Definition is the binding for Key, typically a command name or
+	// another keymap.
+	Definition In
+}
+
+// Keymap is an [In] that creates a new sparse keymap.  See [Keymaps].
+//
+// [Keymaps]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Keymaps.html
+type Keymap struct {
+	// Prompt is the overall prompt string of the keymap.  It’s only used
+	// for menu keymaps and can be empty.
+	Prompt string
+
+	// Parent is the parent keymap.  If nil, the keymap has no parent.
+	Parent In
+
+	// Bindings are the initial bindings of the keymap.  Later bindings
+	// override earlier ones for the same key.
+	Bindings []KeyBinding
+}
+
+// Emacs creates the keymap using make-sparse-keymap and adds the bindings in
+// order using [Env.DefineKey].
+func (m Keymap) Emacs(e Env) (Value, error) {
+	var prompt In = Nil
+	if m.Prompt != "" {
+		prompt = String(m.Prompt)
+	}
+	r, err := e.Call("make-sparse-keymap", prompt)
+	if err != nil {
+		return Value{}, err
+	}
+	if m.Parent != nil {
+		if _, err := e.Call("set-keymap-parent", r, m.Parent); err != nil {
+			return Value{}, err
+		}
+	}
+	for _, b := range m.Bindings {
+		if err := e.DefineKey(r, b.Key, b.Definition); err != nil {
+			return Value{}, err
+		}
+	}
+	return r, nil
+}
+
+// DefineKey binds key to def in keymap using define-key.  If def is nil,
+// DefineKey removes the binding for key.
+func (e Env) DefineKey(keymap In, key KeySequence, def In) error {
+	if def == nil {
+		def = Nil
+	}
+	_, err := e.Call("define-key", keymap, key, def)
+	return err
+}
+
+// LookupKey returns the binding for key in keymap using lookup-key.  If key
+// isn’t bound, LookupKey returns false.  Like lookup-key, LookupKey considers
+// default bindings only if acceptDefault is true.
+func (e Env) LookupKey(keymap In, key KeySequence, acceptDefault bool) (Value, bool, error) {
+	r, err := e.Call("lookup-key", keymap, key, Bool(acceptDefault))
+	if err != nil {
+		return Value{}, false, err
+	}
+	// lookup-key returns a number if key is too long, i.e., if a prefix of
+	// key is bound to a non-prefix command.
+	var num Bool
+	if err := e.CallOut("numberp", &num, r); err != nil {
+		return Value{}, false, err
+	}
+	if bool(num) || e.IsNil(r) {
+		return Value{}, false, nil
+	}
+	return r, true, nil
+}
+
+// BindKeys arranges for bindings to be added to the keymap stored in the
+// variable keymap once the module is loaded, for example global-map, a major
+// mode map such as emacs-lisp-mode-map, or the map of a minor mode defined by
+// [DefineMinorMode].  Bindings registered by BindKeys are added after all
+// modes, functions, and other entities of this package have been defined, so
+// keymap can refer to a mode defined by the same module.  If the keymap
+// variable isn’t defined at that point or one of the keys is already bound
+// to a different definition, loading the module fails with a
+// [KeyConflictError].  Call BindKeys in an init function.
+func BindKeys(keymap Name, bindings ...KeyBinding) {
+	enqueueUI(keyBindings{keymap, bindings})
+}
+
+// BindKeys is like the global [BindKeys] function, except that it requires a
+// live environment, adds the bindings immediately, and returns errors instead
+// of panicking.  If a key is already bound to a different definition,
+// BindKeys returns a [KeyConflictError] and doesn’t add any bindings.
+func (e Env) BindKeys(keymap Name, bindings ...KeyBinding) error {
+	return keyBindings{keymap, bindings}.Define(e)
+}
+
+// KeyConflictError is returned by [Env.BindKeys] and [Env.DefineMenu] if a key
+// is already bound in a keymap.
+type KeyConflictError struct {
+	// Keymap is the name of the keymap variable.
+	Keymap Name
+
+	// Key is a description of the key sequence.
+	Key string
+
+	// Existing is a printed representation of the existing binding.
+	Existing string
+}
+
+func (e *KeyConflictError) Error() string {
+	return fmt.Sprintf("key %s in %s is already bound to %s", e.Key, e.Keymap, e.Existing)
+}
+
+type keyBindings struct {
+	keymap   Name
+	bindings []KeyBinding
+}
+
+func (b keyBindings) Define(e Env) error {
+	keymap, err := e.keymapValue(b.keymap)
+	if err != nil {
+		return err
+	}
+	for _, kb := range b.bindings {
+		var def In = Nil
+		if kb.Definition != nil {
+			def = kb.Definition
+		}
+		if err := e.checkKeyConflict(b.keymap, keymap, kb.Key, string(kb.Key), def); err != nil {
+			return err
+		}
+	}
+	for _, kb := range b.bindings {
+		if err := e.DefineKey(keymap, kb.Key, kb.Definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keymapValue returns the keymap stored in the variable name.
+func (e Env) keymapValue(name Name) (Value, error) {
+	var bound Bool
+	if err := e.CallOut("boundp", &bound, name); err != nil {
+		return Value{}, err
+	}
+	if !bound {
+		return Value{}, fmt.Errorf("keymap variable %s isn’t defined", name)
+	}
+	keymap, err := e.Call("symbol-value", name)
+	if err != nil {
+		return Value{}, err
+	}
+	var ok Bool
+	if err := e.CallOut("keymapp", &ok, keymap); err != nil {
+		return Value{}, err
+	}
+	if !ok {
+		return Value{}, WrongTypeArgument("keymapp", keymap)
+	}
+	return keymap, nil
+}
+
+// checkKeyConflict returns a [KeyConflictError] if key is bound in keymap to
+// something other than def, or if a prefix of key is bound to a non-prefix
+// command.
+func (e Env) checkKeyConflict(name Name, keymap Value, key In, desc string, def In) error {
+	r, err := e.Call("lookup-key", keymap, key)
+	if err != nil {
+		return err
+	}
+	if e.IsNil(r) {
+		return nil
+	}
+	var num Bool
+	if err := e.CallOut("numberp", &num, r); err != nil {
+		return err
+	}
+	if num {
+		return &KeyConflictError{name, desc, "a prefix of the key sequence"}
+	}
+	var same Bool
+	if err := e.CallOut("equal", &same, r, def); err != nil {
+		return err
+	}
+	if same {
+		return nil
+	}
+	return &KeyConflictError{name, desc, e.FormatMessage("%S", r)}
+}
+
+// enqueueUI registers an item that should be defined after all other entities
+// of this package.  The first call registers the initializer, which therefore
+// runs after the initializers of all managers created during package
+// initialization.
+func enqueueUI(item QueuedItem) {
+	uiOnce.Do(func() { OnInit(uiItems.DefineQueued) })
+	uiItems.MustEnqueue("", item)
+}
+
+var (
+	uiOnce  sync.Once
+	uiItems Manager
+)
@@ -0,0 +1,128 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+func init() {
+	ERTTest(keymap)
+	ERTTest(keySequence)
+	ERTTest(bindKeys)
+}
+
+func keymap(e Env) error {
+	parent, err := Keymap{Bindings: []KeyBinding{{"C-c p", Symbol("ignore")}}}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	m, err := Keymap{
+		Parent: parent,
+		Bindings: []KeyBinding{
+			{"C-c a", Symbol("forward-char")},
+			{"C-c b", Symbol("backward-char")},
+			{"C-c b", Symbol("backward-word")},
+		},
+	}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	for key, want := range map[KeySequence]Symbol{
+		"C-c a": "forward-char",
+		"C-c b": "backward-word",
+		"C-c p": "ignore",
+	} {
+		v, ok, err := e.LookupKey(m, key, false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("key %s unbound", key)
+		}
+		got, err := e.Symbol(v)
+		if err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("key %s: got %s, want %s", key, got, want)
+		}
+	}
+	for _, key := range []KeySequence{"C-c z", "C-c a b"} {
+		if _, ok, err := e.LookupKey(m, key, false); err != nil || ok {
+			return fmt.Errorf("LookupKey(%s): got %v, %v; want unbound", key, ok, err)
+		}
+	}
+	if err := e.DefineKey(m, "C-c a", nil); err != nil {
+		return err
+	}
+	if _, ok, err := e.LookupKey(m, "C-c a", false); err != nil || ok {
+		return fmt.Errorf("LookupKey after removal: got %v, %v; want unbound", ok, err)
+	}
+	return nil
+}
+
+func keySequence(e Env) error {
+	v, err := KeySequence("C-x <f5>").Emacs(e)
+	if err != nil {
+		return err
+	}
+	var k KeySequence
+	if err := k.FromEmacs(e, v); err != nil {
+		return err
+	}
+	if want := KeySequence("C-x <f5>"); k != want {
+		return fmt.Errorf("KeySequence roundtrip: got %q, want %q", k, want)
+	}
+	if keyValidCapability.Available() {
+		_, err := KeySequence("C-xx").Emacs(e)
+		if !e.IsWrongTypeArgument(err) {
+			return fmt.Errorf("KeySequence(%q): got error %v, want wrong-type-argument", "C-xx", err)
+		}
+	}
+	return nil
+}
+
+func bindKeys(e Env) error {
+	const name Name = "emacs-go-test-bind-keys-map"
+	if _, err := e.Eval(List{Symbol("defvar"), name, List{Symbol("make-sparse-keymap")}}); err != nil {
+		return err
+	}
+	if err := e.BindKeys(name, KeyBinding{"C-c a", Symbol("ignore")}, KeyBinding{"C-c b", Symbol("undefined")}); err != nil {
+		return err
+	}
+	// Binding a key to the same definition again is fine.
+	if err := e.BindKeys(name, KeyBinding{"C-c a", Symbol("ignore")}); err != nil {
+		return err
+	}
+	err := e.BindKeys(name, KeyBinding{"C-c c", Symbol("ignore")}, KeyBinding{"C-c b", Symbol("ignore")})
+	var conflict *KeyConflictError
+	if !errors.As(err, &conflict) {
+		return fmt.Errorf("BindKeys: got error %v, want key conflict", err)
+	}
+	if conflict.Key != "C-c b" {
+		return fmt.Errorf("conflicting key: got %q, want %q", conflict.Key, "C-c b")
+	}
+	keymap, err := e.Call("symbol-value", name)
+	if err != nil {
+		return err
+	}
+	// A conflict prevents all bindings.
+	if _, found, err := e.LookupKey(keymap, "C-c c", false); err != nil || found {
+		return fmt.Errorf("C-c c: got found = %t, error %v; want no binding", found, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "reflect"
+
+// Keywords marks a struct type as a set of keyword arguments.  If the last
+// parameter of a function exported using [Export] or [AutoFunc] is a struct
+// that embeds Keywords, the struct fields become &key arguments in Emacs, like
+// in cl-defun.  Callers pass them as a property list after the other
+// arguments.  The mapping between fields and keywords is the same as for
+// converting structs to property lists, as described in the package
+// documentation.  For example:
+//
+//	type greetOptions struct {
+//		emacs.Keywords
+//		Name    string
+//		Timeout time.Duration
+//	}
+//
+//	func greet(e emacs.Env, text string, opts greetOptions) error { … }
+//
+// After exporting greet, Lisp code can call it as (greet "hi" :name "x" :timeout
+// 5).  Fields whose keyword the caller doesn’t pass receive zero values.
+// Passing a keyword that doesn’t correspond to a field signals an error of
+// type go-invalid-keyword-argument.  A function with keyword arguments can’t
+// also be variadic.
+type Keywords struct{}
+
+var keywordsType = reflect.TypeOf(Keywords{})
+
+// hasKeywords returns whether t is a struct type that embeds [Keywords].
+func hasKeywords(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == keywordsType {
+			return true
+		}
+	}
+	return false
+}
+
+// setKeywords sets the fields of the keyword struct s from the keyword
+// arguments in args.
+func (c *structCodec) setKeywords(e Env, s reflect.Value, args []Value) error {
+	if err := c.init(); err != nil {
+		return err
+	}
+	if len(args)%2 != 0 {
+		return WrongTypeArgument("plistp", List(valuesIn(args)))
+	}
+	return c.setFields(e, s, args, true)
+}
+
+// valuesIn converts vals to a slice of [In] values.
+func valuesIn(vals []Value) []In {
+	r := make([]In, len(vals))
+	for i, v := range vals {
+		r[i] = v
+	}
+	return r
+}
+
+var invalidKeywordArgument = DefineError("go-invalid-keyword-argument", "Invalid keyword argument", baseError)
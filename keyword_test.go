@@ -0,0 +1,73 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	ERTTest(keywordArguments)
+}
+
+type testKeywords struct {
+	Keywords
+	Name    string
+	Timeout int `emacs:"time-limit"`
+}
+
+func keywordArguments(e Env) error {
+	fun, del, err := e.Lambda(func(a int, b Optional[int], k testKeywords) string {
+		return fmt.Sprintf("%d %d %q %d", a, b.Value, k.Name, k.Timeout)
+	})
+	if err != nil {
+		return err
+	}
+	defer del()
+	for _, tc := range []struct {
+		args []In
+		want string
+	}{
+		{[]In{Int(1)}, `1 0 "" 0`},
+		{[]In{Int(1), Int(2)}, `1 2 "" 0`},
+		{[]In{Int(1), Int(2), Symbol(":name"), String("x")}, `1 2 "x" 0`},
+		{[]In{Int(1), Nil, Symbol(":time-limit"), Int(5), Symbol(":name"), String("y")}, `1 0 "y" 5`},
+	} {
+		var got String
+		if err := e.CallOut("funcall", &got, append([]In{fun}, tc.args...)...); err != nil {
+			return err
+		}
+		if string(got) != tc.want {
+			return fmt.Errorf("funcall with %d arguments: got %s, want %s", len(tc.args), got, tc.want)
+		}
+	}
+	_, err = e.Call("funcall", fun, Int(1), Nil, Symbol(":unknown"), Int(1))
+	if !invalidKeywordArgument.match(e, err) {
+		return fmt.Errorf("unknown keyword: got error %v, want go-invalid-keyword-argument", err)
+	}
+	_, err = e.Call("funcall", fun, Int(1), Nil, Symbol(":name"))
+	if !e.IsWrongTypeArgument(err) {
+		return fmt.Errorf("missing keyword value: got error %v, want wrong-type-argument", err)
+	}
+	return nil
+}
+
+func TestKeywordArity(t *testing.T) {
+	_, _, arity, _ := AutoFunc(func(Env, int, testKeywords) {}, Anonymous{})
+	if want := (Arity{1, -1}); arity != want {
+		t.Errorf("got arity %v, want %v", arity, want)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// SetLeakTracking enables or disables capturing the call stacks of functions
+// created by [Env.Lambda], [Env.LambdaFunc], [Hook.Add], and [Env.AdviceAdd].
+// [Leaks] includes these call stacks in its report, which makes it easy to
+// find out where a forgotten [DeleteFunc] call should be.  Leak tracking is
+// initially enabled if debug mode is enabled when the program starts; see
+// [SetDebugMode].  Leak tracking slows down creating functions, but not
+// calling them.  You can call SetLeakTracking safely from multiple
+// goroutines.
+func SetLeakTracking(on bool) {
+	var i int32
+	if on {
+		i = 1
+	}
+	atomic.StoreInt32(&leakTracking, i)
+}
+
+var leakTracking int32 = debugModeFromEnv()
+
+// Leak describes a function that was created using [Env.Lambda],
+// [Env.LambdaFunc], [Hook.Add], or [Env.AdviceAdd] and hasn’t been deleted
+// yet.  See [Leaks].
+type Leak struct {
+	// Function is the name of the function.  It’s empty for anonymous
+	// functions.
+	Function Name
+
+	// Created is the time when the function was created.
+	Created time.Time
+
+	// Stack is the call stack at the time the function was created, one
+	// frame per line.  It’s empty unless leak tracking was enabled at
+	// that time; see [SetLeakTracking].
+	Stack string
+}
+
+// String formats the leak for human consumption.
+func (l Leak) String() string {
+	fun := string(l.Function)
+	if fun == "" {
+		fun = "anonymous function"
+	}
+	s := fmt.Sprintf("%s created at %s", fun, l.Created.Format(time.RFC3339))
+	if l.Stack != "" {
+		s += ":\n" + l.Stack
+	}
+	return s
+}
+
+// Emacs returns a property list with the keys :function, :created, and
+// :stack.  The function is a symbol or nil, the creation time is a Lisp
+// timestamp, and the stack is a string or nil.
+func (l Leak) Emacs(e Env) (Value, error) {
+	var fun, stack In = Nil, Nil
+	if l.Function != "" {
+		fun = l.Function
+	}
+	if l.Stack != "" {
+		stack = String(l.Stack)
+	}
+	return List{
+		Symbol(":function"), fun,
+		Symbol(":created"), Time(l.Created),
+		Symbol(":stack"), stack,
+	}.Emacs(e)
+}
+
+// Leaks returns the functions created using [Env.Lambda], [Env.LambdaFunc],
+// [Hook.Add], or [Env.AdviceAdd] that are older than minAge and haven’t been
+// deleted yet, oldest first.  Such functions are potential memory leaks.
+// Functions exported using [Export] or [Env.ExportFunc] are never included,
+// since they can’t be deleted.  You can call Leaks safely from multiple
+// goroutines.
+func Leaks(minAge time.Duration) []Leak {
+	fs := funcs.leaks(time.Now().Add(-minAge))
+	r := make([]Leak, len(fs))
+	for i, f := range fs {
+		r[i] = Leak{f.name, f.created.time, f.created.stack()}
+	}
+	sort.SliceStable(r, func(i, j int) bool { return r[i].Created.Before(r[j].Created) })
+	return r
+}
+
+// ExportLeakCommand arranges for an interactive command with the given name
+// to be defined once the module is loaded.  The command displays the result of
+// [Leaks] in a buffer.  It takes an optional argument, the minimum age in
+// seconds.  Interactively, a numeric prefix argument specifies the minimum
+// age; without prefix argument, the minimum age is minAge.  Call
+// ExportLeakCommand in an init function.
+func ExportLeakCommand(name Name, minAge time.Duration) {
+	OnInit(func(e Env) error {
+		return e.exportCommands([]command{
+			{name, Arity{0, 1}, func(e Env, args []Value) (Value, error) {
+				age := minAge
+				if len(args) > 0 && e.IsNotNil(args[0]) {
+					var s Float
+					if err := e.CallOut("float", &s, args[0]); err != nil {
+						return Value{}, err
+					}
+					age = time.Duration(float64(s) * float64(time.Second))
+				}
+				var b strings.Builder
+				leaks := Leaks(age)
+				fmt.Fprintf(&b, "%d functions older than %s not deleted\n", len(leaks), age)
+				for _, l := range leaks {
+					fmt.Fprintf(&b, "\n%s\n", l)
+				}
+				return e.showReport("*"+string(name)+"*", b.String())
+			}, "Display functions created by the Go module that were never deleted.\nOnly display functions older than MIN-AGE seconds.\n\n(fn &optional MIN-AGE)", List{
+				Symbol("and"), Symbol("current-prefix-arg"),
+				List{Symbol("list"), List{Symbol("prefix-numeric-value"), Symbol("current-prefix-arg")}},
+			}},
+		})
+	})
+}
+
+// creation records when and where a function was created.
+type creation struct {
+	time time.Time
+	pcs  []uintptr // nil unless leak tracking was enabled
+}
+
+func newCreation() *creation {
+	c := &creation{time: time.Now()}
+	if atomic.LoadInt32(&leakTracking) != 0 {
+		pcs := make([]uintptr, 32)
+		// Skip runtime.Callers, newCreation, and the function
+		// creating the lambda.
+		n := runtime.Callers(3, pcs)
+		c.pcs = pcs[:n]
+	}
+	return c
+}
+
+// stack formats the captured call stack.
+func (c *creation) stack() string {
+	if len(c.pcs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(c.pcs)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
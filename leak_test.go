@@ -0,0 +1,66 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func init() {
+	ERTTest(leakDetection)
+}
+
+func leakDetection(e Env) error {
+	old := atomic.LoadInt32(&leakTracking)
+	SetLeakTracking(true)
+	defer atomic.StoreInt32(&leakTracking, old)
+	before := len(Leaks(0))
+	_, del, err := e.Lambda(func() {})
+	if err != nil {
+		return err
+	}
+	leaks := Leaks(0)
+	if len(leaks) != before+1 {
+		del()
+		return errors.New("new lambda not reported as potential leak")
+	}
+	if l := leaks[len(leaks)-1]; !strings.Contains(l.Stack, "leakDetection") {
+		del()
+		return errors.New("stack of potential leak doesn’t mention creating function:\n" + l.Stack)
+	}
+	del()
+	if len(Leaks(0)) != before {
+		return errors.New("deleted lambda still reported as potential leak")
+	}
+	return nil
+}
+
+func TestCreationStack(t *testing.T) {
+	old := atomic.LoadInt32(&leakTracking)
+	defer atomic.StoreInt32(&leakTracking, old)
+
+	SetLeakTracking(false)
+	if s := newCreation().stack(); s != "" {
+		t.Errorf("stack with leak tracking disabled: got %q, want empty", s)
+	}
+
+	SetLeakTracking(true)
+	if s := newCreation().stack(); !strings.Contains(s, "testing.tRunner") {
+		t.Errorf("stack with leak tracking enabled doesn’t contain caller:\n%s", s)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build emacsdebug
+
+package emacs
+
+// #include "emacs-module.h"
+import "C"
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// generation identifies a live environment.  If the emacsdebug build tag is
+// set, each environment gets a new generation when it becomes live, and
+// values remember the generation of the environment that created them.
+// Using an environment or value whose generation has ended panics.  The zero
+// generation is never checked; it’s used for global references and for
+// values that aren’t associated with an environment.
+type generation struct{ serial uint64 }
+
+// generations contains the live generations.
+var generations struct {
+	last uint64 // accessed atomically
+
+	mu   sync.Mutex
+	live map[uint64]struct{}
+}
+
+func newGeneration() generation {
+	g := generation{atomic.AddUint64(&generations.last, 1)}
+	generations.mu.Lock()
+	defer generations.mu.Unlock()
+	if generations.live == nil {
+		generations.live = make(map[uint64]struct{})
+	}
+	generations.live[g.serial] = struct{}{}
+	return g
+}
+
+// end marks the generation as dead.
+func (g generation) end() {
+	generations.mu.Lock()
+	defer generations.mu.Unlock()
+	delete(generations.live, g.serial)
+}
+
+func (g generation) live() bool {
+	if g.serial == 0 {
+		return true
+	}
+	generations.mu.Lock()
+	defer generations.mu.Unlock()
+	_, ok := generations.live[g.serial]
+	return ok
+}
+
+// check panics if the environment with generation g is no longer live.
+func (g generation) check() {
+	if !g.live() {
+		panic(fmt.Sprintf("environment #%d used after the exported function or module initializer that received it returned", g.serial))
+	}
+}
+
+// raw returns the underlying Emacs value.  It panics if the environment
+// that created v is no longer live.
+func (v Value) raw() C.emacs_value {
+	if !v.gen.live() {
+		panic(fmt.Sprintf("value used after environment #%d that created it has become invalid", v.gen.serial))
+	}
+	return v.r
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !emacsdebug
+
+package emacs
+
+// #include "emacs-module.h"
+import "C"
+
+// generation is empty unless the emacsdebug build tag is set, so that
+// lifetime checks don’t cost anything by default.
+type generation struct{}
+
+func newGeneration() generation { return generation{} }
+
+func (generation) end() {}
+
+func (generation) check() {}
+
+// raw returns the underlying Emacs value.
+func (v Value) raw() C.emacs_value { return v.r }
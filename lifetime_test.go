@@ -0,0 +1,45 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build emacsdebug
+
+package emacs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneration(t *testing.T) {
+	g := newGeneration()
+	v := Value{gen: g}
+	g.check()
+	v.raw()
+	g.end()
+	for name, f := range map[string]func(){"Env": g.check, "Value": func() { v.raw() }} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				x := recover()
+				if s, ok := x.(string); !ok || !strings.Contains(s, "#") {
+					t.Errorf("got panic %v, want panic mentioning the environment", x)
+				}
+			}()
+			f()
+		})
+	}
+	// Values without a generation, such as global references, are never
+	// checked.
+	Value{}.raw()
+	generation{}.check()
+}
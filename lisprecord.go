@@ -0,0 +1,207 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Record is an [In] that creates an Emacs record of type Type, like the Emacs
+// function record, and fills its slots from the fields of the Go struct Data.
+// Data must be a struct or a pointer to a struct.  Slot 0 of a record contains
+// its type; the fields map to the other slots as follows.
+//
+// If Type names a record-based structure type defined using cl‑defstruct,
+// each exported field corresponds to the structure slot with the same name in
+// Lisp style: the field FooBar corresponds to the slot foo-bar.  To use a
+// different slot name, add a struct tag of the form emacs:"slot-name" to the
+// field.  Slots that don’t correspond to a field are nil.
+//
+// Otherwise, the exported fields fill the slots 1, 2, … in order.
+//
+// In both cases, the struct tag option slot=N sets the slot index explicitly,
+// as in emacs:"name,slot=2"; subsequent fields without a slot index of
+// their own then continue after N.  The tag emacs:"-" excludes a field.
+type Record struct {
+	Type Symbol
+	Data interface{}
+}
+
+// Emacs creates a new record using make-record and sets its slots using aset.
+func (r Record) Emacs(e Env) (Value, error) {
+	if r.Type == "" {
+		return Value{}, errors.New("empty record type")
+	}
+	v, err := structValue(reflect.ValueOf(r.Data))
+	if err != nil {
+		return Value{}, err
+	}
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return Value{}, err
+	}
+	slots, size, err := recordSlots(e, r.Type, fields)
+	if err != nil {
+		return Value{}, err
+	}
+	rec, err := e.Call("make-record", r.Type, Int(size), Nil)
+	if err != nil {
+		return Value{}, err
+	}
+	for i, f := range fields {
+		fv, ok := fieldValue(v, f.index)
+		if !ok {
+			continue
+		}
+		if _, err := e.Call("aset", rec, Int(slots[i]), f.in(fv)); err != nil {
+			return Value{}, conversionContext(err, fmt.Sprintf("struct field %s", f.name), fv.Type())
+		}
+	}
+	return rec, nil
+}
+
+// RecordOut is an [Out] that reads the slots of an Emacs record into the
+// fields of the Go struct that Data points to.  If Type is nonempty, the
+// record must be of type Type, or of a structure type that includes Type.
+// Fields map to slots as described for [Record], using Type or the type of the
+// record.
+type RecordOut struct {
+	Type Symbol
+	Data interface{}
+}
+
+// FromEmacs sets the fields of o.Data from the slots of the record v using
+// aref.
+func (o RecordOut) FromEmacs(e Env, v Value) error {
+	p := reflect.ValueOf(o.Data)
+	if p.Kind() != reflect.Ptr || p.IsNil() || p.Elem().Kind() != reflect.Struct {
+		return WrongTypeArgument("go-struct-pointer-p", String(fmt.Sprintf("%T", o.Data)))
+	}
+	s := p.Elem()
+	fields, err := structFields(s.Type())
+	if err != nil {
+		return err
+	}
+	var ok Bool
+	if err := e.CallOut("recordp", &ok, v); err != nil {
+		return err
+	}
+	if !ok {
+		return WrongTypeArgument("recordp", v)
+	}
+	var typ Symbol
+	if err := e.CallOut("type-of", &typ, v); err != nil {
+		return err
+	}
+	if o.Type != "" && typ != o.Type {
+		isStruct, err := isRecordStruct(e, o.Type)
+		if err != nil {
+			return err
+		}
+		if isStruct {
+			if err := e.CallOut("cl-typep", &ok, v, o.Type); err != nil {
+				return err
+			}
+		}
+		if !isStruct || !bool(ok) {
+			return WrongTypeArgument(o.Type+"-p", v)
+		}
+		typ = o.Type
+	}
+	slots, _, err := recordSlots(e, typ, fields)
+	if err != nil {
+		return err
+	}
+	for i, f := range fields {
+		fp, err := fieldPointer(s, f.index)
+		if err != nil {
+			return err
+		}
+		if err := e.CallOut("aref", f.out(fp), v, Int(slots[i])); err != nil {
+			return conversionContext(err, fmt.Sprintf("struct field %s", f.name), fp.Type().Elem())
+		}
+	}
+	return nil
+}
+
+// recordSlots returns the slot indices for the given fields in a record of
+// type typ, as well as the number of slots excluding the type slot.
+func recordSlots(e Env, typ Symbol, fields []structField) ([]int, int, error) {
+	isStruct, err := isRecordStruct(e, typ)
+	if err != nil {
+		return nil, 0, err
+	}
+	size := 0
+	if isStruct {
+		// The slot information includes the type slot.
+		info, err := e.Call("cl-struct-slot-info", typ)
+		if err != nil {
+			return nil, 0, err
+		}
+		var n Int
+		if err := e.CallOut("length", &n, info); err != nil {
+			return nil, 0, err
+		}
+		size = int(n) - 1
+	}
+	slots := make([]int, len(fields))
+	next := 1
+	for i, f := range fields {
+		switch {
+		case f.slot > 0:
+			slots[i] = f.slot
+		case isStruct:
+			var offset Int
+			if err := e.CallOut("cl-struct-slot-offset", &offset, typ, f.name); err != nil {
+				return nil, 0, err
+			}
+			slots[i] = int(offset)
+		default:
+			slots[i] = next
+		}
+		next = slots[i] + 1
+		if slots[i] > size {
+			size = slots[i]
+		}
+	}
+	return slots, size, nil
+}
+
+// isRecordStruct returns whether typ names a structure type defined by
+// cl‑defstruct that uses records.  It returns an error if typ names a
+// structure type based on vectors or lists.
+func isRecordStruct(e Env, typ Symbol) (bool, error) {
+	if _, err := e.Call("require", Symbol("cl-lib")); err != nil {
+		return false, err
+	}
+	class, err := e.Call("cl-find-class", typ)
+	if err != nil || !e.IsNotNil(class) {
+		return false, err
+	}
+	var ok Bool
+	if err := e.CallOut("cl-typep", &ok, class, Symbol("cl-structure-class")); err != nil || !ok {
+		return false, err
+	}
+	seq, err := e.Call("cl-struct-sequence-type", typ)
+	if err != nil {
+		return false, err
+	}
+	if e.IsNotNil(seq) {
+		return false, WrongTypeArgument("recordp", typ)
+	}
+	return true, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type recordPoint struct {
+	X, Y  int
+	Label string `emacs:"name"`
+}
+
+type recordPair struct {
+	First  string
+	Second string `emacs:",slot=3"`
+}
+
+func init() {
+	ERTTest(recordStruct)
+	ERTTest(recordPlain)
+}
+
+func recordStruct(e Env) error {
+	if _, err := e.Eval(List{Symbol("cl-defstruct"), Symbol("go-record-point"), Symbol("name"), Symbol("x"), Symbol("y")}); err != nil {
+		return err
+	}
+	in := recordPoint{X: 1, Y: 2, Label: "origin"}
+	rec, err := Record{"go-record-point", in}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	var x Int
+	if err := e.CallOut("go-record-point-x", &x, rec); err != nil {
+		return err
+	}
+	if x != 1 {
+		return fmt.Errorf("go-record-point-x: got %d, want 1", x)
+	}
+	var name String
+	if err := e.CallOut("go-record-point-name", &name, rec); err != nil {
+		return err
+	}
+	if name != "origin" {
+		return fmt.Errorf("go-record-point-name: got %q, want origin", name)
+	}
+	var out recordPoint
+	if err := (RecordOut{"go-record-point", &out}).FromEmacs(e, rec); err != nil {
+		return err
+	}
+	if out != in {
+		return fmt.Errorf("record roundtrip: got %+v, want %+v", out, in)
+	}
+	other, err := e.Call("record", Symbol("go-record-other"), Int(1))
+	if err != nil {
+		return err
+	}
+	if err := (RecordOut{"go-record-point", &out}).FromEmacs(e, other); !e.IsWrongTypeArgument(err) {
+		return fmt.Errorf("converting record of wrong type: got error %v, want wrong-type-argument", err)
+	}
+	return nil
+}
+
+func recordPlain(e Env) error {
+	in := recordPair{"a", "b"}
+	rec, err := Record{"go-record-pair", &in}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	var got String
+	if err := e.CallOut("prin1-to-string", &got, rec); err != nil {
+		return err
+	}
+	if want := `#s(go-record-pair "a" nil "b")`; string(got) != want {
+		return fmt.Errorf("got record %s, want %s", got, want)
+	}
+	var out recordPair
+	if err := (RecordOut{Data: &out}).FromEmacs(e, rec); err != nil {
+		return err
+	}
+	if out != in {
+		return fmt.Errorf("record roundtrip: got %+v, want %+v", out, in)
+	}
+	return nil
+}
+
+func TestStructFieldsSlot(t *testing.T) {
+	fields, err := structFields(reflect.TypeOf(recordPair{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 2 || fields[0].slot != 0 || fields[1].slot != 3 {
+		t.Errorf("got fields %+v, want slot indices 0 and 3", fields)
+	}
+	type invalid struct {
+		A int `emacs:",slot=0"`
+	}
+	if _, err := structFields(reflect.TypeOf(invalid{})); err == nil {
+		t.Error("got no error for slot index 0")
+	}
+}
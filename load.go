@@ -0,0 +1,226 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// #include "emacs-module.h"
+// #include "wrappers.h"
+import "C"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RequireOptions contains options for [Env.Require].
+type RequireOptions struct {
+	// Filename is the file to load if the feature isn’t present yet.  If
+	// it’s empty, Emacs searches the load path for a file named after
+	// the feature.
+	Filename string
+
+	// If NoError is true, Require returns an empty name instead of an
+	// error if the file can’t be found.  Other errors are still
+	// reported.
+	NoError bool
+}
+
+// Require loads feature by calling the Emacs function require unless the
+// feature is already present.  It returns the feature, or an empty name if
+// opts.NoError is true and the file wasn’t found.  If loading fails,
+// Require returns an error of type *[LoadError].  Use Require to load
+// optional Lisp dependencies before using them.
+func (e Env) Require(feature Name, opts RequireOptions) (Name, error) {
+	var file In = Nil
+	if opts.Filename != "" {
+		file = String(opts.Filename)
+	}
+	v, err := e.Call("require", feature, file, Bool(opts.NoError))
+	if err != nil {
+		return "", e.loadError(err, opts.Filename, feature)
+	}
+	if !e.IsNotNil(v) {
+		return "", nil
+	}
+	return feature, nil
+}
+
+// LoadOptions contains options for [Env.Load].  The options correspond to
+// the optional arguments of the Emacs function load.
+type LoadOptions struct {
+	// If NoError is true, Load returns false instead of an error if the
+	// file can’t be found.  Other errors are still reported.
+	NoError bool
+
+	// If NoMessage is true, Load doesn’t display messages while loading.
+	NoMessage bool
+
+	// If NoSuffix is true, Load doesn’t try adding the suffixes .elc and
+	// .el to the file name.
+	NoSuffix bool
+
+	// If MustSuffix is true, Load only loads files whose names end in
+	// .elc or .el.
+	MustSuffix bool
+}
+
+// Load loads a Lisp file by calling the Emacs function load.  It returns true
+// if the file was loaded, and false if opts.NoError is true and the file
+// wasn’t found.  If loading fails, Load returns an error of type
+// *[LoadError].
+func (e Env) Load(file string, opts LoadOptions) (bool, error) {
+	var r Bool
+	err := e.CallOut("load", &r, String(file), Bool(opts.NoError), Bool(opts.NoMessage), Bool(opts.NoSuffix), Bool(opts.MustSuffix))
+	if err != nil {
+		return false, e.loadError(err, file, "")
+	}
+	return bool(r), nil
+}
+
+// LoadFile loads the Lisp file with exactly the given name, like the Emacs
+// command load-file.  Relative file names are relative to the default
+// directory of the current buffer.  If loading fails, LoadFile returns an
+// error of type *[LoadError].
+func (e Env) LoadFile(file string) error {
+	abs, err := e.Call("expand-file-name", String(file))
+	if err != nil {
+		return err
+	}
+	if _, err := e.Call("load", abs, Nil, Nil, T); err != nil {
+		return e.loadError(err, file, "")
+	}
+	return nil
+}
+
+// LoadErrorKind classifies errors reported by [Env.Require], [Env.Load], and
+// [Env.LoadFile].
+type LoadErrorKind int
+
+const (
+	// LoadFailed means that evaluating the file signaled an error.
+	LoadFailed LoadErrorKind = iota
+
+	// FileMissing means that the file or a file that it loads wasn’t
+	// found.
+	FileMissing
+
+	// FeatureMissing means that [Env.Require] loaded the file, but the
+	// file didn’t provide the feature.
+	FeatureMissing
+
+	// ReadError means that the file or a file that it loads contains
+	// invalid Lisp syntax.
+	ReadError
+)
+
+// String returns a description of the error kind.
+func (k LoadErrorKind) String() string {
+	switch k {
+	case LoadFailed:
+		return "load failed"
+	case FileMissing:
+		return "file missing"
+	case FeatureMissing:
+		return "feature missing"
+	case ReadError:
+		return "read error"
+	default:
+		return fmt.Sprintf("LoadErrorKind(%d)", int(k))
+	}
+}
+
+// LoadError is an error returned by [Env.Require], [Env.Load], and
+// [Env.LoadFile].  When returned from an exported function, it signals the
+// original Emacs error.
+type LoadError struct {
+	// Kind classifies the error.
+	Kind LoadErrorKind
+
+	// File is the file name passed to the loading function, if any.
+	File string
+
+	// Feature is the feature passed to [Env.Require], if any.
+	Feature Name
+
+	// Message is the Emacs error message.
+	Message string
+
+	// Err is the original error, typically of type [Signal].
+	Err error
+}
+
+// Error implements the error interface.
+func (l *LoadError) Error() string {
+	what := l.File
+	if l.Feature != "" {
+		what = "feature " + string(l.Feature)
+	}
+	return fmt.Sprintf("loading %s: %s: %s", what, l.Kind, l.Message)
+}
+
+// Unwrap returns l.Err.
+func (l *LoadError) Unwrap() error { return l.Err }
+
+func (l *LoadError) signal(e Env) C.struct_result_base_with_optional_error_info {
+	if n, ok := l.Err.(nonlocalExit); ok {
+		return n.signal(e)
+	}
+	return Error{baseError, List{String(l.Error())}}.signal(e)
+}
+
+// loadError converts an error signaled while loading a file into a
+// *LoadError.  Throws are returned unchanged.
+func (e Env) loadError(err error, file string, feature Name) error {
+	sig, ok := err.(Signal)
+	if !ok {
+		return err
+	}
+	kind := LoadFailed
+	switch {
+	case e.hasCondition(sig, "file-missing"):
+		kind = FileMissing
+	case e.hasCondition(sig, "invalid-read-syntax"), e.hasCondition(sig, "end-of-file"):
+		kind = ReadError
+	case feature != "" && e.isFeatureMissing(sig, feature):
+		kind = FeatureMissing
+	}
+	return &LoadError{kind, file, feature, sig.Message(e), err}
+}
+
+// hasCondition returns whether the error symbol of sig has the given
+// condition.  It returns false if that can’t be determined.
+func (e Env) hasCondition(sig Signal, cond Symbol) bool {
+	var r Bool
+	err := e.CallOut("eval", &r, List{
+		Symbol("memq"), List{Symbol("quote"), cond},
+		List{Symbol("get"), List{Symbol("quote"), sig.Symbol}, List{Symbol("quote"), Symbol("error-conditions")}},
+	})
+	return err == nil && bool(r)
+}
+
+// isFeatureMissing returns whether sig is the error that require signals if a
+// file doesn’t provide the requested feature.
+func (e Env) isFeatureMissing(sig Signal, feature Name) bool {
+	var provided Bool
+	if err := e.CallOut("featurep", &provided, feature); err != nil || provided {
+		return false
+	}
+	plain, err := e.Intern("error")
+	if err != nil || !e.Eq(sig.Symbol, plain) {
+		return false
+	}
+	// There’s no dedicated error symbol, so we have to look at the
+	// message.  See the definition of require in fns.c.
+	return strings.Contains(sig.Message(e), "failed to provide feature")
+}
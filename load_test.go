@@ -0,0 +1,89 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func init() {
+	ERTTest(requireAndLoad)
+}
+
+func requireAndLoad(e Env) error {
+	if got, err := e.Require("subr-x", RequireOptions{}); err != nil || got != "subr-x" {
+		return fmt.Errorf("Require(subr-x): got %q, %v; want subr-x, nil", got, err)
+	}
+	if got, err := e.Require("emacs-test-no-such-feature", RequireOptions{NoError: true}); err != nil || got != "" {
+		return fmt.Errorf("Require with NoError: got %q, %v; want empty name, nil", got, err)
+	}
+	dir, err := os.MkdirTemp("", "emacs-test-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	noProvide := filepath.Join(dir, "no-provide.el")
+	broken := filepath.Join(dir, "broken.el")
+	good := filepath.Join(dir, "good.el")
+	for file, contents := range map[string]string{
+		noProvide: "(defvar emacs-test--no-provide t)\n",
+		broken:    "(defvar emacs-test--broken\n",
+		good:      "(defvar emacs-test--good t)\n",
+	} {
+		if err := os.WriteFile(file, []byte(contents), 0600); err != nil {
+			return err
+		}
+	}
+	for _, tc := range []struct {
+		desc string
+		load func() error
+		want LoadErrorKind
+	}{
+		{"missing feature file", func() error {
+			_, err := e.Require("emacs-test-no-such-feature", RequireOptions{})
+			return err
+		}, FileMissing},
+		{"file not providing feature", func() error {
+			_, err := e.Require("emacs-test-no-provide", RequireOptions{Filename: noProvide})
+			return err
+		}, FeatureMissing},
+		{"broken file", func() error {
+			_, err := e.Load(broken, LoadOptions{NoMessage: true})
+			return err
+		}, ReadError},
+		{"missing file", func() error {
+			return e.LoadFile(filepath.Join(dir, "missing.el"))
+		}, FileMissing},
+	} {
+		err := tc.load()
+		var l *LoadError
+		if !errors.As(err, &l) {
+			return fmt.Errorf("%s: got error %v, want LoadError", tc.desc, err)
+		}
+		if l.Kind != tc.want {
+			return fmt.Errorf("%s: got error kind %s, want %s", tc.desc, l.Kind, tc.want)
+		}
+	}
+	if err := e.LoadFile(good); err != nil {
+		return err
+	}
+	if ok, err := e.Load(filepath.Join(dir, "missing.el"), LoadOptions{NoError: true}); ok || err != nil {
+		return fmt.Errorf("Load with NoError: got %t, %v; want false, nil", ok, err)
+	}
+	return nil
+}
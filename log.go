@@ -0,0 +1,200 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// LogOptions contains options for [NewLogHandler].
+type LogOptions struct {
+	// Level is the minimum level of records to forward.  If nil, use
+	// [slog.LevelInfo].
+	Level slog.Leveler
+
+	// Buffer is the name of a buffer to append log records to.  If
+	// empty, log records are shown using message and end up in the
+	// *Messages* buffer.
+	Buffer string
+
+	// RingSize is the number of most recent log records to keep in
+	// memory.  Use [LogHandler.Records] or [LogHandler.DefineCommand] to
+	// access them.  If zero, no records are kept.
+	RingSize int
+}
+
+// LogHandler is a [slog.Handler] that forwards log records to Emacs.  Create
+// LogHandler objects using [NewLogHandler]; the zero LogHandler isn’t valid.
+// You can use a LogHandler from any goroutine.  Log records are formatted
+// like [slog.TextHandler] and queued, and the LogHandler notifies Emacs using
+// the notification channel passed to [NewLogHandler].  When notified, Emacs
+// should call [LogHandler.Flush] to show the pending records, similar to
+// [Async.Flush].
+//
+// To also forward output of the standard log package, pass the result of
+// [LogHandler.Writer] to [log.SetOutput].
+type LogHandler struct {
+	inner slog.Handler
+	q     *logQueue
+}
+
+type logQueue struct {
+	notify chan<- struct{}
+	opts   LogOptions
+
+	mu       sync.Mutex
+	buf      bytes.Buffer // output of the inner handler
+	pending  []string
+	notified bool
+	ring     []string
+	next     int // next ring index to overwrite once the ring is full
+}
+
+// NewLogHandler creates a new [LogHandler].  It uses the given notification
+// channel to tell Emacs about pending log records; use [NotifyWriter] or
+// [NotifyListener] to create usable channels.
+func NewLogHandler(notify chan<- struct{}, opts LogOptions) *LogHandler {
+	if notify == nil {
+		panic("nil notification channel")
+	}
+	q := &logQueue{notify: notify, opts: opts}
+	inner := slog.NewTextHandler(&q.buf, &slog.HandlerOptions{Level: opts.Level})
+	return &LogHandler{inner, q}
+}
+
+// Enabled implements [slog.Handler.Enabled].
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler.Handle].
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.q.mu.Lock()
+	defer h.q.mu.Unlock()
+	h.q.buf.Reset()
+	if err := h.inner.Handle(ctx, r); err != nil {
+		return err
+	}
+	h.q.addLocked(h.q.buf.String())
+	return nil
+}
+
+// WithAttrs implements [slog.Handler.WithAttrs].
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{h.inner.WithAttrs(attrs), h.q}
+}
+
+// WithGroup implements [slog.Handler.WithGroup].
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{h.inner.WithGroup(name), h.q}
+}
+
+// Writer returns a writer that forwards each line written to it as a log
+// record, bypassing the level check.  It’s intended for [log.SetOutput].
+func (h *LogHandler) Writer() io.Writer {
+	return logWriter{h.q}
+}
+
+type logWriter struct{ q *logQueue }
+
+func (w logWriter) Write(b []byte) (int, error) {
+	w.q.mu.Lock()
+	defer w.q.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+		w.q.addLocked(line)
+	}
+	return len(b), nil
+}
+
+func (q *logQueue) addLocked(s string) {
+	s = strings.TrimSuffix(s, "\n")
+	q.pending = append(q.pending, s)
+	if n := q.opts.RingSize; n > 0 {
+		if len(q.ring) < n {
+			q.ring = append(q.ring, s)
+		} else {
+			q.ring[q.next] = s
+			q.next = (q.next + 1) % n
+		}
+	}
+	if !q.notified {
+		q.notified = true
+		// Don’t block the logging goroutine if Emacs is busy.
+		go func() { q.notify <- struct{}{} }()
+	}
+}
+
+// Records returns the most recent log records, oldest first.  It returns at
+// most RingSize records; see [LogOptions].
+func (h *LogHandler) Records() []string {
+	h.q.mu.Lock()
+	defer h.q.mu.Unlock()
+	r := make([]string, 0, len(h.q.ring))
+	r = append(r, h.q.ring[h.q.next:]...)
+	return append(r, h.q.ring[:h.q.next]...)
+}
+
+// Flush shows all pending log records in Emacs and removes them from the
+// queue.  Call Flush from Emacs Lisp when notified about pending records.
+func (h *LogHandler) Flush(e Env) error {
+	h.q.mu.Lock()
+	pending := h.q.pending
+	h.q.pending = nil
+	h.q.notified = false
+	h.q.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+	if h.q.opts.Buffer == "" {
+		for _, s := range pending {
+			if _, err := e.Call("message", String("%s"), String(s)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return e.appendToBuffer(h.q.opts.Buffer, strings.Join(pending, "\n")+"\n")
+}
+
+// DefineCommand defines an interactive command with the given name that
+// displays the records returned by [LogHandler.Records] in a buffer.
+func (h *LogHandler) DefineCommand(e Env, name Name) error {
+	f, err := e.ExportFunc(name, func(e Env, args []Value) (Value, error) {
+		var text string
+		if r := h.Records(); len(r) > 0 {
+			text = strings.Join(r, "\n") + "\n"
+		}
+		return e.showReport("*"+string(name)+"*", text)
+	}, Arity{0, 0}, "Display the most recent log records of the module.")
+	if err != nil {
+		return err
+	}
+	spec, err := Nil.Emacs(e)
+	if err != nil {
+		return err
+	}
+	return e.MakeInteractive(f, spec)
+}
+
+// appendToBuffer inserts text at the end of the buffer with the given name,
+// creating the buffer if necessary.
+func (e Env) appendToBuffer(buffer, text string) error {
+	return e.insertAtEnd(List{Symbol("get-buffer-create"), String(buffer)}, text)
+}
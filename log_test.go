@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func init() {
+	ERTTest(logHandlerFlush)
+}
+
+func TestLogHandler(t *testing.T) {
+	notify := make(chan struct{}, 1)
+	h := NewLogHandler(notify, LogOptions{RingSize: 2})
+	logger := slog.New(h).With("module", "test")
+	logger.Debug("hidden")
+	logger.Info("first")
+	<-notify
+	logger.Warn("second", "n", 2)
+	l := log.New(h.Writer(), "", 0)
+	l.Print("third")
+	got := h.Records()
+	if len(got) != 2 {
+		t.Fatalf("got %d records, want 2: %q", len(got), got)
+	}
+	if !strings.Contains(got[0], `level=WARN msg=second module=test n=2`) {
+		t.Errorf("record 0: got %q, want the second message", got[0])
+	}
+	if got[1] != "third" {
+		t.Errorf("record 1: got %q, want %q", got[1], "third")
+	}
+	if n := len(h.q.pending); n != 3 {
+		t.Errorf("got %d pending records, want 3", n)
+	}
+}
+
+func logHandlerFlush(e Env) error {
+	const buffer = " *emacs-go-test-log*"
+	notify := make(chan struct{}, 1)
+	h := NewLogHandler(notify, LogOptions{Buffer: buffer})
+	slog.New(h).Info("hello", "answer", 42)
+	if err := h.Flush(e); err != nil {
+		return err
+	}
+	defer e.Call("kill-buffer", String(buffer))
+	var text String
+	v, err := e.Eval(List{
+		Symbol("with-current-buffer"), String(buffer),
+		List{Symbol("buffer-string")},
+	})
+	if err != nil {
+		return err
+	}
+	if err := text.FromEmacs(e, v); err != nil {
+		return err
+	}
+	if !strings.Contains(string(text), "msg=hello answer=42\n") {
+		return fmt.Errorf("log buffer contents: got %q", text)
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-// Copyright 2020, 2023 Google LLC
+// Copyright 2020, 2023, 2026 Google LLC
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -17,47 +17,88 @@ package emacs
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"unicode"
 )
 
-// Manager simplifies registering and maintaining Emacs entities.  Often,
-// entities such as functions, variables, or error symbols should be registered
-// during initialization of the Go package, when Emacs hasn’t yet loaded the
-// module.  In a second phase, these entities then have to be defined as soon
-// as Emacs has loaded the module.  To support this form of two-phase
-// initialization, Manager maintains a queue of [QueuedItem] objects for later
-// definition.
+// ManagerOf simplifies registering and maintaining Emacs entities of type T.
+// Often, entities such as functions, variables, or error symbols should be
+// registered during initialization of the Go package, when Emacs hasn’t yet
+// loaded the module.  In a second phase, these entities then have to be
+// defined as soon as Emacs has loaded the module.  To support this form of
+// two-phase initialization, ManagerOf maintains a queue of [QueuedItem]
+// objects for later definition.
 //
-// Optionally, you can use a Manager to ensure that entities are named and/or
-// that names are unique within the scope of the manager.
+// Optionally, you can use a ManagerOf to ensure that entities are named
+// and/or that names are unique within the scope of the manager.  Named
+// entities can be removed or replaced using [ManagerOf.Remove] and
+// [ManagerOf.Replace].  [ManagerOf.Items] and [ManagerOf.Lookup] return the
+// registered items with their static type, so that you don’t need type
+// assertions to inspect them.
 //
-// You can use [NewManager] to create Manager objects.  The zero Manager is
-// valid and equivalent to NewManager(0).  Manager objects can’t be copied once
-// they are initialized.
+// You can use [NewManagerOf] to create ManagerOf objects.  The zero ManagerOf
+// is valid and equivalent to NewManagerOf[T](0).  ManagerOf objects can’t be
+// copied once they are initialized.
 //
 // Usually you’d create one global manager object per entity type.  All methods
-// of Manager are safe for concurrent use, assuming the QueuedItems aren’t
+// of ManagerOf are safe for concurrent use, assuming the QueuedItems aren’t
 // modified after registering them, and [QueuedItem.Define] is safe for
 // concurrent use.
-type Manager struct {
+type ManagerOf[T QueuedItem] struct {
 	mu    sync.Mutex
 	flag  ManagerFlag
-	queue []QueuedItem
 	names map[Name]struct{}
+
+	// queue contains the entries that haven’t been defined yet, in
+	// registration order.  Entries that have been defined in the
+	// meantime are removed lazily by compactLocked.
+	queue []*queuedEntry[T]
+
+	// all contains the named entries and the unnamed entries that haven’t
+	// been defined yet, in registration order.  Unnamed entries that have
+	// been defined can’t be looked up, removed, or replaced, so we don’t
+	// keep them; see compactLocked.
+	all []*queuedEntry[T]
+
+	// byName indexes the named entries in all.
+	byName map[Name][]*queuedEntry[T]
+
+	// prefix is the namespace prefix set by SetPrefix.  If it’s empty,
+	// names aren’t checked.
+	prefix Name
+	exempt map[Name]struct{}
+
+	// deps contains the dependencies declared using DependOn.
+	deps map[Name][]Name
+
+	observers []func(Name, error)
 }
 
+// Manager is a [ManagerOf] for arbitrary [QueuedItem] objects.  Use
+// [NewManager] to create Manager objects.  The zero Manager is valid and
+// equivalent to NewManager(0).
+type Manager = ManagerOf[QueuedItem]
+
 // NewManager creates a new [Manager] object with the given flags.  If flags
 // includes [RequireName], then all entities registered on this manager must
 // have a nonempty name.  If flags includes [RequireUniqueName], then all named
 // entities must have unique names.  If flags includes [DefineOnInit],
-// NewManager arranges for [Manager.DefineQueued] to be called when the module
+// NewManager arranges for [ManagerOf.DefineQueued] to be called when the module
 // is initialized.  NewManager(0) is equivalent to new(Manager).
 //
 // Having flags include [RequireUniqueName] but not [RequireName] is valid; in
 // this case names are optional (i. e. the registration functions accept empty
 // names), but if an entity is named, its name must be unique.
 func NewManager(flags ManagerFlag) *Manager {
-	m := &Manager{flag: flags}
+	return NewManagerOf[QueuedItem](flags)
+}
+
+// NewManagerOf creates a new [ManagerOf] object with the given flags.  The
+// flags have the same meaning as for [NewManager].  NewManagerOf[T](0) is
+// equivalent to new(ManagerOf[T]).
+func NewManagerOf[T QueuedItem](flags ManagerFlag) *ManagerOf[T] {
+	m := &ManagerOf[T]{flag: flags}
 	if flags&RequireUniqueName != 0 {
 		m.names = make(map[Name]struct{})
 	}
@@ -71,19 +112,35 @@ func NewManager(flags ManagerFlag) *Manager {
 type ManagerFlag uint
 
 const (
-	// RequireName causes a [Manager] to fail if the name of an entity to
+	// RequireName causes a [ManagerOf] to fail if the name of an entity to
 	// be managed is empty.
 	RequireName ManagerFlag = 1 << iota
 
-	// RequireUniqueName causes a [Manager] to fail if two named entities
+	// RequireUniqueName causes a [ManagerOf] to fail if two named entities
 	// have the same name.
 	RequireUniqueName
 
-	// DefineOnInit arranges for [Manager.DefineQueued] to be called when
+	// DefineOnInit arranges for [ManagerOf.DefineQueued] to be called when
 	// the module is initialized.
 	DefineOnInit
 
+	// MultiPhase allows [ManagerOf.DefineQueued] to be called more than
+	// once, and [ManagerOf.Enqueue] to be called after
+	// [ManagerOf.DefineQueued].  Each call to DefineQueued defines the
+	// items enqueued since the previous call.  This is useful for
+	// plugin-style architectures where items can be registered late.
+	// Note that items enqueued after module initialization aren’t defined
+	// until you call DefineQueued again, even if the flags include
+	// [DefineOnInit].
+	MultiPhase
+
+	// ContinueOnError causes [ManagerOf.DefineQueued] to continue defining
+	// the remaining items if defining an item fails.  DefineQueued then
+	// returns all errors joined using [errors.Join].
+	ContinueOnError
+
 	initDone
+	listed // manager is in the global list of managers
 )
 
 // Enqueue registers a [QueuedItem] for later definition.  Enqueue is usually
@@ -91,9 +148,9 @@ const (
 // may not be empty if the flag [RequireName] has been passed to [NewManager].
 // The name must be unique if it’s nonempty and the flag [RequireUniqueName]
 // has been passed to [NewManager].  If neither flag was passed, the name is
-// ignored.  Enqueue returns an error if [Manager.RegisterAndDefine] has
-// already been called.
-func (m *Manager) Enqueue(name Name, item QueuedItem) error {
+// ignored.  Enqueue returns an error if [ManagerOf.DefineQueued] has already
+// been called, unless the flags include [MultiPhase].
+func (m *ManagerOf[T]) Enqueue(name Name, item T) error {
 	return m.register(name, item, true)
 }
 
@@ -103,9 +160,10 @@ func (m *Manager) Enqueue(name Name, item QueuedItem) error {
 // NewManager.  The name must be unique if it’s nonempty and the flag
 // [RequireUniqueName] has been passed to [NewManager].  If neither flag was
 // passed, the name is ignored.  MustEnqueue panics if the name is invalid or
-// if [Manager.RegisterAndDefine] has already been called.  MustEnqueue is like
-// [Manager.Enqueue], except that it panics on all errors.
-func (m *Manager) MustEnqueue(name Name, item QueuedItem) {
+// if [ManagerOf.DefineQueued] has already been called, unless the flags include
+// [MultiPhase].  MustEnqueue is like [ManagerOf.Enqueue], except that it panics
+// on all errors.
+func (m *ManagerOf[T]) MustEnqueue(name Name, item T) {
 	if err := m.Enqueue(name, item); err != nil {
 		panic(err)
 	}
@@ -118,29 +176,299 @@ func (m *Manager) MustEnqueue(name Name, item QueuedItem) {
 // The name must be unique if it’s nonempty and the flag [RequireUniqueName]
 // has been passed to [NewManager].  If neither flag was passed, the name is
 // ignored.
-func (m *Manager) RegisterAndDefine(e Env, name Name, item QueuedItem) error {
+func (m *ManagerOf[T]) RegisterAndDefine(e Env, name Name, item T) error {
 	if err := m.register(name, item, false); err != nil {
 		return err
 	}
-	return item.Define(e)
+	err := item.Define(e)
+	m.notify(name, err)
+	return err
+}
+
+// OnDefine registers an observer function.  The manager calls f after each
+// call to [QueuedItem.Define], passing the name of the item (empty for
+// unnamed items) and the error returned by Define.  This includes definitions
+// by [ManagerOf.DefineQueued], [ManagerOf.RegisterAndDefine],
+// [ManagerOf.Replace], and [ManagerOf.Redefine].  Observers are called in
+// registration order, on the goroutine that defines the item.  Use observers
+// for logging, metrics, or test instrumentation of the definition process.
+func (m *ManagerOf[T]) OnDefine(f func(Name, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observers = append(m.observers, f)
+}
+
+// notify calls the observers registered using OnDefine.
+func (m *ManagerOf[T]) notify(name Name, err error) {
+	m.mu.Lock()
+	observers := m.observers
+	m.mu.Unlock()
+	for _, f := range observers {
+		f(name, err)
+	}
 }
 
 // DefineQueued defines all queued items using the given environment.
-// DefineQueued may be called at most once.  Usually, you should call it during
-// module initialization, either using OnInit or by passing the [DefineOnInit]
-// flag to [NewManager].  Once DefineQueued has been called, [Manager.Enqueue]
-// and [Manager.MustEnqueue] can no longer be used.  DefineQueued returns the
-// error of the first failed definition, or nil if all definitions succeeded.
-func (m *Manager) DefineQueued(e Env) error {
-	for _, i := range m.drain() {
-		if err := i.Define(e); err != nil {
+// DefineQueued may be called at most once, unless the flags include
+// [MultiPhase].  Usually, you should call it during module initialization,
+// either using OnInit or by passing the [DefineOnInit] flag to [NewManager].
+// Once DefineQueued has been called, [ManagerOf.Enqueue] and
+// [ManagerOf.MustEnqueue] can no longer be used, unless the flags include
+// [MultiPhase].  DefineQueued returns the error of the first failed
+// definition, or nil if all definitions succeeded.  Items after the failed
+// one stay queued and don’t count as defined; with [MultiPhase], the next call
+// to DefineQueued defines them.  If the flags include [ContinueOnError],
+// DefineQueued instead defines all items and returns the errors of all failed
+// definitions.  DefineQueued records how long each
+// definition took; use [DefinitionTimings] to retrieve the durations.
+//
+// DefineQueued defines items in registration order, except that it defines
+// the dependencies of an item before the item itself; see [Dependent] and
+// [ManagerOf.DependOn].  If a dependency is still queued in another manager,
+// DefineQueued takes it out of that manager’s queue and defines it first.  If
+// the dependencies form a cycle, DefineQueued stops and returns an error
+// describing the cycle, even if the flags include [ContinueOnError].
+func (m *ManagerOf[T]) DefineQueued(e Env) error {
+	err := m.define(e, m.drain())
+	m.mu.Lock()
+	m.compactLocked()
+	m.mu.Unlock()
+	return err
+}
+
+// Redefine calls [QueuedItem.Define] again for all named items that have
+// already been defined, in registration order.  Items that are still queued
+// aren’t affected.  This is useful if the definitions depend on state that has
+// changed since the items were defined, for example to switch between debug
+// and release variants of functions.  Functions exported using [Export] or
+// [Env.ExportFunc] keep working while and after they are redefined.  Like
+// [ManagerOf.DefineQueued], Redefine returns the error of the first failed
+// definition, or the errors of all failed definitions if the flags include
+// [ContinueOnError], and records how long each definition took.
+func (m *ManagerOf[T]) Redefine(e Env) error {
+	m.mu.Lock()
+	var entries []*queuedEntry[T]
+	for _, q := range m.all {
+		if q.defined {
+			entries = append(entries, q)
+		}
+	}
+	m.mu.Unlock()
+	return m.define(e, entries)
+}
+
+// define defines the given entries.  See [ManagerOf.DefineQueued] for the error
+// semantics.
+func (m *ManagerOf[T]) define(e Env, entries []*queuedEntry[T]) error {
+	d := newDefiner(e, m.flag&ContinueOnError != 0, m.nodes(entries))
+	return d.run()
+}
+
+// SetPrefix arranges for the manager to check that the names of items
+// registered from now on follow the Emacs [naming conventions]: a name must
+// either be prefix itself or start with prefix followed by a hyphen, and it may
+// not contain uppercase letters, underscores, or whitespace.  Registering an
+// item with a name that violates these conventions fails, unless the name has
+// been passed to [ManagerOf.Exempt].  Unnamed items aren’t checked.  To apply
+// the prefix automatically, pass names through [ManagerOf.Qualify] before
+// registering them.  Pass an empty prefix to disable the checks.  Call
+// SetPrefix before registering any items, typically right after creating the
+// manager.
+//
+// [naming conventions]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Coding-Conventions.html
+func (m *ManagerOf[T]) SetPrefix(prefix Name) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prefix = prefix
+}
+
+// Qualify returns name with the prefix set by [ManagerOf.SetPrefix] applied.
+// If there’s no prefix, or name is empty or already starts with the prefix,
+// Qualify returns name unchanged.  Otherwise, it returns the prefix, a hyphen,
+// and name.
+func (m *ManagerOf[T]) Qualify(name Name) Name {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.prefix == "" || name == "" || hasPrefix(name, m.prefix) {
+		return name
+	}
+	return m.prefix + "-" + name
+}
+
+// Exempt exempts the given name from the checks enabled by
+// [ManagerOf.SetPrefix].  Use this for the few names that deliberately live
+// outside the namespace of the package, for example advice or hook functions
+// whose names are dictated by other packages.
+func (m *ManagerOf[T]) Exempt(name Name) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.exempt == nil {
+		m.exempt = make(map[Name]struct{})
+	}
+	m.exempt[name] = struct{}{}
+}
+
+// checkNameLocked checks name against the prefix set by SetPrefix.
+func (m *ManagerOf[T]) checkNameLocked(name Name) error {
+	if m.prefix == "" || name == "" {
+		return nil
+	}
+	if _, ok := m.exempt[name]; ok {
+		return nil
+	}
+	return checkNamespace(m.prefix, name)
+}
+
+// checkNamespace checks whether name follows the Emacs naming conventions
+// for a package with the given prefix.
+func checkNamespace(prefix, name Name) error {
+	if !hasPrefix(name, prefix) {
+		return fmt.Errorf("name %s doesn’t start with prefix %s-", name, prefix)
+	}
+	for _, r := range name {
+		if unicode.IsUpper(r) || r == '_' || unicode.IsSpace(r) {
+			return fmt.Errorf("name %s contains %q, which violates Emacs naming conventions", name, r)
+		}
+	}
+	return nil
+}
+
+func hasPrefix(name, prefix Name) bool {
+	return name == prefix || strings.HasPrefix(string(name), string(prefix)+"-")
+}
+
+// Remove removes the items with the given name from the manager.  If the items
+// haven’t been defined yet, Remove simply removes them from the queue; e isn’t
+// used in this case and may be the zero [Env], so that Remove also works
+// before Emacs has loaded the module.  Otherwise, the items must implement
+// [Undefiner], and Remove calls their Undefine method using e.  Remove returns
+// an error if there’s no item with the given name or an item can’t be
+// undefined; in the latter case, no item is removed.  After Remove, the name
+// can be registered again.
+func (m *ManagerOf[T]) Remove(e Env, name Name) error {
+	entries, queued, err := m.find(name)
+	if err != nil {
+		return err
+	}
+	for i, q := range entries {
+		if queued[i] {
+			continue
+		}
+		if _, ok := interface{}(q.item).(Undefiner); !ok {
+			return fmt.Errorf("%s can’t be undefined", name)
+		}
+	}
+	for i, q := range entries {
+		if !queued[i] {
+			if err := interface{}(q.item).(Undefiner).Undefine(e); err != nil {
+				return err
+			}
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queue = removeEntries(m.queue, name)
+	m.all = removeEntries(m.all, name)
+	delete(m.byName, name)
+	delete(m.names, name)
+	return nil
+}
+
+// Replace replaces the item with the given name by a new item.  If the old
+// item hasn’t been defined yet, Replace replaces it in the queue, keeping its
+// position; e isn’t used in this case and may be the zero [Env], so that
+// Replace also works before Emacs has loaded the module.  Otherwise, Replace
+// undefines the old item using e if it implements [Undefiner], and then
+// defines the new item using e.  Replace returns an error if there isn’t
+// exactly one item with the given name.
+func (m *ManagerOf[T]) Replace(e Env, name Name, item T) error {
+	entries, queued, err := m.find(name)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 1 {
+		return fmt.Errorf("multiple items named %s", name)
+	}
+	if !queued[0] {
+		if u, ok := interface{}(entries[0].item).(Undefiner); ok {
+			if err := u.Undefine(e); err != nil {
+				return err
+			}
+		}
+		err := item.Define(e)
+		m.notify(name, err)
+		if err != nil {
 			return err
 		}
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// The queue shares the entry with byName.
+	for _, q := range m.byName[name] {
+		q.item = item
+	}
 	return nil
 }
 
-func (m *Manager) register(name Name, item QueuedItem, queue bool) error {
+// find returns the entries with the given name and whether each of them is
+// still queued.  It returns an error if there are no such entries.
+func (m *ManagerOf[T]) find(name Name) ([]queuedEntry[T], []bool, error) {
+	if name == "" {
+		return nil, nil, errors.New("empty name")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var entries []queuedEntry[T]
+	var queued []bool
+	for _, q := range m.byName[name] {
+		entries = append(entries, *q)
+		queued = append(queued, !q.defined)
+	}
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("no item named %s", name)
+	}
+	return entries, queued, nil
+}
+
+func removeEntries[T QueuedItem](s []*queuedEntry[T], name Name) []*queuedEntry[T] {
+	return filterEntries(s, func(q *queuedEntry[T]) bool { return q.name != name })
+}
+
+// filterEntries removes the entries for which keep returns false from s,
+// reusing its storage.
+func filterEntries[T QueuedItem](s []*queuedEntry[T], keep func(*queuedEntry[T]) bool) []*queuedEntry[T] {
+	r := s[:0]
+	for _, q := range s {
+		if keep(q) {
+			r = append(r, q)
+		}
+	}
+	// Don’t keep references to removed items.
+	for i := len(r); i < len(s); i++ {
+		s[i] = nil
+	}
+	return r
+}
+
+// definitionError is an error returned by [QueuedItem.Define] together with
+// the item name and the error message.  We compute the message eagerly
+// because [Signal.Error] doesn’t return the actual message.
+type definitionError struct {
+	name Name
+	msg  string
+	err  error
+}
+
+func (d definitionError) Error() string {
+	if d.name == "" {
+		return d.msg
+	}
+	return fmt.Sprintf("%s: %s", d.name, d.msg)
+}
+
+func (d definitionError) Unwrap() error { return d.err }
+
+func (m *ManagerOf[T]) register(name Name, item T, queue bool) error {
 	if name != "" {
 		if err := name.validate(); err != nil {
 			return err
@@ -148,12 +476,15 @@ func (m *Manager) register(name Name, item QueuedItem, queue bool) error {
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if queue && m.flag&initDone != 0 {
+	if queue && m.flag&(initDone|MultiPhase) == initDone {
 		return errors.New("initialization already complete")
 	}
 	if name == "" && m.flag&RequireName != 0 {
 		return errors.New("unnamed queue item")
 	}
+	if err := m.checkNameLocked(name); err != nil {
+		return err
+	}
 	if name != "" && m.flag&RequireUniqueName != 0 {
 		if _, dup := m.names[name]; dup {
 			return fmt.Errorf("duplicate name %s", name)
@@ -161,27 +492,117 @@ func (m *Manager) register(name Name, item QueuedItem, queue bool) error {
 		// No more non-fatal errors from this point on.
 		m.names[name] = struct{}{}
 	}
+	if name != "" && m.flag&listed == 0 {
+		managers.add(m)
+		m.flag |= listed
+	}
+	entry := &queuedEntry[T]{name, item, !queue}
 	if queue {
-		m.queue = append(m.queue, item)
+		m.queue = append(m.queue, entry)
+	}
+	if name != "" {
+		if m.byName == nil {
+			m.byName = make(map[Name][]*queuedEntry[T])
+		}
+		m.byName[name] = append(m.byName[name], entry)
+	}
+	if name != "" || queue {
+		m.all = append(m.all, entry)
 	}
 	return nil
 }
 
-func (m *Manager) drain() []QueuedItem {
+func (m *ManagerOf[T]) drain() []*queuedEntry[T] {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if m.flag&initDone != 0 {
+	if m.flag&(initDone|MultiPhase) == initDone {
 		panic("initialization already complete")
 	}
-	r := make([]QueuedItem, len(m.queue))
+	// Entries stay in the queue until they have been passed to Define;
+	// see markDefined.  Another manager might have defined some of them
+	// as dependencies in the meantime.
+	m.compactLocked()
+	r := make([]*queuedEntry[T], len(m.queue))
 	copy(r, m.queue)
-	m.queue = nil
 	m.flag |= initDone
 	return r
 }
 
-// QueuedItem is an item stored in a [Manager]’s queue.
+// markDefined records that q has been passed to [QueuedItem.Define].  The
+// next call to compactLocked removes it from the queue.
+func (m *ManagerOf[T]) markDefined(q *queuedEntry[T]) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q.defined = true
+}
+
+// compactLocked removes defined entries from the queue and defined unnamed
+// entries from the list of all entries.  m.mu must be locked.
+func (m *ManagerOf[T]) compactLocked() {
+	m.queue = filterEntries(m.queue, func(q *queuedEntry[T]) bool { return !q.defined })
+	m.all = filterEntries(m.all, func(q *queuedEntry[T]) bool { return q.name != "" || !q.defined })
+}
+
+// Items returns all named items registered so far and all unnamed items that
+// are still queued, in registration order.  This includes named items that
+// have already been defined, but not items that have been removed using
+// [ManagerOf.Remove].
+func (m *ManagerOf[T]) Items() []T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := make([]T, len(m.all))
+	for i, q := range m.all {
+		r[i] = q.item
+	}
+	return r
+}
+
+// Lookup returns the first item registered with the given name.  It returns
+// false if there’s no such item.
+func (m *ManagerOf[T]) Lookup(name Name) (T, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s := m.byName[name]; len(s) > 0 {
+		return s[0].item, true
+	}
+	var zero T
+	return zero, false
+}
+
+// entries returns copies of the entries in m.all, in registration order.
+// This includes named entries that have already been defined.
+func (m *ManagerOf[T]) entries() []queuedEntry[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := make([]queuedEntry[T], len(m.all))
+	for i, q := range m.all {
+		r[i] = *q
+	}
+	return r
+}
+
+// queuedEntry is an item in a [ManagerOf]’s queue together with its name and
+// state.  The name is empty for unnamed items.
+type queuedEntry[T QueuedItem] struct {
+	name Name
+	item T
+
+	// defined specifies whether the item has been passed to
+	// [QueuedItem.Define].
+	defined bool
+}
+
+// QueuedItem is an item stored in a [ManagerOf]’s queue.
 type QueuedItem interface {
 	// Define should define the item using the given Emacs environment.
 	Define(Env) error
 }
+
+// Undefiner is an optional interface for [QueuedItem] objects.  Items that
+// implement Undefiner can be removed from a [ManagerOf] after they have been
+// defined; see [ManagerOf.Remove].
+type Undefiner interface {
+	// Undefine should undo the effect of Define using the given Emacs
+	// environment.
+	Undefine(Env) error
+}
@@ -14,13 +14,19 @@
 
 package emacs
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
 
 // We assume that we want to allow module authors to register a Foo entity.
 // Registration should be possible eagerly (if an Emacs environment is
 // available) as well as lazily (before Emacs has loaded the module).  For that
-// we use a Manager and pass DefineOnInit.
-var foos = NewManager(RequireUniqueName | DefineOnInit)
+// we use a ManagerOf and pass DefineOnInit.  Using ManagerOf[foo] instead of
+// Manager allows us to retrieve registered Foos without type assertions.
+var foos = NewManagerOf[foo](RequireUniqueName | DefineOnInit)
 
 // Define a type to hold the necessary information about a Foo.  The type needs
 // to implement the QueuedItem interface.
@@ -44,6 +50,12 @@ func DefineFooEventually(name Name, message string) error {
 	return foos.Enqueue(name, foo{name, message})
 }
 
+// FooMessage returns the message of the Foo with the given name.
+func FooMessage(name Name) (string, bool) {
+	f, ok := foos.Lookup(name)
+	return f.message, ok
+}
+
 func ExampleManager() {
 	for _, n := range []Name{"foo-1", "foo-1", ""} {
 		if err := DefineFooEventually(n, fmt.Sprintf("hi from foo %q", n)); err != nil {
@@ -57,3 +69,335 @@ func init() {
 	// We would normally call ExampleManager here, but the test runner
 	// already calls it for us.
 }
+
+func TestManagerRemoveReplace(t *testing.T) {
+	var log []string
+	item := func(s string) QueuedItem { return &loggingItem{&log, s} }
+	m := NewManager(RequireUniqueName)
+	for _, n := range []Name{"a", "b", "c"} {
+		if err := m.Enqueue(n, item(string(n))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var e Env // not needed before initialization
+	if err := m.Remove(e, "b"); err != nil {
+		t.Error(err)
+	}
+	if err := m.Replace(e, "c", item("c2")); err != nil {
+		t.Error(err)
+	}
+	if err := m.Remove(e, "x"); err == nil {
+		t.Error("Remove of unknown item succeeded")
+	}
+	// After removal, the name is available again.
+	if err := m.Enqueue("b", unremovableItem{&log}); err != nil {
+		t.Error(err)
+	}
+	if err := m.DefineQueued(e); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"define a", "define c2", "define unremovable"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("definitions: got %q, want %q", log, want)
+	}
+
+	log = nil
+	if err := m.Replace(e, "a", item("a2")); err != nil {
+		t.Error(err)
+	}
+	if err := m.Remove(e, "c"); err != nil {
+		t.Error(err)
+	}
+	if err := m.Remove(e, "b"); err == nil {
+		t.Error("Remove of item without Undefine method succeeded")
+	}
+	if want := []string{"undefine a", "define a2", "undefine c2"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("definitions after initialization: got %q, want %q", log, want)
+	}
+	var names []Name
+	for _, q := range m.entries() {
+		names = append(names, q.name)
+	}
+	if want := []Name{"a", "b"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("remaining items: got %q, want %q", names, want)
+	}
+}
+
+type loggingItem struct {
+	log  *[]string
+	name string
+}
+
+func (i *loggingItem) Define(Env) error {
+	*i.log = append(*i.log, "define "+i.name)
+	return nil
+}
+
+func (i *loggingItem) Undefine(Env) error {
+	*i.log = append(*i.log, "undefine "+i.name)
+	return nil
+}
+
+type unremovableItem struct{ log *[]string }
+
+func (i unremovableItem) Define(Env) error {
+	*i.log = append(*i.log, "define unremovable")
+	return nil
+}
+
+func TestManagerMultiPhase(t *testing.T) {
+	var log []string
+	var e Env // not used by loggingItem
+	single := NewManager(0)
+	if err := single.DefineQueued(e); err != nil {
+		t.Fatal(err)
+	}
+	if err := single.Enqueue("a", &loggingItem{&log, "a"}); err == nil {
+		t.Error("Enqueue after DefineQueued succeeded without MultiPhase")
+	}
+
+	m := NewManager(MultiPhase)
+	for _, phase := range [][]string{{"a", "b"}, nil, {"c"}} {
+		for _, n := range phase {
+			if err := m.Enqueue(Name(n), &loggingItem{&log, n}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		log = nil
+		if err := m.DefineQueued(e); err != nil {
+			t.Fatal(err)
+		}
+		var want []string
+		for _, n := range phase {
+			want = append(want, "define "+n)
+		}
+		if !reflect.DeepEqual(log, want) {
+			t.Errorf("definitions: got %q, want %q", log, want)
+		}
+	}
+}
+
+func TestManagerContinueOnError(t *testing.T) {
+	var log []string
+	var e Env // not used by the items
+	errA, errC := errors.New("error a"), errors.New("error c")
+	for _, tc := range []struct {
+		flags ManagerFlag
+		defs  []string
+		errs  []error
+		msg   string
+	}{
+		{0, []string{"define a"}, []error{errA}, "error a"},
+		{ContinueOnError, []string{"define a", "define b", "define c"}, []error{errA, errC}, "a: error a\nc: error c"},
+	} {
+		m := NewManager(tc.flags)
+		m.MustEnqueue("a", failingItem{&log, "a", errA})
+		m.MustEnqueue("b", failingItem{&log, "b", nil})
+		m.MustEnqueue("c", failingItem{&log, "c", errC})
+		log = nil
+		err := m.DefineQueued(e)
+		if !reflect.DeepEqual(log, tc.defs) {
+			t.Errorf("flags %v: definitions: got %q, want %q", tc.flags, log, tc.defs)
+		}
+		for _, want := range tc.errs {
+			if !errors.Is(err, want) {
+				t.Errorf("flags %v: error %v doesn’t wrap %v", tc.flags, err, want)
+			}
+		}
+		if err == nil || err.Error() != tc.msg {
+			t.Errorf("flags %v: error message: got %v, want %q", tc.flags, err, tc.msg)
+		}
+	}
+}
+
+func TestManagerStopOnError(t *testing.T) {
+	var log []string
+	var e Env // not used by the items
+	errA := errors.New("error a")
+	m := NewManager(RequireUniqueName | MultiPhase)
+	m.MustEnqueue("a", failingItem{&log, "a", errA})
+	m.MustEnqueue("b", &loggingItem{&log, "b"})
+	if err := m.DefineQueued(e); !errors.Is(err, errA) {
+		t.Errorf("DefineQueued: got error %v, want %v", err, errA)
+	}
+	// Item b was never defined, so Redefine must skip it, and Remove
+	// must not undefine it.
+	log = nil
+	if err := m.Redefine(e); !errors.Is(err, errA) {
+		t.Errorf("Redefine: got error %v, want %v", err, errA)
+	}
+	if want := []string{"define a"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("redefinitions: got %q, want %q", log, want)
+	}
+	// Item b is still queued, so the next phase defines it.
+	log = nil
+	if err := m.DefineQueued(e); err != nil {
+		t.Errorf("second DefineQueued: %v", err)
+	}
+	if want := []string{"define b"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("definitions in second phase: got %q, want %q", log, want)
+	}
+
+	log = nil
+	single := NewManager(RequireUniqueName)
+	single.MustEnqueue("a", failingItem{&log, "a", errA})
+	single.MustEnqueue("b", &loggingItem{&log, "b"})
+	if err := single.DefineQueued(e); !errors.Is(err, errA) {
+		t.Errorf("DefineQueued: got error %v, want %v", err, errA)
+	}
+	if err := single.Remove(e, "b"); err != nil {
+		t.Error(err)
+	}
+	if want := []string{"define a"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("Remove of undefined item: got %q, want %q", log, want)
+	}
+}
+
+func TestManagerRedefine(t *testing.T) {
+	var log []string
+	var e Env // not used by the items
+	errB := errors.New("error b")
+	m := NewManager(MultiPhase | ContinueOnError)
+	m.MustEnqueue("a", &loggingItem{&log, "a"})
+	m.MustEnqueue("b", failingItem{&log, "b", errB})
+	if err := m.DefineQueued(e); !errors.Is(err, errB) {
+		t.Errorf("DefineQueued: got error %v, want %v", err, errB)
+	}
+	m.MustEnqueue("c", &loggingItem{&log, "c"})
+	log = nil
+	if err := m.Redefine(e); !errors.Is(err, errB) {
+		t.Errorf("Redefine: got error %v, want %v", err, errB)
+	}
+	// Item c is still queued and therefore not redefined.
+	if want := []string{"define a", "define b"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("redefinitions: got %q, want %q", log, want)
+	}
+}
+
+func TestFuncManagerObjects(t *testing.T) {
+	var m funcManager
+	f := new(function)
+	if err := m.register(f); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate redefinition: two function objects, the older one gets
+	// garbage-collected first.
+	m.retain(f.index)
+	m.retain(f.index)
+	m.release(f.index)
+	if got := m.get(f.index); got != f {
+		t.Errorf("function after first release: got %p, want %p", got, f)
+	}
+	m.release(f.index)
+	if n, a := m.counts(); n+a != 0 {
+		t.Errorf("%d functions left after releasing all function objects", n+a)
+	}
+}
+
+func TestManagerOfItems(t *testing.T) {
+	var log []string
+	m := NewManagerOf[*loggingItem](RequireUniqueName)
+	a, b := &loggingItem{&log, "a"}, &loggingItem{&log, "b"}
+	m.MustEnqueue("a", a)
+	m.MustEnqueue("", b)
+	if got, want := m.Items(), []*loggingItem{a, b}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Items: got %v, want %v", got, want)
+	}
+	if got, ok := m.Lookup("a"); !ok || got != a {
+		t.Errorf("Lookup(a): got %v, %v; want %v, true", got, ok, a)
+	}
+	for _, n := range []Name{"", "b"} {
+		if got, ok := m.Lookup(n); ok || got != nil {
+			t.Errorf("Lookup(%q): got %v, %v; want nil, false", n, got, ok)
+		}
+	}
+}
+
+func TestManagerOfDropsDefinedUnnamedItems(t *testing.T) {
+	var log []string
+	var e Env // not used by the items
+	m := NewManagerOf[*loggingItem](MultiPhase)
+	a, b, c := &loggingItem{&log, "a"}, &loggingItem{&log, "b"}, &loggingItem{&log, "c"}
+	m.MustEnqueue("a", a)
+	m.MustEnqueue("", b)
+	if err := m.DefineQueued(e); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterAndDefine(e, "", c); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.Items(), []*loggingItem{a}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Items: got %v, want %v", got, want)
+	}
+	if len(m.queue) != 0 {
+		t.Errorf("queue: got %d entries, want none", len(m.queue))
+	}
+}
+
+func TestManagerPrefix(t *testing.T) {
+	var log []string
+	m := NewManagerOf[*loggingItem](RequireUniqueName)
+	m.SetPrefix("my-pkg")
+	m.Exempt("other-hook")
+	for _, tc := range []struct {
+		name Name
+		ok   bool
+	}{
+		{"my-pkg", true},
+		{"my-pkg-foo", true},
+		{"my-pkg--internal", true},
+		{"", true},
+		{"other-hook", true},
+		{"my-pkgfoo", false},
+		{"foo", false},
+		{"my-pkg-Foo", false},
+		{"my-pkg-foo_bar", false},
+		{"my-pkg-foo bar", false},
+	} {
+		err := m.Enqueue(tc.name, &loggingItem{&log, string(tc.name)})
+		if ok := err == nil; ok != tc.ok {
+			t.Errorf("Enqueue(%q): got error %v, want success %t", tc.name, err, tc.ok)
+		}
+	}
+	for _, tc := range []struct{ name, want Name }{
+		{"foo", "my-pkg-foo"},
+		{"my-pkg-foo", "my-pkg-foo"},
+		{"my-pkg", "my-pkg"},
+		{"", ""},
+	} {
+		if got := m.Qualify(tc.name); got != tc.want {
+			t.Errorf("Qualify(%q): got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestManagerOnDefine(t *testing.T) {
+	var log, events []string
+	var e Env // not used by the items
+	errB := errors.New("error b")
+	m := NewManager(ContinueOnError)
+	m.OnDefine(func(name Name, err error) {
+		events = append(events, fmt.Sprintf("%s: %v", name, err))
+	})
+	m.MustEnqueue("a", &loggingItem{&log, "a"})
+	m.MustEnqueue("b", failingItem{&log, "b", errB})
+	if err := m.DefineQueued(e); !errors.Is(err, errB) {
+		t.Errorf("DefineQueued: got error %v, want %v", err, errB)
+	}
+	if err := m.RegisterAndDefine(e, "c", &loggingItem{&log, "c"}); err != nil {
+		t.Error(err)
+	}
+	if want := []string{"a: <nil>", "b: error b", "c: <nil>"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("events: got %q, want %q", events, want)
+	}
+}
+
+type failingItem struct {
+	log  *[]string
+	name string
+	err  error
+}
+
+func (i failingItem) Define(Env) error {
+	*i.log = append(*i.log, "define "+i.name)
+	return i.err
+}
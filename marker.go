@@ -0,0 +1,160 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Marker is an Emacs marker, i.e., a buffer position that moves when text is
+// inserted or deleted before it.  See [Markers].  Unlike a [Value], a Marker
+// stays valid across calls, because it holds a global reference to the
+// marker object.
+//
+// Emacs has to update every marker that points into a buffer whenever the
+// buffer text changes, until the marker is garbage-collected.  Many
+// unreachable markers therefore slow down editing.  Call [Marker.Close] once
+// you no longer need a marker; for markers created by this package, Close
+// makes the marker point nowhere, so that it no longer costs anything.  It
+// also releases the global reference.  If you
+// don’t call Close, the global reference is released once the Marker is
+// garbage-collected, but the marker keeps pointing into its buffer until
+// Emacs garbage-collects it as well.
+//
+// *Marker implements [In] and [Out], so exported functions can accept and
+// return markers.
+//
+// [Markers]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Markers.html
+type Marker struct {
+	ref Value // global reference, zero after Close
+
+	// owned is true if the marker was created by this package, so that
+	// Close may make it point nowhere.
+	owned bool
+}
+
+// MakeMarker returns a new marker that points nowhere, like make-marker.
+func (e Env) MakeMarker() (*Marker, error) {
+	return e.newMarker(e.Call("make-marker"))
+}
+
+// PointMarker returns a new marker at point in the current buffer, like
+// point-marker.
+func (e Env) PointMarker() (*Marker, error) {
+	return e.newMarker(e.Call("point-marker"))
+}
+
+// CopyMarker returns a new marker that points to pos, which is either a
+// position in the current buffer or a marker, like copy-marker.  If
+// insertionType is true, the marker advances when text is inserted at its
+// position.
+func (e Env) CopyMarker(pos In, insertionType bool) (*Marker, error) {
+	return e.newMarker(e.Call("copy-marker", pos, Bool(insertionType)))
+}
+
+func (e Env) newMarker(v Value, err error) (*Marker, error) {
+	if err != nil {
+		return nil, err
+	}
+	ref, err := e.makeGlobalRef(v)
+	if err != nil {
+		return nil, err
+	}
+	m := &Marker{ref, true}
+	runtime.SetFinalizer(m, (*Marker).finalize)
+	return m, nil
+}
+
+// Emacs implements [In.Emacs].  It returns the marker object.  It returns an
+// error if the marker has been closed.
+func (m *Marker) Emacs(Env) (Value, error) {
+	if m.ref.r == nil {
+		return Value{}, errors.New("marker already closed")
+	}
+	return m.ref, nil
+}
+
+// FromEmacs implements [Out.FromEmacs].  It sets *m to a new global reference
+// to the marker v.  It returns an error if v isn’t a marker.  If *m already
+// refers to a marker, FromEmacs doesn’t close it.  The marker belongs to the
+// caller, so [Marker.Close] only releases the global reference and leaves
+// the marker pointing where it was.  Because *m might not be a separate
+// allocation, FromEmacs can’t arrange for the global reference to be
+// released automatically; call Close once you no longer need the marker.
+func (m *Marker) FromEmacs(e Env, v Value) error {
+	var ok Bool
+	if err := e.CallOut("markerp", &ok, v); err != nil {
+		return err
+	}
+	if !ok {
+		return WrongTypeArgument("markerp", v)
+	}
+	ref, err := e.makeGlobalRef(v)
+	if err != nil {
+		return err
+	}
+	*m = Marker{ref, false}
+	return nil
+}
+
+// Position returns the position of the marker.  It returns false if the
+// marker points nowhere.
+func (m *Marker) Position(e Env) (int, bool, error) {
+	return e.optionalPosition(e.Call("marker-position", m))
+}
+
+// Buffer returns the buffer the marker points into.  It returns nil if the
+// marker points nowhere.
+func (m *Marker) Buffer(e Env) (Value, error) {
+	return e.Call("marker-buffer", m)
+}
+
+// Set moves the marker to pos in buffer, like set-marker.  pos is either a
+// position or a marker; buffer is a buffer or nil for the current buffer.
+func (m *Marker) Set(e Env, pos In, buffer In) error {
+	if buffer == nil {
+		buffer = Nil
+	}
+	_, err := e.Call("set-marker", m, pos, buffer)
+	return err
+}
+
+// Close releases the global reference of the marker.  If the marker was
+// created by [Env.MakeMarker], [Env.PointMarker], or [Env.CopyMarker], Close
+// also makes it point nowhere; markers received using [Marker.FromEmacs]
+// belong to the caller and keep pointing where they were.  Afterwards, using
+// the Marker fails.  Calling Close more than once has no effect.
+func (m *Marker) Close(e Env) error {
+	if m.ref.r == nil {
+		return nil
+	}
+	ref := m.ref
+	m.ref = Value{}
+	var err error
+	if m.owned {
+		_, err = e.Call("set-marker", ref, Nil)
+	}
+	if relErr := e.freeGlobalRef(ref); err == nil {
+		err = relErr
+	}
+	return err
+}
+
+func (m *Marker) finalize() {
+	if m.ref.r != nil {
+		releaseLater(m.ref)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+func init() {
+	ERTTest(markerMoves)
+}
+
+func markerMoves(e Env) error {
+	return withTestBuffer(e, "hello world", func() error {
+		if _, err := e.Call("goto-char", Int(7)); err != nil {
+			return err
+		}
+		m, err := e.PointMarker()
+		if err != nil {
+			return err
+		}
+		defer m.Close(e)
+		c, err := e.CopyMarker(m, true)
+		if err != nil {
+			return err
+		}
+		defer c.Close(e)
+		if _, err := e.Call("insert", String("big ")); err != nil {
+			return err
+		}
+		if pos, ok, err := m.Position(e); err != nil || !ok || pos != 7 {
+			return fmt.Errorf("marker position after insertion: got %d, %v, %v, want 7, true, nil", pos, ok, err)
+		}
+		if pos, ok, err := c.Position(e); err != nil || !ok || pos != 11 {
+			return fmt.Errorf("advancing marker position after insertion: got %d, %v, %v, want 11, true, nil", pos, ok, err)
+		}
+		if _, err := e.Call("goto-char", Int(1)); err != nil {
+			return err
+		}
+		if _, err := e.Call("insert", String(">")); err != nil {
+			return err
+		}
+		if pos, _, err := m.Position(e); err != nil || pos != 8 {
+			return fmt.Errorf("marker position after insertion before it: got %d, %v, want 8, nil", pos, err)
+		}
+		if err := m.Set(e, Int(2), nil); err != nil {
+			return err
+		}
+		if pos, _, err := m.Position(e); err != nil || pos != 2 {
+			return fmt.Errorf("marker position after Set: got %d, %v, want 2, nil", pos, err)
+		}
+		buf, err := m.Buffer(e)
+		if err != nil {
+			return err
+		}
+		cur, err := e.Call("current-buffer")
+		if err != nil {
+			return err
+		}
+		if !e.Eq(buf, cur) {
+			return errors.New("marker doesn’t point into current buffer")
+		}
+		var copied Marker
+		if err := copied.FromEmacs(e, m.ref); err != nil {
+			return err
+		}
+		var borrowed Marker
+		if err := borrowed.FromEmacs(e, c.ref); err != nil {
+			return err
+		}
+		if err := borrowed.Close(e); err != nil {
+			return err
+		}
+		// The marker received from Emacs belongs to the caller, so
+		// Close must leave it alone.
+		if pos, ok, err := c.Position(e); err != nil || !ok || pos != 12 {
+			return fmt.Errorf("position after closing borrowed marker: got %d, %v, %v, want 12, true, nil", pos, ok, err)
+		}
+		if err := m.Close(e); err != nil {
+			return err
+		}
+		if _, _, err := m.Position(e); err == nil {
+			return errors.New("using closed marker succeeded")
+		}
+		// Close makes the marker object itself point nowhere, so other
+		// references see that, too.
+		if _, ok, err := copied.Position(e); err != nil || ok {
+			return fmt.Errorf("position of closed marker: got %v, %v, want false, nil", ok, err)
+		}
+		if err := copied.Close(e); err != nil {
+			return err
+		}
+		empty, err := e.MakeMarker()
+		if err != nil {
+			return err
+		}
+		defer empty.Close(e)
+		if _, ok, err := empty.Position(e); err != nil || ok {
+			return fmt.Errorf("position of new marker: got %v, %v, want false, nil", ok, err)
+		}
+		return nil
+	})
+}
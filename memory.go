@@ -0,0 +1,130 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"time"
+)
+
+// GCStat describes the memory used by one type of Lisp object after a garbage
+// collection.  See [Env.GarbageCollect].
+type GCStat struct {
+	// Name is the type of object, for example conses or strings.
+	Name Symbol
+
+	// Size is the size of one object in bytes.
+	Size int64
+
+	// Used is the number of live objects.
+	Used int64
+
+	// Free is the number of objects that are allocated, but free.  It’s
+	// -1 if Emacs doesn’t report free objects for this type.
+	Free int64
+}
+
+// GarbageCollect runs the Emacs garbage collector by calling garbage-collect
+// and returns the statistics that it reports.  It returns nil if garbage
+// collection is currently inhibited.  See [Garbage Collection] for the
+// meaning of the statistics.  Garbage collection in Emacs doesn’t affect the
+// Go heap; use [runtime.GC] or [Health] for that.
+//
+// [Garbage Collection]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Garbage-Collection.html
+func (e Env) GarbageCollect() ([]GCStat, error) {
+	v, err := e.Call("garbage-collect")
+	if err != nil {
+		return nil, err
+	}
+	var r []GCStat
+	err = e.Dolist(v, func(entry Value) error {
+		var name Symbol
+		var size, used, free Int
+		u := UnpackList{&name, &size, &used, &free}
+		if err := u.FromEmacs(e, entry); err != nil {
+			return err
+		}
+		if len(u) < 3 {
+			return WrongTypeArgument("listp", entry)
+		}
+		s := GCStat{name, int64(size), int64(used), -1}
+		if len(u) == 4 {
+			s.Free = int64(free)
+		}
+		r = append(r, s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MemoryInfo describes the memory allocation of Emacs.  See [Env.MemoryInfo].
+type MemoryInfo struct {
+	// Conses, Floats, VectorCells, Symbols, StringChars, Intervals, and
+	// Strings are the numbers of objects of the respective types that
+	// Emacs has allocated so far, as reported by memory-use-counts.
+	// Garbage collection doesn’t decrease these numbers, but they can wrap
+	// around.
+	Conses, Floats, VectorCells, Symbols, StringChars, Intervals, Strings int64
+
+	// Limit is the value of memory-limit: a rough estimate of the total
+	// memory that Emacs has allocated, in KiB.
+	Limit int64
+
+	// GCsDone is the number of garbage collections so far, and GCElapsed
+	// is the total time spent in them.
+	GCsDone   int64
+	GCElapsed time.Duration
+}
+
+// MemoryInfo returns information about the memory allocation of Emacs
+// without triggering a garbage collection.  Use it together with
+// [Env.GarbageCollect] to detect and react to memory pressure on the Lisp
+// side.
+func (e Env) MemoryInfo() (MemoryInfo, error) {
+	var counts [7]Int
+	u := UnpackList{&counts[0], &counts[1], &counts[2], &counts[3], &counts[4], &counts[5], &counts[6]}
+	if err := e.CallOut("memory-use-counts", &u); err != nil {
+		return MemoryInfo{}, err
+	}
+	if len(u) != len(counts) {
+		return MemoryInfo{}, fmt.Errorf("memory-use-counts returned %d values, want %d", len(u), len(counts))
+	}
+	var limit, gcs Int
+	var elapsed Float
+	if err := e.CallOut("memory-limit", &limit); err != nil {
+		return MemoryInfo{}, err
+	}
+	if err := e.CallOut("symbol-value", &gcs, Symbol("gcs-done")); err != nil {
+		return MemoryInfo{}, err
+	}
+	if err := e.CallOut("symbol-value", &elapsed, Symbol("gc-elapsed")); err != nil {
+		return MemoryInfo{}, err
+	}
+	return MemoryInfo{
+		Conses:      int64(counts[0]),
+		Floats:      int64(counts[1]),
+		VectorCells: int64(counts[2]),
+		Symbols:     int64(counts[3]),
+		StringChars: int64(counts[4]),
+		Intervals:   int64(counts[5]),
+		Strings:     int64(counts[6]),
+		Limit:       int64(limit),
+		GCsDone:     int64(gcs),
+		GCElapsed:   time.Duration(float64(elapsed) * float64(time.Second)),
+	}, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "fmt"
+
+func init() {
+	ERTTest(garbageCollect)
+}
+
+func garbageCollect(e Env) error {
+	before, err := e.MemoryInfo()
+	if err != nil {
+		return err
+	}
+	if before.Conses <= 0 {
+		return fmt.Errorf("memory information %+v reports no conses", before)
+	}
+	stats, err := e.GarbageCollect()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, s := range stats {
+		if s.Name == "conses" && s.Size > 0 && s.Used > 0 {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("garbage collection statistics %+v contain no conses", stats)
+	}
+	after, err := e.MemoryInfo()
+	if err != nil {
+		return err
+	}
+	if after.GCsDone <= before.GCsDone {
+		return fmt.Errorf("number of garbage collections didn’t increase: before %d, after %d", before.GCsDone, after.GCsDone)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "errors"
+
+// Menu describes a menu in the format that easy-menu-define accepts.  See
+// [Easy Menu].
+//
+// [Easy Menu]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Easy-Menu.html
+type Menu struct {
+	// Title is the title of the menu.  For a menu bar menu, it’s the
+	// name shown in the menu bar.
+	Title string
+
+	Items []MenuItem
+}
+
+// MenuItem is an item in a [Menu].  Exactly one of Command and Submenu must be
+// set, unless the item is a separator.
+type MenuItem struct {
+	// Label is the text of the item.  A label consisting only of hyphens,
+	// such as "--", denotes a separator.
+	Label string
+
+	// Command is the command that the item invokes.
+	Command Name
+
+	// Help is the help text of the item, shown as a tooltip.
+	Help string
+
+	// Submenu is a nested menu.  The item label is ignored in favor of
+	// the submenu title.
+	Submenu *Menu
+}
+
+// Emacs returns the menu as a list suitable for easy-menu-define.
+func (m Menu) Emacs(e Env) (Value, error) {
+	l, err := m.list()
+	if err != nil {
+		return Value{}, err
+	}
+	return l.Emacs(e)
+}
+
+func (m Menu) list() (List, error) {
+	if m.Title == "" {
+		return nil, errors.New("menu without title")
+	}
+	r := List{String(m.Title)}
+	for _, it := range m.Items {
+		switch {
+		case it.Submenu != nil:
+			sub, err := it.Submenu.list()
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, sub)
+		case isSeparator(it.Label):
+			r = append(r, String(it.Label))
+		case it.Command == "":
+			return nil, WrongTypeArgument("commandp", String(it.Label))
+		default:
+			v := Vector{String(it.Label), it.Command}
+			if it.Help != "" {
+				v = append(v, Symbol(":help"), String(it.Help))
+			}
+			r = append(r, v)
+		}
+	}
+	return r, nil
+}
+
+func isSeparator(label string) bool {
+	if label == "" {
+		return false
+	}
+	for _, c := range label {
+		if c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// DefineMenu arranges for a menu to be defined using easy-menu-define once
+// the module is loaded.  The variable symbol holds the menu keymap.  If keymap
+// is nonempty, it names a keymap variable, and the menu is added to the menu
+// bar of that keymap.  Like [BindKeys], the menu is defined after all other
+// entities of this package, and loading the module fails if keymap isn’t
+// defined or already has a menu bar entry with the same title.  DefineMenu
+// panics if menu is invalid.
+func DefineMenu(symbol, keymap Name, doc Doc, menu Menu) {
+	if _, err := menu.list(); err != nil {
+		panic(err)
+	}
+	enqueueUI(menuDefinition{symbol, keymap, doc, menu})
+}
+
+// DefineMenu is like the global [DefineMenu] function, except that it
+// requires a live environment, defines the menu immediately, and returns
+// errors instead of panicking.  If the menu bar of keymap already has an entry
+// with the same title, DefineMenu returns a [KeyConflictError].
+func (e Env) DefineMenu(symbol, keymap Name, doc Doc, menu Menu) error {
+	return menuDefinition{symbol, keymap, doc, menu}.Define(e)
+}
+
+type menuDefinition struct {
+	symbol Name
+	keymap Name
+	doc    Doc
+	menu   Menu
+}
+
+func (d menuDefinition) Define(e Env) error {
+	list, err := d.menu.list()
+	if err != nil {
+		return err
+	}
+	var keymap In = Nil
+	if d.keymap != "" {
+		km, err := e.keymapValue(d.keymap)
+		if err != nil {
+			return err
+		}
+		// easy-menu-define binds [menu-bar TITLE], with TITLE interned.
+		key := Vector{Symbol("menu-bar"), Symbol(d.menu.Title)}
+		if err := e.checkKeyConflict(d.keymap, km, key, "<menu-bar> <"+d.menu.Title+">", Nil); err != nil {
+			return err
+		}
+		keymap = d.keymap
+	}
+	_, err = e.Eval(List{
+		Symbol("easy-menu-define"), d.symbol, keymap, String(d.doc),
+		List{Symbol("quote"), list},
+	})
+	return err
+}
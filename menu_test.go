@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	ERTTest(defineMenu)
+}
+
+func TestMenuList(t *testing.T) {
+	m := Menu{
+		Title: "Test",
+		Items: []MenuItem{
+			{Label: "Run", Command: "test-run", Help: "Run the test"},
+			{Label: "--"},
+			{Submenu: &Menu{Title: "More", Items: []MenuItem{{Label: "Stop", Command: "test-stop"}}}},
+		},
+	}
+	got, err := m.list()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := List{
+		String("Test"),
+		Vector{String("Run"), Name("test-run"), Symbol(":help"), String("Run the test")},
+		String("--"),
+		List{String("More"), Vector{String("Stop"), Name("test-stop")}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+	for _, m := range []Menu{
+		{},
+		{Title: "Test", Items: []MenuItem{{Label: "No command"}}},
+	} {
+		if _, err := m.list(); err == nil {
+			t.Errorf("%+v: got no error", m)
+		}
+	}
+}
+
+func defineMenu(e Env) error {
+	const keymap Name = "emacs-go-test-menu-map"
+	if _, err := e.Eval(List{Symbol("defvar"), keymap, List{Symbol("make-sparse-keymap")}}); err != nil {
+		return err
+	}
+	menu := Menu{Title: "Go Test", Items: []MenuItem{{Label: "Ignore", Command: "ignore"}}}
+	if err := e.DefineMenu("emacs-go-test-menu", keymap, "Test menu.", menu); err != nil {
+		return err
+	}
+	km, err := e.Call("symbol-value", keymap)
+	if err != nil {
+		return err
+	}
+	entry, err := e.Call("lookup-key", km, Vector{Symbol("menu-bar"), Symbol("Go Test")})
+	if err != nil {
+		return err
+	}
+	if e.IsNil(entry) {
+		return fmt.Errorf("menu bar entry not defined in %s", keymap)
+	}
+	err = e.DefineMenu("emacs-go-test-menu-2", keymap, "", menu)
+	var conflict *KeyConflictError
+	if !errors.As(err, &conflict) {
+		return fmt.Errorf("DefineMenu: got error %v, want key conflict", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// Messagef displays a message in the echo area and logs it in the *Messages*
+// buffer by calling the Emacs function message.  format is an Emacs format
+// string, not a Go format string; see [Formatting Strings].  Messagef converts
+// args to Emacs values as described in the package documentation.  Use
+// [Env.Message] instead to obtain the message of an error.
+//
+// [Formatting Strings]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Formatting-Strings.html
+func (e Env) Messagef(format string, args ...interface{}) error {
+	return e.Invoke("message", Ignore{}, append([]interface{}{String(format)}, args...)...)
+}
+
+// MessageToBuffer is like [Env.Messagef], except that it appends the message
+// and a newline to the end of the buffer with the given name instead of
+// displaying it in the echo area.  MessageToBuffer creates the buffer if it
+// doesn’t exist yet.  It inserts the message even if the buffer is read-only.
+func (e Env) MessageToBuffer(buffer, format string, args ...interface{}) error {
+	var msg String
+	if err := e.Invoke("format", &msg, append([]interface{}{String(format)}, args...)...); err != nil {
+		return err
+	}
+	return e.appendToBuffer(buffer, string(msg)+"\n")
+}
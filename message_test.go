@@ -0,0 +1,43 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "fmt"
+
+func init() {
+	ERTTest(messageToBuffer)
+}
+
+func messageToBuffer(e Env) error {
+	const buffer = " *emacs-test-messages*"
+	defer e.Call("kill-buffer", String(buffer))
+	if err := e.Messagef("hi from %s: %S", "Go", []int{1, 2}); err != nil {
+		return err
+	}
+	if err := e.MessageToBuffer(buffer, "first %d", 1); err != nil {
+		return err
+	}
+	if err := e.MessageToBuffer(buffer, "second %S", "two"); err != nil {
+		return err
+	}
+	var got String
+	if err := e.CallOut("eval", &got, List{Symbol("with-current-buffer"), String(buffer), List{Symbol("buffer-string")}}); err != nil {
+		return err
+	}
+	if want := String("first 1\nsecond \"two\"\n"); got != want {
+		return fmt.Errorf("buffer contents: got %q, want %q", got, want)
+	}
+	return nil
+}
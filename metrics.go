@@ -0,0 +1,309 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"expvar"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is a metric that only increases, such as the number of processed
+// requests.  Create counters using [NewCounter].  All methods are cheap and
+// safe for concurrent use.
+type Counter struct {
+	value int64 // accessed atomically; first field for alignment
+	name  string
+}
+
+// NewCounter creates a new [Counter] with the given name and registers it so
+// that [Metrics] returns it.  NewCounter panics if a metric with the same
+// name already exists.  Typically you’d assign the result to a global
+// variable.
+func NewCounter(name string) *Counter {
+	c := &Counter{name: name}
+	metrics.register(name, c)
+	return c
+}
+
+// Inc increments the counter by one.
+func (c *Counter) Inc() { atomic.AddInt64(&c.value, 1) }
+
+// Add increments the counter by n, which must not be negative.
+func (c *Counter) Add(n int64) {
+	if n < 0 {
+		panic(fmt.Errorf("counter %s: negative increment %d", c.name, n))
+	}
+	atomic.AddInt64(&c.value, n)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() int64 { return atomic.LoadInt64(&c.value) }
+
+func (c *Counter) snapshot() Metric {
+	return Metric{Name: c.name, Kind: CounterMetric, Value: float64(c.Value())}
+}
+
+// Gauge is a metric that can go up and down, such as the number of open
+// connections.  Create gauges using [NewGauge].  All methods are cheap and
+// safe for concurrent use.
+type Gauge struct {
+	bits uint64 // float64 bits, accessed atomically; first field for alignment
+	name string
+}
+
+// NewGauge creates a new [Gauge] with the given name and registers it so that
+// [Metrics] returns it.  NewGauge panics if a metric with the same name
+// already exists.
+func NewGauge(name string) *Gauge {
+	g := &Gauge{name: name}
+	metrics.register(name, g)
+	return g
+}
+
+// Set sets the gauge to v.
+func (g *Gauge) Set(v float64) { atomic.StoreUint64(&g.bits, math.Float64bits(v)) }
+
+// Add adds d to the gauge.  d may be negative.
+func (g *Gauge) Add(d float64) { addFloat(&g.bits, d) }
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 { return math.Float64frombits(atomic.LoadUint64(&g.bits)) }
+
+func (g *Gauge) snapshot() Metric {
+	return Metric{Name: g.name, Kind: GaugeMetric, Value: g.Value()}
+}
+
+// Histogram is a metric that counts observations in buckets, such as request
+// latencies.  Create histograms using [NewHistogram].  All methods are cheap
+// and safe for concurrent use.
+type Histogram struct {
+	count  uint64   // accessed atomically; first field for alignment
+	sum    uint64   // float64 bits, accessed atomically
+	counts []uint64 // one per bound plus overflow, accessed atomically
+	bounds []float64
+	name   string
+}
+
+// NewHistogram creates a new [Histogram] with the given name and bucket upper
+// bounds, and registers it so that [Metrics] returns it.  The bounds must be
+// strictly increasing.  An additional bucket without upper bound counts
+// observations greater than the last bound.  NewHistogram panics if the
+// bounds are invalid or a metric with the same name already exists.
+func NewHistogram(name string, bounds ...float64) *Histogram {
+	for i := 1; i < len(bounds); i++ {
+		if !(bounds[i-1] < bounds[i]) {
+			panic(fmt.Errorf("histogram %s: bounds %v not strictly increasing", name, bounds))
+		}
+	}
+	h := &Histogram{
+		counts: make([]uint64, len(bounds)+1),
+		bounds: append([]float64(nil), bounds...),
+		name:   name,
+	}
+	metrics.register(name, h)
+	return h
+}
+
+// Observe records the value v.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+	atomic.AddUint64(&h.counts[i], 1)
+	addFloat(&h.sum, v)
+	atomic.AddUint64(&h.count, 1)
+}
+
+func (h *Histogram) snapshot() Metric {
+	m := Metric{
+		Name:    h.name,
+		Kind:    HistogramMetric,
+		Count:   atomic.LoadUint64(&h.count),
+		Buckets: make([]Bucket, len(h.counts)),
+	}
+	m.Value = math.Float64frombits(atomic.LoadUint64(&h.sum))
+	for i := range h.counts {
+		b := Bucket{UpperBound: math.Inf(1), Count: atomic.LoadUint64(&h.counts[i])}
+		if i < len(h.bounds) {
+			b.UpperBound = h.bounds[i]
+		}
+		m.Buckets[i] = b
+	}
+	return m
+}
+
+// addFloat atomically adds d to the float64 stored as bits in p.
+func addFloat(p *uint64, d float64) {
+	for {
+		old := atomic.LoadUint64(p)
+		n := math.Float64bits(math.Float64frombits(old) + d)
+		if atomic.CompareAndSwapUint64(p, old, n) {
+			return
+		}
+	}
+}
+
+// MetricKind is the kind of a [Metric].
+type MetricKind int
+
+// Kinds of metrics.
+const (
+	CounterMetric MetricKind = iota
+	GaugeMetric
+	HistogramMetric
+)
+
+// String returns “counter”, “gauge”, or “histogram”.
+func (k MetricKind) String() string {
+	switch k {
+	case CounterMetric:
+		return "counter"
+	case GaugeMetric:
+		return "gauge"
+	case HistogramMetric:
+		return "histogram"
+	default:
+		return fmt.Sprintf("MetricKind(%d)", int(k))
+	}
+}
+
+// Metric is a snapshot of a [Counter], [Gauge], or [Histogram].
+type Metric struct {
+	Name string
+	Kind MetricKind
+
+	// Value is the value of a counter or gauge, or the sum of all
+	// observations of a histogram.
+	Value float64
+
+	// Count is the number of observations of a histogram.
+	Count uint64
+
+	// Buckets are the buckets of a histogram.  Each bucket counts the
+	// observations that are less than or equal to its upper bound and
+	// greater than the upper bound of the previous bucket.
+	Buckets []Bucket
+}
+
+// Bucket is a histogram bucket.  See [Metric].
+type Bucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+// Emacs returns a property list with the keys :name, :kind, and :value.
+// For histograms, the property list additionally contains :count and
+// :buckets, whose value is a list of (UPPER-BOUND . COUNT) pairs.  The last
+// upper bound is 1.0e+INF.
+func (m Metric) Emacs(e Env) (Value, error) {
+	r := List{
+		Symbol(":name"), String(m.Name),
+		Symbol(":kind"), Symbol(m.Kind.String()),
+		Symbol(":value"), Float(m.Value),
+	}
+	if m.Kind == HistogramMetric {
+		buckets := make(List, len(m.Buckets))
+		for i, b := range m.Buckets {
+			buckets[i] = Cons{Car: Float(b.UpperBound), Cdr: Uint(b.Count)}
+		}
+		r = append(r, Symbol(":count"), Uint(m.Count), Symbol(":buckets"), buckets)
+	}
+	return r.Emacs(e)
+}
+
+// Metrics returns a snapshot of all registered metrics, sorted by name.  You
+// can call Metrics safely from multiple goroutines.
+func Metrics() []Metric {
+	return metrics.snapshot()
+}
+
+// ExportMetrics arranges for a function with the given name to be exported
+// to Emacs.  The function takes no arguments and returns the result of
+// [Metrics] as a list of property lists; see [Metric.Emacs].  Call
+// ExportMetrics in an init function.
+func ExportMetrics(name Name) {
+	ExportFunc(name, func(e Env, _ []Value) (Value, error) {
+		ms := Metrics()
+		r := make(List, len(ms))
+		for i, m := range ms {
+			r[i] = m
+		}
+		return r.Emacs(e)
+	}, Arity{0, 0}, "Return a snapshot of the module’s metrics.")
+}
+
+// PublishMetrics publishes the registered metrics as an [expvar] variable with
+// the given name.  The variable is a JSON object that maps metric names to
+// values.  Histograms are represented as objects with the keys “count”,
+// “sum”, and “buckets”; the latter maps upper bounds to counts.  Like
+// [expvar.Publish], PublishMetrics panics if the name is already in use.
+func PublishMetrics(name string) {
+	expvar.Publish(name, expvar.Func(metricsVar))
+}
+
+func metricsVar() interface{} {
+	r := make(map[string]interface{})
+	for _, m := range Metrics() {
+		if m.Kind != HistogramMetric {
+			r[m.Name] = m.Value
+			continue
+		}
+		buckets := make(map[string]uint64, len(m.Buckets))
+		for _, b := range m.Buckets {
+			// JSON doesn’t support infinity, so format the bounds
+			// as strings.
+			buckets[strconv.FormatFloat(b.UpperBound, 'g', -1, 64)] = b.Count
+		}
+		r[m.Name] = map[string]interface{}{"count": m.Count, "sum": m.Value, "buckets": buckets}
+	}
+	return r
+}
+
+type metric interface {
+	snapshot() Metric
+}
+
+type metricsRegistry struct {
+	mu      sync.Mutex
+	metrics map[string]metric
+}
+
+var metrics metricsRegistry
+
+func (r *metricsRegistry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.metrics[name]; dup {
+		panic(fmt.Errorf("duplicate metric %s", name))
+	}
+	if r.metrics == nil {
+		r.metrics = make(map[string]metric)
+	}
+	r.metrics[name] = m
+}
+
+func (r *metricsRegistry) snapshot() []Metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := make([]Metric, 0, len(r.metrics))
+	for _, m := range r.metrics {
+		s = append(s, m.snapshot())
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Name < s[j].Name })
+	return s
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func init() {
+	ERTTest(metricEmacs)
+}
+
+var (
+	testCounter   = NewCounter("test.counter")
+	testGauge     = NewGauge("test.gauge")
+	testHistogram = NewHistogram("test.histogram", 1, 10)
+)
+
+func TestMetrics(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			testCounter.Inc()
+			testGauge.Add(0.5)
+		}()
+	}
+	wg.Wait()
+	testHistogram.Observe(0.5)
+	testHistogram.Observe(1)
+	testHistogram.Observe(5)
+	testHistogram.Observe(100)
+	got := make(map[string]Metric)
+	for _, m := range Metrics() {
+		got[m.Name] = m
+	}
+	if m := got["test.counter"]; m.Kind != CounterMetric || m.Value != 10 {
+		t.Errorf("counter: got %+v, want value 10", m)
+	}
+	if m := got["test.gauge"]; m.Kind != GaugeMetric || m.Value != 5 {
+		t.Errorf("gauge: got %+v, want value 5", m)
+	}
+	want := Metric{
+		Name:    "test.histogram",
+		Kind:    HistogramMetric,
+		Value:   106.5,
+		Count:   4,
+		Buckets: []Bucket{{1, 2}, {10, 1}, {math.Inf(1), 1}},
+	}
+	if m := got["test.histogram"]; !reflect.DeepEqual(m, want) {
+		t.Errorf("histogram: got %+v, want %+v", m, want)
+	}
+	PublishMetrics("test.metrics")
+	var vars map[string]interface{}
+	if err := json.Unmarshal([]byte(expvar.Get("test.metrics").String()), &vars); err != nil {
+		t.Fatal(err)
+	}
+	if v := vars["test.counter"]; v != 10.0 {
+		t.Errorf("expvar counter: got %v, want 10", v)
+	}
+}
+
+func TestHistogramInvalidBounds(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewHistogram didn’t panic")
+		}
+	}()
+	NewHistogram("test.invalid", 2, 1)
+}
+
+func metricEmacs(e Env) error {
+	m := Metric{Name: "test", Kind: HistogramMetric, Value: 3, Count: 2, Buckets: []Bucket{{1, 1}, {math.Inf(1), 1}}}
+	v, err := m.Emacs(e)
+	if err != nil {
+		return err
+	}
+	var kind Symbol
+	if err := e.CallOut("plist-get", &kind, v, Symbol(":kind")); err != nil {
+		return err
+	}
+	if kind != "histogram" {
+		return fmt.Errorf(":kind: got %s, want histogram", kind)
+	}
+	var count Int
+	if err := e.CallOut("plist-get", &count, v, Symbol(":count")); err != nil {
+		return err
+	}
+	if count != 2 {
+		return fmt.Errorf(":count: got %d, want 2", count)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// ReadStringOptions contains optional arguments for [Env.ReadString].
+type ReadStringOptions struct {
+	// Initial is the initial minibuffer contents.  Its use is
+	// discouraged; prefer Default.
+	Initial string
+
+	// History is the name of the history list variable.  If empty, use
+	// minibuffer-history.
+	History Name
+
+	// Default is the value to return if the user enters empty input.
+	Default string
+
+	// If InheritInputMethod is true, the minibuffer inherits the current
+	// input method.
+	InheritInputMethod bool
+}
+
+// ReadString reads a string from the minibuffer using read-string.  See
+// [Reading Text Strings with the Minibuffer].
+//
+// [Reading Text Strings with the Minibuffer]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Text-from-Minibuffer.html
+func (e Env) ReadString(prompt string, opts ReadStringOptions) (string, error) {
+	var r String
+	err := e.CallOut(
+		"read-string", &r,
+		String(prompt), optionalString(opts.Initial), optionalName(opts.History),
+		optionalString(opts.Default), Bool(opts.InheritInputMethod),
+	)
+	return string(r), err
+}
+
+// ReadNumberOptions contains optional arguments for [Env.ReadNumber].
+type ReadNumberOptions struct {
+	// If HasDefault is true, Default is the value to return if the user
+	// enters empty input.
+	HasDefault bool
+	Default    float64
+
+	// History is the name of the history list variable.  If empty, use
+	// read-number-history.
+	History Name
+}
+
+// ReadNumber reads a number from the minibuffer using read-number.
+func (e Env) ReadNumber(prompt string, opts ReadNumberOptions) (float64, error) {
+	var def In = Nil
+	if opts.HasDefault {
+		def = Float(opts.Default)
+	}
+	v, err := e.Call("read-number", String(prompt), def, optionalName(opts.History))
+	if err != nil {
+		return 0, err
+	}
+	var r Float
+	err = e.CallOut("float", &r, v)
+	return float64(r), err
+}
+
+// CompletingReadOptions contains optional arguments for
+// [Env.CompletingRead].
+type CompletingReadOptions struct {
+	// If Predicate is non-nil, only candidates for which it returns true
+	// are acceptable.  The argument is a candidate as passed by
+	// completing-read, e.g., a string or a cons cell whose car is a
+	// string.
+	Predicate func(Env, Value) (bool, error)
+
+	// If RequireMatch is true, the user must select one of the
+	// candidates.
+	RequireMatch bool
+
+	// Initial is the initial minibuffer contents.  Its use is
+	// discouraged; prefer Default.
+	Initial string
+
+	// History is the name of the history list variable.  If empty, use
+	// minibuffer-history.
+	History Name
+
+	// Default is the value to return if the user enters empty input.
+	Default string
+}
+
+// CompletingRead reads a string from the minibuffer with completion using
+// completing-read.  collection is a completion table, e.g., a list of
+// strings.  See [Completion].  If opts.Predicate is non-nil, CompletingRead
+// passes it to Emacs as a temporary function.
+//
+// [Completion]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Completion.html
+func (e Env) CompletingRead(prompt string, collection In, opts CompletingReadOptions) (string, error) {
+	pred, del, err := e.predicate(opts.Predicate)
+	if err != nil {
+		return "", err
+	}
+	defer del()
+	var r String
+	err = e.CallOut(
+		"completing-read", &r,
+		String(prompt), collection, pred, Bool(opts.RequireMatch),
+		optionalString(opts.Initial), optionalName(opts.History), optionalString(opts.Default),
+	)
+	return string(r), err
+}
+
+// ReadFileNameOptions contains optional arguments for [Env.ReadFileName].
+type ReadFileNameOptions struct {
+	// Dir is the directory to use for completing relative file names.
+	// If empty, use default-directory.
+	Dir string
+
+	// Default is the file name to return if the user exits with the
+	// same non-empty contents that the minibuffer started with.
+	Default string
+
+	// If MustMatch is true, the user must select an existing file.
+	MustMatch bool
+
+	// Initial is the initial file name in the minibuffer, relative to
+	// Dir.
+	Initial string
+
+	// If Predicate is non-nil, only file names for which it returns true
+	// are acceptable.
+	Predicate func(Env, string) (bool, error)
+}
+
+// ReadFileName reads a file name from the minibuffer using read-file-name.
+// If opts.Predicate is non-nil, ReadFileName passes it to Emacs as a
+// temporary function.  See [Reading File Names].
+//
+// [Reading File Names]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Reading-File-Names.html
+func (e Env) ReadFileName(prompt string, opts ReadFileNameOptions) (string, error) {
+	var p func(Env, Value) (bool, error)
+	if opts.Predicate != nil {
+		p = func(e Env, v Value) (bool, error) {
+			s, err := e.Str(v)
+			if err != nil {
+				return false, err
+			}
+			return opts.Predicate(e, s)
+		}
+	}
+	pred, del, err := e.predicate(p)
+	if err != nil {
+		return "", err
+	}
+	defer del()
+	var r String
+	err = e.CallOut(
+		"read-file-name", &r,
+		String(prompt), optionalString(opts.Dir), optionalString(opts.Default),
+		Bool(opts.MustMatch), optionalString(opts.Initial), pred,
+	)
+	return string(r), err
+}
+
+// YesOrNo asks the user a yes-or-no question using yes-or-no-p and returns
+// the answer.  See [Yes-or-No Queries].
+//
+// [Yes-or-No Queries]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Yes_002dor_002dNo-Queries.html
+func (e Env) YesOrNo(prompt string) (bool, error) {
+	var r Bool
+	err := e.CallOut("yes-or-no-p", &r, String(prompt))
+	return bool(r), err
+}
+
+// predicate exports p as a temporary Emacs function of one argument.  If p is
+// nil, predicate returns nil.  Call the returned DeleteFunc once the function
+// isn’t needed any more.
+func (e Env) predicate(p func(Env, Value) (bool, error)) (Value, DeleteFunc, error) {
+	if p == nil {
+		v, err := e.Nil()
+		return v, func() {}, err
+	}
+	return e.LambdaFunc(func(e Env, args []Value) (Value, error) {
+		ok, err := p(e, args[0])
+		if err != nil {
+			return Value{}, err
+		}
+		return Bool(ok).Emacs(e)
+	}, Arity{1, 1}, "")
+}
+
+// optionalString returns nil if s is empty and s otherwise.
+func optionalString(s string) In {
+	if s == "" {
+		return Nil
+	}
+	return String(s)
+}
+
+// optionalName returns nil if n is empty and n otherwise.
+func optionalName(n Name) In {
+	if n == "" {
+		return Nil
+	}
+	return n
+}
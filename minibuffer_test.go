@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	ERTTest(readString)
+	ERTTest(completingRead)
+}
+
+// withStub calls body while the Emacs function fun is temporarily replaced by
+// stub, a Lisp lambda expression.
+func withStub(e Env, fun Name, stub string, body func() error) error {
+	f, del, err := e.Lambda(body)
+	if err != nil {
+		return err
+	}
+	defer del()
+	if _, err := e.Call("require", Symbol("cl-lib")); err != nil {
+		return err
+	}
+	s, err := e.Call("read", String(stub))
+	if err != nil {
+		return err
+	}
+	_, err = e.Eval(List{
+		Symbol("cl-letf"),
+		List{List{List{Symbol("symbol-function"), List{Symbol("quote"), fun}}, s}},
+		List{Symbol("funcall"), f},
+	})
+	return err
+}
+
+func readString(e Env) error {
+	var got string
+	err := withStub(e, "read-string", "(lambda (&rest args) (prin1-to-string args))", func() error {
+		var err error
+		got, err = e.ReadString("Name: ", ReadStringOptions{History: "my-history", Default: "dflt"})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if want := `("Name: " nil my-history "dflt" nil)`; got != want {
+		return fmt.Errorf("ReadString: got %s, want %s", got, want)
+	}
+	return nil
+}
+
+func completingRead(e Env) error {
+	var got string
+	stub := `(lambda (_prompt collection predicate &rest _) (car (all-completions "" collection predicate)))`
+	err := withStub(e, "completing-read", stub, func() error {
+		var err error
+		got, err = e.CompletingRead("Fruit: ", List{String("apple"), String("banana")}, CompletingReadOptions{
+			Predicate: func(e Env, v Value) (bool, error) {
+				s, err := e.Str(v)
+				return strings.HasPrefix(s, "b"), err
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if want := "banana"; got != want {
+		return fmt.Errorf("CompletingRead: got %q, want %q", got, want)
+	}
+	return nil
+}
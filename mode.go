@@ -0,0 +1,314 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "fmt"
+
+// MinorModeOptions contains optional settings for [DefineMinorMode].
+type MinorModeOptions struct {
+	// Lighter is the mode line indicator, typically starting with a space.
+	// If empty, the mode has no indicator.
+	Lighter string
+
+	// If Global is true, the mode is global instead of buffer-local.
+	Global bool
+
+	// InitValue is the initial value of the mode variable.
+	InitValue bool
+
+	// Keymap is the keymap that is active while the mode is enabled.
+	// It’s stored in the variable whose name is the mode name followed by
+	// “-map”.
+	Keymap Keymap
+
+	// If OnEnable or OnDisable are non-nil, Emacs calls them whenever the
+	// mode is enabled or disabled, respectively, before running the mode
+	// hook.  The mode variable has already been set when they are called.
+	// If they return an error, the mode hook doesn’t run.
+	OnEnable, OnDisable func(Env) error
+}
+
+// DefineMinorMode arranges for a minor mode to be defined once the module is
+// loaded.  It uses define-minor-mode, which defines the mode command and
+// variable name, the keymap variable name-map, and the hook variable
+// name-hook.  See [Defining Minor Modes].  If doc is empty, define-minor-mode
+// generates a documentation string.  If opts.OnEnable or opts.OnDisable are
+// non-nil, DefineMinorMode also defines internal helper functions whose names
+// start with name followed by two hyphens.  DefineMinorMode panics if name is
+// empty or already registered.  It returns name so you can assign it directly
+// to a Go variable if you want.
+//
+// [Defining Minor Modes]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Defining-Minor-Modes.html
+func DefineMinorMode(name Name, doc Doc, opts MinorModeOptions) Name {
+	modes.MustEnqueue(name, minorMode{name, doc, opts})
+	return name
+}
+
+// DefineMinorMode is like the global [DefineMinorMode] function, except that
+// it requires a live environment, defines the mode immediately, and returns
+// errors instead of panicking.
+func (e Env) DefineMinorMode(name Name, doc Doc, opts MinorModeOptions) error {
+	return modes.RegisterAndDefine(e, name, minorMode{name, doc, opts})
+}
+
+var modes = NewManager(RequireName | RequireUniqueName | DefineOnInit)
+
+type minorMode struct {
+	name Name
+	doc  Doc
+	opts MinorModeOptions
+}
+
+func (m minorMode) Define(e Env) error {
+	onEnable, err := e.modeCallback(m.name+"--enable", m.opts.OnEnable, "Called when the mode is enabled.")
+	if err != nil {
+		return err
+	}
+	onDisable, err := e.modeCallback(m.name+"--disable", m.opts.OnDisable, "Called when the mode is disabled.")
+	if err != nil {
+		return err
+	}
+	keymap, err := m.opts.Keymap.Emacs(e)
+	if err != nil {
+		return err
+	}
+	var doc In = Nil
+	if m.doc != "" {
+		doc = m.doc
+	}
+	form := List{
+		Symbol("define-minor-mode"), m.name, doc,
+		Symbol(":lighter"), optionalString(m.opts.Lighter),
+		Symbol(":global"), Bool(m.opts.Global),
+		Symbol(":init-value"), Bool(m.opts.InitValue),
+		Symbol(":keymap"), List{Symbol("quote"), keymap},
+	}
+	if m.opts.OnEnable != nil || m.opts.OnDisable != nil {
+		form = append(form, List{Symbol("if"), m.name, onEnable, onDisable})
+	}
+	_, err = e.Eval(form)
+	return err
+}
+
+// modeCallback exports fun as a function of no arguments with the given name
+// and returns a form that calls it.  If fun is nil, modeCallback returns nil.
+func (e Env) modeCallback(name Name, fun func(Env) error, doc Doc) (In, error) {
+	if fun == nil {
+		return Nil, nil
+	}
+	if _, err := e.ExportFunc(name, func(e Env, args []Value) (Value, error) {
+		if err := fun(e); err != nil {
+			return Value{}, err
+		}
+		return e.Nil()
+	}, Arity{0, 0}, doc); err != nil {
+		return nil, err
+	}
+	return List{name}, nil
+}
+
+// DerivedModeOptions contains optional settings for [DefineDerivedMode].
+type DerivedModeOptions struct {
+	// Parent is the parent mode, for example prog-mode or text-mode.  If
+	// empty, the mode has no parent.
+	Parent Name
+
+	// ModeName is the value of mode-name, which the mode line displays.
+	// If empty, use the mode name.
+	ModeName string
+
+	// Syntax contains modifications to the syntax table of the mode.
+	Syntax []SyntaxEntry
+
+	// FontLock contains the font-lock keywords of the mode.
+	FontLock []FontLockKeyword
+
+	// AutoMode contains regular expressions for file names.  Visiting a
+	// file whose name matches one of them enables the mode.
+	// DefineDerivedMode adds them to auto-mode-alist.
+	AutoMode []string
+
+	// If OnActivate is non-nil, Emacs calls it whenever the mode is
+	// activated, after setting up the mode and before running the mode
+	// hook.
+	OnActivate func(Env) error
+}
+
+// SyntaxEntry is a modification to a syntax table, see modify-syntax-entry
+// and [Syntax Descriptors].
+//
+// [Syntax Descriptors]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Syntax-Descriptors.html
+type SyntaxEntry struct {
+	// Char is the character whose syntax to modify.
+	Char rune
+
+	// Descriptor is the syntax descriptor, for example “.” for
+	// punctuation or “< b” for a comment starter.
+	Descriptor string
+}
+
+// FontLockKeyword is a search-based fontification rule.  See [Search-based
+// Fontification].  Exactly one of Regexp and Matcher must be set.
+//
+// [Search-based Fontification]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Search_002dbased-Fontification.html
+type FontLockKeyword struct {
+	// Regexp is a regular expression to search for.
+	Regexp string
+
+	// Matcher searches for the next match starting at point and before
+	// limit.  If it finds a match, it returns its start and end
+	// positions and true.  It may not move point; Emacs moves point to
+	// the end of the match.
+	Matcher func(e Env, limit int) (start, end int, found bool, err error)
+
+	// Face is the face to apply to the match.
+	Face Symbol
+}
+
+// DefineDerivedMode arranges for a major mode to be defined once the module is
+// loaded.  It uses define-derived-mode, which defines the mode command name,
+// the keymap variable name-map, the syntax table variable name-syntax-table,
+// and the hook variable name-hook.  See [Derived Modes].  If doc is empty,
+// define-derived-mode generates a documentation string.  If opts.OnActivate
+// is non-nil or opts.FontLock contains matcher functions, DefineDerivedMode
+// also defines internal helper functions whose names start with name followed
+// by two hyphens.  DefineDerivedMode panics if name is empty or already
+// registered, or if opts is invalid.  It returns name so you can assign it
+// directly to a Go variable if you want.
+//
+// [Derived Modes]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Derived-Modes.html
+func DefineDerivedMode(name Name, doc Doc, opts DerivedModeOptions) Name {
+	m, err := newDerivedMode(name, doc, opts)
+	if err != nil {
+		panic(err)
+	}
+	modes.MustEnqueue(name, m)
+	return name
+}
+
+// DefineDerivedMode is like the global [DefineDerivedMode] function, except
+// that it requires a live environment, defines the mode immediately, and
+// returns errors instead of panicking.
+func (e Env) DefineDerivedMode(name Name, doc Doc, opts DerivedModeOptions) error {
+	m, err := newDerivedMode(name, doc, opts)
+	if err != nil {
+		return err
+	}
+	return modes.RegisterAndDefine(e, name, m)
+}
+
+type derivedMode struct {
+	name Name
+	doc  Doc
+	opts DerivedModeOptions
+}
+
+func newDerivedMode(name Name, doc Doc, opts DerivedModeOptions) (derivedMode, error) {
+	for _, k := range opts.FontLock {
+		if (k.Regexp == "") == (k.Matcher == nil) {
+			return derivedMode{}, fmt.Errorf("mode %s: font-lock keyword for face %s needs either a regular expression or a matcher", name, k.Face)
+		}
+		if k.Face == "" {
+			return derivedMode{}, fmt.Errorf("mode %s: font-lock keyword %q has no face", name, k.Regexp)
+		}
+	}
+	return derivedMode{name, doc, opts}, nil
+}
+
+func (m derivedMode) Define(e Env) error {
+	var parent In = Nil
+	if m.opts.Parent != "" {
+		parent = m.opts.Parent
+	}
+	modeName := m.opts.ModeName
+	if modeName == "" {
+		modeName = string(m.name)
+	}
+	var doc In = Nil
+	if m.doc != "" {
+		doc = m.doc
+	}
+	form := List{Symbol("define-derived-mode"), m.name, parent, String(modeName), doc}
+	if len(m.opts.FontLock) > 0 {
+		keywords := make(List, len(m.opts.FontLock))
+		for i, k := range m.opts.FontLock {
+			var matcher In = String(k.Regexp)
+			if k.Matcher != nil {
+				name := m.name + Name(fmt.Sprintf("--font-lock-%d", i))
+				if err := e.exportMatcher(name, k.Matcher); err != nil {
+					return err
+				}
+				matcher = name
+			}
+			keywords[i] = List{matcher, Int(0), List{Symbol("quote"), k.Face}}
+		}
+		form = append(form, List{
+			Symbol("setq-local"), Symbol("font-lock-defaults"),
+			List{Symbol("quote"), List{keywords}},
+		})
+	}
+	if m.opts.OnActivate != nil {
+		call, err := e.modeCallback(m.name+"--activate", m.opts.OnActivate, "Called when the mode is activated.")
+		if err != nil {
+			return err
+		}
+		form = append(form, call)
+	}
+	if _, err := e.Eval(form); err != nil {
+		return err
+	}
+	if len(m.opts.Syntax) > 0 {
+		table, err := e.Call("symbol-value", m.name+"-syntax-table")
+		if err != nil {
+			return err
+		}
+		for _, s := range m.opts.Syntax {
+			if _, err := e.Call("modify-syntax-entry", Int(s.Char), String(s.Descriptor), table); err != nil {
+				return err
+			}
+		}
+	}
+	for _, re := range m.opts.AutoMode {
+		if _, err := e.Call("add-to-list", Symbol("auto-mode-alist"), Cons{Car: String(re), Cdr: m.name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportMatcher exports a font-lock matcher function with the given name.
+func (e Env) exportMatcher(name Name, matcher func(Env, int) (int, int, bool, error)) error {
+	_, err := e.ExportFunc(name, func(e Env, args []Value) (Value, error) {
+		limit, err := e.Int(args[0])
+		if err != nil {
+			return Value{}, err
+		}
+		start, end, found, err := matcher(e, int(limit))
+		if err != nil {
+			return Value{}, err
+		}
+		if !found {
+			return e.Nil()
+		}
+		if _, err := e.Call("set-match-data", List{Int(start), Int(end)}); err != nil {
+			return Value{}, err
+		}
+		if _, err := e.Call("goto-char", Int(end)); err != nil {
+			return Value{}, err
+		}
+		return T.Emacs(e)
+	}, Arity{1, 1}, "Font-lock matcher.\n\n(fn LIMIT)")
+	return err
+}
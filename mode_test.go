@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "fmt"
+
+func init() {
+	ERTTest(defineMinorMode)
+	ERTTest(defineDerivedMode)
+}
+
+func defineMinorMode(e Env) error {
+	var enabled, disabled int
+	const name Name = "emacs-go-test-minor-mode"
+	if err := e.DefineMinorMode(name, "Test minor mode.", MinorModeOptions{
+		Lighter:   " Test",
+		Keymap:    Keymap{Bindings: []KeyBinding{{"C-c t", Symbol("ignore")}}},
+		OnEnable:  func(Env) error { enabled++; return nil },
+		OnDisable: func(Env) error { disabled++; return nil },
+	}); err != nil {
+		return err
+	}
+	if _, err := e.Eval(List{
+		Symbol("with-temp-buffer"),
+		List{name, Int(1)},
+		List{name, Int(-1)},
+	}); err != nil {
+		return err
+	}
+	if enabled != 1 || disabled != 1 {
+		return fmt.Errorf("callbacks: got %d enable and %d disable calls, want one each", enabled, disabled)
+	}
+	keymap, err := e.Call("symbol-value", name+"-map")
+	if err != nil {
+		return err
+	}
+	def, found, err := e.LookupKey(keymap, "C-c t", false)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("key C-c t not bound in %s-map", name)
+	}
+	var sym Symbol
+	if err := sym.FromEmacs(e, def); err != nil {
+		return err
+	}
+	if sym != "ignore" {
+		return fmt.Errorf("key C-c t: got %s, want ignore", sym)
+	}
+	return nil
+}
+
+func defineDerivedMode(e Env) error {
+	var activated int
+	const name Name = "emacs-go-test-derived-mode"
+	if err := e.DefineDerivedMode(name, "Test major mode.", DerivedModeOptions{
+		Parent:   "prog-mode",
+		ModeName: "Test",
+		Syntax:   []SyntaxEntry{{'$', "."}},
+		FontLock: []FontLockKeyword{
+			{Regexp: `\<let\>`, Face: "font-lock-keyword-face"},
+			{Matcher: func(Env, int) (int, int, bool, error) { return 0, 0, false, nil }, Face: "font-lock-constant-face"},
+		},
+		AutoMode:   []string{`\.emacs-go-test\'`},
+		OnActivate: func(Env) error { activated++; return nil },
+	}); err != nil {
+		return err
+	}
+	v, err := e.Eval(List{
+		Symbol("with-temp-buffer"),
+		List{name},
+		List{Symbol("list"), Symbol("major-mode"), List{Symbol("string"), List{Symbol("char-syntax"), Int('$')}}},
+	})
+	if err != nil {
+		return err
+	}
+	var mode Symbol
+	var syntax String
+	if err := e.CallOut("car", &mode, v); err != nil {
+		return err
+	}
+	if err := e.CallOut("cadr", &syntax, v); err != nil {
+		return err
+	}
+	if mode != Symbol(name) {
+		return fmt.Errorf("major-mode: got %s, want %s", mode, name)
+	}
+	if syntax != "." {
+		return fmt.Errorf("syntax of $: got %q, want \".\"", syntax)
+	}
+	if activated != 1 {
+		return fmt.Errorf("OnActivate: got %d calls, want 1", activated)
+	}
+	alist, err := e.Call("symbol-value", Symbol("auto-mode-alist"))
+	if err != nil {
+		return err
+	}
+	var auto Symbol
+	if err := e.CallOut("assoc-default", &auto, String("foo.emacs-go-test"), alist, Symbol("string-match-p")); err != nil {
+		return err
+	}
+	if auto != Symbol(name) {
+		return fmt.Errorf("auto-mode-alist: got %s, want %s", auto, name)
+	}
+	return nil
+}
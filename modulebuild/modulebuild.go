@@ -0,0 +1,310 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package modulebuild builds Go packages as Emacs modules.  It takes care of
+// the platform-specific details: the build mode, the file name extension that
+// Emacs expects, the C compiler and cgo flags for cross-compilation, and
+// locating the emacs-module.h header.  On macOS, it can also combine modules
+// for several architectures into a single universal file.
+//
+// The emacsbuild command (github.com/phst/emacs/cmd/emacsbuild) is a thin
+// command-line wrapper around this package.
+package modulebuild
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Platform is a target operating system and architecture, using the Go names
+// for them.
+type Platform struct {
+	GOOS, GOARCH string
+}
+
+// Host returns the platform of the running program.
+func Host() Platform {
+	return Platform{runtime.GOOS, runtime.GOARCH}
+}
+
+// ParsePlatform parses a platform of the form GOOS/GOARCH.
+func ParsePlatform(s string) (Platform, error) {
+	goos, goarch, ok := strings.Cut(s, "/")
+	if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+		return Platform{}, fmt.Errorf("invalid platform %q, must be of the form GOOS/GOARCH", s)
+	}
+	return Platform{goos, goarch}, nil
+}
+
+// String returns p in the form GOOS/GOARCH.
+func (p Platform) String() string {
+	return p.GOOS + "/" + p.GOARCH
+}
+
+// Extension returns the file name extension for Emacs modules on the given
+// operating system, including the leading dot.  This is the value of the Emacs
+// variable module-file-suffix on that system.
+func Extension(goos string) string {
+	switch goos {
+	case "darwin":
+		return ".dylib"
+	case "windows":
+		return ".dll"
+	default:
+		return ".so"
+	}
+}
+
+// Options describe how to build a module.
+type Options struct {
+	// Package is the Go package to build.  It defaults to the package in
+	// the current directory.
+	Package string
+
+	// Dir is the working directory for go build.  It defaults to the
+	// current directory.
+	Dir string
+
+	// Output is the name of the module file without extension, relative
+	// to Dir.  If there’s more than one output file, Build appends the
+	// GOOS and GOARCH values to the name.  Output defaults to the last
+	// element of Package.
+	Output string
+
+	// Platforms are the platforms to build for.  If empty, Build builds
+	// only for the host platform.
+	Platforms []Platform
+
+	// Universal requests combining all macOS modules into a single
+	// universal file using lipo.  The output file name then contains
+	// “universal” instead of the architecture.
+	Universal bool
+
+	// IncludeDir is a directory containing emacs-module.h.  If empty,
+	// Build uses [FindIncludeDir] to find the header for the Emacs binary
+	// named by Emacs.  If that fails as well, Build relies on the C
+	// compiler finding the header in its default search path.
+	IncludeDir string
+
+	// Emacs is the Emacs binary used to find emacs-module.h.  It defaults
+	// to “emacs”.
+	Emacs string
+
+	// BuildFlags are additional flags for go build.
+	BuildFlags []string
+
+	// Stdout and Stderr receive the output of the commands that Build
+	// runs.  If nil, the output is discarded.
+	Stdout, Stderr io.Writer
+}
+
+// Result describes a module file produced by [Build].
+type Result struct {
+	// File is the name of the module file, relative to the current
+	// directory.
+	File string
+
+	// Platforms contains the platforms that the file supports.  For
+	// universal macOS files, it contains more than one element.
+	Platforms []Platform
+}
+
+// Build builds a Go package as Emacs module for each of the requested
+// platforms.  It returns the module files that it has produced.
+func Build(ctx context.Context, o Options) ([]Result, error) {
+	if o.Package == "" {
+		o.Package = "."
+	}
+	if o.Output == "" {
+		abs, err := filepath.Abs(filepath.Join(o.Dir, o.Package))
+		if err != nil {
+			return nil, err
+		}
+		o.Output = filepath.Base(abs)
+	}
+	if len(o.Platforms) == 0 {
+		o.Platforms = []Platform{Host()}
+	}
+	if o.Emacs == "" {
+		o.Emacs = "emacs"
+	}
+	if o.IncludeDir == "" {
+		// Ignore errors; the header might be installed in a standard
+		// location.
+		o.IncludeDir, _ = FindIncludeDir(ctx, o.Emacs)
+	}
+	seen := make(map[Platform]bool)
+	var darwin []Platform
+	for _, p := range o.Platforms {
+		if seen[p] {
+			return nil, fmt.Errorf("duplicate platform %s", p)
+		}
+		seen[p] = true
+		if p.GOOS == "darwin" {
+			darwin = append(darwin, p)
+		}
+	}
+	universal := o.Universal && len(darwin) > 1
+	single := len(o.Platforms) == 1
+	var results []Result
+	var slices []string
+	for _, p := range o.Platforms {
+		file := o.Output
+		if !single {
+			file = fmt.Sprintf("%s-%s-%s", o.Output, p.GOOS, p.GOARCH)
+		}
+		file += Extension(p.GOOS)
+		if err := o.build(ctx, p, file); err != nil {
+			return nil, err
+		}
+		if universal && p.GOOS == "darwin" {
+			slices = append(slices, file)
+			continue
+		}
+		results = append(results, Result{o.path(file), []Platform{p}})
+	}
+	if universal {
+		file := o.Output + "-darwin-universal" + Extension("darwin")
+		args := append([]string{"-create", "-output", file}, slices...)
+		if err := o.run(exec.CommandContext(ctx, "lipo", args...), nil); err != nil {
+			return nil, fmt.Errorf("can’t create universal module: %w", err)
+		}
+		for _, s := range slices {
+			if err := os.Remove(o.path(s)); err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, Result{o.path(file), darwin})
+	}
+	return results, nil
+}
+
+// build builds the module for a single platform.
+func (o *Options) build(ctx context.Context, p Platform, file string) error {
+	args := []string{"build", "-buildmode=c-shared", "-o", file}
+	args = append(args, o.BuildFlags...)
+	args = append(args, o.Package)
+	cmd := exec.CommandContext(ctx, "go", args...)
+	env := []string{"GOOS=" + p.GOOS, "GOARCH=" + p.GOARCH, "CGO_ENABLED=1"}
+	if o.IncludeDir != "" {
+		cflags, ok := os.LookupEnv("CGO_CFLAGS")
+		if !ok {
+			cflags = "-g -O2" // default of the go command
+		}
+		env = append(env, "CGO_CFLAGS="+strings.TrimSpace(cflags+" -I"+o.IncludeDir))
+	}
+	if cc := crossCompiler(p); cc != "" && os.Getenv("CC") == "" {
+		env = append(env, "CC="+cc)
+	}
+	if err := o.run(cmd, env); err != nil {
+		return fmt.Errorf("can’t build module for %s: %w", p, err)
+	}
+	// go build also writes a C header file that’s useless for Emacs
+	// modules.
+	header := strings.TrimSuffix(file, Extension(p.GOOS)) + ".h"
+	if err := os.Remove(o.path(header)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// crossCompiler returns the conventional name of a C compiler that targets the
+// given platform from the host platform, or an empty string if the default
+// compiler should be used.
+func crossCompiler(p Platform) string {
+	h := Host()
+	if p == h {
+		return ""
+	}
+	switch p.GOOS {
+	case "darwin":
+		// Apple’s clang can target all architectures, and the go
+		// command passes the right -arch flag.
+		if h.GOOS == "darwin" {
+			return "clang"
+		}
+	case "linux":
+		if triple, ok := linuxTriples[p.GOARCH]; ok {
+			return triple + "-gcc"
+		}
+	case "windows":
+		if triple, ok := mingwTriples[p.GOARCH]; ok {
+			return triple + "-gcc"
+		}
+	}
+	return ""
+}
+
+var linuxTriples = map[string]string{
+	"amd64": "x86_64-linux-gnu",
+	"arm64": "aarch64-linux-gnu",
+	"386":   "i686-linux-gnu",
+}
+
+var mingwTriples = map[string]string{
+	"amd64": "x86_64-w64-mingw32",
+	"arm64": "aarch64-w64-mingw32",
+	"386":   "i686-w64-mingw32",
+}
+
+func (o *Options) run(cmd *exec.Cmd, env []string) error {
+	cmd.Dir = o.Dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = o.Stdout
+	cmd.Stderr = o.Stderr
+	return cmd.Run()
+}
+
+// path returns the name of file relative to the current directory.
+func (o *Options) path(file string) string {
+	if o.Dir == "" || filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(o.Dir, file)
+}
+
+// FindIncludeDir returns the directory containing the emacs-module.h header
+// that belongs to the given Emacs binary.  It asks Emacs for its installation
+// directory and looks for the header in the usual places relative to it.
+func FindIncludeDir(ctx context.Context, emacs string) (string, error) {
+	cmd := exec.CommandContext(ctx, emacs, "--quick", "--batch", "--eval",
+		`(princ (expand-file-name invocation-directory))`)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("can’t run %s: %w", emacs, err)
+	}
+	bin := strings.TrimSpace(stdout.String())
+	for _, rel := range []string{
+		"../include",              // Unix installations
+		"../Resources/include",    // macOS application bundles
+		"../../Resources/include", // macOS application bundles with Contents/MacOS/bin
+		"../src",                  // running from a build tree
+		"../../include",           // Windows installations
+	} {
+		dir := filepath.Clean(filepath.Join(bin, rel))
+		if _, err := os.Stat(filepath.Join(dir, "emacs-module.h")); err == nil {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("can’t find emacs-module.h for Emacs in %s", bin)
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modulebuild
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePlatform(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want Platform
+		ok   bool
+	}{
+		{"linux/amd64", Platform{"linux", "amd64"}, true},
+		{"darwin/arm64", Platform{"darwin", "arm64"}, true},
+		{"linux", Platform{}, false},
+		{"linux/", Platform{}, false},
+		{"/amd64", Platform{}, false},
+		{"linux/amd64/v2", Platform{}, false},
+	} {
+		got, err := ParsePlatform(tc.in)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("ParsePlatform(%q): got %v, %v; want %v, success = %t", tc.in, got, err, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestCrossCompiler(t *testing.T) {
+	if got := crossCompiler(Host()); got != "" {
+		t.Errorf("crossCompiler(host): got %q, want none", got)
+	}
+	if Host() != (Platform{"windows", "amd64"}) {
+		if got, want := crossCompiler(Platform{"windows", "amd64"}), "x86_64-w64-mingw32-gcc"; got != want {
+			t.Errorf("crossCompiler(windows/amd64): got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	dir := t.TempDir()
+	results, err := Build(context.Background(), Options{
+		Package:    "./testdata/mod",
+		Output:     filepath.Join(dir, "mod"),
+		IncludeDir: dir,
+		Stderr:     os.Stderr,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "mod"+Extension(Host().GOOS))
+	if len(results) != 1 || results[0].File != want || len(results[0].Platforms) != 1 || results[0].Platforms[0] != Host() {
+		t.Fatalf("Build: got %v, want single result %s", results, want)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "mod.h")); !os.IsNotExist(err) {
+		t.Errorf("header file not removed: %v", err)
+	}
+}
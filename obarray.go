@@ -0,0 +1,132 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Obarray is an Emacs obarray, i.e., a table of interned symbols separate
+// from the default obarray.  See [Creating and Interning Symbols].  Modules
+// can use obarrays to keep private symbol namespaces, for example for
+// completion tables or abbrev tables.  Create obarrays using
+// [Env.NewObarray].  Unlike a [Value], an Obarray stays valid across calls;
+// the Emacs object stays alive as long as the Obarray is reachable from Go,
+// or until you call [Obarray.Release].
+//
+// [Creating and Interning Symbols]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Creating-Symbols.html
+type Obarray struct {
+	ref Value // global reference, zero after Release
+}
+
+// NewObarray returns a new, empty obarray created by obarray-make.
+func (e Env) NewObarray() (*Obarray, error) {
+	// obarray-make is defined in obarray.el before Emacs 30.
+	if _, err := e.Call("require", Symbol("obarray")); err != nil {
+		return nil, err
+	}
+	v, err := e.Call("obarray-make")
+	if err != nil {
+		return nil, err
+	}
+	ref, err := e.makeGlobalRef(v)
+	if err != nil {
+		return nil, err
+	}
+	o := &Obarray{ref}
+	runtime.SetFinalizer(o, (*Obarray).finalize)
+	return o, nil
+}
+
+// InternIn interns the given symbol name in obarray and returns the symbol
+// object, like intern with an obarray argument.  obarray may be an
+// [*Obarray] or any other Emacs obarray.  Symbols interned in a different
+// obarray are distinct from the symbols with the same name in the default
+// obarray; in particular, converting a [Symbol] to Emacs always uses the
+// default obarray, so use the returned value to refer to the symbol.
+func (e Env) InternIn(obarray In, name Symbol) (Value, error) {
+	return e.Call("intern", String(name), obarray)
+}
+
+// Emacs returns the obarray object.  It returns an error if the Obarray has
+// been released.
+func (o *Obarray) Emacs(Env) (Value, error) {
+	if o.ref.r == nil {
+		return Value{}, errors.New("obarray already released")
+	}
+	return o.ref, nil
+}
+
+// Intern interns the given symbol name in the obarray and returns the
+// symbol object.  It’s a shorthand for [Env.InternIn].
+func (o *Obarray) Intern(e Env, name Symbol) (Value, error) {
+	return e.InternIn(o, name)
+}
+
+// Lookup returns the symbol with the given name in the obarray, like
+// intern-soft.  If there’s no such symbol, Lookup returns false.
+func (o *Obarray) Lookup(e Env, name Symbol) (Value, bool, error) {
+	r, err := e.Call("intern-soft", String(name), o)
+	if err != nil {
+		return Value{}, false, err
+	}
+	return r, e.IsNotNil(r), nil
+}
+
+// Unintern removes the symbol with the given name from the obarray, like
+// unintern.  It returns whether there was such a symbol.
+func (o *Obarray) Unintern(e Env, name Symbol) (bool, error) {
+	var r Bool
+	err := e.CallOut("unintern", &r, String(name), o)
+	return bool(r), err
+}
+
+// Names returns the names of all symbols in the obarray, in unspecified
+// order.
+func (o *Obarray) Names(e Env) ([]Symbol, error) {
+	var r []Symbol
+	fun, del, err := e.Lambda(func(s Symbol) {
+		r = append(r, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer del()
+	if _, err := e.Call("mapatoms", fun, o); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Release releases the obarray object.  Afterwards, using the Obarray fails.
+// Calling Release is optional, since the obarray is released once the
+// Obarray is garbage-collected, but calling Release frees resources earlier.
+// Calling Release more than once has no effect.
+func (o *Obarray) Release(e Env) error {
+	if o.ref.r == nil {
+		return nil
+	}
+	runtime.SetFinalizer(o, nil)
+	ref := o.ref
+	o.ref = Value{}
+	return e.freeGlobalRef(ref)
+}
+
+func (o *Obarray) finalize() {
+	if o.ref.r != nil {
+		releaseLater(o.ref)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+func init() {
+	ERTTest(obarrayIntern)
+}
+
+func obarrayIntern(e Env) error {
+	o, err := e.NewObarray()
+	if err != nil {
+		return err
+	}
+	defer o.Release(e)
+	if _, ok, err := o.Lookup(e, "go--obarray-test"); err != nil || ok {
+		return fmt.Errorf("Lookup in empty obarray: got %v, %v, want false, nil", ok, err)
+	}
+	sym, err := o.Intern(e, "go--obarray-test")
+	if err != nil {
+		return err
+	}
+	if _, err := o.Intern(e, "go--obarray-other"); err != nil {
+		return err
+	}
+	global, err := e.Intern("go--obarray-test")
+	if err != nil {
+		return err
+	}
+	if e.Eq(sym, global) {
+		return errors.New("symbol in private obarray is the same as in default obarray")
+	}
+	again, err := e.InternIn(o, "go--obarray-test")
+	if err != nil {
+		return err
+	}
+	if !e.Eq(sym, again) {
+		return errors.New("interning twice returned different symbols")
+	}
+	found, ok, err := o.Lookup(e, "go--obarray-test")
+	if err != nil {
+		return err
+	}
+	if !ok || !e.Eq(found, sym) {
+		return errors.New("Lookup didn’t find interned symbol")
+	}
+	names, err := o.Names(e)
+	if err != nil {
+		return err
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	if want := []Symbol{"go--obarray-other", "go--obarray-test"}; !reflect.DeepEqual(names, want) {
+		return fmt.Errorf("Names: got %q, want %q", names, want)
+	}
+	if ok, err := o.Unintern(e, "go--obarray-test"); err != nil || !ok {
+		return fmt.Errorf("Unintern: got %v, %v, want true, nil", ok, err)
+	}
+	if _, ok, err := o.Lookup(e, "go--obarray-test"); err != nil || ok {
+		return fmt.Errorf("Lookup after Unintern: got %v, %v, want false, nil", ok, err)
+	}
+	if err := o.Release(e); err != nil {
+		return err
+	}
+	if _, err := o.Intern(e, "go--obarray-test"); err == nil {
+		return errors.New("interning in released obarray succeeded")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "reflect"
+
+// Optional is an optional argument of a function exported using [Export] or
+// [AutoFunc].  Trailing parameters of type Optional[T] become &optional
+// arguments in Emacs, so that callers can omit them.  If the caller omits the
+// argument or passes nil, the Go function receives an Optional with Present
+// set to false and Value set to the zero value of T.  Otherwise, the argument
+// is converted to T as described in the package documentation.  Since Lisp
+// doesn’t distinguish between omitted optional arguments and nil, Optional
+// can’t represent a present nil value; for example, Optional[bool] is either
+// absent or true.
+//
+// For example, the following function can be called as (my-func 1) or
+// (my-func 1 "x"):
+//
+//	func myFunc(a int, b emacs.Optional[string]) { … }
+//
+// All parameters after the first Optional parameter must also be of Optional
+// types, except for a variadic parameter.  Optional values can also be
+// converted to Emacs: absent values become nil.
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// Some returns a present [Optional] with the given value.
+func Some[T any](v T) Optional[T] { return Optional[T]{v, true} }
+
+// Get returns o.Value and o.Present.
+func (o Optional[T]) Get() (T, bool) { return o.Value, o.Present }
+
+// Emacs returns nil if o isn’t present, and o.Value converted to Emacs
+// otherwise.
+func (o Optional[T]) Emacs(e Env) (Value, error) {
+	if !o.Present {
+		return e.Nil()
+	}
+	v := reflect.ValueOf(&o.Value).Elem()
+	conv, err := InFuncFor(v.Type())
+	if err != nil {
+		return Value{}, err
+	}
+	return conv(v).Emacs(e)
+}
+
+// FromEmacs sets o from v.  If v is nil, o becomes absent; otherwise,
+// FromEmacs converts v to o.Value.
+func (o *Optional[T]) FromEmacs(e Env, v Value) error {
+	if !e.IsNotNil(v) {
+		*o = Optional[T]{}
+		return nil
+	}
+	p := reflect.ValueOf(&o.Value)
+	conv, err := OutFuncFor(p.Type())
+	if err != nil {
+		return err
+	}
+	if err := conv(p).FromEmacs(e, v); err != nil {
+		return err
+	}
+	o.Present = true
+	return nil
+}
+
+func (*Optional[T]) optional() {}
+
+// optionalParam is implemented by pointers to [Optional] types.
+type optionalParam interface{ optional() }
+
+var optionalParamType = reflect.TypeOf((*optionalParam)(nil)).Elem()
+
+// isOptional returns whether t is an [Optional] type.
+func isOptional(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(optionalParamType)
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	ERTTest(optionalArguments)
+}
+
+func optionalArguments(e Env) error {
+	fun, del, err := e.Lambda(func(a int, b Optional[string], c Optional[int]) string {
+		return fmt.Sprintf("%d %q/%t %d/%t", a, b.Value, b.Present, c.Value, c.Present)
+	})
+	if err != nil {
+		return err
+	}
+	defer del()
+	for _, tc := range []struct {
+		args []In
+		want string
+	}{
+		{[]In{Int(1)}, `1 ""/false 0/false`},
+		{[]In{Int(1), String("x")}, `1 "x"/true 0/false`},
+		{[]In{Int(1), Nil, Int(3)}, `1 ""/false 3/true`},
+	} {
+		var got String
+		if err := e.CallOut("funcall", &got, append([]In{fun}, tc.args...)...); err != nil {
+			return err
+		}
+		if string(got) != tc.want {
+			return fmt.Errorf("funcall with %d arguments: got %s, want %s", len(tc.args), got, tc.want)
+		}
+	}
+	return nil
+}
+
+func TestOptionalArity(t *testing.T) {
+	_, _, arity, _ := AutoFunc(func(Env, int, Optional[int], ...string) {}, Anonymous{})
+	if want := (Arity{1, -1}); arity != want {
+		t.Errorf("variadic function: got arity %v, want %v", arity, want)
+	}
+	_, _, arity, _ = AutoFunc(func(int, Optional[int], Optional[bool]) {}, Anonymous{})
+	if want := (Arity{1, 3}); arity != want {
+		t.Errorf("nonvariadic function: got arity %v, want %v", arity, want)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("AutoFunc didn’t panic for a required argument following an optional one")
+		}
+	}()
+	AutoFunc(func(Optional[int], int) {}, Anonymous{})
+}
@@ -0,0 +1,332 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// OrgTable is an [In] that converts a slice of structs to the Lisp
+// representation of an Org table, i.e., a list of rows in the format returned
+// by org-table-to-lisp.  Each row is a list of cell strings; the symbol hline
+// represents a horizontal rule.  Rows must be a slice of structs or of
+// pointers to structs.
+//
+// Each exported field of the struct type is a column.  The column header is
+// the field name, or the name given in a struct tag of the form
+// emacs:"Header".  The tag emacs:"-" excludes the field.  Fields can have
+// string, boolean, or numeric types, or types that implement
+// [encoding.TextMarshaler] and [encoding.TextUnmarshaler].  Use
+// [Env.FormatOrgTable] to convert the result to text.
+type OrgTable struct {
+	Rows interface{}
+
+	// If NoHeader is true, the table has no header row.
+	NoHeader bool
+}
+
+// Emacs returns a new list representing the table.
+func (t OrgTable) Emacs(e Env) (Value, error) {
+	s := reflect.ValueOf(t.Rows)
+	if s.Kind() != reflect.Slice {
+		return Value{}, WrongTypeArgument("go-slice-p", String(fmt.Sprintf("%T", t.Rows)))
+	}
+	cols, err := structColumns(s.Type().Elem())
+	if err != nil {
+		return Value{}, err
+	}
+	var rows List
+	if !t.NoHeader {
+		header := make(List, len(cols))
+		for i, c := range cols {
+			header[i] = String(c.name)
+		}
+		rows = append(rows, header, Symbol("hline"))
+	}
+	for i := 0; i < s.Len(); i++ {
+		cells, err := formatRow(cols, s.Index(i))
+		if err != nil {
+			return Value{}, err
+		}
+		row := make(List, len(cells))
+		for j, c := range cells {
+			row[j] = String(c)
+		}
+		rows = append(rows, row)
+	}
+	return rows.Emacs(e)
+}
+
+// OrgTableOut is an [Out] that parses the Lisp representation of an Org table
+// into a slice of structs.  Rows must be a pointer to a slice of structs or
+// of pointers to structs.  See [OrgTable] for how fields map to columns.
+//
+// If the first row of the table is followed by a horizontal rule, it’s
+// treated as a header, and columns are matched to fields by header name.
+// Columns without a corresponding field are ignored.  Otherwise, columns are
+// matched to fields by position.  FromEmacs ignores horizontal rules and
+// trims whitespace around cells.
+type OrgTableOut struct {
+	Rows interface{}
+}
+
+// FromEmacs sets *t.Rows to a new slice with one element for each data row in
+// the table v.
+func (t OrgTableOut) FromEmacs(e Env, v Value) error {
+	p := reflect.ValueOf(t.Rows)
+	if p.Kind() != reflect.Ptr || p.IsNil() || p.Elem().Kind() != reflect.Slice {
+		return WrongTypeArgument("go-slice-pointer-p", String(fmt.Sprintf("%T", t.Rows)))
+	}
+	cols, err := structColumns(p.Type().Elem().Elem())
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	var hlines []int // indices into rows of rows followed by a horizontal rule
+	err = e.Dolist(v, func(row Value) error {
+		var sym Bool
+		if err := e.CallOut("symbolp", &sym, row); err != nil {
+			return err
+		}
+		if sym {
+			hlines = append(hlines, len(rows)-1)
+			return nil
+		}
+		var cells []string
+		if err := e.Dolist(row, func(cell Value) error {
+			var s String
+			if err := e.CallOut("format", &s, String("%s"), cell); err != nil {
+				return err
+			}
+			cells = append(cells, strings.TrimSpace(string(s)))
+			return nil
+		}); err != nil {
+			return err
+		}
+		rows = append(rows, cells)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	header := false
+	for _, h := range hlines {
+		header = header || h == 0
+	}
+	// mapping[i] is the index of the field for column i, or −1.
+	var mapping []int
+	if len(rows) > 0 && header {
+		byName := make(map[string]int, len(cols))
+		for i, c := range cols {
+			byName[c.name] = i
+		}
+		for _, h := range rows[0] {
+			i, ok := byName[h]
+			if !ok {
+				i = -1
+			}
+			mapping = append(mapping, i)
+		}
+		rows = rows[1:]
+	} else {
+		for i := range cols {
+			mapping = append(mapping, i)
+		}
+	}
+	r := reflect.MakeSlice(p.Type().Elem(), len(rows), len(rows))
+	for i, cells := range rows {
+		elem := r.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(elem.Type().Elem()))
+			elem = elem.Elem()
+		}
+		for j, cell := range cells {
+			if j >= len(mapping) || mapping[j] < 0 {
+				continue
+			}
+			c := cols[mapping[j]]
+			fp, err := fieldPointer(elem, c.index)
+			if err != nil {
+				return fmt.Errorf("row %d, column %s: %w", i, c.name, err)
+			}
+			if err := c.parse(cell, fp.Elem()); err != nil {
+				return fmt.Errorf("row %d, column %s: %w", i, c.name, err)
+			}
+		}
+	}
+	p.Elem().Set(r)
+	return nil
+}
+
+// FormatOrgTable converts the Lisp representation of an Org table, for
+// example the result of converting an [OrgTable], to aligned Org table text
+// using orgtbl-to-orgtbl.
+func (e Env) FormatOrgTable(table In) (string, error) {
+	if _, err := e.Call("require", Symbol("org-table")); err != nil {
+		return "", err
+	}
+	var s String
+	err := e.CallOut("orgtbl-to-orgtbl", &s, table, Nil)
+	return string(s), err
+}
+
+// ParseOrgTable parses the Org table text into its Lisp representation using
+// org-table-to-lisp.  Use [OrgTableOut] to convert the result to Go.
+func (e Env) ParseOrgTable(text string) (Value, error) {
+	if _, err := e.Call("require", Symbol("org-table")); err != nil {
+		return Value{}, err
+	}
+	return e.Call("org-table-to-lisp", String(text))
+}
+
+// structColumn describes the mapping between a struct field and a column of
+// a textual table.
+type structColumn struct {
+	index  []int
+	name   string
+	format func(reflect.Value) (string, error)
+	parse  func(string, reflect.Value) error
+}
+
+// structColumns returns the columns for the struct type t, which can also be
+// a pointer to a struct type.  See [OrgTable] for the mapping rules.
+func structColumns(t reflect.Type) ([]structColumn, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, WrongTypeArgument("go-struct-p", String(t.String()))
+	}
+	var r []structColumn
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name := f.Tag.Get("emacs")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		format, parse, err := cellFuncs(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of type %s: %w", f.Name, t, err)
+		}
+		r = append(r, structColumn{f.Index, name, format, parse})
+	}
+	return r, nil
+}
+
+// formatRow formats the fields of the struct v, which can also be a pointer
+// to a struct.  If v is a nil pointer, all cells are empty.
+func formatRow(cols []structColumn, v reflect.Value) ([]string, error) {
+	r := make([]string, len(cols))
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return r, nil
+		}
+		v = v.Elem()
+	}
+	for i, c := range cols {
+		fv, ok := fieldValue(v, c.index)
+		if !ok {
+			continue // empty cell
+		}
+		s, err := c.format(fv)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", c.name, err)
+		}
+		r[i] = s
+	}
+	return r, nil
+}
+
+// cellFuncs returns functions that format and parse table cells of type t.
+// The parse function receives an addressable value.
+func cellFuncs(t reflect.Type) (func(reflect.Value) (string, error), func(string, reflect.Value) error, error) {
+	if t.Implements(textMarshalerType) && reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		format := func(v reflect.Value) (string, error) {
+			b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+			return string(b), err
+		}
+		parse := func(s string, v reflect.Value) error {
+			return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		}
+		return format, parse, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value) (string, error) { return v.String(), nil },
+			func(s string, v reflect.Value) error { v.SetString(s); return nil },
+			nil
+	case reflect.Bool:
+		return func(v reflect.Value) (string, error) { return strconv.FormatBool(v.Bool()), nil },
+			func(s string, v reflect.Value) error {
+				if s == "" {
+					v.SetBool(false)
+					return nil
+				}
+				b, err := strconv.ParseBool(s)
+				v.SetBool(b)
+				return err
+			},
+			nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) (string, error) { return strconv.FormatInt(v.Int(), 10), nil },
+			func(s string, v reflect.Value) error {
+				if s == "" {
+					v.SetInt(0)
+					return nil
+				}
+				i, err := strconv.ParseInt(s, 10, t.Bits())
+				v.SetInt(i)
+				return err
+			},
+			nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(v reflect.Value) (string, error) { return strconv.FormatUint(v.Uint(), 10), nil },
+			func(s string, v reflect.Value) error {
+				if s == "" {
+					v.SetUint(0)
+					return nil
+				}
+				i, err := strconv.ParseUint(s, 10, t.Bits())
+				v.SetUint(i)
+				return err
+			},
+			nil
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value) (string, error) {
+				return strconv.FormatFloat(v.Float(), 'g', -1, t.Bits()), nil
+			},
+			func(s string, v reflect.Value) error {
+				if s == "" {
+					v.SetFloat(0)
+					return nil
+				}
+				f, err := strconv.ParseFloat(s, t.Bits())
+				v.SetFloat(f)
+				return err
+			},
+			nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported table cell type %s", t)
+	}
+}
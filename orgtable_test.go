@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+func init() {
+	ERTTest(orgTable)
+}
+
+type orgTableRow struct {
+	Name    string
+	Count   int     `emacs:"Number"`
+	Ratio   float64 `emacs:"Ratio (%)"`
+	Done    bool
+	Ignored string `emacs:"-"`
+}
+
+func orgTable(e Env) error {
+	in := []orgTableRow{
+		{"foo", 1, 0.5, true, "x"},
+		{"bar baz", -2, 12.25, false, "y"},
+	}
+	v, err := OrgTable{Rows: in}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	text, err := e.FormatOrgTable(v)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[0], "Ratio (%)") || !strings.HasPrefix(lines[1], "|--") {
+		return fmt.Errorf("FormatOrgTable: unexpected table\n%s", text)
+	}
+	// Reorder and add columns to test that columns are matched by name.
+	parsed, err := e.ParseOrgTable(strings.Join([]string{
+		"| Done  | Other | Name    | Number | Ratio (%) |",
+		"|-------+-------+---------+--------+-----------|",
+		"| true  | a     | foo     |      1 |       0.5 |",
+		"|-------+-------+---------+--------+-----------|",
+		"| false | b     | bar baz |     -2 |     12.25 |",
+	}, "\n"))
+	if err != nil {
+		return err
+	}
+	var out []orgTableRow
+	if err := (OrgTableOut{&out}).FromEmacs(e, parsed); err != nil {
+		return err
+	}
+	want := []orgTableRow{
+		{"foo", 1, 0.5, true, ""},
+		{"bar baz", -2, 12.25, false, ""},
+	}
+	if !reflect.DeepEqual(out, want) {
+		return fmt.Errorf("OrgTableOut: got %+v, want %+v", out, want)
+	}
+	// Without header, columns are matched by position.
+	headless, err := OrgTable{Rows: []*orgTableRow{&in[0]}, NoHeader: true}.Emacs(e)
+	if err != nil {
+		return err
+	}
+	var ptrs []*orgTableRow
+	if err := (OrgTableOut{&ptrs}).FromEmacs(e, headless); err != nil {
+		return err
+	}
+	if len(ptrs) != 1 || *ptrs[0] != want[0] {
+		return fmt.Errorf("OrgTableOut without header: got %+v, want [%+v]", ptrs, want[0])
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Persistent is an Emacs value that stays valid across calls into the module.
+// It’s a wrapper around a [Global] reference that frees the reference
+// automatically: either explicitly when calling [Persistent.Release], or once
+// the Persistent is garbage-collected.  Use it to cache Emacs objects such as
+// symbols, buffers, or keymaps in Go.  Since freeing a global reference
+// requires a live environment, references of garbage-collected Persistent
+// objects are freed during the next call into the module.
+type Persistent struct {
+	g Global // zero after Release
+}
+
+// NewPersistent returns a new Persistent for v.
+func (e Env) NewPersistent(v Value) (*Persistent, error) {
+	g, err := e.GlobalRef(v)
+	if err != nil {
+		return nil, err
+	}
+	p := &Persistent{g}
+	runtime.SetFinalizer(p, (*Persistent).finalize)
+	return p, nil
+}
+
+// Emacs returns the value.  This allows passing a Persistent to Emacs.  Emacs
+// returns an error if the Persistent has been released.
+func (p *Persistent) Emacs(Env) (Value, error) {
+	if !p.g.Valid() {
+		return Value{}, errors.New("persistent value already released")
+	}
+	return p.g.Value(), nil
+}
+
+// Released returns whether [Persistent.Release] has been called.
+func (p *Persistent) Released() bool { return !p.g.Valid() }
+
+// Release frees the underlying global reference.  Afterwards, converting the
+// Persistent to Emacs fails.  Calling Release is optional, but it frees
+// resources earlier than garbage collection would.  Calling Release more than
+// once has no effect.
+func (p *Persistent) Release(e Env) error {
+	if !p.g.Valid() {
+		return nil
+	}
+	runtime.SetFinalizer(p, nil)
+	g := p.g
+	p.g = Global{}
+	return e.FreeGlobalRef(g)
+}
+
+func (p *Persistent) finalize() {
+	if p.g.Valid() {
+		releaseLater(p.g.Value())
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+func init() {
+	ERTTest(persistentValue)
+}
+
+func persistentValue(e Env) error {
+	var cached *Persistent
+	lookup, del, err := e.Lambda(func(e Env) (Value, error) {
+		if cached == nil {
+			v, err := e.Call("make-sparse-keymap")
+			if err != nil {
+				return Value{}, err
+			}
+			if cached, err = e.NewPersistent(v); err != nil {
+				return Value{}, err
+			}
+		}
+		return cached.Emacs(e)
+	})
+	if err != nil {
+		return err
+	}
+	defer del()
+	a, err := e.Call("funcall", lookup)
+	if err != nil {
+		return err
+	}
+	b, err := e.Call("funcall", lookup)
+	if err != nil {
+		return err
+	}
+	if !e.Eq(a, b) {
+		return fmt.Errorf("cached keymap changed between calls: %s vs. %s", e.traceSummary(a), e.traceSummary(b))
+	}
+	if err := cached.Release(e); err != nil {
+		return err
+	}
+	if !cached.Released() {
+		return errors.New("Released returned false after Release")
+	}
+	if err := cached.Release(e); err != nil {
+		return fmt.Errorf("second Release: %v", err)
+	}
+	if _, err := cached.Emacs(e); err == nil {
+		return errors.New("converting a released Persistent succeeded")
+	}
+	return nil
+}
@@ -21,7 +21,7 @@ import (
 )
 
 func init() {
-	ERTTest(pipe)
+	ERTTest(pipe, Requires{OpenChannel})
 }
 
 func pipe(e Env) error {
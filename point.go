@@ -0,0 +1,93 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// This file contains wrappers for functions that move point and access the
+// region.  See
+// https://www.gnu.org/software/emacs/manual/html_node/elisp/Positions.html
+// and https://www.gnu.org/software/emacs/manual/html_node/elisp/The-Mark.html.
+// All positions are buffer positions in the current buffer, starting at 1.
+
+// Point returns the position of point in the current buffer, like point.
+func (e Env) Point() (int, error) {
+	return e.position("point")
+}
+
+// PointMin returns the beginning of the accessible portion of the current
+// buffer, like point-min.  It’s 1 unless the buffer is narrowed.
+func (e Env) PointMin() (int, error) {
+	return e.position("point-min")
+}
+
+// PointMax returns the end of the accessible portion of the current buffer,
+// like point-max.
+func (e Env) PointMax() (int, error) {
+	return e.position("point-max")
+}
+
+// GotoChar moves point to pos in the current buffer, like goto-char.  If pos
+// is outside the accessible portion of the buffer, GotoChar moves point to
+// its beginning or end.
+func (e Env) GotoChar(pos int) error {
+	_, err := e.Call("goto-char", Int(pos))
+	return err
+}
+
+// RegionActive returns whether the region is active and nonempty, like
+// use-region-p.  Commands that act on the region if it’s active should check
+// this before calling [Env.RegionBeginning] and [Env.RegionEnd].
+func (e Env) RegionActive() (bool, error) {
+	var r Bool
+	err := e.CallOut("use-region-p", &r)
+	return bool(r), err
+}
+
+// RegionBeginning returns the beginning of the region in the current buffer,
+// like region-beginning.  It returns an error if the mark isn’t set.
+func (e Env) RegionBeginning() (int, error) {
+	return e.position("region-beginning")
+}
+
+// RegionEnd returns the end of the region in the current buffer, like
+// region-end.  It returns an error if the mark isn’t set.
+func (e Env) RegionEnd() (int, error) {
+	return e.position("region-end")
+}
+
+// PushMark sets the mark in the current buffer to pos and pushes the old
+// mark onto the mark ring, like push-mark.  It doesn’t display a message.
+// If activate is true, it also activates the mark, so that the region
+// becomes active.
+func (e Env) PushMark(pos int, activate bool) error {
+	_, err := e.Call("push-mark", Int(pos), T, Bool(activate))
+	return err
+}
+
+// BufferSubstring returns the text between start and end in the current
+// buffer as a Go string, like buffer-substring-no-properties.  Text
+// properties are discarded.
+func (e Env) BufferSubstring(start, end int) (string, error) {
+	var s String
+	err := e.CallOut("buffer-substring-no-properties", &s, Int(start), Int(end))
+	return string(s), err
+}
+
+// position calls the function fun without arguments and returns its result
+// as a position.
+func (e Env) position(fun Name) (int, error) {
+	var r Int
+	err := e.CallOut(fun, &r)
+	return int(r), err
+}
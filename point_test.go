@@ -0,0 +1,63 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "fmt"
+
+func init() {
+	ERTTest(pointRegion)
+}
+
+func pointRegion(e Env) error {
+	return withTestBuffer(e, "hello world", func() error {
+		if pos, err := e.Point(); err != nil || pos != 12 {
+			return fmt.Errorf("Point after insertion: got %d, %v, want 12, nil", pos, err)
+		}
+		if pos, err := e.PointMin(); err != nil || pos != 1 {
+			return fmt.Errorf("PointMin: got %d, %v, want 1, nil", pos, err)
+		}
+		if pos, err := e.PointMax(); err != nil || pos != 12 {
+			return fmt.Errorf("PointMax: got %d, %v, want 12, nil", pos, err)
+		}
+		if err := e.GotoChar(100); err != nil {
+			return err
+		}
+		if pos, err := e.Point(); err != nil || pos != 12 {
+			return fmt.Errorf("Point after GotoChar beyond end: got %d, %v, want 12, nil", pos, err)
+		}
+		if err := e.PushMark(7, true); err != nil {
+			return err
+		}
+		start, err := e.RegionBeginning()
+		if err != nil {
+			return err
+		}
+		end, err := e.RegionEnd()
+		if err != nil {
+			return err
+		}
+		if start != 7 || end != 12 {
+			return fmt.Errorf("region: got [%d, %d), want [7, 12)", start, end)
+		}
+		s, err := e.BufferSubstring(start, end)
+		if err != nil {
+			return err
+		}
+		if s != "world" {
+			return fmt.Errorf("BufferSubstring: got %q, want %q", s, "world")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,280 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Process is an Emacs process object.  See [Processes].  Like a [Value], a
+// Process is only valid while the environment that created it is live.
+// Process implements [In] and [Out], so exported functions, filters, and
+// sentinels can accept Process arguments.
+//
+// [Processes]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Processes.html
+type Process struct{ v Value }
+
+// ProcessOptions contains the arguments for [Env.MakeProcess].  Name and
+// Command are required.
+type ProcessOptions struct {
+	// Name is the name of the process.  Emacs makes it unique if
+	// necessary.
+	Name string
+
+	// Command contains the program to run and its arguments.
+	Command []string
+
+	// Buffer is the buffer associated with the process, or a buffer name.
+	// If Buffer is nil, the process has no buffer.
+	Buffer In
+
+	// Stderr is a buffer or pipe process that receives the standard error
+	// output of the process.  If Stderr is nil, standard error is
+	// combined with standard output.
+	Stderr In
+
+	// Filter is a Go function that receives the output of the process.
+	// It’s converted as described in [AutoLambda]; a typical filter has
+	// the signature func(Process, string).  If Filter is nil, the process
+	// uses the default filter, which inserts the output into the process
+	// buffer.
+	Filter interface{}
+
+	// Sentinel is a Go function that’s called when the status of the
+	// process changes.  It’s converted as described in [AutoLambda]; a
+	// typical sentinel has the signature func(Process, string).  If
+	// Sentinel is nil, the process uses the default sentinel.
+	Sentinel interface{}
+
+	// ConnectionType is either pipe or pty.  If empty, the process uses
+	// the value of process-connection-type.
+	ConnectionType Symbol
+
+	// If NoQuery is true, Emacs doesn’t ask the user whether to kill the
+	// process when exiting.
+	NoQuery bool
+}
+
+// MakeProcess starts an asynchronous subprocess using make-process.  If
+// opts.Filter or opts.Sentinel are set, MakeProcess exports them as function
+// symbols whose names start with “go--process-filter-” or
+// “go--process-sentinel-”, followed by a number.  MakeProcess releases these
+// Go functions automatically once the process has exited and its sentinel has
+// run.  MakeProcess panics if the filter or sentinel functions aren’t
+// convertible or can’t accept two arguments.
+func (e Env) MakeProcess(opts ProcessOptions) (Process, error) {
+	if len(opts.Command) == 0 {
+		return Process{}, errors.New("empty process command")
+	}
+	command := make(List, len(opts.Command))
+	for i, arg := range opts.Command {
+		command[i] = String(arg)
+	}
+	args := []In{Symbol(":name"), String(opts.Name), Symbol(":command"), command}
+	if opts.Buffer != nil {
+		args = append(args, Symbol(":buffer"), opts.Buffer)
+	}
+	if opts.Stderr != nil {
+		args = append(args, Symbol(":stderr"), opts.Stderr)
+	}
+	if opts.ConnectionType != "" {
+		args = append(args, Symbol(":connection-type"), opts.ConnectionType)
+	}
+	if opts.NoQuery {
+		args = append(args, Symbol(":noquery"), T)
+	}
+	fs := new(processFuncs)
+	if opts.Filter != nil {
+		l := processLambda(opts.Filter, "filter")
+		name, index, err := e.exportNumbered("go--process-filter-", l)
+		if err != nil {
+			return Process{}, err
+		}
+		fs.filter = numberedFunc{name, index}
+		args = append(args, Symbol(":filter"), name)
+	}
+	if opts.Filter != nil || opts.Sentinel != nil {
+		// Always install a sentinel so that we notice when the process
+		// exits and can release the filter.
+		var sentinel Func
+		if opts.Sentinel != nil {
+			sentinel = processLambda(opts.Sentinel, "sentinel").Fun
+		}
+		wrapper := func(e Env, args []Value) (Value, error) {
+			var r Value
+			var err error
+			if sentinel != nil {
+				r, err = sentinel(e, args)
+			} else {
+				r, err = e.Call("internal-default-process-sentinel", args[0], args[1])
+			}
+			if live, liveErr := (Process{args[0]}).Live(e); liveErr == nil && !live {
+				if relErr := fs.release(e); err == nil {
+					err = relErr
+				}
+			}
+			return r, err
+		}
+		name, index, err := e.exportNumbered("go--process-sentinel-", Lambda{wrapper, Arity{2, 2}, "Process sentinel defined in Go."})
+		if err != nil {
+			fs.release(e)
+			return Process{}, err
+		}
+		fs.sentinel = numberedFunc{name, index}
+		args = append(args, Symbol(":sentinel"), name)
+	}
+	p, err := e.Call("make-process", args...)
+	if err != nil {
+		fs.release(e)
+		return Process{}, err
+	}
+	return Process{p}, nil
+}
+
+// StartProcess starts an asynchronous subprocess running program with the
+// given arguments, like start-process.  buffer is the buffer associated with
+// the process or a buffer name; if it’s nil, the process has no buffer.  Use
+// [Env.MakeProcess] for more options.
+func (e Env) StartProcess(name string, buffer In, program string, args ...string) (Process, error) {
+	return e.MakeProcess(ProcessOptions{Name: name, Buffer: buffer, Command: append([]string{program}, args...)})
+}
+
+// processLambda converts a filter or sentinel function to a [Lambda] and
+// checks that it accepts two arguments.
+func processLambda(fun interface{}, kind string) Lambda {
+	l := AutoLambda(fun, Anonymous{})
+	if l.Arity.Min > 2 || (l.Arity.Max >= 0 && l.Arity.Max < 2) {
+		panic(fmt.Errorf("process %s must accept two arguments, but its arity is %v", kind, l.Arity))
+	}
+	return l
+}
+
+// numberedFunc is a function exported by exportNumbered.  The zero
+// numberedFunc doesn’t refer to any function.
+type numberedFunc struct {
+	name  Name
+	index funcIndex
+}
+
+// processFuncs contains the Go functions exported for a process.
+type processFuncs struct {
+	filter, sentinel numberedFunc
+	released         bool
+}
+
+// release releases the filter and sentinel functions.  Releasing them more
+// than once does nothing.
+func (fs *processFuncs) release(e Env) error {
+	if fs.released {
+		return nil
+	}
+	fs.released = true
+	var err error
+	for _, f := range []numberedFunc{fs.filter, fs.sentinel} {
+		if f.name == "" {
+			continue
+		}
+		if relErr := e.releaseNumbered(f.name, f.index); err == nil {
+			err = relErr
+		}
+	}
+	return err
+}
+
+// Value returns the Emacs process object.
+func (p Process) Value() Value {
+	return p.v
+}
+
+// Emacs implements [In.Emacs].  It returns the process object.
+func (p Process) Emacs(Env) (Value, error) {
+	return p.v, nil
+}
+
+// FromEmacs implements [Out.FromEmacs].  It sets *p to the process object v.
+// It returns an error if v isn’t a process.
+func (p *Process) FromEmacs(e Env, v Value) error {
+	var ok Bool
+	if err := e.CallOut("processp", &ok, v); err != nil {
+		return err
+	}
+	if !ok {
+		return WrongTypeArgument("processp", v)
+	}
+	*p = Process{v}
+	return nil
+}
+
+// Name returns the name of the process.
+func (p Process) Name(e Env) (string, error) {
+	var s String
+	err := e.CallOut("process-name", &s, p.v)
+	return string(s), err
+}
+
+// Send sends s to the standard input of the process.
+func (p Process) Send(e Env, s string) error {
+	_, err := e.Call("process-send-string", p.v, String(s))
+	return err
+}
+
+// CloseInput closes the standard input of the process by sending an
+// end-of-file.
+func (p Process) CloseInput(e Env) error {
+	_, err := e.Call("process-send-eof", p.v)
+	return err
+}
+
+// Kill deletes the process, killing the subprocess if it’s still running.
+func (p Process) Kill(e Env) error {
+	_, err := e.Call("delete-process", p.v)
+	return err
+}
+
+// Live returns whether the process is still alive.
+func (p Process) Live(e Env) (bool, error) {
+	var b Bool
+	err := e.CallOut("process-live-p", &b, p.v)
+	return bool(b), err
+}
+
+// Status returns the status of the process, such as run or exit.
+func (p Process) Status(e Env) (Symbol, error) {
+	var s Symbol
+	err := e.CallOut("process-status", &s, p.v)
+	return s, err
+}
+
+// ExitStatus returns the exit status of the process if it has exited, or the
+// signal number if it was killed by a signal.  It returns zero if the process
+// is still running.
+func (p Process) ExitStatus(e Env) (int, error) {
+	var i Int
+	err := e.CallOut("process-exit-status", &i, p.v)
+	return int(i), err
+}
+
+// ID returns the process ID of the subprocess.  It returns zero for processes
+// that don’t correspond to a subprocess, such as network connections.
+func (p Process) ID(e Env) (int, error) {
+	v, err := e.Call("process-id", p.v)
+	if err != nil || !e.IsNotNil(v) {
+		return 0, err
+	}
+	var i Int
+	err = i.FromEmacs(e, v)
+	return int(i), err
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func init() {
+	ERTTest(processFilterSentinel)
+}
+
+func processFilterSentinel(e Env) error {
+	var output strings.Builder
+	var events []string
+	p, err := e.MakeProcess(ProcessOptions{
+		Name:           "go-test",
+		Command:        []string{"cat"},
+		ConnectionType: "pipe",
+		NoQuery:        true,
+		Filter:         func(p Process, s string) { output.WriteString(s) },
+		Sentinel:       func(p Process, event string) { events = append(events, event) },
+	})
+	if err != nil {
+		return err
+	}
+	if err := p.Send(e, "hello\n"); err != nil {
+		return err
+	}
+	if err := p.CloseInput(e); err != nil {
+		return err
+	}
+	for i := 0; i < 100 && len(events) == 0; i++ {
+		if _, err := e.Call("accept-process-output", p, Float(0.1)); err != nil {
+			return err
+		}
+	}
+	if got, want := output.String(), "hello\n"; got != want {
+		return fmt.Errorf("process output: got %q, want %q", got, want)
+	}
+	if len(events) != 1 || events[0] != "finished\n" {
+		return fmt.Errorf("process events: got %q, want [\"finished\\n\"]", events)
+	}
+	if live, err := p.Live(e); err != nil || live {
+		return errors.New("process still live after finishing")
+	}
+	if status, err := p.ExitStatus(e); err != nil || status != 0 {
+		return fmt.Errorf("exit status: got %d, %v, want 0", status, err)
+	}
+	return nil
+}
+
+func TestProcessLambdaArity(t *testing.T) {
+	for _, fun := range []interface{}{
+		func(Process, string) {},
+		func(Process, ...string) {},
+		func(...Value) {},
+	} {
+		processLambda(fun, "filter")
+	}
+	for _, fun := range []interface{}{
+		func() {},
+		func(Process) {},
+		func(Process, string, int) {},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("processLambda(%T) didn’t panic", fun)
+				}
+			}()
+			processLambda(fun, "filter")
+		}()
+	}
+}
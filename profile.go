@@ -0,0 +1,198 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+)
+
+// ExportProfilingCommands arranges for interactive commands to be defined once
+// the module is loaded that profile the Go side of the module.  The commands
+// write profiles in the format that go tool pprof and go tool trace
+// understand.  Their names start with the given prefix:
+//
+//   - prefix-start-cpu-profile and prefix-stop-cpu-profile start and stop a
+//     CPU profile, see [pprof.StartCPUProfile].
+//   - prefix-write-profile writes a snapshot of a named profile such as
+//     heap, goroutine, or mutex, see [pprof.Lookup].  Note that the mutex and
+//     block profiles are empty unless you enable them using
+//     [runtime.SetMutexProfileFraction] or [runtime.SetBlockProfileRate].
+//   - prefix-start-execution-trace and prefix-stop-execution-trace start and
+//     stop an execution trace, see [trace.Start].
+//
+// The commands prompt for the file names.  Since profiling can reveal
+// internals of the module, the commands aren’t defined by default; call
+// ExportProfilingCommands in an init function to define them.
+func ExportProfilingCommands(prefix Name) {
+	OnInit(func(e Env) error {
+		return e.exportCommands([]command{
+			{prefix + "-start-cpu-profile", Arity{1, 1}, startCPUProfile, "Start a CPU profile of the Go code, writing it to FILE.\n\n(fn FILE)", String("FCPU profile file: ")},
+			{prefix + "-stop-cpu-profile", Arity{0, 0}, stopCPUProfile, "Stop the CPU profile of the Go code.", Nil},
+			{prefix + "-write-profile", Arity{2, 2}, writeProfile, "Write the Go profile named PROFILE to FILE.\n\n(fn PROFILE FILE)", profilePrompt()},
+			{prefix + "-start-execution-trace", Arity{1, 1}, startExecutionTrace, "Start a Go execution trace, writing it to FILE.\n\n(fn FILE)", String("FExecution trace file: ")},
+			{prefix + "-stop-execution-trace", Arity{0, 0}, stopExecutionTrace, "Stop the Go execution trace.", Nil},
+		})
+	})
+}
+
+// profilePrompt returns an interactive form that prompts for a profile name
+// and a file name.
+func profilePrompt() In {
+	var names List
+	for _, p := range pprof.Profiles() {
+		names = append(names, String(p.Name()))
+	}
+	return List{
+		Symbol("list"),
+		List{Symbol("completing-read"), String("Profile: "), List{Symbol("quote"), names}, Nil, T},
+		List{Symbol("read-file-name"), String("Profile file: ")},
+	}
+}
+
+var profiling struct {
+	mu        sync.Mutex
+	cpu, exec *os.File
+}
+
+func startCPUProfile(e Env, args []Value) (Value, error) {
+	profiling.mu.Lock()
+	defer profiling.mu.Unlock()
+	if profiling.cpu != nil {
+		return Value{}, errors.New("CPU profiling already active")
+	}
+	f, err := e.createFile(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return Value{}, err
+	}
+	profiling.cpu = f
+	return e.Nil()
+}
+
+func stopCPUProfile(e Env, args []Value) (Value, error) {
+	profiling.mu.Lock()
+	defer profiling.mu.Unlock()
+	if profiling.cpu == nil {
+		return Value{}, errors.New("CPU profiling not active")
+	}
+	pprof.StopCPUProfile()
+	err := profiling.cpu.Close()
+	profiling.cpu = nil
+	if err != nil {
+		return Value{}, err
+	}
+	return e.Nil()
+}
+
+func writeProfile(e Env, args []Value) (Value, error) {
+	name, err := e.Str(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	p := pprof.Lookup(name)
+	if p == nil {
+		return Value{}, fmt.Errorf("unknown profile %q", name)
+	}
+	f, err := e.createFile(args[1])
+	if err != nil {
+		return Value{}, err
+	}
+	if err := p.WriteTo(f, 0); err != nil {
+		f.Close()
+		return Value{}, err
+	}
+	if err := f.Close(); err != nil {
+		return Value{}, err
+	}
+	return e.Nil()
+}
+
+func startExecutionTrace(e Env, args []Value) (Value, error) {
+	profiling.mu.Lock()
+	defer profiling.mu.Unlock()
+	if profiling.exec != nil {
+		return Value{}, errors.New("execution tracing already active")
+	}
+	f, err := e.createFile(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	if err := trace.Start(f); err != nil {
+		f.Close()
+		return Value{}, err
+	}
+	profiling.exec = f
+	return e.Nil()
+}
+
+func stopExecutionTrace(e Env, args []Value) (Value, error) {
+	profiling.mu.Lock()
+	defer profiling.mu.Unlock()
+	if profiling.exec == nil {
+		return Value{}, errors.New("execution tracing not active")
+	}
+	trace.Stop()
+	err := profiling.exec.Close()
+	profiling.exec = nil
+	if err != nil {
+		return Value{}, err
+	}
+	return e.Nil()
+}
+
+// createFile creates the file whose name is given by the Lisp string v.
+// Relative file names are resolved against default-directory.
+func (e Env) createFile(v Value) (*os.File, error) {
+	var name String
+	if err := e.CallOut("expand-file-name", &name, v); err != nil {
+		return nil, err
+	}
+	return os.Create(string(name))
+}
+
+// command describes an interactive command.
+type command struct {
+	name  Name
+	arity Arity
+	fun   Func
+	doc   Doc
+	spec  In
+}
+
+// exportCommands defines the given commands.
+func (e Env) exportCommands(cmds []command) error {
+	for _, c := range cmds {
+		f, err := e.ExportFunc(c.name, c.fun, c.arity, c.doc)
+		if err != nil {
+			return err
+		}
+		spec, err := c.spec.Emacs(e)
+		if err != nil {
+			return err
+		}
+		if err := e.MakeInteractive(f, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
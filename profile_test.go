@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func init() {
+	ERTTest(profiles)
+}
+
+func profiles(e Env) error {
+	dir, err := os.MkdirTemp("", "emacs-go-profile-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	cpu, err := String(filepath.Join(dir, "cpu.pprof")).Emacs(e)
+	if err != nil {
+		return err
+	}
+	heap, err := String(filepath.Join(dir, "heap.pprof")).Emacs(e)
+	if err != nil {
+		return err
+	}
+	name, err := String("heap").Emacs(e)
+	if err != nil {
+		return err
+	}
+	if _, err := startCPUProfile(e, []Value{cpu}); err != nil {
+		return err
+	}
+	if _, err := startCPUProfile(e, []Value{cpu}); err == nil {
+		return fmt.Errorf("starting a second CPU profile succeeded unexpectedly")
+	}
+	if _, err := stopCPUProfile(e, nil); err != nil {
+		return err
+	}
+	if _, err := writeProfile(e, []Value{name, heap}); err != nil {
+		return err
+	}
+	for _, f := range []string{"cpu.pprof", "heap.pprof"} {
+		info, err := os.Stat(filepath.Join(dir, f))
+		if err != nil {
+			return err
+		}
+		if info.Size() == 0 {
+			return fmt.Errorf("profile %s is empty", f)
+		}
+	}
+	return nil
+}
@@ -23,11 +23,14 @@ func Provide(feature Name) {
 	provides.MustEnqueue(feature, provide(feature))
 }
 
-var provides = NewManager(RequireName | RequireUniqueName | DefineOnInit)
+var provides = NewManagerOf[provide](RequireName | RequireUniqueName | DefineOnInit)
 
 type provide Name
 
 func (p provide) Define(e Env) error {
+	if err := e.defineUnloadFunction(Name(p)); err != nil {
+		return err
+	}
 	_, err := e.Call("provide", Name(p))
 	return err
 }
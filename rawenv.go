@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// #include "emacs-module.h"
+// #include "wrappers.h"
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/phst/emacs/internal/rawenv"
+)
+
+// Connect package github.com/phst/emacs/emacstest, which provides
+// environments that don’t come from Emacs.
+func init() {
+	rawenv.NewEnv = func(p unsafe.Pointer) (interface{}, func()) {
+		symbols.disable()
+		env := (*C.emacs_env)(p)
+		e, tracked := enter(env)
+		return e, func() { e.exit(tracked) }
+	}
+	rawenv.Init = func(p unsafe.Pointer) error {
+		symbols.disable()
+		env := (*C.emacs_env)(p)
+		r := phst_emacs_init(env).base
+		switch {
+		case r.exit == C.emacs_funcall_exit_return:
+			return nil
+		case !bool(r.has_error_info):
+			return baseError.Error()
+		case r.exit == C.emacs_funcall_exit_throw:
+			return Throw{Value{r: r.error_symbol}, Value{r: r.error_data}}
+		default:
+			return Signal{Value{r: r.error_symbol}, Value{r: r.error_data}}
+		}
+	}
+	rawenv.ValueHandle = func(v interface{}) unsafe.Pointer {
+		return unsafe.Pointer(v.(Value).r)
+	}
+}
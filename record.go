@@ -0,0 +1,252 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// StartRecording starts recording calls of exported functions to w.  The
+// recording is an Emacs Lisp file that contains one form per call, for
+// example
+//
+//	(my-func '1 '"foo")
+//
+// Evaluating the forms in order replays the calls.  To replay a recording,
+// use [Env.Replay], or load the module and then the recording in a batch
+// Emacs:
+//
+//	emacs --batch --load=my-module --load=recording.el
+//
+// Only the outermost calls are recorded as forms; calls of exported functions
+// that happen while another exported function is active are recorded as
+// comments, since replaying the outer call will repeat them.  Calls of
+// anonymous functions are also recorded as comments, since they can’t be
+// replayed by name.  Arguments are printed using prin1.  Arguments without a
+// readable printed representation, such as buffers, can’t be replayed; you
+// have to edit such forms manually.
+//
+// Recording slows down all calls, since it needs to print all arguments.
+// StartRecording stops any previous recording.  You can call StartRecording
+// safely from multiple goroutines, but w must not be used concurrently while
+// recording is active.
+func StartRecording(w io.Writer) {
+	startRecording(w, nil)
+}
+
+// StopRecording stops recording calls.  It returns the first error that
+// occurred while writing the recording.  If no recording is active,
+// StopRecording does nothing and returns nil.  You can call StopRecording
+// safely from multiple goroutines.
+func StopRecording() error {
+	return recorder.stop()
+}
+
+// Replay evaluates the forms read from r in order.  r should contain a
+// recording created by [StartRecording].  Replay stops at the first form that
+// signals an error.
+func (e Env) Replay(r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	_, err = e.Eval(List{
+		Symbol("with-temp-buffer"),
+		List{Symbol("insert"), String(b)},
+		List{Symbol("goto-char"), List{Symbol("point-min")}},
+		List{
+			Symbol("let"), List{Symbol("form")},
+			List{
+				Symbol("while"),
+				List{
+					Symbol("setq"), Symbol("form"),
+					List{
+						Symbol("condition-case"), Nil,
+						List{Symbol("list"), List{Symbol("read"), List{Symbol("current-buffer")}}},
+						List{Symbol("end-of-file"), Nil},
+					},
+				},
+				List{Symbol("eval"), List{Symbol("car"), Symbol("form")}, T},
+			},
+		},
+	})
+	return err
+}
+
+// ExportRecordingCommands arranges for three interactive commands to be
+// defined once the module is loaded: prefix-start-recording,
+// prefix-stop-recording, and prefix-replay-recording, where prefix is the
+// given prefix.  The first one prompts for a file name and calls
+// [StartRecording] to record calls into that file.  The second one calls
+// [StopRecording].  The third one prompts for a file name and calls
+// [Env.Replay] with that file.  Call ExportRecordingCommands in an init
+// function.
+func ExportRecordingCommands(prefix Name) {
+	OnInit(func(e Env) error {
+		return e.exportCommands([]command{
+			{prefix + "-start-recording", Arity{1, 1}, startRecordingCommand, "Start recording calls of Go functions to FILE.\n\n(fn FILE)", String("FRecording file: ")},
+			{prefix + "-stop-recording", Arity{0, 0}, func(e Env, _ []Value) (Value, error) {
+				if err := StopRecording(); err != nil {
+					return Value{}, err
+				}
+				return e.Nil()
+			}, "Stop recording calls of Go functions.", Nil},
+			{prefix + "-replay-recording", Arity{1, 1}, replayCommand, "Replay the calls of Go functions recorded in FILE.\n\n(fn FILE)", String("fReplay recording: ")},
+		})
+	})
+}
+
+func startRecordingCommand(e Env, args []Value) (Value, error) {
+	f, err := e.createFile(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	startRecording(f, f)
+	return e.Nil()
+}
+
+func replayCommand(e Env, args []Value) (Value, error) {
+	var name String
+	if err := e.CallOut("expand-file-name", &name, args[0]); err != nil {
+		return Value{}, err
+	}
+	f, err := os.Open(string(name))
+	if err != nil {
+		return Value{}, err
+	}
+	defer f.Close()
+	if err := e.Replay(f); err != nil {
+		return Value{}, err
+	}
+	return e.Nil()
+}
+
+func startRecording(w io.Writer, c io.Closer) {
+	// Report errors from a previous recording at least on standard
+	// error, since there’s nobody else to report them to.
+	if err := recorder.stop(); err != nil {
+		os.Stderr.WriteString("error writing call recording: " + err.Error() + "\n")
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.w = w
+	recorder.closer = c
+	recorder.base = atomic.LoadInt32(&callDepth)
+	_, recorder.err = io.WriteString(w, ";; Recorded calls of Go functions  -*- lexical-binding: t; -*-\n")
+	atomic.StoreInt32(&recorder.enabled, 1)
+}
+
+// callDepth is the number of currently active calls of exported functions.
+// Accessed atomically.
+var callDepth int32
+
+type callRecorder struct {
+	enabled int32 // accessed atomically
+
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer // may be nil
+	err    error     // first write error
+	base   int32     // depth of outermost calls
+}
+
+var recorder callRecorder
+
+func (r *callRecorder) active() bool {
+	return atomic.LoadInt32(&r.enabled) != 0
+}
+
+func (r *callRecorder) stop() error {
+	atomic.StoreInt32(&r.enabled, 0)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.w == nil {
+		return nil
+	}
+	err := r.err
+	if r.closer != nil {
+		if cerr := r.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	r.w, r.closer, r.err = nil, nil, nil
+	return err
+}
+
+// record records a call of the function with the given name and arguments.
+// depth is the number of exported functions that were active when the call
+// started.
+func (r *callRecorder) record(e Env, name Name, args []Value, depth int32) {
+	form := make(List, 0, len(args)+1)
+	if name != "" {
+		form = append(form, name)
+	}
+	for _, a := range args {
+		form = append(form, List{Symbol("quote"), a})
+	}
+	text, err := e.printForm(form)
+	if err != nil {
+		text = "unprintable call of " + string(name) + ": " + e.Message(err)
+		name = ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.w == nil || r.err != nil {
+		return
+	}
+	// If recording started while an exported function was active, the
+	// outermost calls can be less deeply nested than expected.
+	if depth < r.base {
+		r.base = depth
+	}
+	switch {
+	case name == "":
+		text = ";; anonymous: " + text
+	case depth > r.base:
+		text = ";; " + strings.Repeat("  ", int(depth-r.base)) + "nested: " + text
+	}
+	_, r.err = io.WriteString(r.w, text+"\n")
+}
+
+// printForm prints form on a single line so that it can be read back.
+func (e Env) printForm(form List) (string, error) {
+	v, err := e.Eval(List{
+		Symbol("let"),
+		List{
+			List{Symbol("print-length"), Nil},
+			List{Symbol("print-level"), Nil},
+			List{Symbol("print-circle"), T},
+			List{Symbol("print-escape-newlines"), T},
+			List{Symbol("print-escape-control-characters"), T},
+		},
+		List{Symbol("prin1-to-string"), List{Symbol("quote"), form}},
+	})
+	if err != nil {
+		return "", err
+	}
+	s, err := e.Str(v)
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(s, "\n\r") {
+		return "", errors.New("printed representation contains newlines")
+	}
+	return s, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+func init() {
+	ERTTest(recordAndReplay)
+}
+
+func recordAndReplay(e Env) error {
+	const name Name = "emacs-go-test-recorded"
+	type call struct {
+		i int64
+		s string
+	}
+	var calls []call
+	if _, err := e.ExportFunc(name, func(e Env, args []Value) (Value, error) {
+		i, err := e.Int(args[0])
+		if err != nil {
+			return Value{}, err
+		}
+		s, err := e.Str(args[1])
+		if err != nil {
+			return Value{}, err
+		}
+		calls = append(calls, call{i, s})
+		return e.Nil()
+	}, Arity{2, 2}, ""); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	StartRecording(&buf)
+	_, err1 := e.Call(name, Int(1), String("foo\nbar"))
+	_, err2 := e.Call(name, Int(2), String("baz"))
+	if err := StopRecording(); err != nil {
+		return err
+	}
+	if err1 != nil {
+		return err1
+	}
+	if err2 != nil {
+		return err2
+	}
+	const want = `(emacs-go-test-recorded '1 '"foo\nbar")`
+	if !strings.Contains(buf.String(), want) {
+		return fmt.Errorf("recording doesn’t contain %s:\n%s", want, buf.String())
+	}
+	recorded := calls
+	calls = nil
+	if err := e.Replay(&buf); err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(calls, recorded) {
+		return fmt.Errorf("replayed calls: got %v, want %v", calls, recorded)
+	}
+	return nil
+}
@@ -68,17 +68,27 @@ type (
 )
 
 // InFuncFor returns an [InFunc] for the given type.  If there’s no known
-// conversion from t to Emacs, InFuncFor returns an error.
+// conversion from t to Emacs, InFuncFor returns an error.  See
+// [RegisterInFunc] to add conversions for other types.
 func InFuncFor(t reflect.Type) (InFunc, error) {
 	if t.Implements(inType) {
 		return castToIn, nil
 	}
+	if f := registeredInFunc(t); f != nil {
+		return f, nil
+	}
 	if u := valueTypes[t]; u != nil {
 		return func(v reflect.Value) In { return castToIn(v.Convert(u)) }, nil
 	}
 	if u := pointerTypes[t]; u != nil {
 		return func(v reflect.Value) In { return castToIn(v.Convert(u)) }, nil
 	}
+	if isCallbackInterface(t) {
+		return callbackIn, nil
+	}
+	if t.Implements(textMarshalerType) && !numericKind(t.Kind()) {
+		return textIn, nil
+	}
 	switch t.Kind() {
 	case reflect.Array, reflect.Slice:
 		if t.Elem().Kind() == reflect.Uint8 {
@@ -86,19 +96,25 @@ func InFuncFor(t reflect.Type) (InFunc, error) {
 		}
 		elem, err := InFuncFor(t.Elem())
 		if err != nil {
-			return nil, err
+			return nil, conversionContext(err, "vector element", t.Elem())
 		}
 		return vectorIn{elem}.call, nil
 	case reflect.Map:
 		key, err := InFuncFor(t.Key())
 		if err != nil {
-			return nil, err
+			return nil, conversionContext(err, "map key", t.Key())
 		}
 		value, err := InFuncFor(t.Elem())
 		if err != nil {
-			return nil, err
+			return nil, conversionContext(err, "map value", t.Elem())
 		}
 		return hashIn{HashTestFor(t.Key()), key, value}.call, nil
+	case reflect.Struct:
+		c, err := structCodecFor(t)
+		if err != nil {
+			return nil, err
+		}
+		return c.in, nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return intIn, nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -111,7 +127,8 @@ func InFuncFor(t reflect.Type) (InFunc, error) {
 }
 
 // OutFuncFor returns an [OutFunc] for the given type.  If there’s no known
-// conversion from Emacs to t, OutFuncFor returns an error.
+// conversion from Emacs to t, OutFuncFor returns an error.  See
+// [RegisterOutFunc] to add conversions for other types.
 func OutFuncFor(t reflect.Type) (OutFunc, error) {
 	if t.Implements(outType) {
 		return castToOut, nil
@@ -123,12 +140,21 @@ func OutFuncFor(t reflect.Type) (OutFunc, error) {
 		return nil, WrongTypeArgument("go-pointer-type-p", String(t.String()))
 	}
 	t = t.Elem()
+	if f := registeredOutFunc(t); f != nil {
+		return f, nil
+	}
 	if u := valueTypes[t]; u != nil {
 		f := func(v reflect.Value) Out {
 			return castToOut(v.Convert(reflect.PtrTo(u)))
 		}
 		return f, nil
 	}
+	if isCallbackInterface(t) {
+		return callbackOut, nil
+	}
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) && !numericKind(t.Kind()) {
+		return textOut, nil
+	}
 	switch t.Kind() {
 	case reflect.Array, reflect.Slice:
 		if t.Elem().Kind() == reflect.Uint8 {
@@ -136,19 +162,25 @@ func OutFuncFor(t reflect.Type) (OutFunc, error) {
 		}
 		elem, err := OutFuncFor(reflect.PtrTo(t.Elem()))
 		if err != nil {
-			return nil, err
+			return nil, conversionContext(err, "vector element", t.Elem())
 		}
 		return vectorOut{elem}.call, nil
 	case reflect.Map:
 		key, err := OutFuncFor(reflect.PtrTo(t.Key()))
 		if err != nil {
-			return nil, err
+			return nil, conversionContext(err, "map key", t.Key())
 		}
 		value, err := OutFuncFor(reflect.PtrTo(t.Elem()))
 		if err != nil {
-			return nil, err
+			return nil, conversionContext(err, "map value", t.Elem())
 		}
 		return hashOut{key, value}.call, nil
+	case reflect.Struct:
+		c, err := structCodecFor(t)
+		if err != nil {
+			return nil, err
+		}
+		return c.out, nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return intOut, nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -181,5 +213,7 @@ var valueTypes = map[reflect.Type]reflect.Type{
 // implement both [In] and [Out].  This is a variant of valueTypes for types
 // that are always used as pointers.
 var pointerTypes = map[reflect.Type]reflect.Type{
-	reflect.TypeOf((*big.Int)(nil)): reflect.TypeOf((*BigInt)(nil)),
+	reflect.TypeOf((*big.Int)(nil)):   reflect.TypeOf((*BigInt)(nil)),
+	reflect.TypeOf((*big.Float)(nil)): reflect.TypeOf((*BigFloat)(nil)),
+	reflect.TypeOf((*big.Rat)(nil)):   reflect.TypeOf((*BigRat)(nil)),
 }
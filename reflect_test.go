@@ -17,8 +17,11 @@ package emacs
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"math/big"
 	"math/rand"
+	"net"
+	"net/netip"
 	"reflect"
 	"testing"
 	"testing/quick"
@@ -227,9 +230,16 @@ func TestInOutFunc(t *testing.T) {
 		{map[string]float64{"hi": 1}, 0},
 		{big.Int{}, inErr},
 		{big.NewInt(1), outErr},
+		{big.NewFloat(1), outErr},
+		{big.NewRat(1, 2), outErr},
 		{temp, 0},
 		{func() {}, inErr | outErr},
-		{struct{ F int }{1}, inErr | outErr},
+		{struct{ F int }{1}, 0},
+		{struct{ F func() }{}, inErr | outErr},
+		{struct{ f int }{}, inErr | outErr},
+		{netip.Addr{}, 0},
+		{net.IP{}, 0},
+		{slog.LevelInfo, 0},
 	} {
 		t.Run(fmt.Sprintf("%#v", tc.val), func(t *testing.T) {
 			inVal := reflect.ValueOf(tc.val)
@@ -0,0 +1,138 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// SpanTracer creates spans for calls across the boundary between Go and
+// Emacs.  Use [SetSpanTracer] to install a SpanTracer.  This package doesn’t
+// depend on any particular tracing library; a SpanTracer is a small adapter.
+// For example, an adapter for [OpenTelemetry] could look like this:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) StartSpan(ctx context.Context, kind emacs.TraceKind, name string) (context.Context, emacs.Span) {
+//		ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(attribute.String("emacs.kind", kind.String())))
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ span trace.Span }
+//
+//	func (s otelSpan) End(err error) {
+//		if err != nil {
+//			s.span.RecordError(err)
+//			s.span.SetStatus(codes.Error, err.Error())
+//		}
+//		s.span.End()
+//	}
+//
+// [OpenTelemetry]: https://pkg.go.dev/go.opentelemetry.io/otel/trace
+type SpanTracer interface {
+	// StartSpan starts a new span that’s a child of the span in ctx, if
+	// any.  kind is [TraceExported] for calls of exported Go functions
+	// and [TraceFuncall] for calls of Emacs functions from Go.  name is
+	// the name of the called function.  StartSpan returns a new context
+	// that contains the new span.
+	StartSpan(ctx context.Context, kind TraceKind, name string) (context.Context, Span)
+}
+
+// Span is a span created by a [SpanTracer].
+type Span interface {
+	// End ends the span.  err is the error returned by the call, or nil
+	// if the call succeeded.
+	End(err error)
+}
+
+// SetSpanTracer installs a [SpanTracer].  Once installed, each call of an
+// exported Go function and each call of an Emacs function from Go creates a
+// span.  Spans nest: calls of Emacs functions from an exported function
+// create child spans of the span of the exported function, and so on.  Pass
+// nil to remove the tracer.  Exported functions can access the context of
+// their span using [Env.SpanContext], or by accepting a [context.Context]
+// parameter; see [AutoFunc].  You can call SetSpanTracer safely from
+// multiple goroutines.
+func SetSpanTracer(t SpanTracer) {
+	spans.mu.Lock()
+	defer spans.mu.Unlock()
+	spans.tracer = t
+	var i int32
+	if t != nil {
+		i = 1
+	}
+	atomic.StoreInt32(&spans.enabled, i)
+}
+
+// SpanContext returns the context of the innermost active span created by the
+// [SpanTracer] installed using [SetSpanTracer].  If there’s no active span,
+// SpanContext returns [context.Background].  Pass the context to Go functions
+// that should create child spans.
+func (e Env) SpanContext() context.Context {
+	spans.mu.Lock()
+	defer spans.mu.Unlock()
+	if n := len(spans.stack); n > 0 {
+		return spans.stack[n-1]
+	}
+	return context.Background()
+}
+
+var spans struct {
+	enabled int32 // accessed atomically
+
+	mu     sync.Mutex
+	tracer SpanTracer
+
+	// stack contains the contexts of the active spans, innermost last.
+	// Since Emacs only runs on one thread at a time, there’s only one
+	// stack.
+	stack []context.Context
+}
+
+// startSpan starts a span if a [SpanTracer] is installed.  It returns nil if
+// no span was started; otherwise, call endSpan with the result when the call
+// returns.
+func startSpan(kind TraceKind, name func() string) Span {
+	if atomic.LoadInt32(&spans.enabled) == 0 {
+		return nil
+	}
+	spans.mu.Lock()
+	t := spans.tracer
+	parent := context.Background()
+	if n := len(spans.stack); n > 0 {
+		parent = spans.stack[n-1]
+	}
+	spans.mu.Unlock()
+	if t == nil {
+		return nil
+	}
+	ctx, span := t.StartSpan(parent, kind, name())
+	spans.mu.Lock()
+	spans.stack = append(spans.stack, ctx)
+	spans.mu.Unlock()
+	return span
+}
+
+// endSpan ends a span started by startSpan.
+func endSpan(span Span, err error) {
+	spans.mu.Lock()
+	if n := len(spans.stack); n > 0 {
+		spans.stack = spans.stack[:n-1]
+	}
+	spans.mu.Unlock()
+	span.End(err)
+}
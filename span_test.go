@@ -0,0 +1,120 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func init() {
+	ERTTest(spanPropagation)
+}
+
+func spanPropagation(e Env) error {
+	tr := new(testSpanTracer)
+	SetSpanTracer(tr)
+	defer SetSpanTracer(nil)
+	var got string
+	fun, del, err := e.Lambda(func(e Env, ctx context.Context) error {
+		got = spanPath(ctx)
+		_, err := e.Call("ignore")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer del()
+	if _, err := e.Funcall(fun, nil); err != nil {
+		return err
+	}
+	// The outermost span is the one for the Funcall call above; its name
+	// is the printed representation of the lambda.
+	if want := "/lambda"; !strings.HasSuffix(got, want) {
+		return fmt.Errorf("span path: got %q, want suffix %q", got, want)
+	}
+	if want := got + "/ignore"; !containsString(tr.ended, want) {
+		return fmt.Errorf("span %q not ended; ended spans: %q", want, tr.ended)
+	}
+	return nil
+}
+
+func TestSpanNesting(t *testing.T) {
+	tr := new(testSpanTracer)
+	SetSpanTracer(tr)
+	defer SetSpanTracer(nil)
+	var e Env
+	outer := startSpan(TraceExported, func() string { return "outer" })
+	inner := startSpan(TraceFuncall, func() string { return "inner" })
+	if got, want := spanPath(e.SpanContext()), "/outer/inner"; got != want {
+		t.Errorf("innermost span: got %q, want %q", got, want)
+	}
+	endSpan(inner, errors.New("failure"))
+	if got, want := spanPath(e.SpanContext()), "/outer"; got != want {
+		t.Errorf("innermost span after ending inner span: got %q, want %q", got, want)
+	}
+	endSpan(outer, nil)
+	if got := spanPath(e.SpanContext()); got != "" {
+		t.Errorf("innermost span after ending all spans: got %q, want none", got)
+	}
+	if want := []string{"/outer/inner: failure", "/outer"}; !reflect.DeepEqual(tr.ended, want) {
+		t.Errorf("ended spans: got %q, want %q", tr.ended, want)
+	}
+
+	SetSpanTracer(nil)
+	if span := startSpan(TraceExported, func() string { panic("unexpected call") }); span != nil {
+		t.Errorf("startSpan without tracer returned %v", span)
+	}
+}
+
+type testSpanTracer struct{ ended []string }
+
+type spanPathKey struct{}
+
+func spanPath(ctx context.Context) string {
+	s, _ := ctx.Value(spanPathKey{}).(string)
+	return s
+}
+
+func (tr *testSpanTracer) StartSpan(ctx context.Context, kind TraceKind, name string) (context.Context, Span) {
+	path := spanPath(ctx) + "/" + name
+	return context.WithValue(ctx, spanPathKey{}, path), testSpan{tr, path}
+}
+
+type testSpan struct {
+	tr   *testSpanTracer
+	path string
+}
+
+func (s testSpan) End(err error) {
+	r := s.path
+	if err != nil {
+		r += ": " + err.Error()
+	}
+	s.tr.ended = append(s.tr.ended, r)
+}
+
+func containsString(s []string, x string) bool {
+	for _, y := range s {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
@@ -74,7 +74,7 @@ func (s *String) FromEmacs(e Env, v Value) error {
 // string, or if it’s not a valid Unicode scalar value sequence.  Str is not
 // named String to avoid confusion with the [fmt.Stringer.String] method.
 func (e Env) Str(v Value) (string, error) {
-	r := C.phst_emacs_copy_string_contents(e.raw(), v.r)
+	r := C.phst_emacs_copy_string_contents(e.raw(), v.raw())
 	if err := e.check(r.base); err != nil {
 		return "", err
 	}
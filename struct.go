@@ -0,0 +1,171 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// structCodec converts between a Go struct type and Emacs property lists.
+// Each exported field of the struct corresponds to the property whose key is
+// the keyword formed from the field name in Lisp style; see [structFields]
+// for the struct tags that change this mapping.  structCodec computes the
+// field mapping lazily so that recursive types work.
+type structCodec struct {
+	t    reflect.Type
+	done int32 // accessed atomically
+	once sync.Once
+
+	// The remaining fields are valid once done is nonzero.
+	fields []structField
+	byKey  map[Symbol]int
+	err    error
+}
+
+// structCodecs maps struct types to their *structCodec.
+var structCodecs sync.Map
+
+// structCodecFor returns the [structCodec] for the struct type t.  It returns
+// an error if a field has a type that can’t be converted.  If t is being
+// processed already, which happens for recursive types, structCodecFor
+// returns the unfinished codec without error; the outer call reports errors.
+func structCodecFor(t reflect.Type) (*structCodec, error) {
+	if c, ok := structCodecs.Load(t); ok {
+		c := c.(*structCodec)
+		if atomic.LoadInt32(&c.done) == 0 {
+			return c, nil
+		}
+		return c, c.err
+	}
+	c, _ := structCodecs.LoadOrStore(t, &structCodec{t: t})
+	codec := c.(*structCodec)
+	return codec, codec.init()
+}
+
+func (c *structCodec) init() error {
+	c.once.Do(func() {
+		c.fields, c.err = structFields(c.t)
+		if c.err == nil && len(c.fields) == 0 {
+			// Most likely an opaque type such as big.Float; don’t
+			// silently convert it to an empty property list.
+			c.err = WrongTypeArgument("go-known-type-p", String(c.t.String()))
+		}
+		c.byKey = make(map[Symbol]int, len(c.fields))
+		for i, f := range c.fields {
+			c.byKey[plistKey(f.name)] = i
+		}
+		atomic.StoreInt32(&c.done, 1)
+	})
+	return c.err
+}
+
+// plistKey returns the property list key for the Lisp name of a struct field.
+// That’s the name itself if it’s already a keyword, and otherwise the
+// corresponding keyword.
+func plistKey(name Symbol) Symbol {
+	if strings.HasPrefix(string(name), ":") {
+		return name
+	}
+	return ":" + name
+}
+
+func (c *structCodec) in(v reflect.Value) In { return structPlist{c, v} }
+
+type structPlist struct {
+	*structCodec
+	reflect.Value
+}
+
+func (p structPlist) Emacs(e Env) (Value, error) {
+	if err := p.init(); err != nil {
+		return Value{}, err
+	}
+	if !p.IsValid() {
+		return Value{}, WrongTypeArgument("go-valid-reflect-p", String(p.String()))
+	}
+	r := make(List, 0, 2*len(p.fields))
+	for _, f := range p.fields {
+		fv, ok := fieldValue(p.Value, f.index)
+		if !ok || f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		u, err := f.in(fv).Emacs(e)
+		if err != nil {
+			return Value{}, conversionContext(err, fmt.Sprintf("struct field %s", f.name), fv.Type())
+		}
+		r = append(r, plistKey(f.name), u)
+	}
+	return r.Emacs(e)
+}
+
+func (c *structCodec) out(v reflect.Value) Out { return structFromPlist{c, v} }
+
+type structFromPlist struct {
+	*structCodec
+	reflect.Value
+}
+
+// FromEmacs sets the fields of the struct from the property list v.  Fields
+// whose key doesn’t appear in v keep their values, and FromEmacs ignores keys
+// that don’t correspond to a field.
+func (p structFromPlist) FromEmacs(e Env, v Value) error {
+	if err := p.init(); err != nil {
+		return err
+	}
+	var elems []Value
+	if err := e.Dolist(v, func(u Value) error {
+		elems = append(elems, u)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(elems)%2 != 0 {
+		return WrongTypeArgument("plistp", v)
+	}
+	return p.setFields(e, p.Elem(), elems, false)
+}
+
+// setFields sets the fields of the struct s from the property list elements
+// in elems, which must have even length.  If strict is true, setFields
+// returns an error for keys that don’t correspond to a field; otherwise, it
+// ignores them.
+func (c *structCodec) setFields(e Env, s reflect.Value, elems []Value, strict bool) error {
+	for i := 0; i < len(elems); i += 2 {
+		key, err := e.Symbol(elems[i])
+		if err != nil {
+			return err
+		}
+		j, ok := c.byKey[key]
+		if !ok {
+			if strict {
+				return invalidKeywordArgument.Error(elems[i])
+			}
+			continue
+		}
+		f := c.fields[j]
+		fp, err := fieldPointer(s, f.index)
+		if err != nil {
+			return err
+		}
+		if err := f.out(fp).FromEmacs(e, elems[i+1]); err != nil {
+			return conversionContext(err, fmt.Sprintf("struct field %s", f.name), fp.Type().Elem())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	ERTTest(structPlists)
+	ERTTest(structPlistNilEmbedded)
+}
+
+type testServer struct {
+	Host    string
+	Port    int    `emacs:"port-number"`
+	Comment string `emacs:",omitempty"`
+	Secret  string `emacs:"-"`
+	Proxy   testProxy
+}
+
+type testProxy struct {
+	URL     string `emacs:":url"`
+	Enabled bool
+}
+
+func structPlists(e Env) error {
+	fun, del, err := e.Lambda(func(s testServer) testServer {
+		s.Port++
+		s.Proxy.Enabled = !s.Proxy.Enabled
+		return s
+	})
+	if err != nil {
+		return err
+	}
+	defer del()
+	var got String
+	if err := e.CallOut("eval", &got, List{Symbol("prin1-to-string"), List{
+		Symbol("funcall"), fun, List{Symbol("quote"), List{
+			Symbol(":host"), String("example.com"),
+			Symbol(":port-number"), Int(80),
+			Symbol(":unknown"), Int(1),
+			Symbol(":proxy"), List{Symbol(":url"), String("http://proxy")},
+		}},
+	}}, T); err != nil {
+		return err
+	}
+	const want = `(:host "example.com" :port-number 81 :proxy (:url "http://proxy" :enabled t))`
+	if got != want {
+		return fmt.Errorf("got %s, want %s", got, want)
+	}
+	if _, err := e.Call("funcall", fun, List{Symbol(":host")}); !e.IsWrongTypeArgument(err) {
+		return fmt.Errorf("odd-length property list: got error %v, want wrong-type-argument", err)
+	}
+	return nil
+}
+
+func structPlistNilEmbedded(e Env) error {
+	in, err := e.Call("identity", Reflect(reflect.ValueOf(withEmbeddedPointer{Outer: 1})))
+	if err != nil {
+		return err
+	}
+	var got String
+	if err := e.CallOut("prin1-to-string", &got, in); err != nil {
+		return err
+	}
+	if want := "(:outer 1)"; string(got) != want {
+		return fmt.Errorf("nil embedded pointer: got %s, want %s", got, want)
+	}
+	plist, err := e.List(Symbol(":inner"), Int(2))
+	if err != nil {
+		return err
+	}
+	var out withEmbeddedPointer
+	if err := Reflect(reflect.ValueOf(&out)).FromEmacs(e, plist); err != nil {
+		return err
+	}
+	if out.Embedded == nil || out.Inner != 2 {
+		return fmt.Errorf("converting from property list: got %+v, want Inner 2", out)
+	}
+	return nil
+}
+
+func TestStructCodecRecursive(t *testing.T) {
+	type node struct {
+		Name     string
+		Children []node
+	}
+	typ := reflect.TypeOf(node{})
+	if _, err := InFuncFor(typ); err != nil {
+		t.Errorf("InFuncFor(%s): %v", typ, err)
+	}
+	if _, err := OutFuncFor(reflect.PtrTo(typ)); err != nil {
+		t.Errorf("OutFuncFor(*%s): %v", typ, err)
+	}
+}
+
+func TestStructFieldsTagOptions(t *testing.T) {
+	fields, err := structFields(reflect.TypeOf(testServer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var omit []Symbol
+	for _, f := range fields {
+		if f.omitEmpty {
+			omit = append(omit, f.name)
+		}
+	}
+	if want := []Symbol{"comment"}; !reflect.DeepEqual(omit, want) {
+		t.Errorf("omitempty fields: got %q, want %q", omit, want)
+	}
+	type bad struct {
+		F int `emacs:"f,frobnicate"`
+	}
+	if _, err := structFields(reflect.TypeOf(bad{})); err == nil {
+		t.Error("structFields accepted unknown tag option")
+	}
+}
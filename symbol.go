@@ -100,7 +100,10 @@ func (n Name) validate() error {
 }
 
 // Intern interns the given symbol name in the default obarray and returns the
-// symbol object.
+// symbol object.  Intern caches the symbol objects for most ASCII names, so
+// that interning the same symbol again is cheap.  Therefore, don’t unintern
+// symbols that the module uses; Intern would keep returning the uninterned
+// symbol.  Use [Env.InternIn] to intern symbols in other obarrays.
 func (e Env) Intern(s Symbol) (Value, error) {
 	// See
 	// https://www.gnu.org/software/emacs/manual/html_node/elisp/Module-Misc.html#index-intern-1.
@@ -128,13 +131,53 @@ func (e Env) MaybeIntern(nameOrValue interface{}) (Value, error) {
 	}
 }
 
+// UninternedSymbol is an [In] that creates a new uninterned symbol with the
+// given name, like make-symbol.  Each conversion creates a different symbol,
+// even if the names are equal.  Therefore, if the same symbol needs to appear
+// more than once, for example in generated code, call [Env.MakeSymbol] or
+// [Env.Gensym] once and use the returned value.
+type UninternedSymbol string
+
+// Emacs implements [In.Emacs].  It returns a new uninterned symbol.
+func (s UninternedSymbol) Emacs(e Env) (Value, error) {
+	return e.MakeSymbol(string(s))
+}
+
+// MakeSymbol returns a new uninterned symbol with the given name, like
+// make-symbol.  The symbol isn’t eq to any other symbol, including interned
+// symbols with the same name.  This is useful when generating code, for
+// example to avoid capturing variables in macro expansions.
+func (e Env) MakeSymbol(name string) (Value, error) {
+	return e.Call("make-symbol", String(name))
+}
+
+// Gensym returns a new uninterned symbol whose name consists of prefix and
+// an increasing number, like gensym.  If prefix is empty, gensym uses its
+// default prefix “g”.  The numbers make the symbols distinguishable when
+// printing generated code.
+func (e Env) Gensym(prefix string) (Value, error) {
+	if prefix == "" {
+		return e.Call("gensym")
+	}
+	return e.Call("gensym", String(prefix))
+}
+
 // Nil returns the interned symbol nil.  It fails only if interning nil fails.
 func (e Env) Nil() (Value, error) {
 	return e.internASCII("nil")
 }
 
 // internASCII interns the ASCII symbol s in the default obarray.  s must not
-// contain non-ASCII characters or null bytes.
+// contain non-ASCII characters or null bytes.  internASCII uses the symbol
+// cache.
 func (e Env) internASCII(s Symbol) (Value, error) {
-	return e.checkValue(C.phst_emacs_intern(e.raw(), string(s)+"\x00"))
+	if v, ok := symbols.get(s); ok {
+		return v, nil
+	}
+	v, err := e.checkValue(C.phst_emacs_intern(e.raw(), string(s)+"\x00"))
+	if err != nil {
+		return Value{}, err
+	}
+	symbols.add(e, s, v)
+	return v, nil
 }
@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	ERTTest(symbolUninterned)
+}
+
+func symbolUninterned(e Env) error {
+	interned, err := e.Intern("go--uninterned")
+	if err != nil {
+		return err
+	}
+	a, err := e.MakeSymbol("go--uninterned")
+	if err != nil {
+		return err
+	}
+	b, err := UninternedSymbol("go--uninterned").Emacs(e)
+	if err != nil {
+		return err
+	}
+	if e.Eq(a, interned) || e.Eq(b, interned) || e.Eq(a, b) {
+		return errors.New("uninterned symbols aren’t distinct")
+	}
+	name, err := e.Symbol(a)
+	if err != nil {
+		return err
+	}
+	if name != "go--uninterned" {
+		return fmt.Errorf("MakeSymbol: got name %q, want %q", name, "go--uninterned")
+	}
+	soft, err := e.Call("intern-soft", a)
+	if err != nil {
+		return err
+	}
+	if e.IsNotNil(soft) {
+		return errors.New("intern-soft found uninterned symbol")
+	}
+	g1, err := e.Gensym("go--gensym-")
+	if err != nil {
+		return err
+	}
+	g2, err := e.Gensym("go--gensym-")
+	if err != nil {
+		return err
+	}
+	if e.Eq(g1, g2) {
+		return errors.New("Gensym returned the same symbol twice")
+	}
+	n1, err := e.Symbol(g1)
+	if err != nil {
+		return err
+	}
+	n2, err := e.Symbol(g2)
+	if err != nil {
+		return err
+	}
+	if n1 == n2 || !strings.HasPrefix(string(n1), "go--gensym-") {
+		return fmt.Errorf("Gensym: got names %q and %q", n1, n2)
+	}
+	return nil
+}
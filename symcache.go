@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "sync"
+
+// symbolCache caches interned symbols in global references.  Interning a
+// symbol requires a call into Emacs and an obarray lookup, and
+// [Env.Call] interns the function name on every call.  Global references are
+// valid in all environments, so the cache works across calls into the
+// module.  Interned symbols are never garbage-collected anyway, so keeping
+// references to them costs little.
+type symbolCache struct {
+	mu       sync.RWMutex
+	values   map[Symbol]Value
+	disabled bool
+}
+
+// maxCachedSymbols is the maximum number of cached symbols.  It protects
+// against modules that intern many generated symbol names.
+const maxCachedSymbols = 4096
+
+func (c *symbolCache) get(s Symbol) (Value, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.values[s]
+	return v, ok
+}
+
+// add caches the symbol object v for s if there’s still room.  Failing to
+// cache a symbol isn’t an error.
+func (c *symbolCache) add(e Env, s Symbol, v Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.disabled || len(c.values) >= maxCachedSymbols {
+		return
+	}
+	if _, ok := c.values[s]; ok {
+		return
+	}
+	ref, err := e.makeGlobalRef(v)
+	if err != nil {
+		return
+	}
+	if c.values == nil {
+		c.values = make(map[Symbol]Value)
+	}
+	c.values[s] = ref
+}
+
+// disable clears and disables the cache.  Environments created by package
+// emacstest don’t share objects, so the cache doesn’t work for them.
+func (c *symbolCache) disable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disabled = true
+	c.values = nil
+}
+
+// len returns the number of cached symbols.
+func (c *symbolCache) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.values)
+}
+
+var symbols symbolCache
@@ -0,0 +1,44 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import "errors"
+
+func init() {
+	ERTTest(symbolCacheIntern)
+}
+
+func symbolCacheIntern(e Env) error {
+	const name Symbol = "emacs-test--cached-symbol"
+	a, err := e.Intern(name)
+	if err != nil {
+		return err
+	}
+	b, err := e.Intern(name)
+	if err != nil {
+		return err
+	}
+	c, err := e.Call("intern", String(name))
+	if err != nil {
+		return err
+	}
+	if !e.Eq(a, b) || !e.Eq(a, c) {
+		return errors.New("interning the same symbol twice returned different objects")
+	}
+	if _, ok := symbols.get(name); !ok {
+		return errors.New("symbol not cached after interning")
+	}
+	return nil
+}
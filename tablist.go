@@ -0,0 +1,166 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+)
+
+// TabulatedList describes the contents of a buffer in tabulated-list-mode.
+// See [Tabulated List Mode].  Rows must be a slice of structs or of pointers
+// to structs.  Each exported field is a column; see [OrgTable] for how fields
+// map to columns and which field types are supported.  The columns are
+// sortable and wide enough to display the header and all cells.
+//
+// [Tabulated List Mode]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Tabulated-List-Mode.html
+type TabulatedList struct {
+	Rows interface{}
+
+	// ID returns the entry ID for a row, i.e., an element of the Rows
+	// slice.  If nil, the ID is the index of the row.
+	ID func(row interface{}) In
+
+	// If Refresh is non-nil, [Env.SetTabulatedList] arranges for Emacs to
+	// call it whenever it recomputes the entries, e.g., when the user
+	// reverts the buffer.  Refresh must return a new slice of the same type
+	// as Rows.
+	Refresh func(Env) (interface{}, error)
+}
+
+// Format returns a new vector suitable for tabulated-list-format.
+func (t TabulatedList) Format(e Env) (Value, error) {
+	cols, rows, err := t.table(t.Rows)
+	if err != nil {
+		return Value{}, err
+	}
+	r := make(Vector, len(cols))
+	for i, c := range cols {
+		width := utf8.RuneCountInString(c.name)
+		for _, row := range rows {
+			if n := utf8.RuneCountInString(row[i]); n > width {
+				width = n
+			}
+		}
+		r[i] = List{String(c.name), Int(width), T}
+	}
+	return r.Emacs(e)
+}
+
+// Entries returns a new list suitable for tabulated-list-entries.
+func (t TabulatedList) Entries(e Env) (Value, error) {
+	return t.entries(e, t.Rows)
+}
+
+func (t TabulatedList) entries(e Env, rows interface{}) (Value, error) {
+	_, cells, err := t.table(rows)
+	if err != nil {
+		return Value{}, err
+	}
+	s := reflect.ValueOf(rows)
+	r := make(List, len(cells))
+	for i, row := range cells {
+		var id In = Int(i)
+		if t.ID != nil {
+			id = t.ID(s.Index(i).Interface())
+		}
+		vec := make(Vector, len(row))
+		for j, c := range row {
+			vec[j] = String(c)
+		}
+		r[i] = List{id, vec}
+	}
+	return r.Emacs(e)
+}
+
+// table returns the columns and formatted cells for rows.
+func (t TabulatedList) table(rows interface{}) ([]structColumn, [][]string, error) {
+	s := reflect.ValueOf(rows)
+	if s.Kind() != reflect.Slice {
+		return nil, nil, WrongTypeArgument("go-slice-p", String(fmt.Sprintf("%T", rows)))
+	}
+	cols, err := structColumns(s.Type().Elem())
+	if err != nil {
+		return nil, nil, err
+	}
+	cells := make([][]string, s.Len())
+	for i := range cells {
+		row, err := formatRow(cols, s.Index(i))
+		if err != nil {
+			return nil, nil, err
+		}
+		cells[i] = row
+	}
+	return cols, cells, nil
+}
+
+// SetTabulatedList sets up the current buffer, which must be in
+// tabulated-list-mode or a mode derived from it, to display t.  It sets the
+// buffer-local values of tabulated-list-format and tabulated-list-entries,
+// initializes the header line, and prints the entries.
+//
+// If t.Refresh is non-nil, tabulated-list-entries is a Go function that calls
+// t.Refresh.  When you don’t need the buffer any more, call the returned
+// [DeleteFunc] to unregister the function; see [Env.LambdaFunc].  If
+// t.Refresh is nil, the returned DeleteFunc does nothing.
+func (e Env) SetTabulatedList(t TabulatedList) (DeleteFunc, error) {
+	format, err := t.Format(e)
+	if err != nil {
+		return nil, err
+	}
+	del := DeleteFunc(func() {})
+	var entries Value
+	if t.Refresh == nil {
+		entries, err = t.Entries(e)
+	} else {
+		entries, del, err = e.LambdaFunc(func(e Env, args []Value) (Value, error) {
+			rows, err := t.Refresh(e)
+			if err != nil {
+				return Value{}, err
+			}
+			return t.entries(e, rows)
+		}, Arity{0, 0}, "Return the tabulated list entries.")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range []struct {
+		name  Name
+		value Value
+	}{
+		{"tabulated-list-format", format},
+		{"tabulated-list-entries", entries},
+	} {
+		sym, err := e.Call("make-local-variable", b.name)
+		if err != nil {
+			del()
+			return nil, err
+		}
+		if _, err := e.Call("set", sym, b.value); err != nil {
+			del()
+			return nil, err
+		}
+	}
+	if _, err := e.Call("tabulated-list-init-header"); err != nil {
+		del()
+		return nil, err
+	}
+	if _, err := e.Call("tabulated-list-print"); err != nil {
+		del()
+		return nil, err
+	}
+	return del, nil
+}
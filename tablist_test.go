@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	ERTTest(tabulatedList)
+}
+
+func tabulatedList(e Env) error {
+	type process struct {
+		PID     int `emacs:"PID"`
+		Command string
+	}
+	generation := 0
+	list := TabulatedList{
+		Rows: []process{{1, "init"}, {42, "emacs"}},
+		ID:   func(row interface{}) In { return Int(row.(process).PID) },
+		Refresh: func(Env) (interface{}, error) {
+			generation++
+			return []process{{7, fmt.Sprintf("refreshed-%d", generation)}}, nil
+		},
+	}
+	buffer, err := e.Call("generate-new-buffer", String(" *temp*"))
+	if err != nil {
+		return err
+	}
+	defer e.Call("kill-buffer", buffer)
+	var del DeleteFunc
+	setup, deleteSetup, err := e.Lambda(func(e Env) error {
+		var err error
+		del, err = e.SetTabulatedList(list)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer deleteSetup()
+	if _, err := e.Eval(List{
+		Symbol("with-current-buffer"), buffer,
+		List{Symbol("tabulated-list-mode")},
+		List{Symbol("funcall"), setup},
+	}); err != nil {
+		return err
+	}
+	defer del()
+	contents := func() (string, error) {
+		var s String
+		err := e.CallOut("eval", &s, List{Symbol("with-current-buffer"), buffer, List{Symbol("buffer-string")}})
+		return string(s), err
+	}
+	s, err := contents()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(s, "refreshed-1") {
+		return fmt.Errorf("buffer contents: got %q, want refreshed entries", s)
+	}
+	if _, err := e.Eval(List{Symbol("with-current-buffer"), buffer, List{Symbol("revert-buffer")}}); err != nil {
+		return err
+	}
+	if s, err = contents(); err != nil {
+		return err
+	}
+	if !strings.Contains(s, "refreshed-2") {
+		return fmt.Errorf("buffer contents after revert: got %q, want refreshed entries", s)
+	}
+	format, err := list.Format(e)
+	if err != nil {
+		return err
+	}
+	col, err := e.VecGet(format, 1)
+	if err != nil {
+		return err
+	}
+	var width Int
+	if err := e.CallOut("nth", &width, Int(1), col); err != nil {
+		return err
+	}
+	if width != 7 {
+		return fmt.Errorf("width of column Command: got %d, want 7", width)
+	}
+	entries, err := list.Entries(e)
+	if err != nil {
+		return err
+	}
+	var id Int
+	if err := e.CallOut("caadr", &id, entries); err != nil {
+		return err
+	}
+	if id != 42 {
+		return fmt.Errorf("ID of second entry: got %d, want 42", id)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// numericKind returns whether k is an integral or floating-point kind.
+// Autoconversion doesn’t use the text representation of such types, so that
+// enumeration types with a MarshalText method still become Emacs integers.
+func numericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// textIn converts v to an Emacs string using its MarshalText method.
+func textIn(v reflect.Value) In { return textMarshaler{v} }
+
+type textMarshaler struct{ v reflect.Value }
+
+func (t textMarshaler) Emacs(e Env) (Value, error) {
+	if t.v.Kind() == reflect.Ptr && t.v.IsNil() {
+		return Value{}, WrongTypeArgument("go-not-nil-p", String(fmt.Sprintf("%#v", t.v)))
+	}
+	b, err := t.v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return Value{}, err
+	}
+	return String(b).Emacs(e)
+}
+
+// textOut sets the value that v points to from an Emacs string using its
+// UnmarshalText method.
+func textOut(v reflect.Value) Out { return textUnmarshaler{v} }
+
+type textUnmarshaler struct{ v reflect.Value }
+
+func (t textUnmarshaler) FromEmacs(e Env, v Value) error {
+	s, err := e.Str(v)
+	if err != nil {
+		return err
+	}
+	return t.v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+}
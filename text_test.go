@@ -0,0 +1,69 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	ERTTest(textConversion)
+}
+
+func textConversion(e Env) error {
+	addr := netip.MustParseAddr("192.0.2.1")
+	v, err := e.Emacs(addr)
+	if err != nil {
+		return err
+	}
+	var s String
+	if err := s.FromEmacs(e, v); err != nil {
+		return err
+	}
+	if s != "192.0.2.1" {
+		return fmt.Errorf("got %q, want %q", s, "192.0.2.1")
+	}
+	var got netip.Addr
+	if err := e.Go(v, &got); err != nil {
+		return err
+	}
+	if got != addr {
+		return fmt.Errorf("roundtrip: got %v, want %v", got, addr)
+	}
+	invalid, err := String("no address").Emacs(e)
+	if err != nil {
+		return err
+	}
+	if err := e.Go(invalid, &got); err == nil {
+		return fmt.Errorf("parsing invalid address succeeded: %v", got)
+	}
+	return nil
+}
+
+func TestTextConversionNumeric(t *testing.T) {
+	// slog.Level implements encoding.TextMarshaler, but it’s numeric, so
+	// it should still convert to an integer.
+	f, err := InFuncFor(reflect.TypeOf(slog.LevelWarn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := f(reflect.ValueOf(slog.LevelWarn)).(Int); !ok || got != Int(slog.LevelWarn) {
+		t.Errorf("got %#v, want Int(%d)", f(reflect.ValueOf(slog.LevelWarn)), slog.LevelWarn)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// This file contains wrappers for functions that access text properties and
+// overlays.  See
+// https://www.gnu.org/software/emacs/manual/html_node/elisp/Text-Properties.html
+// and https://www.gnu.org/software/emacs/manual/html_node/elisp/Overlays.html.
+// All positions are buffer positions in the current buffer, starting at 1.
+
+// TextProperty returns the value of the text property prop of the character
+// at pos in the current buffer, like get-text-property.  It returns nil if
+// the character doesn’t have the property.
+func (e Env) TextProperty(pos int, prop Symbol) (Value, error) {
+	return e.Call("get-text-property", Int(pos), prop)
+}
+
+// TextProperties returns the property list of the character at pos in the
+// current buffer, like text-properties-at.
+func (e Env) TextProperties(pos int) (Value, error) {
+	return e.Call("text-properties-at", Int(pos))
+}
+
+// CharProperty returns the value of the property prop of the character at
+// pos in the current buffer, like get-char-property.  Unlike
+// [Env.TextProperty], it also considers overlays, which take precedence over
+// text properties.
+func (e Env) CharProperty(pos int, prop Symbol) (Value, error) {
+	return e.Call("get-char-property", Int(pos), prop)
+}
+
+// PutTextProperty sets the text property prop to value for the characters
+// between start and end in the current buffer, like put-text-property.
+func (e Env) PutTextProperty(start, end int, prop Symbol, value In) error {
+	_, err := e.Call("put-text-property", Int(start), Int(end), prop, value)
+	return err
+}
+
+// AddTextProperties adds the text properties in the property list props to
+// the characters between start and end in the current buffer, like
+// add-text-properties.  It returns whether any property value changed.
+func (e Env) AddTextProperties(start, end int, props List) (bool, error) {
+	var r Bool
+	err := e.CallOut("add-text-properties", &r, Int(start), Int(end), props)
+	return bool(r), err
+}
+
+// SetTextProperties replaces all text properties of the characters between
+// start and end in the current buffer with the property list props, like
+// set-text-properties.  If props is empty, SetTextProperties removes all text
+// properties.
+func (e Env) SetTextProperties(start, end int, props List) error {
+	_, err := e.Call("set-text-properties", Int(start), Int(end), props)
+	return err
+}
+
+// RemoveTextProperties removes the given text properties from the characters
+// between start and end in the current buffer, like
+// remove-list-of-text-properties.  It returns whether any property was
+// removed.
+func (e Env) RemoveTextProperties(start, end int, props ...Symbol) (bool, error) {
+	list := make(List, len(props))
+	for i, p := range props {
+		list[i] = p
+	}
+	var r Bool
+	err := e.CallOut("remove-list-of-text-properties", &r, Int(start), Int(end), list)
+	return bool(r), err
+}
+
+// NextPropertyChange returns the position of the next change of any text
+// property after pos in the current buffer, like next-property-change.  It
+// returns false if the properties don’t change until the end of the buffer.
+func (e Env) NextPropertyChange(pos int) (int, bool, error) {
+	return e.optionalPosition(e.Call("next-property-change", Int(pos)))
+}
+
+// NextSinglePropertyChange returns the position of the next change of the
+// text property prop after pos in the current buffer, like
+// next-single-property-change.  It returns false if the property doesn’t
+// change until the end of the buffer.
+func (e Env) NextSinglePropertyChange(pos int, prop Symbol) (int, bool, error) {
+	return e.optionalPosition(e.Call("next-single-property-change", Int(pos), prop))
+}
+
+// PreviousSinglePropertyChange returns the position of the previous change
+// of the text property prop before pos in the current buffer, like
+// previous-single-property-change.  It returns false if the property doesn’t
+// change until the beginning of the buffer.
+func (e Env) PreviousSinglePropertyChange(pos int, prop Symbol) (int, bool, error) {
+	return e.optionalPosition(e.Call("previous-single-property-change", Int(pos), prop))
+}
+
+// NextSingleCharPropertyChange returns the position of the next change of
+// the property prop after pos in the current buffer, like
+// next-single-char-property-change.  Like [Env.CharProperty], it considers
+// both text properties and overlays.  It returns the end of the accessible
+// portion of the buffer if the property doesn’t change.
+func (e Env) NextSingleCharPropertyChange(pos int, prop Symbol) (int, error) {
+	var r Int
+	err := e.CallOut("next-single-char-property-change", &r, Int(pos), prop)
+	return int(r), err
+}
+
+// TextPropertyAny returns the first position between start and end in the
+// current buffer whose text property prop is non-nil, like
+// text-property-not-all with a nil value.  It returns false if there’s no
+// such position.
+func (e Env) TextPropertyAny(start, end int, prop Symbol) (int, bool, error) {
+	return e.optionalPosition(e.Call("text-property-not-all", Int(start), Int(end), prop, Nil))
+}
+
+// optionalPosition converts the result of a function that returns either a
+// position or nil.
+func (e Env) optionalPosition(v Value, err error) (int, bool, error) {
+	if err != nil || e.IsNil(v) {
+		return 0, false, err
+	}
+	var r Int
+	if err := r.FromEmacs(e, v); err != nil {
+		return 0, false, err
+	}
+	return int(r), true, nil
+}
+
+// Overlay is an Emacs overlay object.  Like a [Value], an Overlay is only
+// valid while the environment that created it is live.  Overlay implements
+// [In] and [Out], so exported functions can accept and return overlays.
+type Overlay struct{ v Value }
+
+// MakeOverlay creates a new overlay between start and end in the current
+// buffer, like make-overlay.
+func (e Env) MakeOverlay(start, end int) (Overlay, error) {
+	v, err := e.Call("make-overlay", Int(start), Int(end))
+	return Overlay{v}, err
+}
+
+// OverlaysIn returns the overlays that overlap the region between start and
+// end in the current buffer, like overlays-in.
+func (e Env) OverlaysIn(start, end int) ([]Overlay, error) {
+	list, err := e.Call("overlays-in", Int(start), Int(end))
+	if err != nil {
+		return nil, err
+	}
+	var r []Overlay
+	err = e.Dolist(list, func(v Value) error {
+		r = append(r, Overlay{v})
+		return nil
+	})
+	return r, err
+}
+
+// Value returns the Emacs overlay object.
+func (o Overlay) Value() Value {
+	return o.v
+}
+
+// Emacs implements [In.Emacs].  It returns the overlay object.
+func (o Overlay) Emacs(Env) (Value, error) {
+	return o.v, nil
+}
+
+// FromEmacs implements [Out.FromEmacs].  It sets *o to the overlay object v.
+// It returns an error if v isn’t an overlay.
+func (o *Overlay) FromEmacs(e Env, v Value) error {
+	var ok Bool
+	if err := e.CallOut("overlayp", &ok, v); err != nil {
+		return err
+	}
+	if !ok {
+		return WrongTypeArgument("overlayp", v)
+	}
+	*o = Overlay{v}
+	return nil
+}
+
+// Property returns the value of the overlay property prop, like
+// overlay-get.
+func (o Overlay) Property(e Env, prop Symbol) (Value, error) {
+	return e.Call("overlay-get", o.v, prop)
+}
+
+// Put sets the overlay property prop to value, like overlay-put.
+func (o Overlay) Put(e Env, prop Symbol, value In) error {
+	_, err := e.Call("overlay-put", o.v, prop, value)
+	return err
+}
+
+// Start returns the start position of the overlay.  It returns false if the
+// overlay has been deleted.
+func (o Overlay) Start(e Env) (int, bool, error) {
+	return e.optionalPosition(e.Call("overlay-start", o.v))
+}
+
+// End returns the end position of the overlay.  It returns false if the
+// overlay has been deleted.
+func (o Overlay) End(e Env) (int, bool, error) {
+	return e.optionalPosition(e.Call("overlay-end", o.v))
+}
+
+// Move moves the overlay to the region between start and end in the current
+// buffer, like move-overlay.
+func (o Overlay) Move(e Env, start, end int) error {
+	_, err := e.Call("move-overlay", o.v, Int(start), Int(end))
+	return err
+}
+
+// Delete deletes the overlay from its buffer, like delete-overlay.  The
+// overlay keeps its properties and can be moved back into a buffer using
+// [Overlay.Move].
+func (o Overlay) Delete(e Env) error {
+	_, err := e.Call("delete-overlay", o.v)
+	return err
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+)
+
+func init() {
+	ERTTest(textPropertyAccessors)
+	ERTTest(textPropertyOverlays)
+}
+
+// withTestBuffer calls f with a temporary buffer containing text as the
+// current buffer.
+func withTestBuffer(e Env, text string, f func() error) error {
+	return e.WithTempBuffer(func(Buffer) error {
+		if _, err := e.Call("insert", String(text)); err != nil {
+			return err
+		}
+		return f()
+	})
+}
+
+func textPropertyAccessors(e Env) error {
+	return withTestBuffer(e, "hello world", func() error {
+		if err := e.PutTextProperty(1, 6, "face", Symbol("bold")); err != nil {
+			return err
+		}
+		v, err := e.TextProperty(3, "face")
+		if err != nil {
+			return err
+		}
+		var face Symbol
+		if err := face.FromEmacs(e, v); err != nil {
+			return err
+		}
+		if face != "bold" {
+			return fmt.Errorf("TextProperty: got %q, want bold", face)
+		}
+		next, ok, err := e.NextSinglePropertyChange(1, "face")
+		if err != nil {
+			return err
+		}
+		if !ok || next != 6 {
+			return fmt.Errorf("NextSinglePropertyChange: got %d, %v, want 6, true", next, ok)
+		}
+		if _, ok, err := e.NextSinglePropertyChange(6, "face"); err != nil || ok {
+			return fmt.Errorf("NextSinglePropertyChange at end: got %v, %v, want false, nil", ok, err)
+		}
+		prev, ok, err := e.PreviousSinglePropertyChange(9, "face")
+		if err != nil {
+			return err
+		}
+		if !ok || prev != 6 {
+			return fmt.Errorf("PreviousSinglePropertyChange: got %d, %v, want 6, true", prev, ok)
+		}
+		changed, err := e.AddTextProperties(7, 12, List{Symbol("go-test"), T})
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return errors.New("AddTextProperties didn’t change anything")
+		}
+		pos, ok, err := e.TextPropertyAny(1, 12, "go-test")
+		if err != nil {
+			return err
+		}
+		if !ok || pos != 7 {
+			return fmt.Errorf("TextPropertyAny: got %d, %v, want 7, true", pos, ok)
+		}
+		removed, err := e.RemoveTextProperties(1, 12, "face", "go-test")
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return errors.New("RemoveTextProperties didn’t remove anything")
+		}
+		props, err := e.TextProperties(3)
+		if err != nil {
+			return err
+		}
+		if e.IsNotNil(props) {
+			return errors.New("text properties remain after RemoveTextProperties")
+		}
+		return nil
+	})
+}
+
+func textPropertyOverlays(e Env) error {
+	return withTestBuffer(e, "hello world", func() error {
+		if err := e.PutTextProperty(1, 12, "go-test", Symbol("text")); err != nil {
+			return err
+		}
+		o, err := e.MakeOverlay(3, 5)
+		if err != nil {
+			return err
+		}
+		if err := o.Put(e, "go-test", Symbol("overlay")); err != nil {
+			return err
+		}
+		v, err := e.CharProperty(4, "go-test")
+		if err != nil {
+			return err
+		}
+		var got Symbol
+		if err := got.FromEmacs(e, v); err != nil {
+			return err
+		}
+		if got != "overlay" {
+			return fmt.Errorf("CharProperty: got %q, want overlay", got)
+		}
+		if v, err = o.Property(e, "go-test"); err != nil {
+			return err
+		}
+		if err := got.FromEmacs(e, v); err != nil {
+			return err
+		}
+		if got != "overlay" {
+			return fmt.Errorf("Overlay.Property: got %q, want overlay", got)
+		}
+		next, err := e.NextSingleCharPropertyChange(1, "go-test")
+		if err != nil {
+			return err
+		}
+		if next != 3 {
+			return fmt.Errorf("NextSingleCharPropertyChange: got %d, want 3", next)
+		}
+		overlays, err := e.OverlaysIn(1, 12)
+		if err != nil {
+			return err
+		}
+		if len(overlays) != 1 || !e.Eq(overlays[0].Value(), o.Value()) {
+			return fmt.Errorf("OverlaysIn: got %v, want one overlay", overlays)
+		}
+		if err := o.Move(e, 6, 8); err != nil {
+			return err
+		}
+		if start, ok, err := o.Start(e); err != nil || !ok || start != 6 {
+			return fmt.Errorf("Overlay.Start after Move: got %d, %v, %v, want 6, true, nil", start, ok, err)
+		}
+		if err := o.Delete(e); err != nil {
+			return err
+		}
+		if _, ok, err := o.End(e); err != nil || ok {
+			return fmt.Errorf("Overlay.End after Delete: got %v, %v, want false, nil", ok, err)
+		}
+		return nil
+	})
+}
@@ -119,6 +119,6 @@ func (e Env) makeTime(s int64, ns int) (Value, error) {
 }
 
 func (e Env) extractTime(v Value) (s int64, ns int, err error) {
-	r := C.phst_emacs_extract_time(e.raw(), v.r)
+	r := C.phst_emacs_extract_time(e.raw(), v.raw())
 	return int64(r.value.tv_sec), int(r.value.tv_nsec), e.check(r.base)
 }
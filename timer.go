@@ -0,0 +1,147 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Timer is an Emacs timer that calls a Go function.  See [Timers].  Create
+// timers using [Env.RunAtTime] or [Env.RunWithIdleTimer].  Unlike a
+// [Value], a Timer stays valid across calls, so you can store it in a
+// variable and cancel it later.  All methods must be called on the Emacs
+// thread.
+//
+// [Timers]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Timers.html
+type Timer struct {
+	ref Value // global reference to the timer object, zero once released
+	fun numberedFunc
+}
+
+// RunAtTime starts a timer that calls fun after delay, like run-at-time.  If
+// repeat is positive, the timer then calls fun every repeat interval until
+// it’s canceled; otherwise, it calls fun only once.  fun is converted as
+// described in [AutoLambda] and must accept zero arguments; RunAtTime panics
+// otherwise.  RunAtTime exports fun as a function symbol whose name starts
+// with “go--timer-” followed by a number.  It releases the function once a
+// non-repeating timer has run or the timer has been canceled.  If fun returns
+// an error, Emacs reports it like any other error in a timer function.
+func (e Env) RunAtTime(delay, repeat time.Duration, fun interface{}) (*Timer, error) {
+	var rep In = Nil
+	if repeat > 0 {
+		rep = Float(repeat.Seconds())
+	}
+	return e.startTimer("run-at-time", delay, rep, repeat > 0, fun)
+}
+
+// RunWithIdleTimer starts a timer that calls fun once Emacs has been idle
+// for idle, like run-with-idle-timer.  If repeat is true, the timer calls
+// fun each time Emacs becomes idle for that long, until it’s canceled;
+// otherwise, it calls fun only once.  Use repeating idle timers for
+// background maintenance that shouldn’t interfere with the user.  The
+// requirements for fun and the release rules are the same as for
+// [Env.RunAtTime].
+func (e Env) RunWithIdleTimer(idle time.Duration, repeat bool, fun interface{}) (*Timer, error) {
+	return e.startTimer("run-with-idle-timer", idle, Bool(repeat), repeat, fun)
+}
+
+func (e Env) startTimer(run Name, delay time.Duration, rep In, repeat bool, fun interface{}) (*Timer, error) {
+	l := timerLambda(fun)
+	t := new(Timer)
+	wrapper := func(e Env, args []Value) (Value, error) {
+		r, err := l.Fun(e, args)
+		if !repeat {
+			if relErr := t.release(e); err == nil {
+				err = relErr
+			}
+		}
+		return r, err
+	}
+	name, index, err := e.exportNumbered("go--timer-", Lambda{wrapper, Arity{0, 0}, "Timer function defined in Go."})
+	if err != nil {
+		return nil, err
+	}
+	t.fun = numberedFunc{name, index}
+	v, err := e.Call(run, Float(delay.Seconds()), rep, name)
+	if err == nil {
+		t.ref, err = e.makeGlobalRef(v)
+	}
+	if err != nil {
+		if v.r != nil {
+			e.Call("cancel-timer", v)
+		}
+		e.releaseNumbered(name, index)
+		return nil, err
+	}
+	return t, nil
+}
+
+// timerLambda converts a timer function to a [Lambda] and checks that it
+// accepts zero arguments.
+func timerLambda(fun interface{}) Lambda {
+	if fun == nil {
+		panic("nil timer function")
+	}
+	l := AutoLambda(fun, Anonymous{})
+	if l.Arity.Min > 0 {
+		panic(fmt.Errorf("timer function must accept zero arguments, but its arity is %v", l.Arity))
+	}
+	return l
+}
+
+// Cancel cancels the timer, like cancel-timer, and releases its function.
+// Canceling a timer that has already run or been canceled does nothing.
+func (t *Timer) Cancel(e Env) error {
+	if t.ref.r == nil {
+		return nil
+	}
+	_, err := e.Call("cancel-timer", t.ref)
+	if relErr := t.release(e); err == nil {
+		err = relErr
+	}
+	return err
+}
+
+// Active returns whether the timer is still scheduled, i.e., it hasn’t been
+// canceled and, if it doesn’t repeat, hasn’t run yet.
+func (t *Timer) Active() bool {
+	return t.ref.r != nil
+}
+
+// Emacs implements [In.Emacs].  It returns the Emacs timer object.  It
+// returns an error if the timer is no longer active.
+func (t *Timer) Emacs(Env) (Value, error) {
+	if t.ref.r == nil {
+		return Value{}, errors.New("timer no longer active")
+	}
+	return t.ref, nil
+}
+
+// release releases the timer function and the global reference to the timer
+// object.  Releasing more than once does nothing.
+func (t *Timer) release(e Env) error {
+	if t.ref.r == nil {
+		return nil
+	}
+	ref := t.ref
+	t.ref = Value{}
+	err := e.releaseNumbered(t.fun.name, t.fun.index)
+	if refErr := e.freeGlobalRef(ref); err == nil {
+		err = refErr
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func init() {
+	ERTTest(timerRunAtTime)
+	ERTTest(timerRepeat)
+	ERTTest(timerIdle)
+}
+
+func timerRunAtTime(e Env) error {
+	called := 0
+	t, err := e.RunAtTime(0, 0, func() { called++ })
+	if err != nil {
+		return err
+	}
+	if err := waitForTimer(e, func() bool { return called > 0 }); err != nil {
+		return err
+	}
+	if t.Active() {
+		return errors.New("timer still active after running")
+	}
+	if err := t.Cancel(e); err != nil {
+		return err
+	}
+	if called != 1 {
+		return fmt.Errorf("timer function called %d times, want 1", called)
+	}
+	return nil
+}
+
+func timerRepeat(e Env) error {
+	called := 0
+	t, err := e.RunAtTime(0, 10*time.Millisecond, func() { called++ })
+	if err != nil {
+		return err
+	}
+	if err := waitForTimer(e, func() bool { return called >= 2 }); err != nil {
+		t.Cancel(e)
+		return err
+	}
+	if !t.Active() {
+		return errors.New("repeating timer inactive after running")
+	}
+	if err := t.Cancel(e); err != nil {
+		return err
+	}
+	if t.Active() {
+		return errors.New("timer still active after Cancel")
+	}
+	n := called
+	if _, err := e.Call("accept-process-output", Nil, Float(0.05)); err != nil {
+		return err
+	}
+	if called != n {
+		return errors.New("timer function called after Cancel")
+	}
+	return nil
+}
+
+func timerIdle(e Env) error {
+	t, err := e.RunWithIdleTimer(time.Hour, true, func() error { return errors.New("unexpected call") })
+	if err != nil {
+		return err
+	}
+	var ok Bool
+	if err := e.CallOut("timerp", &ok, t); err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("idle timer isn’t a timer object")
+	}
+	if err := t.Cancel(e); err != nil {
+		return err
+	}
+	if _, err := t.Emacs(e); err == nil {
+		return errors.New("converting canceled timer succeeded")
+	}
+	return nil
+}
+
+// waitForTimer processes timers until cond returns true.
+func waitForTimer(e Env, cond func() bool) error {
+	for i := 0; i < 100 && !cond(); i++ {
+		if _, err := e.Call("accept-process-output", Nil, Float(0.01)); err != nil {
+			return err
+		}
+	}
+	if !cond() {
+		return errors.New("timer didn’t run")
+	}
+	return nil
+}
+
+func TestTimerLambda(t *testing.T) {
+	for _, fun := range []interface{}{
+		func() {},
+		func() error { return nil },
+		func(Env) {},
+		func(...Value) {},
+	} {
+		timerLambda(fun)
+	}
+	for _, fun := range []interface{}{
+		func(Value) {},
+		func(int, string) {},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("timerLambda(%T) didn’t panic", fun)
+				}
+			}()
+			timerLambda(fun)
+		}()
+	}
+}
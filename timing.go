@@ -0,0 +1,122 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefinitionTiming describes how long it took to define a single item queued
+// in a [Manager].  [ManagerOf.DefineQueued] records one DefinitionTiming for
+// each item it defines.
+type DefinitionTiming struct {
+	// The name of the item.  Empty for unnamed items such as
+	// initialization functions registered with [OnInit].
+	Name Name
+
+	// The time it took to define the item.  For items that themselves
+	// define the items of another [Manager] (for example, managers created
+	// with [DefineOnInit]), this includes the time for all nested
+	// definitions.
+	Duration time.Duration
+
+	// The error returned by [QueuedItem.Define], or nil if the definition
+	// succeeded.
+	Err error
+}
+
+// Emacs returns a list (NAME SECONDS ERROR).  NAME is the item name as a
+// symbol, or nil if the item is unnamed.  SECONDS is the duration as a
+// floating-point number.  ERROR is the error message as a string, or nil if
+// the definition succeeded.
+func (t DefinitionTiming) Emacs(e Env) (Value, error) {
+	var name In = Nil
+	if t.Name != "" {
+		name = t.Name
+	}
+	var err In = Nil
+	if t.Err != nil {
+		err = String(e.Message(t.Err))
+	}
+	return e.List(name, Float(t.Duration.Seconds()), err)
+}
+
+// DefinitionTimings returns the timings of the definitions performed by
+// [ManagerOf.DefineQueued] so far, in the order in which the definitions
+// finished.  It keeps only the 10,000 most recent timings and discards older
+// ones, so that modules that redefine their items repeatedly don’t
+// accumulate timings without bound.  Use this to find out why loading a
+// module takes a long time.  Typically you’d make this information available
+// to Emacs by exporting DefinitionTimings itself:
+//
+//	emacs.Export(emacs.DefinitionTimings, emacs.Name("my-module-definition-timings"))
+//
+// You can call DefinitionTimings safely from multiple goroutines.
+func DefinitionTimings() []DefinitionTiming {
+	return definitionTimes.get()
+}
+
+// LogSlowDefinitions arranges for [ManagerOf.DefineQueued] to log each
+// definition that takes longer than threshold using the standard [log]
+// package.  A nonpositive threshold disables logging, which is the default.
+// Call LogSlowDefinitions before Emacs loads the module, typically in an init
+// function.  You can call LogSlowDefinitions safely from multiple goroutines.
+func LogSlowDefinitions(threshold time.Duration) {
+	atomic.StoreInt64(&definitionTimes.slow, int64(threshold))
+}
+
+// maxDefinitionTimings is the maximum number of timings that
+// [DefinitionTimings] returns.
+const maxDefinitionTimings = 10000
+
+type definitionRecorder struct {
+	mu      sync.Mutex
+	size    int                // maximum number of timings to keep
+	timings []DefinitionTiming // ring buffer
+	next    int                // index of the oldest timing once the ring is full
+	slow    int64              // time.Duration, accessed atomically
+}
+
+var definitionTimes = definitionRecorder{size: maxDefinitionTimings}
+
+func (r *definitionRecorder) record(name Name, d time.Duration, err error) {
+	if slow := time.Duration(atomic.LoadInt64(&r.slow)); slow > 0 && d > slow {
+		if name == "" {
+			log.Printf("slow definition of unnamed item: %s", d)
+		} else {
+			log.Printf("slow definition of %s: %s", name, d)
+		}
+	}
+	t := DefinitionTiming{name, d, err}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.timings) < r.size {
+		r.timings = append(r.timings, t)
+	} else {
+		r.timings[r.next] = t
+		r.next = (r.next + 1) % r.size
+	}
+}
+
+func (r *definitionRecorder) get() []DefinitionTiming {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := make([]DefinitionTiming, 0, len(r.timings))
+	s = append(s, r.timings[r.next:]...)
+	return append(s, r.timings[:r.next]...)
+}
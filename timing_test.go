@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	ERTTest(definitionTimings)
+}
+
+func TestDefinitionRecorderRing(t *testing.T) {
+	r := definitionRecorder{size: 2}
+	for _, name := range []Name{"a", "b", "c"} {
+		r.record(name, 0, nil)
+	}
+	got := r.get()
+	want := []DefinitionTiming{{Name: "b"}, {Name: "c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("timings: got %v, want %v", got, want)
+	}
+}
+
+func definitionTimings(e Env) error {
+	for _, t := range DefinitionTimings() {
+		if t.Name != baseError.name {
+			continue
+		}
+		if t.Err != nil {
+			return fmt.Errorf("definition of %s failed: %s", t.Name, e.Message(t.Err))
+		}
+		if t.Duration < 0 {
+			return fmt.Errorf("negative duration %s for %s", t.Duration, t.Name)
+		}
+		if _, err := t.Emacs(e); err != nil {
+			return fmt.Errorf("couldn’t convert timing %#v to Emacs: %s", t, e.Message(err))
+		}
+		return nil
+	}
+	return fmt.Errorf("no timing recorded for %s", baseError.name)
+}
@@ -0,0 +1,331 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// TraceKind is the kind of a [TraceEvent].
+type TraceKind int
+
+const (
+	// TraceExported is a call from Emacs to a Go function exported to
+	// Emacs, for example using [Export] or [Env.Lambda].
+	TraceExported TraceKind = iota
+
+	// TraceFuncall is a call from Go to an Emacs function using
+	// [Env.Funcall] or one of the functions built on top of it, such as
+	// [Env.Call].
+	TraceFuncall
+)
+
+// String returns “exported” or “funcall”.
+func (k TraceKind) String() string {
+	switch k {
+	case TraceExported:
+		return "exported"
+	case TraceFuncall:
+		return "funcall"
+	default:
+		return fmt.Sprintf("TraceKind(%d)", int(k))
+	}
+}
+
+// TraceEvent describes a single call across the boundary between Go and
+// Emacs, recorded while tracing is enabled.  See [StartTracing].
+type TraceEvent struct {
+	Kind TraceKind
+
+	// Function is the name of the called function.  For anonymous
+	// exported functions, it’s “lambda”.  For calls to Emacs functions,
+	// it’s the printed representation of the function, truncated if it’s
+	// long.
+	Function string
+
+	// Args contains the printed representations of the arguments,
+	// truncated if they are long.
+	Args []string
+
+	// Start is the time when the call started.
+	Start time.Time
+
+	// Duration is the duration of the call.  It’s zero if the call hasn’t
+	// returned yet.
+	Duration time.Duration
+
+	// Depth is the nesting depth of the call.  Calls made while no other
+	// traced call is active have depth zero.
+	Depth int
+
+	// Done specifies whether the call has returned.
+	Done bool
+
+	// Err is the error returned by the call, if any.
+	Err error
+}
+
+// Emacs returns a property list with the keys :kind, :function, :args,
+// :start, :duration, :depth, :done, and :error.  The kind is a symbol, the
+// start time is a Lisp timestamp, the duration is a floating-point number of
+// seconds, and the error is the error message or nil.
+func (ev TraceEvent) Emacs(e Env) (Value, error) {
+	args := make(List, len(ev.Args))
+	for i, a := range ev.Args {
+		args[i] = String(a)
+	}
+	var err In = Nil
+	if ev.Err != nil {
+		err = String(e.Message(ev.Err))
+	}
+	return List{
+		Symbol(":kind"), Symbol(ev.Kind.String()),
+		Symbol(":function"), String(ev.Function),
+		Symbol(":args"), args,
+		Symbol(":start"), Time(ev.Start),
+		Symbol(":duration"), Float(ev.Duration.Seconds()),
+		Symbol(":depth"), Int(ev.Depth),
+		Symbol(":done"), Bool(ev.Done),
+		Symbol(":error"), err,
+	}.Emacs(e)
+}
+
+// String formats the event as a single line, indented according to its
+// depth.
+func (ev TraceEvent) String() string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("  ", ev.Depth))
+	arrow := "→"
+	if ev.Kind == TraceFuncall {
+		arrow = "←"
+	}
+	fmt.Fprintf(&b, "%s %s(%s)", arrow, ev.Function, strings.Join(ev.Args, " "))
+	switch {
+	case !ev.Done:
+		b.WriteString(" …")
+	case ev.Err != nil:
+		fmt.Fprintf(&b, " %s error: %s", ev.Duration, ev.Err)
+	default:
+		fmt.Fprintf(&b, " %s", ev.Duration)
+	}
+	return b.String()
+}
+
+// StartTracing enables tracing of calls across the Go/Emacs boundary.  While
+// tracing is enabled, each call of an exported Go function and each call of
+// an Emacs function from Go records a [TraceEvent].  Tracing slows down all
+// calls considerably, since it needs to print all arguments.  StartTracing
+// discards previously recorded events.  It records at most limit events;
+// further events are dropped.  A nonpositive limit means no limit.  You can
+// call StartTracing safely from multiple goroutines.
+func StartTracing(limit int) {
+	tracer.reset(limit)
+	atomic.StoreInt32(&tracer.enabled, 1)
+}
+
+// StopTracing disables tracing.  The recorded events remain available via
+// [TraceEvents].  You can call StopTracing safely from multiple goroutines.
+func StopTracing() {
+	atomic.StoreInt32(&tracer.enabled, 0)
+}
+
+// TraceEvents returns the events recorded since the last call to
+// [StartTracing], in the order in which the calls started, and the number of
+// events that were dropped because of the limit.  You can call TraceEvents
+// safely from multiple goroutines.
+func TraceEvents() (events []TraceEvent, dropped int) {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	r := make([]TraceEvent, len(tracer.events))
+	copy(r, tracer.events)
+	return r, tracer.dropped
+}
+
+// ExportTraceCommands arranges for three interactive commands to be defined
+// once the module is loaded: prefix-start-tracing, prefix-stop-tracing, and
+// prefix-dump-trace, where prefix is the given prefix.  The first two call
+// [StartTracing] without limit and [StopTracing], respectively.  The third one
+// displays the recorded events in a buffer.  Call ExportTraceCommands in an
+// init function.
+func ExportTraceCommands(prefix Name) {
+	OnInit(func(e Env) error {
+		return e.exportCommands([]command{
+			{prefix + "-start-tracing", Arity{0, 0}, func(e Env, _ []Value) (Value, error) {
+				StartTracing(0)
+				return e.Nil()
+			}, "Start tracing calls between Go and Emacs.", Nil},
+			{prefix + "-stop-tracing", Arity{0, 0}, func(e Env, _ []Value) (Value, error) {
+				StopTracing()
+				return e.Nil()
+			}, "Stop tracing calls between Go and Emacs.", Nil},
+			{prefix + "-dump-trace", Arity{0, 0}, func(e Env, _ []Value) (Value, error) {
+				return e.dumpTrace("*" + string(prefix) + " trace*")
+			}, "Display the calls between Go and Emacs recorded so far.", Nil},
+		})
+	})
+}
+
+func (e Env) dumpTrace(buffer string) (Value, error) {
+	// Don’t trace the dump itself.
+	enabled := tracer.active()
+	StopTracing()
+	if enabled {
+		defer atomic.StoreInt32(&tracer.enabled, 1)
+	}
+	events, dropped := TraceEvents()
+	var b strings.Builder
+	for _, ev := range events {
+		fmt.Fprintf(&b, "%s %s\n", ev.Start.Format("15:04:05.000000"), ev)
+	}
+	if dropped > 0 {
+		fmt.Fprintf(&b, "%d events dropped\n", dropped)
+	}
+	return e.showReport(buffer, b.String())
+}
+
+type traceRecorder struct {
+	enabled int32 // accessed atomically
+
+	mu      sync.Mutex
+	events  []TraceEvent
+	gen     uint64 // incremented by StartTracing
+	limit   int
+	dropped int
+	depth   int
+}
+
+// traceSlot identifies an event recorded by [traceRecorder.begin].  gen is
+// the tracing generation at the time of the call, so that calls that are
+// still in flight when StartTracing discards the events don’t overwrite new
+// events with the same index.
+type traceSlot struct {
+	gen   uint64
+	index int // -1 if the event was dropped
+}
+
+var tracer traceRecorder
+
+func (r *traceRecorder) active() bool {
+	return atomic.LoadInt32(&r.enabled) != 0
+}
+
+// reset discards all events and starts a new tracing generation.
+func (r *traceRecorder) reset(limit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+	r.gen++
+	r.limit = limit
+	r.dropped = 0
+}
+
+// begin records the start of a call and returns the slot of the new event.
+func (r *traceRecorder) begin(kind TraceKind, fun string, args []string) traceSlot {
+	start := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	depth := r.depth
+	r.depth++
+	if r.limit > 0 && len(r.events) >= r.limit {
+		r.dropped++
+		return traceSlot{r.gen, -1}
+	}
+	r.events = append(r.events, TraceEvent{Kind: kind, Function: fun, Args: args, Start: start, Depth: depth})
+	return traceSlot{r.gen, len(r.events) - 1}
+}
+
+// end records the end of the call with the given event slot.
+func (r *traceRecorder) end(s traceSlot, err error) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.depth > 0 {
+		r.depth--
+	}
+	// StartTracing might have discarded the event in the meantime.
+	if s.gen != r.gen || s.index < 0 || s.index >= len(r.events) {
+		return
+	}
+	ev := &r.events[s.index]
+	ev.Duration = now.Sub(ev.Start)
+	ev.Done = true
+	ev.Err = err
+}
+
+// call calls the exported function, tracing the call if tracing is enabled
+// and recording it if recording is enabled.
+func (f *function) call(e Env, args []Value) (v Value, err error) {
+	depth := atomic.AddInt32(&callDepth, 1) - 1
+	defer atomic.AddInt32(&callDepth, -1)
+	if recorder.active() {
+		recorder.record(e, f.name, args, depth)
+	}
+	if span := startSpan(TraceExported, f.spanName); span != nil {
+		defer func() { endSpan(span, err) }()
+	}
+	if !tracer.active() {
+		return f.Fun(e, args)
+	}
+	s := tracer.begin(TraceExported, f.spanName(), e.traceSummaries(args))
+	defer func() { tracer.end(s, err) }()
+	return f.Fun(e, args)
+}
+
+// spanName returns the name of f for [TraceEvent] and [SpanTracer.StartSpan].
+func (f *function) spanName() string {
+	if f.name == "" {
+		return "lambda"
+	}
+	return string(f.name)
+}
+
+// traceSummaries returns short printed representations of args.
+func (e Env) traceSummaries(args []Value) []string {
+	r := make([]string, len(args))
+	for i, a := range args {
+		r[i] = e.traceSummary(a)
+	}
+	return r
+}
+
+// maxTraceSummary is the maximum number of characters in a printed
+// representation of a traced value.
+const maxTraceSummary = 60
+
+// traceSummary returns a short printed representation of v.  It uses
+// [Env.funcall] so that it doesn’t trace itself.
+func (e Env) traceSummary(v Value) string {
+	prin1, err := e.Intern("prin1-to-string")
+	if err != nil {
+		return "?"
+	}
+	r, err := e.funcall(prin1, []Value{v})
+	if err != nil {
+		return "?"
+	}
+	s, err := e.Str(r)
+	if err != nil {
+		return "?"
+	}
+	if utf8.RuneCountInString(s) > maxTraceSummary {
+		s = string([]rune(s)[:maxTraceSummary-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	ERTTest(traceFuncall)
+}
+
+func TestTraceRecorder(t *testing.T) {
+	var r traceRecorder
+	r.limit = 2
+	outer := r.begin(TraceExported, "outer", nil)
+	inner := r.begin(TraceFuncall, "inner", []string{"1"})
+	dropped := r.begin(TraceFuncall, "dropped", nil)
+	r.end(dropped, nil)
+	r.end(inner, errors.New("boom"))
+	if outer.index != 0 || inner.index != 1 || dropped.index != -1 {
+		t.Errorf("indices: got %d, %d, %d; want 0, 1, -1", outer.index, inner.index, dropped.index)
+	}
+	if r.dropped != 1 {
+		t.Errorf("dropped: got %d, want 1", r.dropped)
+	}
+	if ev := r.events[0]; ev.Done || ev.Depth != 0 {
+		t.Errorf("outer event: got %+v, want unfinished event at depth 0", ev)
+	}
+	if ev := r.events[1]; !ev.Done || ev.Depth != 1 || ev.Err == nil {
+		t.Errorf("inner event: got %+v, want finished event at depth 1 with error", ev)
+	}
+	r.end(outer, nil)
+	if r.depth != 0 {
+		t.Errorf("depth: got %d, want 0", r.depth)
+	}
+}
+
+func TestTraceRecorderReset(t *testing.T) {
+	var r traceRecorder
+	stale := r.begin(TraceExported, "stale", nil)
+	r.reset(0)
+	fresh := r.begin(TraceExported, "fresh", nil)
+	if stale.index != fresh.index {
+		t.Fatalf("indices: got %d and %d, want equal indices", stale.index, fresh.index)
+	}
+	r.end(stale, errors.New("boom"))
+	if ev := r.events[0]; ev.Done || ev.Err != nil {
+		t.Errorf("call from before reset finished new event %+v", ev)
+	}
+	r.end(fresh, nil)
+	if ev := r.events[0]; !ev.Done {
+		t.Errorf("event %+v not done", ev)
+	}
+}
+
+func traceFuncall(e Env) error {
+	StartTracing(0)
+	_, err := e.Call("+", Int(1), Int(2))
+	StopTracing()
+	if err != nil {
+		return err
+	}
+	events, _ := TraceEvents()
+	for _, ev := range events {
+		if ev.Kind == TraceFuncall && ev.Function == "+" {
+			if want := []string{"1", "2"}; !reflect.DeepEqual(ev.Args, want) {
+				return fmt.Errorf("arguments: got %q, want %q", ev.Args, want)
+			}
+			if !ev.Done {
+				return fmt.Errorf("event %s not done", ev)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no trace event for + in %v", events)
+}
@@ -20,6 +20,7 @@ import "C"
 
 import (
 	"fmt"
+	"os"
 	"runtime"
 	"runtime/debug"
 	"unsafe"
@@ -35,10 +36,15 @@ func phst_emacs_trampoline(env *C.emacs_env, nargs C.int64_t, args *C.emacs_valu
 	// https://www.gnu.org/software/emacs/manual/html_node/elisp/Module-Functions.html.
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
-	e := Env{env}
-	// Don’t allow Go panics to crash Emacs.
-	defer protect(e, &r.base)
-	fun := funcs.get(funcIndex(data))
+	e, tracked := enter(env)
+	defer e.exit(tracked)
+	// Don’t allow Go panics to crash Emacs.  protect reads name only
+	// after a panic, so we can set it below.
+	var name Name
+	defer protect(e, &name, &r.base)
+	e.freePendingRefs()
+	f := funcs.get(funcIndex(data))
+	name = f.name
 	var in []Value
 	if nargs > 0 {
 		// See
@@ -46,19 +52,34 @@ func phst_emacs_trampoline(env *C.emacs_env, nargs C.int64_t, args *C.emacs_valu
 		argSlice := (*[1 << 40]C.emacs_value)(unsafe.Pointer(args))[:nargs:nargs]
 		in = make([]Value, nargs)
 		for i, a := range argSlice {
-			in[i] = Value{a}
+			in[i] = e.value(a)
 		}
 	}
-	v, err := fun(e, in)
-	return C.struct_phst_emacs_trampoline_result{e.signal(err), v.r}
+	var v Value
+	var err error
+	if DebugMode() {
+		err = f.Arity.check(len(in))
+	}
+	if err == nil {
+		v, err = f.call(e, in)
+	}
+	if _, throw := err.(Throw); err != nil && !throw && DebugMode() {
+		// f has already returned, so this stack trace only shows how
+		// Emacs called f, not where the error originated.
+		e.reportFailure(f.name, err, debug.Stack())
+	}
+	return C.struct_phst_emacs_trampoline_result{e.signal(err), v.raw()}
 }
 
 //export phst_emacs_function_finalizer
 func phst_emacs_function_finalizer(data C.uint64_t) {
-	funcs.delete(funcIndex(data))
+	funcs.release(funcIndex(data))
 }
 
-func protect(e Env, r *C.struct_result_base_with_optional_error_info) {
+// protect recovers from a panic and converts it to a Lisp signal.  name
+// points to the name of the exported function that’s running; it may be nil
+// if there’s no such function.
+func protect(e Env, name *Name, r *C.struct_result_base_with_optional_error_info) {
 	if x := recover(); x != nil {
 		// Because to Go runtime calls deferred functions in the call
 		// frame of the panic, this stack trace will be useful.  This
@@ -66,8 +87,17 @@ func protect(e Env, r *C.struct_result_base_with_optional_error_info) {
 		// function prints combine nicely: The Go stack trace starts at
 		// the Cgo entry point, the Emacs stack trace ends at the
 		// module interface.
-		debug.PrintStack()
-		*r = e.signal(errPanic.Error(String(fmt.Sprint(x))))
+		stack := debug.Stack()
+		os.Stderr.Write(stack)
+		err := errPanic.Error(String(fmt.Sprint(x)))
+		if DebugMode() {
+			var n Name
+			if name != nil {
+				n = *name
+			}
+			e.reportFailure(n, err, stack)
+		}
+		*r = e.signal(err)
 	}
 }
 
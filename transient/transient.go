@@ -0,0 +1,269 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transient defines transient menus from Go.  A transient menu is a
+// prefix command that shows a popup with infix arguments, such as switches
+// and options, and suffix commands that act on these arguments.  See the
+// [Transient manual].
+//
+//	func init() {
+//		transient.Register(transient.Prefix{
+//			Name: "my-tool",
+//			Doc:  "Run my tool.",
+//			Groups: []transient.Group{
+//				{"Arguments", []transient.Item{
+//					transient.Switch{Key: "-v", Description: "Verbose", Argument: "--verbose"},
+//					transient.Option{Key: "-o", Description: "Output", Argument: "--output="},
+//				}},
+//				{"Actions", []transient.Item{
+//					transient.Suffix{Key: "r", Description: "Run", Name: "my-tool-run", Fun: run},
+//				}},
+//			},
+//		})
+//	}
+//
+// Here, run receives the arguments selected in the menu, for example
+// ["--verbose", "--output=out.txt"].
+//
+// [Transient manual]: https://www.gnu.org/software/emacs/manual/html_mono/transient.html
+package transient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/phst/emacs"
+)
+
+// Prefix describes a transient prefix command.
+type Prefix struct {
+	// Name is the name of the prefix command.  It must not be empty.
+	Name emacs.Name
+
+	// Doc is the documentation string of the prefix command.
+	Doc emacs.Doc
+
+	// Groups are the groups of infix arguments and suffix commands.
+	Groups []Group
+}
+
+// Group is a group of items in a transient menu.
+type Group struct {
+	// Description is the heading of the group.  It can be empty.
+	Description string
+
+	Items []Item
+}
+
+// Item is an item in a [Group].  Its implementations are [Switch], [Option],
+// and [Suffix].
+type Item interface {
+	spec() (emacs.List, error)
+}
+
+// Switch is an infix argument that can be turned on or off.
+type Switch struct {
+	// Key is the key sequence that toggles the switch, for example "-v".
+	Key string
+
+	Description string
+
+	// Argument is the command-line argument, for example "--verbose".
+	// It must not end with an equals sign.
+	Argument string
+}
+
+func (s Switch) spec() (emacs.List, error) {
+	if s.Key == "" || s.Argument == "" || strings.HasSuffix(s.Argument, "=") {
+		return nil, fmt.Errorf("invalid switch %+v", s)
+	}
+	return emacs.List{emacs.String(s.Key), emacs.String(s.Description), emacs.String(s.Argument)}, nil
+}
+
+// Option is an infix argument that has a value.
+type Option struct {
+	// Key is the key sequence that sets the option, for example "-o".
+	Key string
+
+	Description string
+
+	// Argument is the command-line argument including the trailing
+	// equals sign, for example "--output=".  The value of the option is
+	// appended to it.
+	Argument string
+
+	// Choices, if nonempty, restricts the values of the option.
+	Choices []string
+}
+
+func (o Option) spec() (emacs.List, error) {
+	if o.Key == "" || !strings.HasSuffix(o.Argument, "=") {
+		return nil, fmt.Errorf("invalid option %+v", o)
+	}
+	r := emacs.List{emacs.String(o.Key), emacs.String(o.Description), emacs.String(o.Argument)}
+	if len(o.Choices) > 0 {
+		choices := make(emacs.List, len(o.Choices))
+		for i, c := range o.Choices {
+			choices[i] = emacs.String(c)
+		}
+		r = append(r, emacs.Symbol(":choices"), emacs.List{emacs.Symbol("quote"), choices})
+	}
+	return r, nil
+}
+
+// Suffix is a command in a transient menu.
+type Suffix struct {
+	// Key is the key sequence that invokes the command.
+	Key string
+
+	Description string
+
+	// Name is the name of the command.  It must not be empty.
+	Name emacs.Name
+
+	// If Fun is non-nil, defining the prefix also defines the command
+	// Name.  The command calls Fun with the arguments that are currently
+	// selected in the menu.  If Fun is nil, Name must refer to an existing
+	// command.
+	Fun func(e emacs.Env, args []string) error
+}
+
+func (s Suffix) spec() (emacs.List, error) {
+	if s.Key == "" || s.Name == "" {
+		return nil, fmt.Errorf("invalid suffix %+v", s)
+	}
+	return emacs.List{emacs.String(s.Key), emacs.String(s.Description), s.Name}, nil
+}
+
+// Register arranges for the prefix p and the suffix commands with Go
+// functions to be defined once the module is loaded.  Call Register in an
+// init function.  Register panics if p is invalid or its name is already
+// registered.  It returns p.Name so you can assign it directly to a Go
+// variable if you want.
+func Register(p Prefix) emacs.Name {
+	d, err := newDefinition(p)
+	if err != nil {
+		panic(err)
+	}
+	manager.MustEnqueue(p.Name, d)
+	return p.Name
+}
+
+// Define is like [Register], except that it requires a live environment,
+// defines the prefix immediately, and returns errors instead of panicking.
+func Define(e emacs.Env, p Prefix) error {
+	d, err := newDefinition(p)
+	if err != nil {
+		return err
+	}
+	return manager.RegisterAndDefine(e, p.Name, d)
+}
+
+var manager = emacs.NewManager(emacs.RequireName | emacs.RequireUniqueName | emacs.DefineOnInit)
+
+type definition struct {
+	name     emacs.Name
+	form     emacs.List
+	suffixes []Suffix
+}
+
+func newDefinition(p Prefix) (*definition, error) {
+	if p.Name == "" {
+		return nil, errors.New("transient prefix has no name")
+	}
+	form, err := prefixForm(p)
+	if err != nil {
+		return nil, fmt.Errorf("transient prefix %s: %w", p.Name, err)
+	}
+	d := &definition{name: p.Name, form: form}
+	for _, g := range p.Groups {
+		for _, it := range g.Items {
+			if s, ok := it.(Suffix); ok && s.Fun != nil {
+				d.suffixes = append(d.suffixes, s)
+			}
+		}
+	}
+	return d, nil
+}
+
+// prefixForm returns a transient-define-prefix form for p.
+func prefixForm(p Prefix) (emacs.List, error) {
+	r := emacs.List{emacs.Symbol("transient-define-prefix"), p.Name, emacs.List{}}
+	if p.Doc != "" {
+		r = append(r, emacs.String(p.Doc))
+	}
+	for _, g := range p.Groups {
+		var group emacs.Vector
+		if g.Description != "" {
+			group = append(group, emacs.String(g.Description))
+		}
+		for _, it := range g.Items {
+			s, err := it.spec()
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, s)
+		}
+		r = append(r, group)
+	}
+	return r, nil
+}
+
+func (d *definition) Define(e emacs.Env) error {
+	if _, err := e.Call("require", emacs.Symbol("transient")); err != nil {
+		return err
+	}
+	for _, s := range d.suffixes {
+		if err := d.defineSuffix(e, s); err != nil {
+			return err
+		}
+	}
+	_, err := e.Eval(d.form)
+	return err
+}
+
+func (d *definition) defineSuffix(e emacs.Env, s Suffix) error {
+	fun := s.Fun
+	doc := emacs.Doc(s.Description)
+	if doc == "" {
+		doc = emacs.Doc(fmt.Sprintf("Suffix command of %s.", d.name))
+	}
+	f, err := e.ExportFunc(s.Name, func(e emacs.Env, _ []emacs.Value) (emacs.Value, error) {
+		v, err := e.Call("transient-args", d.name)
+		if err != nil {
+			return emacs.Value{}, err
+		}
+		var args []string
+		if err := e.Dolist(v, func(a emacs.Value) error {
+			s, err := e.Str(a)
+			args = append(args, s)
+			return err
+		}); err != nil {
+			return emacs.Value{}, err
+		}
+		if err := fun(e, args); err != nil {
+			return emacs.Value{}, err
+		}
+		return e.Nil()
+	}, emacs.Arity{}, doc)
+	if err != nil {
+		return err
+	}
+	spec, err := emacs.Nil.Emacs(e)
+	if err != nil {
+		return err
+	}
+	return e.MakeInteractive(f, spec)
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/phst/emacs"
+)
+
+func TestPrefixForm(t *testing.T) {
+	run := func(emacs.Env, []string) error { return nil }
+	p := Prefix{
+		Name: "test-prefix",
+		Doc:  "Test prefix.",
+		Groups: []Group{
+			{"Arguments", []Item{
+				Switch{Key: "-v", Description: "Verbose", Argument: "--verbose"},
+				Option{Key: "-o", Description: "Output", Argument: "--output=", Choices: []string{"a", "b"}},
+			}},
+			{"", []Item{
+				Suffix{Key: "r", Description: "Run", Name: "test-run", Fun: run},
+				Suffix{Key: "q", Description: "Quit", Name: "transient-quit-one"},
+			}},
+		},
+	}
+	d, err := newDefinition(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := emacs.List{
+		emacs.Symbol("transient-define-prefix"), emacs.Name("test-prefix"), emacs.List{},
+		emacs.String("Test prefix."),
+		emacs.Vector{
+			emacs.String("Arguments"),
+			emacs.List{emacs.String("-v"), emacs.String("Verbose"), emacs.String("--verbose")},
+			emacs.List{
+				emacs.String("-o"), emacs.String("Output"), emacs.String("--output="),
+				emacs.Symbol(":choices"), emacs.List{emacs.Symbol("quote"), emacs.List{emacs.String("a"), emacs.String("b")}},
+			},
+		},
+		emacs.Vector{
+			emacs.List{emacs.String("r"), emacs.String("Run"), emacs.Name("test-run")},
+			emacs.List{emacs.String("q"), emacs.String("Quit"), emacs.Name("transient-quit-one")},
+		},
+	}
+	if !reflect.DeepEqual(d.form, want) {
+		t.Errorf("form: got %#v, want %#v", d.form, want)
+	}
+	if len(d.suffixes) != 1 || d.suffixes[0].Name != "test-run" {
+		t.Errorf("suffixes: got %+v, want only test-run", d.suffixes)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, p := range []Prefix{
+		{},
+		{Name: "test", Groups: []Group{{Items: []Item{Switch{Key: "-a", Argument: "--a="}}}}},
+		{Name: "test", Groups: []Group{{Items: []Item{Switch{Argument: "--a"}}}}},
+		{Name: "test", Groups: []Group{{Items: []Item{Option{Key: "-a", Argument: "--a"}}}}},
+		{Name: "test", Groups: []Group{{Items: []Item{Suffix{Key: "a"}}}}},
+	} {
+		if _, err := newDefinition(p); err == nil {
+			t.Errorf("newDefinition(%+v): got no error", p)
+		}
+	}
+}
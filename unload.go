@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"sync"
+)
+
+// UnloadFunc is a cleanup function that should run when the module is
+// unloaded.  Use [OnUnload] to register UnloadFunc functions.
+type UnloadFunc func(Env) error
+
+// OnUnload arranges for the given function to run when the user unloads the
+// module using unload-feature.  Emacs can’t unload the shared library of a
+// dynamic module, and unload-feature only removes the definitions that it
+// knows about, such as functions, variables, and hooks.  Use unload functions
+// to release everything else: close files, stop goroutines, free global
+// references, remove advice, and the like.
+//
+// Unload functions only run if the module provides a feature using [Provide]
+// and you call OnUnload before Emacs loads the module, typically from an init
+// function.  In that case, the module defines FEATURE-unload-function for
+// each provided FEATURE; don’t define such a function yourself.  Unload
+// functions registered later, e.g. from an exported function after acquiring
+// a resource, then run as well.  The unload functions run in reverse
+// registration order, only once, when the first provided feature is unloaded.
+// If an unload function returns an error, the remaining ones still run, and
+// unload-feature then signals the errors and aborts.  You can also run the
+// unload functions explicitly using [Env.Unload].  You can call OnUnload
+// safely from multiple goroutines.
+func OnUnload(f UnloadFunc) {
+	if f == nil {
+		panic("nil unload function")
+	}
+	unloads.add(f)
+}
+
+// Unload runs the functions registered using [OnUnload] in reverse
+// registration order, unless they have already run.  Typically, you don’t
+// need to call Unload, since unload-feature calls it automatically; but if
+// your module doesn’t provide a feature or defines its own unload function,
+// you can call Unload from there.  Unload returns the errors of the unload
+// functions joined using [errors.Join].
+func (e Env) Unload() error {
+	return unloads.run(e)
+}
+
+// unloadList contains the functions registered using [OnUnload].
+type unloadList struct {
+	mu    sync.Mutex
+	funcs []UnloadFunc
+	done  bool
+}
+
+func (l *unloadList) add(f UnloadFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.funcs = append(l.funcs, f)
+}
+
+func (l *unloadList) empty() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.funcs) == 0
+}
+
+// run runs the unload functions in reverse order unless they have already
+// run.  It doesn’t hold the lock while running them, so that they can call
+// OnUnload themselves; such functions won’t run, though.
+func (l *unloadList) run(e Env) error {
+	l.mu.Lock()
+	if l.done {
+		l.mu.Unlock()
+		return nil
+	}
+	l.done = true
+	fs := l.funcs
+	l.funcs = nil
+	l.mu.Unlock()
+	var errs []error
+	for i := len(fs) - 1; i >= 0; i-- {
+		if err := fs[i](e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// defineUnloadFunction defines FEATURE-unload-function for the given feature
+// if there are any unload functions.
+func (e Env) defineUnloadFunction(feature Name) error {
+	if unloads.empty() {
+		return nil
+	}
+	_, err := e.ExportFunc(feature+"-unload-function", func(e Env, _ []Value) (Value, error) {
+		if err := e.Unload(); err != nil {
+			return Value{}, err
+		}
+		// Return nil so that unload-feature continues with its standard
+		// cleanup.
+		return e.Nil()
+	}, Arity{0, 0}, "Run the unload functions of the Go module.")
+	return err
+}
+
+var unloads unloadList
@@ -0,0 +1,42 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUnloadList(t *testing.T) {
+	var l unloadList
+	var got []int
+	errA := errors.New("a")
+	errB := errors.New("b")
+	l.add(func(Env) error { got = append(got, 1); return errA })
+	l.add(func(Env) error { got = append(got, 2); return nil })
+	l.add(func(Env) error { got = append(got, 3); return errB })
+	err := l.run(Env{})
+	if want := []int{3, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unload functions ran in order %v, want %v", got, want)
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("got error %v, want both %v and %v", err, errA, errB)
+	}
+	got = nil
+	if err := l.run(Env{}); err != nil || got != nil {
+		t.Errorf("second run: got error %v and calls %v, want no error and no calls", err, got)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// #include "emacs-module.h"
+// #include "wrappers.h"
+import "C"
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MakeUserPointer returns a new Emacs user pointer object that represents the
+// Go value v.  The user pointer doesn’t contain a Go pointer; instead, it
+// contains an index into a registry of Go values, so this doesn’t violate
+// the cgo pointer-passing rules.  The registry keeps v alive until Emacs
+// garbage-collects the user pointer object.  Use [Env.UserPointerValue] to
+// retrieve v.  Prefer user pointers over integer handles if the lifetime of v
+// should be tied to the Lisp object: unlike handles, user pointers release
+// the Go value automatically.  Note that Emacs only garbage-collects
+// occasionally, so the Go value might stay alive for a long time.
+func (e Env) MakeUserPointer(v interface{}) (Value, error) {
+	i := userPtrs.add(v)
+	r, err := e.checkValue(C.phst_emacs_make_user_ptr(e.raw(), C.uint64_t(i)))
+	if err != nil {
+		userPtrs.remove(i)
+		return Value{}, err
+	}
+	return r, nil
+}
+
+// UserPointerValue returns the Go value represented by the user pointer
+// object p.  p must have been created by [Env.MakeUserPointer]; otherwise,
+// UserPointerValue returns an error that signals wrong-type-argument.
+func (e Env) UserPointerValue(p Value) (interface{}, error) {
+	r := C.phst_emacs_get_user_ptr(e.raw(), p.raw())
+	if err := e.check(r.base); err != nil {
+		return nil, err
+	}
+	if !bool(r.known) {
+		return nil, WrongTypeArgument("go-user-ptr-p", p)
+	}
+	v, ok := userPtrs.get(uint64(r.value))
+	if !ok {
+		return nil, WrongTypeArgument("go-user-ptr-p", p)
+	}
+	return v, nil
+}
+
+//export phst_emacs_user_ptr_finalizer
+func phst_emacs_user_ptr_finalizer(data C.uint64_t) {
+	userPtrs.remove(uint64(data))
+}
+
+// userPtrRegistry contains the Go values represented by user pointers.
+type userPtrRegistry struct {
+	last uint64 // accessed atomically
+
+	mu     sync.Mutex
+	values map[uint64]interface{}
+}
+
+// add registers v and returns its index, which is never zero.
+func (r *userPtrRegistry) add(v interface{}) uint64 {
+	i := atomic.AddUint64(&r.last, 1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.values == nil {
+		r.values = make(map[uint64]interface{})
+	}
+	r.values[i] = v
+	return i
+}
+
+func (r *userPtrRegistry) get(i uint64) (interface{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.values[i]
+	return v, ok
+}
+
+func (r *userPtrRegistry) remove(i uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.values, i)
+}
+
+// len returns the number of registered values.
+func (r *userPtrRegistry) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.values)
+}
+
+var userPtrs userPtrRegistry
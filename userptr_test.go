@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func init() {
+	ERTTest(userPointer)
+}
+
+func userPointer(e Env) error {
+	type payload struct{ s string }
+	want := &payload{"hello"}
+	p, err := e.MakeUserPointer(want)
+	if err != nil {
+		return err
+	}
+	var ok Bool
+	if err := e.CallOut("user-ptrp", &ok, p); err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("MakeUserPointer didn’t return a user pointer")
+	}
+	got, err := e.UserPointerValue(p)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("UserPointerValue: got %v, want %v", got, want)
+	}
+	i, err := Int(1).Emacs(e)
+	if err != nil {
+		return err
+	}
+	if _, err := e.UserPointerValue(i); !e.IsWrongTypeArgument(err) {
+		return fmt.Errorf("UserPointerValue of integer: got error %v, want wrong-type-argument", err)
+	}
+	return nil
+}
+
+func TestUserPtrRegistry(t *testing.T) {
+	var r userPtrRegistry
+	i := r.add("a")
+	j := r.add("b")
+	if i == 0 || i == j {
+		t.Fatalf("invalid indices %d and %d", i, j)
+	}
+	if v, ok := r.get(j); !ok || v != "b" {
+		t.Errorf("get(%d) = %v, %v; want b, true", j, v, ok)
+	}
+	r.remove(i)
+	if _, ok := r.get(i); ok {
+		t.Errorf("get(%d) succeeded after removal", i)
+	}
+	if n := r.len(); n != 1 {
+		t.Errorf("len = %d, want 1", n)
+	}
+}
@@ -33,7 +33,10 @@ import (
 // Values] for details.
 //
 // [Conversion Between Lisp and Module Values]: https://www.gnu.org/software/emacs/manual/html_node/elisp/Module-Values.html
-type Value struct{ r C.emacs_value }
+type Value struct {
+	r   C.emacs_value
+	gen generation // generation of the environment that created the value
+}
 
 // In is a value that knows how to convert itself into an Emacs object.  You
 // can implement In for your own types if you want this package to convert them
@@ -120,6 +123,10 @@ func newIn(v interface{}) In {
 		return Bytes(v)
 	case *big.Int:
 		return (*BigInt)(v)
+	case *big.Float:
+		return (*BigFloat)(v)
+	case *big.Rat:
+		return (*BigRat)(v)
 	case time.Time:
 		return Time(v)
 	case time.Duration:
@@ -166,6 +173,10 @@ func newOut(p interface{}) Out {
 		return (*Bytes)(p)
 	case *big.Int:
 		return (*BigInt)(p)
+	case *big.Float:
+		return (*BigFloat)(p)
+	case *big.Rat:
+		return (*BigRat)(p)
 	case *time.Time:
 		return (*Time)(p)
 	case *time.Duration:
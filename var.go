@@ -81,4 +81,4 @@ func (v variable) Define(e Env) error {
 	return e.Defvar(v.name, v.init, v.doc)
 }
 
-var vars = NewManager(RequireName | RequireUniqueName | DefineOnInit)
+var vars = NewManagerOf[variable](RequireName | RequireUniqueName | DefineOnInit)
@@ -98,7 +98,7 @@ func (e Env) MakeVector(n int, init In) (Value, error) {
 // VecGet returns the i-th element of vector.  It returns an error if vector is
 // not a vector.
 func (e Env) VecGet(vector Value, i int) (Value, error) {
-	return e.checkValue(C.phst_emacs_vec_get(e.raw(), vector.r, C.int64_t(i)))
+	return e.checkValue(C.phst_emacs_vec_get(e.raw(), vector.raw(), C.int64_t(i)))
 }
 
 // VecGetOut sets elem to the value of the i-th element of vector.  It returns
@@ -113,7 +113,7 @@ func (e Env) VecGetOut(vector Value, i int, elem Out) error {
 
 // VecSet sets the i-th element of the given Emacs vector.
 func (e Env) VecSet(v Value, i int, elem Value) error {
-	return e.checkVoid(C.phst_emacs_vec_set(e.raw(), v.r, C.int64_t(i), elem.r))
+	return e.checkVoid(C.phst_emacs_vec_set(e.raw(), v.raw(), C.int64_t(i), elem.raw()))
 }
 
 // VecSetIn sets the i-th element of the given Emacs vector.
@@ -127,7 +127,7 @@ func (e Env) VecSetIn(v Value, i int, elem In) error {
 
 // VecSize returns the size of the given Emacs vector.
 func (e Env) VecSize(v Value) (int, error) {
-	r := C.phst_emacs_vec_size(e.raw(), v.r)
+	r := C.phst_emacs_vec_size(e.raw(), v.raw())
 	if err := e.check(r.base); err != nil {
 		return -1, err
 	}
@@ -156,7 +156,7 @@ func (m makeVector) Emacs(e Env) (Value, error) {
 	}
 	for i := 0; i < n; i++ {
 		if err := e.VecSetIn(r, i, m.elem(m.Index(i))); err != nil {
-			return Value{}, err
+			return Value{}, conversionContext(err, fmt.Sprintf("vector element %d", i), m.Type().Elem())
 		}
 	}
 	return r, nil
@@ -185,7 +185,7 @@ func (g getVector) FromEmacs(e Env, v Value) error {
 	}
 	for i := 0; i < n; i++ {
 		if err := e.VecGetOut(v, i, g.elem(s.Index(i).Addr())); err != nil {
-			return err
+			return conversionContext(err, fmt.Sprintf("vector element %d", i), s.Type().Elem())
 		}
 	}
 	u.Set(s)
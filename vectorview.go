@@ -0,0 +1,248 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+// #include "wrappers.h"
+import "C"
+
+import (
+	"errors"
+	"runtime"
+)
+
+// VectorView is a live view of an Emacs vector.  Unlike converting the vector
+// to a Go slice, a VectorView doesn’t copy the vector; all operations act on
+// the Emacs vector directly, so changes are visible to Emacs and vice versa.
+// A VectorView stays valid across calls into the module: the Emacs vector
+// stays alive as long as the VectorView is reachable from Go, or until you
+// call [VectorView.Release].  Like all interaction with Emacs, the methods
+// that take an [Env] must only be called from a function called by Emacs.
+type VectorView struct {
+	ref Value // global reference, zero after Release
+	len int
+}
+
+// NewVectorView returns a new VectorView for the Emacs vector v.  It returns
+// an error if v isn’t a vector.
+func (e Env) NewVectorView(v Value) (*VectorView, error) {
+	n, err := e.VecSize(v)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := e.makeGlobalRef(v)
+	if err != nil {
+		return nil, err
+	}
+	w := &VectorView{ref, n}
+	runtime.SetFinalizer(w, (*VectorView).finalize)
+	return w, nil
+}
+
+// Len returns the length of the vector.  Since the length of an Emacs vector
+// never changes, Len doesn’t need to call into Emacs.
+func (v *VectorView) Len() int { return v.len }
+
+// Get returns the i-th element of the vector.
+func (v *VectorView) Get(e Env, i int) (Value, error) {
+	if err := v.check(); err != nil {
+		return Value{}, err
+	}
+	return e.VecGet(v.ref, i)
+}
+
+// GetOut sets elem to the i-th element of the vector.
+func (v *VectorView) GetOut(e Env, i int, elem Out) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	return e.VecGetOut(v.ref, i, elem)
+}
+
+// Set sets the i-th element of the vector.
+func (v *VectorView) Set(e Env, i int, elem In) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	return e.VecSetIn(v.ref, i, elem)
+}
+
+// Slice returns the elements with indices start, start + 1, …, end − 1.  It
+// fetches all elements in one call, which is much faster than calling
+// [VectorView.Get] for each element.
+func (v *VectorView) Slice(e Env, start, end int) ([]Value, error) {
+	if err := v.checkRange(start, end); err != nil {
+		return nil, err
+	}
+	n := end - start
+	if n == 0 {
+		return nil, nil
+	}
+	raw := make([]C.emacs_value, n)
+	if err := e.checkVoid(C.phst_emacs_vec_get_range(e.raw(), v.ref.raw(), C.int64_t(start), C.int64_t(n), &raw[0])); err != nil {
+		return nil, err
+	}
+	r := make([]Value, n)
+	for i, u := range raw {
+		r[i] = e.value(u)
+	}
+	return r, nil
+}
+
+// SetSlice sets the elements with indices start, start + 1, …,
+// start + len(elems) − 1 to elems.  It stores all elements in one call, which
+// is much faster than calling [VectorView.Set] for each element.
+func (v *VectorView) SetSlice(e Env, start int, elems []Value) error {
+	if err := v.checkRange(start, start+len(elems)); err != nil {
+		return err
+	}
+	if len(elems) == 0 {
+		return nil
+	}
+	raw := make([]C.emacs_value, len(elems))
+	for i, u := range elems {
+		raw[i] = u.raw()
+	}
+	return e.checkVoid(C.phst_emacs_vec_set_range(e.raw(), v.ref.raw(), C.int64_t(start), C.int64_t(len(raw)), &raw[0]))
+}
+
+// Sort sorts the vector in place using the Emacs function sort.  pred is the
+// predicate function; it receives two elements and returns whether the first
+// sorts before the second.  Sorting happens entirely within Emacs, so it’s
+// faster than sorting using [VectorView.Sorter] if pred is an Emacs function.
+func (v *VectorView) Sort(e Env, pred In) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	_, err := e.Call("sort", v.ref, pred)
+	return err
+}
+
+// Sorter returns a [VectorSorter] that implements [sort.Interface] for the
+// vector using the given less function.  The sorter is only valid while e is
+// live.
+func (v *VectorView) Sorter(e Env, less func(e Env, a, b Value) (bool, error)) *VectorSorter {
+	return &VectorSorter{env: e, view: v, less: less}
+}
+
+// Emacs returns the vector.  This allows passing a VectorView back to Emacs.
+// Emacs returns an error if the VectorView has been released.
+func (v *VectorView) Emacs(e Env) (Value, error) {
+	if err := v.check(); err != nil {
+		return Value{}, err
+	}
+	return v.ref, nil
+}
+
+// Release releases the vector.  Afterwards, all operations on the VectorView
+// except Len fail.  Calling Release is optional, since the vector is released
+// once the VectorView is garbage-collected, but calling Release frees
+// resources earlier.  Calling Release more than once has no effect.
+func (v *VectorView) Release(e Env) error {
+	if v.ref.r == nil {
+		return nil
+	}
+	runtime.SetFinalizer(v, nil)
+	ref := v.ref
+	v.ref = Value{}
+	return e.freeGlobalRef(ref)
+}
+
+func (v *VectorView) finalize() {
+	if v.ref.r != nil {
+		releaseLater(v.ref)
+	}
+}
+
+func (v *VectorView) check() error {
+	if v.ref.r == nil {
+		return errors.New("vector view already released")
+	}
+	return nil
+}
+
+func (v *VectorView) checkRange(start, end int) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	if start < 0 || end < start || end > v.len {
+		return argsOutOfRange.Error(Int(start), Int(end))
+	}
+	return nil
+}
+
+// VectorSorter implements [sort.Interface] for a [VectorView].  Use
+// [VectorView.Sorter] to create VectorSorter objects.  Since the methods of
+// [sort.Interface] can’t return errors, a VectorSorter records the first
+// error, after which it treats all elements as equal.  Check [VectorSorter.Err]
+// after sorting.
+type VectorSorter struct {
+	env  Env
+	view *VectorView
+	less func(e Env, a, b Value) (bool, error)
+	err  error
+}
+
+// Len returns the length of the vector.
+func (s *VectorSorter) Len() int { return s.view.Len() }
+
+// Less calls the less function on the elements i and j.
+func (s *VectorSorter) Less(i, j int) bool {
+	if s.err != nil {
+		return false
+	}
+	a, err := s.view.Get(s.env, i)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	b, err := s.view.Get(s.env, j)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	r, err := s.less(s.env, a, b)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	return r
+}
+
+// Swap swaps the elements i and j.
+func (s *VectorSorter) Swap(i, j int) {
+	if s.err != nil {
+		return
+	}
+	a, err := s.view.Get(s.env, i)
+	if err != nil {
+		s.err = err
+		return
+	}
+	b, err := s.view.Get(s.env, j)
+	if err != nil {
+		s.err = err
+		return
+	}
+	if err := s.env.VecSet(s.view.ref, i, b); err != nil {
+		s.err = err
+		return
+	}
+	if err := s.env.VecSet(s.view.ref, j, a); err != nil {
+		s.err = err
+	}
+}
+
+// Err returns the first error that occurred while sorting, if any.
+func (s *VectorSorter) Err() error { return s.err }
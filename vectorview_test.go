@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emacs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+func init() {
+	ERTTest(vectorView)
+}
+
+func vectorView(e Env) error {
+	vec, err := e.Call("vector", Int(3), Int(1), Int(2), Int(5), Int(4))
+	if err != nil {
+		return err
+	}
+	v, err := e.NewVectorView(vec)
+	if err != nil {
+		return err
+	}
+	defer v.Release(e)
+	if n := v.Len(); n != 5 {
+		return fmt.Errorf("Len: got %d, want 5", n)
+	}
+	ints := func() ([]int64, error) {
+		elems, err := v.Slice(e, 0, v.Len())
+		if err != nil {
+			return nil, err
+		}
+		r := make([]int64, len(elems))
+		for i, u := range elems {
+			if r[i], err = e.Int(u); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+	if err := v.Set(e, 0, Int(6)); err != nil {
+		return err
+	}
+	// The change must be visible in the original vector.
+	var first Int
+	if err := e.CallOut("aref", &first, vec, Int(0)); err != nil {
+		return err
+	}
+	if first != 6 {
+		return fmt.Errorf("(aref vec 0): got %d, want 6", first)
+	}
+	s := v.Sorter(e, func(e Env, a, b Value) (bool, error) {
+		var r Bool
+		err := e.CallOut("<", &r, a, b)
+		return bool(r), err
+	})
+	sort.Sort(s)
+	if err := s.Err(); err != nil {
+		return err
+	}
+	got, err := ints()
+	if err != nil {
+		return err
+	}
+	if want := []int64{1, 2, 4, 5, 6}; !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("after sort.Sort: got %v, want %v", got, want)
+	}
+	if err := v.Sort(e, Symbol(">")); err != nil {
+		return err
+	}
+	got, err = ints()
+	if err != nil {
+		return err
+	}
+	if want := []int64{6, 5, 4, 2, 1}; !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("after Sort: got %v, want %v", got, want)
+	}
+	tail, err := v.Slice(e, 3, 5)
+	if err != nil {
+		return err
+	}
+	if err := v.SetSlice(e, 0, tail); err != nil {
+		return err
+	}
+	got, err = ints()
+	if err != nil {
+		return err
+	}
+	if want := []int64{2, 1, 4, 2, 1}; !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("after SetSlice: got %v, want %v", got, want)
+	}
+	if _, err := v.Slice(e, 4, 6); err == nil {
+		return errors.New("Slice out of range succeeded")
+	}
+	i, err := first.Emacs(e)
+	if err != nil {
+		return err
+	}
+	if _, err := e.NewVectorView(i); err == nil {
+		return errors.New("NewVectorView with integer succeeded")
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-// Copyright 2019, 2023, 2025 Google LLC
+// Copyright 2019, 2023, 2025, 2026 Google LLC
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -34,6 +34,23 @@ func MajorVersion() int {
 	return v
 }
 
+// RequireEmacs arranges for loading the module to fail if the major version of
+// Emacs is less than major.  If RequireEmacs is called multiple times, the
+// largest version wins.  Together with [RequireCapability], all unmet
+// requirements are reported in a single error.  Call RequireEmacs before Emacs
+// loads the module, typically in an init function.  You can call RequireEmacs
+// safely from multiple goroutines.
+func RequireEmacs(major int) {
+	if major <= 0 {
+		panic(fmt.Errorf("invalid Emacs major version %d", major))
+	}
+	requirements.mu.Lock()
+	defer requirements.mu.Unlock()
+	if major > requirements.major {
+		requirements.major = major
+	}
+}
+
 // Stores the Emacs major version.
 type versionManager struct{ version int32 }
 
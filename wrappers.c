@@ -294,6 +294,34 @@ struct phst_emacs_integer_result phst_emacs_vec_size(emacs_env *env,
   return check_integer(env, env->vec_size(env, vec));
 }
 
+struct phst_emacs_void_result phst_emacs_vec_get_range(emacs_env *env,
+                                                       emacs_value vec,
+                                                       int64_t start,
+                                                       int64_t count,
+                                                       emacs_value *elems) {
+  for (int64_t i = 0; i < count; ++i) {
+    elems[i] = env->vec_get(env, vec, start + i);
+    if (env->non_local_exit_check(env) != emacs_funcall_exit_return) {
+      break;
+    }
+  }
+  return check_void(env);
+}
+
+struct phst_emacs_void_result phst_emacs_vec_set_range(emacs_env *env,
+                                                       emacs_value vec,
+                                                       int64_t start,
+                                                       int64_t count,
+                                                       emacs_value *elems) {
+  for (int64_t i = 0; i < count; ++i) {
+    env->vec_set(env, vec, start + i, elems[i]);
+    if (env->non_local_exit_check(env) != emacs_funcall_exit_return) {
+      break;
+    }
+  }
+  return check_void(env);
+}
+
 static_assert((time_t)1.5 == 1, "unsupported architecture");
 static_assert(LONG_MAX >= 1000000000, "unsupported architecture");
 
@@ -336,6 +364,83 @@ struct phst_emacs_void_result phst_emacs_make_interactive(emacs_env *env,
   return result;
 }
 
+struct phst_emacs_value_result phst_emacs_make_global_ref(emacs_env *env,
+                                                          emacs_value value) {
+  return check_value(env, env->make_global_ref(env, value));
+}
+
+struct phst_emacs_void_result phst_emacs_free_global_ref(emacs_env *env,
+                                                         emacs_value value) {
+  env->free_global_ref(env, value);
+  return check_void(env);
+}
+
+static void user_ptr_finalizer(void *data) {
+  phst_emacs_user_ptr_finalizer((uintptr_t)data);
+}
+
+struct phst_emacs_value_result phst_emacs_make_user_ptr(emacs_env *env,
+                                                        uint64_t data) {
+  return check_value(
+      env, env->make_user_ptr(env, user_ptr_finalizer, (void *)(uintptr_t)data));
+}
+
+struct phst_emacs_user_ptr_result phst_emacs_get_user_ptr(emacs_env *env,
+                                                          emacs_value value) {
+  emacs_finalizer finalizer = env->get_user_finalizer(env, value);
+  struct phst_emacs_result_base base = check(env);
+  if (base.exit != emacs_funcall_exit_return ||
+      finalizer != user_ptr_finalizer) {
+    return (struct phst_emacs_user_ptr_result){base, false, 0};
+  }
+  void *data = env->get_user_ptr(env, value);
+  return (struct phst_emacs_user_ptr_result){check(env), true,
+                                             (uintptr_t)data};
+}
+
+struct phst_emacs_void_result phst_emacs_batch(emacs_env *env,
+                                               const struct phst_emacs_batch_op *ops,
+                                               int64_t nops,
+                                               const char *strings,
+                                               const int64_t *args,
+                                               emacs_value *scratch,
+                                               emacs_value *results) {
+  for (int64_t i = 0; i < nops; ++i) {
+    const struct phst_emacs_batch_op *op = &ops[i];
+    emacs_value value = NULL;
+    switch (op->kind) {
+    case phst_emacs_batch_value:
+      value = op->value;
+      break;
+    case phst_emacs_batch_integer:
+      value = env->make_integer(env, op->integer);
+      break;
+    case phst_emacs_batch_float:
+      value = env->make_float(env, op->floating);
+      break;
+    case phst_emacs_batch_string:
+      value = env->make_string(env, strings + op->offset, op->length);
+      break;
+    case phst_emacs_batch_intern:
+      value = env->intern(env, strings + op->offset);
+      break;
+    case phst_emacs_batch_funcall:
+      for (int64_t j = 0; j < op->length; ++j) {
+        scratch[j] = results[args[op->offset + j]];
+      }
+      value = env->funcall(env, results[op->function], op->length, scratch);
+      break;
+    }
+    struct phst_emacs_result_base base = check(env);
+    if (base.exit != emacs_funcall_exit_return) {
+      return (struct phst_emacs_void_result){base};
+    }
+    results[i] = value;
+  }
+  return (struct phst_emacs_void_result){
+    {emacs_funcall_exit_return, NULL, NULL}};
+}
+
 static void handle_nonlocal_exit(emacs_env *env,
                                  struct result_base_with_optional_error_info result) {
   if (result.exit == emacs_funcall_exit_return) {
@@ -157,6 +157,22 @@ struct phst_emacs_void_result phst_emacs_vec_set(emacs_env *env,
 struct phst_emacs_integer_result phst_emacs_vec_size(emacs_env *env,
                                                      emacs_value vec);
 
+// Copy the elements start, start + 1, …, start + count - 1 of vec into elems.
+// Stop at the first nonlocal exit.
+struct phst_emacs_void_result phst_emacs_vec_get_range(emacs_env *env,
+                                                       emacs_value vec,
+                                                       int64_t start,
+                                                       int64_t count,
+                                                       emacs_value *elems);
+
+// Set the elements start, start + 1, …, start + count - 1 of vec from elems.
+// Stop at the first nonlocal exit.
+struct phst_emacs_void_result phst_emacs_vec_set_range(emacs_env *env,
+                                                       emacs_value vec,
+                                                       int64_t start,
+                                                       int64_t count,
+                                                       emacs_value *elems);
+
 struct phst_emacs_timespec_result {
   struct phst_emacs_result_base base;
   struct timespec value;
@@ -177,4 +193,54 @@ struct phst_emacs_void_result phst_emacs_make_interactive(emacs_env *env,
                                                           emacs_value function,
                                                           emacs_value spec);
 
+struct phst_emacs_value_result phst_emacs_make_global_ref(emacs_env *env,
+                                                          emacs_value value);
+struct phst_emacs_void_result phst_emacs_free_global_ref(emacs_env *env,
+                                                         emacs_value value);
+
+void phst_emacs_user_ptr_finalizer(uint64_t data);
+
+struct phst_emacs_value_result phst_emacs_make_user_ptr(emacs_env *env,
+                                                        uint64_t data);
+
+struct phst_emacs_user_ptr_result {
+  struct phst_emacs_result_base base;
+  // Whether the user pointer was created by phst_emacs_make_user_ptr.
+  bool known;
+  uint64_t value;
+};
+
+struct phst_emacs_user_ptr_result phst_emacs_get_user_ptr(emacs_env *env,
+                                                          emacs_value value);
+
+// Kinds of operations in a batch.  See batch.go.
+enum phst_emacs_batch_kind {
+  phst_emacs_batch_value,
+  phst_emacs_batch_integer,
+  phst_emacs_batch_float,
+  phst_emacs_batch_string,
+  phst_emacs_batch_intern,
+  phst_emacs_batch_funcall,
+};
+
+struct phst_emacs_batch_op {
+  enum phst_emacs_batch_kind kind;
+  emacs_value value;      // for phst_emacs_batch_value
+  int64_t integer;        // for phst_emacs_batch_integer
+  double floating;        // for phst_emacs_batch_float
+  int64_t offset, length; // into strings or args
+  int64_t function;       // for phst_emacs_batch_funcall
+};
+
+// Executes the given operations in sequence, stopping at the first nonlocal
+// exit.  Stores the result of each operation in results.  scratch must have
+// room for the largest number of function arguments.
+struct phst_emacs_void_result phst_emacs_batch(emacs_env *env,
+                                               const struct phst_emacs_batch_op *ops,
+                                               int64_t nops,
+                                               const char *strings,
+                                               const int64_t *args,
+                                               emacs_value *scratch,
+                                               emacs_value *results);
+
 #endif